@@ -10,6 +10,7 @@ import (
 
 	// Commands
 	"github.com/newrelic/newrelic-cli/internal/agent"
+	"github.com/newrelic/newrelic-cli/internal/alerts"
 	"github.com/newrelic/newrelic-cli/internal/apiaccess"
 	"github.com/newrelic/newrelic-cli/internal/apm"
 	"github.com/newrelic/newrelic-cli/internal/changeTracking"
@@ -17,6 +18,7 @@ import (
 	"github.com/newrelic/newrelic-cli/internal/config"
 	configAPI "github.com/newrelic/newrelic-cli/internal/config/api"
 	configCmd "github.com/newrelic/newrelic-cli/internal/config/command"
+	"github.com/newrelic/newrelic-cli/internal/dashboard"
 	"github.com/newrelic/newrelic-cli/internal/decode"
 	diagnose "github.com/newrelic/newrelic-cli/internal/diagnose"
 	"github.com/newrelic/newrelic-cli/internal/edge"
@@ -31,6 +33,7 @@ import (
 	"github.com/newrelic/newrelic-cli/internal/profile"
 	"github.com/newrelic/newrelic-cli/internal/reporting"
 	"github.com/newrelic/newrelic-cli/internal/synthetics"
+	"github.com/newrelic/newrelic-cli/internal/terraform"
 	"github.com/newrelic/newrelic-cli/internal/utils"
 	"github.com/newrelic/newrelic-cli/internal/workload"
 )
@@ -42,24 +45,29 @@ var (
 func init() {
 	// Bind imported sub-commands
 	Command.AddCommand(agent.Command)
+	Command.AddCommand(alerts.Command)
 	Command.AddCommand(apiaccess.Command)
 	Command.AddCommand(synthetics.Command)
 	Command.AddCommand(apm.Command)
 	Command.AddCommand(configCmd.Command)
 	Command.AddCommand(changeTracking.Command)
+	Command.AddCommand(dashboard.Command)
 	Command.AddCommand(decode.Command)
 	Command.AddCommand(diagnose.Command)
 	Command.AddCommand(edge.Command)
 	Command.AddCommand(entities.Command)
 	Command.AddCommand(events.Command)
 	Command.AddCommand(install.Command)
+	Command.AddCommand(install.UninstallCommand)
 	Command.AddCommand(nerdgraph.Command)
 	Command.AddCommand(nerdstorage.Command)
 	Command.AddCommand(fleetcontrol.Command)
 	Command.AddCommand(nrql.Command)
 	Command.AddCommand(migrate.Command)
 	Command.AddCommand(profile.Command)
+	Command.AddCommand(install.RecipeCommand)
 	Command.AddCommand(reporting.Command)
+	Command.AddCommand(terraform.Command)
 	Command.AddCommand(utils.Command)
 	Command.AddCommand(workload.Command)
 
@@ -0,0 +1,19 @@
+package alerts
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// Command represents the alerts command
+var Command = &cobra.Command{
+	Use:   "alerts",
+	Short: "Manage New Relic alert policies, conditions and muting rules",
+	Long: `Manage New Relic alert policies, conditions and muting rules
+
+The alerts commands list, create, update and delete the alert policies, NRQL
+conditions and muting rules of an account.  Conditions and muting rules are
+defined in YAML files, and conditions can be updated in place, such as to raise
+a threshold during an incident.
+`,
+	Example: "newrelic alerts policy list --accountId 12345",
+}
@@ -0,0 +1,244 @@
+package alerts
+
+import (
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+
+	"github.com/newrelic/newrelic-cli/internal/client"
+	configAPI "github.com/newrelic/newrelic-cli/internal/config/api"
+	"github.com/newrelic/newrelic-cli/internal/output"
+	"github.com/newrelic/newrelic-cli/internal/utils"
+	nrAlerts "github.com/newrelic/newrelic-client-go/v2/pkg/alerts"
+)
+
+var (
+	conditionID        string
+	conditionPolicyID  string
+	conditionName      string
+	conditionFile      string
+	conditionThreshold float64
+	conditionPriority  string
+)
+
+var cmdCondition = &cobra.Command{
+	Use:     "condition",
+	Short:   "Manage New Relic alert conditions",
+	Long:    "Manage New Relic alert conditions",
+	Example: "newrelic alerts condition nrql --help",
+}
+
+var cmdConditionNrql = &cobra.Command{
+	Use:   "nrql",
+	Short: "Manage New Relic NRQL alert conditions",
+	Long: `Manage New Relic NRQL alert conditions
+
+NRQL conditions are defined in YAML files, with the fields of the NerdGraph
+condition input.  The type of the condition is static unless the file sets it
+to baseline, and enums can be written in lower case:
+
+  type: static
+  policyId: "123456"
+  name: High error rate
+  enabled: true
+  nrql:
+    query: SELECT percentage(count(*), WHERE error IS true) FROM Transaction
+  terms:
+    - priority: critical
+      operator: above
+      threshold: 5
+      thresholdDuration: 300
+      thresholdOccurrences: all
+  signal:
+    aggregationWindow: 60
+  violationTimeLimitSeconds: 86400
+`,
+	Example: "newrelic alerts condition nrql --help",
+}
+
+var cmdConditionNrqlList = &cobra.Command{
+	Use:   "list",
+	Short: "List the NRQL alert conditions of an account",
+	Long: `List the NRQL alert conditions of an account
+
+The list command lists the NRQL conditions of the account, or of the policy
+given with --policyId.  Use --name to list only the conditions whose name
+contains the given name.
+`,
+	Example: `newrelic alerts condition nrql list --policyId 123456
+newrelic alerts condition nrql list --name "error rate"`,
+	PreRun: client.RequireClient,
+	Run: func(cmd *cobra.Command, args []string) {
+		accountID := configAPI.RequireActiveProfileAccountID()
+
+		conditions, err := client.NRClient.Alerts.SearchNrqlConditionsQueryWithContext(utils.SignalCtx, accountID, nrAlerts.NrqlConditionsSearchCriteria{
+			PolicyID: conditionPolicyID,
+			NameLike: conditionName,
+		})
+		utils.LogIfFatal(err)
+
+		utils.LogIfFatal(output.Print(conditions))
+	},
+}
+
+var cmdConditionNrqlGet = &cobra.Command{
+	Use:     "get",
+	Short:   "Get a NRQL alert condition",
+	Long:    "Get a NRQL alert condition",
+	Example: `newrelic alerts condition nrql get --id 654321`,
+	PreRun:  client.RequireClient,
+	Run: func(cmd *cobra.Command, args []string) {
+		accountID := configAPI.RequireActiveProfileAccountID()
+
+		condition, err := client.NRClient.Alerts.GetNrqlConditionQueryWithContext(utils.SignalCtx, accountID, conditionID)
+		utils.LogIfFatal(err)
+
+		utils.LogIfFatal(output.Print(condition))
+	},
+}
+
+var cmdConditionNrqlCreate = &cobra.Command{
+	Use:   "create",
+	Short: "Create a NRQL alert condition from a YAML file",
+	Long: `Create a NRQL alert condition from a YAML file
+
+The create command creates the NRQL condition of the file in the policy given
+with --policyId, or in the policy of the policyId field of the file.
+`,
+	Example: `newrelic alerts condition nrql create --file condition.yml --policyId 123456`,
+	PreRun:  client.RequireClient,
+	Run: func(cmd *cobra.Command, args []string) {
+		accountID := configAPI.RequireActiveProfileAccountID()
+
+		spec, err := readSpec(conditionFile)
+		utils.LogIfFatal(err)
+
+		policyID := conditionPolicyID
+		if policyID == "" {
+			policyID = specPolicyID(spec)
+		}
+		if policyID == "" {
+			log.Fatal("a policy ID is required, use --policyId or set policyId in the condition file")
+		}
+
+		conditionType, err := conditionType(spec)
+		utils.LogIfFatal(err)
+
+		var input nrAlerts.NrqlConditionCreateInput
+		utils.LogIfFatal(decodeSpec(spec, &input))
+
+		var condition *nrAlerts.NrqlAlertCondition
+		if conditionType == "BASELINE" {
+			condition, err = client.NRClient.Alerts.CreateNrqlConditionBaselineMutationWithContext(utils.SignalCtx, accountID, policyID, input)
+		} else {
+			condition, err = client.NRClient.Alerts.CreateNrqlConditionStaticMutationWithContext(utils.SignalCtx, accountID, policyID, input)
+		}
+		utils.LogIfFatal(err)
+
+		utils.LogIfFatal(output.Print(condition))
+	},
+}
+
+var cmdConditionNrqlUpdate = &cobra.Command{
+	Use:   "update",
+	Short: "Update a NRQL alert condition",
+	Long: `Update a NRQL alert condition
+
+The update command merges the fields of the YAML file given with --file onto the
+current definition of the condition, keeping the fields the file does not set.
+Lists, such as terms, are replaced as a whole.
+
+Use --threshold to only change the threshold of the term of the --priority, such
+as to raise a threshold during an incident.
+`,
+	Example: `newrelic alerts condition nrql update --id 654321 --file condition.yml
+newrelic alerts condition nrql update --id 654321 --threshold 10
+newrelic alerts condition nrql update --id 654321 --threshold 5 --priority warning`,
+	PreRun: client.RequireClient,
+	Run: func(cmd *cobra.Command, args []string) {
+		accountID := configAPI.RequireActiveProfileAccountID()
+
+		if conditionFile == "" && !cmd.Flags().Changed("threshold") {
+			utils.LogIfError(cmd.Help())
+			log.Fatal("one of --file or --threshold is required")
+		}
+
+		current, err := client.NRClient.Alerts.GetNrqlConditionQueryWithContext(utils.SignalCtx, accountID, conditionID)
+		utils.LogIfFatal(err)
+
+		spec, err := toSpec(current)
+		utils.LogIfFatal(err)
+
+		if conditionFile != "" {
+			changes, err := readSpec(conditionFile)
+			utils.LogIfFatal(err)
+			mergeSpec(spec, changes)
+		}
+
+		if cmd.Flags().Changed("threshold") {
+			utils.LogIfFatal(setThreshold(spec, strings.ToUpper(conditionPriority), conditionThreshold))
+		}
+
+		conditionType, err := conditionType(spec)
+		utils.LogIfFatal(err)
+
+		var input nrAlerts.NrqlConditionUpdateInput
+		utils.LogIfFatal(decodeSpec(spec, &input))
+
+		var condition *nrAlerts.NrqlAlertCondition
+		if conditionType == "BASELINE" {
+			condition, err = client.NRClient.Alerts.UpdateNrqlConditionBaselineMutationWithContext(utils.SignalCtx, accountID, conditionID, input)
+		} else {
+			condition, err = client.NRClient.Alerts.UpdateNrqlConditionStaticMutationWithContext(utils.SignalCtx, accountID, conditionID, input)
+		}
+		utils.LogIfFatal(err)
+
+		utils.LogIfFatal(output.Print(condition))
+	},
+}
+
+var cmdConditionNrqlDelete = &cobra.Command{
+	Use:     "delete",
+	Short:   "Delete a NRQL alert condition",
+	Long:    "Delete a NRQL alert condition",
+	Example: `newrelic alerts condition nrql delete --id 654321`,
+	PreRun:  client.RequireClient,
+	Run: func(cmd *cobra.Command, args []string) {
+		accountID := configAPI.RequireActiveProfileAccountID()
+
+		id, err := client.NRClient.Alerts.DeleteConditionMutationWithContext(utils.SignalCtx, accountID, conditionID)
+		utils.LogIfFatal(err)
+
+		utils.LogIfFatal(output.Print(map[string]string{"id": id}))
+	},
+}
+
+func init() {
+	Command.AddCommand(cmdCondition)
+	cmdCondition.AddCommand(cmdConditionNrql)
+
+	cmdConditionNrqlList.Flags().StringVar(&conditionPolicyID, "policyId", "", "list only the conditions of the policy")
+	cmdConditionNrqlList.Flags().StringVarP(&conditionName, "name", "n", "", "list only the conditions whose name contains the name")
+	cmdConditionNrql.AddCommand(cmdConditionNrqlList)
+
+	cmdConditionNrqlGet.Flags().StringVarP(&conditionID, "id", "i", "", "the ID of the condition")
+	utils.LogIfError(cmdConditionNrqlGet.MarkFlagRequired("id"))
+	cmdConditionNrql.AddCommand(cmdConditionNrqlGet)
+
+	cmdConditionNrqlCreate.Flags().StringVarP(&conditionFile, "file", "f", "", "a YAML or JSON file defining the condition")
+	cmdConditionNrqlCreate.Flags().StringVar(&conditionPolicyID, "policyId", "", "the ID of the policy of the condition")
+	utils.LogIfError(cmdConditionNrqlCreate.MarkFlagRequired("file"))
+	cmdConditionNrql.AddCommand(cmdConditionNrqlCreate)
+
+	cmdConditionNrqlUpdate.Flags().StringVarP(&conditionID, "id", "i", "", "the ID of the condition")
+	cmdConditionNrqlUpdate.Flags().StringVarP(&conditionFile, "file", "f", "", "a YAML or JSON file with the fields of the condition to change")
+	cmdConditionNrqlUpdate.Flags().Float64Var(&conditionThreshold, "threshold", 0, "the new threshold of the term of the priority")
+	cmdConditionNrqlUpdate.Flags().StringVar(&conditionPriority, "priority", "critical", "the priority of the term whose threshold is changed, critical or warning")
+	utils.LogIfError(cmdConditionNrqlUpdate.MarkFlagRequired("id"))
+	cmdConditionNrql.AddCommand(cmdConditionNrqlUpdate)
+
+	cmdConditionNrqlDelete.Flags().StringVarP(&conditionID, "id", "i", "", "the ID of the condition")
+	utils.LogIfError(cmdConditionNrqlDelete.MarkFlagRequired("id"))
+	cmdConditionNrql.AddCommand(cmdConditionNrqlDelete)
+}
@@ -0,0 +1,161 @@
+package alerts
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/newrelic/newrelic-cli/internal/client"
+	configAPI "github.com/newrelic/newrelic-cli/internal/config/api"
+	"github.com/newrelic/newrelic-cli/internal/output"
+	"github.com/newrelic/newrelic-cli/internal/utils"
+	nrAlerts "github.com/newrelic/newrelic-client-go/v2/pkg/alerts"
+)
+
+var (
+	mutingRuleID   int
+	mutingRuleFile string
+)
+
+var cmdMutingRule = &cobra.Command{
+	Use:   "muting-rule",
+	Short: "Manage New Relic alert muting rules",
+	Long: `Manage New Relic alert muting rules
+
+Muting rules are defined in YAML files, with the fields of the NerdGraph muting
+rule input.  Enums can be written in lower case:
+
+  name: Deployment window
+  enabled: true
+  condition:
+    operator: and
+    conditions:
+      - attribute: conditionName
+        operator: equals
+        values:
+          - High error rate
+  schedule:
+    startTime: "2024-01-01T22:00:00"
+    endTime: "2024-01-01T23:00:00"
+    timeZone: America/Los_Angeles
+`,
+	Example: "newrelic alerts muting-rule --help",
+}
+
+var cmdMutingRuleList = &cobra.Command{
+	Use:     "list",
+	Short:   "List the alert muting rules of an account",
+	Long:    "List the alert muting rules of an account",
+	Example: `newrelic alerts muting-rule list --accountId 12345`,
+	PreRun:  client.RequireClient,
+	Run: func(cmd *cobra.Command, args []string) {
+		accountID := configAPI.RequireActiveProfileAccountID()
+
+		rules, err := client.NRClient.Alerts.ListMutingRulesWithContext(utils.SignalCtx, accountID)
+		utils.LogIfFatal(err)
+
+		utils.LogIfFatal(output.Print(rules))
+	},
+}
+
+var cmdMutingRuleGet = &cobra.Command{
+	Use:     "get",
+	Short:   "Get an alert muting rule",
+	Long:    "Get an alert muting rule",
+	Example: `newrelic alerts muting-rule get --id 42`,
+	PreRun:  client.RequireClient,
+	Run: func(cmd *cobra.Command, args []string) {
+		accountID := configAPI.RequireActiveProfileAccountID()
+
+		rule, err := client.NRClient.Alerts.GetMutingRuleWithContext(utils.SignalCtx, accountID, mutingRuleID)
+		utils.LogIfFatal(err)
+
+		utils.LogIfFatal(output.Print(rule))
+	},
+}
+
+var cmdMutingRuleCreate = &cobra.Command{
+	Use:     "create",
+	Short:   "Create an alert muting rule from a YAML file",
+	Long:    "Create an alert muting rule from a YAML file",
+	Example: `newrelic alerts muting-rule create --file muting-rule.yml`,
+	PreRun:  client.RequireClient,
+	Run: func(cmd *cobra.Command, args []string) {
+		accountID := configAPI.RequireActiveProfileAccountID()
+
+		spec, err := readSpec(mutingRuleFile)
+		utils.LogIfFatal(err)
+
+		var input nrAlerts.MutingRuleCreateInput
+		utils.LogIfFatal(decodeSpec(spec, &input))
+
+		rule, err := client.NRClient.Alerts.CreateMutingRuleWithContext(utils.SignalCtx, accountID, input)
+		utils.LogIfFatal(err)
+
+		utils.LogIfFatal(output.Print(rule))
+	},
+}
+
+var cmdMutingRuleUpdate = &cobra.Command{
+	Use:   "update",
+	Short: "Update an alert muting rule from a YAML file",
+	Long: `Update an alert muting rule from a YAML file
+
+The update command replaces the definition of the muting rule with the one of
+the file.
+`,
+	Example: `newrelic alerts muting-rule update --id 42 --file muting-rule.yml`,
+	PreRun:  client.RequireClient,
+	Run: func(cmd *cobra.Command, args []string) {
+		accountID := configAPI.RequireActiveProfileAccountID()
+
+		spec, err := readSpec(mutingRuleFile)
+		utils.LogIfFatal(err)
+
+		var input nrAlerts.MutingRuleUpdateInput
+		utils.LogIfFatal(decodeSpec(spec, &input))
+
+		rule, err := client.NRClient.Alerts.UpdateMutingRuleWithContext(utils.SignalCtx, accountID, mutingRuleID, input)
+		utils.LogIfFatal(err)
+
+		utils.LogIfFatal(output.Print(rule))
+	},
+}
+
+var cmdMutingRuleDelete = &cobra.Command{
+	Use:     "delete",
+	Short:   "Delete an alert muting rule",
+	Long:    "Delete an alert muting rule",
+	Example: `newrelic alerts muting-rule delete --id 42`,
+	PreRun:  client.RequireClient,
+	Run: func(cmd *cobra.Command, args []string) {
+		accountID := configAPI.RequireActiveProfileAccountID()
+
+		err := client.NRClient.Alerts.DeleteMutingRuleWithContext(utils.SignalCtx, accountID, mutingRuleID)
+		utils.LogIfFatal(err)
+
+		utils.LogIfFatal(output.Print(map[string]int{"id": mutingRuleID}))
+	},
+}
+
+func init() {
+	Command.AddCommand(cmdMutingRule)
+
+	cmdMutingRule.AddCommand(cmdMutingRuleList)
+
+	cmdMutingRuleGet.Flags().IntVarP(&mutingRuleID, "id", "i", 0, "the ID of the muting rule")
+	utils.LogIfError(cmdMutingRuleGet.MarkFlagRequired("id"))
+	cmdMutingRule.AddCommand(cmdMutingRuleGet)
+
+	cmdMutingRuleCreate.Flags().StringVarP(&mutingRuleFile, "file", "f", "", "a YAML or JSON file defining the muting rule")
+	utils.LogIfError(cmdMutingRuleCreate.MarkFlagRequired("file"))
+	cmdMutingRule.AddCommand(cmdMutingRuleCreate)
+
+	cmdMutingRuleUpdate.Flags().IntVarP(&mutingRuleID, "id", "i", 0, "the ID of the muting rule")
+	cmdMutingRuleUpdate.Flags().StringVarP(&mutingRuleFile, "file", "f", "", "a YAML or JSON file defining the muting rule")
+	utils.LogIfError(cmdMutingRuleUpdate.MarkFlagRequired("id"))
+	utils.LogIfError(cmdMutingRuleUpdate.MarkFlagRequired("file"))
+	cmdMutingRule.AddCommand(cmdMutingRuleUpdate)
+
+	cmdMutingRuleDelete.Flags().IntVarP(&mutingRuleID, "id", "i", 0, "the ID of the muting rule")
+	utils.LogIfError(cmdMutingRuleDelete.MarkFlagRequired("id"))
+	cmdMutingRule.AddCommand(cmdMutingRuleDelete)
+}
@@ -0,0 +1,199 @@
+package alerts
+
+import (
+	"fmt"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+
+	"github.com/newrelic/newrelic-cli/internal/client"
+	configAPI "github.com/newrelic/newrelic-cli/internal/config/api"
+	"github.com/newrelic/newrelic-cli/internal/output"
+	"github.com/newrelic/newrelic-cli/internal/utils"
+	nrAlerts "github.com/newrelic/newrelic-client-go/v2/pkg/alerts"
+)
+
+var (
+	policyID                 string
+	policyName               string
+	policyIncidentPreference string
+)
+
+// incidentPreferences are the ways incidents of a policy are grouped.
+var incidentPreferences = []string{"PER_POLICY", "PER_CONDITION", "PER_CONDITION_AND_TARGET"}
+
+var cmdPolicy = &cobra.Command{
+	Use:     "policy",
+	Short:   "Manage New Relic alert policies",
+	Long:    "Manage New Relic alert policies",
+	Example: "newrelic alerts policy --help",
+}
+
+var cmdPolicyList = &cobra.Command{
+	Use:   "list",
+	Short: "List the alert policies of an account",
+	Long: `List the alert policies of an account
+
+The list command lists the alert policies of the account.  Use --name to list
+only the policies whose name contains the given name.
+`,
+	Example: `newrelic alerts policy list --accountId 12345
+newrelic alerts policy list --name "Checkout"`,
+	PreRun: client.RequireClient,
+	Run: func(cmd *cobra.Command, args []string) {
+		accountID := configAPI.RequireActiveProfileAccountID()
+
+		policies, err := client.NRClient.Alerts.QueryPolicySearchWithContext(utils.SignalCtx, accountID, nrAlerts.AlertsPoliciesSearchCriteriaInput{})
+		utils.LogIfFatal(err)
+
+		results := []*nrAlerts.AlertsPolicy{}
+		for _, p := range policies {
+			if containsFold(p.Name, policyName) {
+				results = append(results, p)
+			}
+		}
+
+		utils.LogIfFatal(output.Print(results))
+	},
+}
+
+var cmdPolicyGet = &cobra.Command{
+	Use:     "get",
+	Short:   "Get an alert policy",
+	Long:    "Get an alert policy",
+	Example: `newrelic alerts policy get --id 123456`,
+	PreRun:  client.RequireClient,
+	Run: func(cmd *cobra.Command, args []string) {
+		accountID := configAPI.RequireActiveProfileAccountID()
+
+		policy, err := client.NRClient.Alerts.QueryPolicyWithContext(utils.SignalCtx, accountID, policyID)
+		utils.LogIfFatal(err)
+
+		utils.LogIfFatal(output.Print(policy))
+	},
+}
+
+var cmdPolicyCreate = &cobra.Command{
+	Use:   "create",
+	Short: "Create an alert policy",
+	Long: `Create an alert policy
+
+The create command creates an alert policy, whose incidents are grouped by
+policy unless --incidentPreference is given.
+`,
+	Example: `newrelic alerts policy create --name "Checkout" --incidentPreference PER_CONDITION`,
+	PreRun:  client.RequireClient,
+	Run: func(cmd *cobra.Command, args []string) {
+		accountID := configAPI.RequireActiveProfileAccountID()
+
+		preference, err := parseIncidentPreference(policyIncidentPreference)
+		utils.LogIfFatal(err)
+
+		policy, err := client.NRClient.Alerts.CreatePolicyMutationWithContext(utils.SignalCtx, accountID, nrAlerts.AlertsPolicyInput{
+			Name:               policyName,
+			IncidentPreference: nrAlerts.AlertsIncidentPreference(preference),
+		})
+		utils.LogIfFatal(err)
+
+		utils.LogIfFatal(output.Print(policy))
+	},
+}
+
+var cmdPolicyUpdate = &cobra.Command{
+	Use:   "update",
+	Short: "Update an alert policy",
+	Long: `Update an alert policy
+
+The update command updates the name or the incident preference of an alert
+policy, keeping the settings not given.
+`,
+	Example: `newrelic alerts policy update --id 123456 --name "Checkout (prod)"`,
+	PreRun:  client.RequireClient,
+	Run: func(cmd *cobra.Command, args []string) {
+		accountID := configAPI.RequireActiveProfileAccountID()
+
+		if !cmd.Flags().Changed("name") && !cmd.Flags().Changed("incidentPreference") {
+			utils.LogIfError(cmd.Help())
+			log.Fatal("one of --name or --incidentPreference is required")
+		}
+
+		current, err := client.NRClient.Alerts.QueryPolicyWithContext(utils.SignalCtx, accountID, policyID)
+		utils.LogIfFatal(err)
+
+		input := nrAlerts.AlertsPolicyUpdateInput{
+			Name:               current.Name,
+			IncidentPreference: current.IncidentPreference,
+		}
+		if cmd.Flags().Changed("name") {
+			input.Name = policyName
+		}
+		if cmd.Flags().Changed("incidentPreference") {
+			preference, err := parseIncidentPreference(policyIncidentPreference)
+			utils.LogIfFatal(err)
+			input.IncidentPreference = nrAlerts.AlertsIncidentPreference(preference)
+		}
+
+		policy, err := client.NRClient.Alerts.UpdatePolicyMutationWithContext(utils.SignalCtx, accountID, policyID, input)
+		utils.LogIfFatal(err)
+
+		utils.LogIfFatal(output.Print(policy))
+	},
+}
+
+var cmdPolicyDelete = &cobra.Command{
+	Use:   "delete",
+	Short: "Delete an alert policy",
+	Long: `Delete an alert policy
+
+The delete command deletes an alert policy, along with its conditions.
+`,
+	Example: `newrelic alerts policy delete --id 123456`,
+	PreRun:  client.RequireClient,
+	Run: func(cmd *cobra.Command, args []string) {
+		accountID := configAPI.RequireActiveProfileAccountID()
+
+		policy, err := client.NRClient.Alerts.DeletePolicyMutationWithContext(utils.SignalCtx, accountID, policyID)
+		utils.LogIfFatal(err)
+
+		utils.LogIfFatal(output.Print(policy))
+	},
+}
+
+// parseIncidentPreference returns the incident preference enum of the value.
+func parseIncidentPreference(value string) (string, error) {
+	preference := strings.ToUpper(strings.TrimSpace(value))
+	for _, p := range incidentPreferences {
+		if p == preference {
+			return preference, nil
+		}
+	}
+
+	return "", fmt.Errorf("invalid incident preference %s, must be one of %s", value, strings.Join(incidentPreferences, ", "))
+}
+
+func init() {
+	Command.AddCommand(cmdPolicy)
+
+	cmdPolicyList.Flags().StringVarP(&policyName, "name", "n", "", "list only the policies whose name contains the name")
+	cmdPolicy.AddCommand(cmdPolicyList)
+
+	cmdPolicyGet.Flags().StringVarP(&policyID, "id", "i", "", "the ID of the policy")
+	utils.LogIfError(cmdPolicyGet.MarkFlagRequired("id"))
+	cmdPolicy.AddCommand(cmdPolicyGet)
+
+	cmdPolicyCreate.Flags().StringVarP(&policyName, "name", "n", "", "the name of the policy")
+	cmdPolicyCreate.Flags().StringVar(&policyIncidentPreference, "incidentPreference", "PER_POLICY", "how incidents are grouped, one of "+strings.Join(incidentPreferences, ", "))
+	utils.LogIfError(cmdPolicyCreate.MarkFlagRequired("name"))
+	cmdPolicy.AddCommand(cmdPolicyCreate)
+
+	cmdPolicyUpdate.Flags().StringVarP(&policyID, "id", "i", "", "the ID of the policy")
+	cmdPolicyUpdate.Flags().StringVarP(&policyName, "name", "n", "", "the new name of the policy")
+	cmdPolicyUpdate.Flags().StringVar(&policyIncidentPreference, "incidentPreference", "", "how incidents are grouped, one of "+strings.Join(incidentPreferences, ", "))
+	utils.LogIfError(cmdPolicyUpdate.MarkFlagRequired("id"))
+	cmdPolicy.AddCommand(cmdPolicyUpdate)
+
+	cmdPolicyDelete.Flags().StringVarP(&policyID, "id", "i", "", "the ID of the policy")
+	utils.LogIfError(cmdPolicyDelete.MarkFlagRequired("id"))
+	cmdPolicy.AddCommand(cmdPolicyDelete)
+}
@@ -0,0 +1,61 @@
+//go:build unit
+
+package alerts
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/newrelic/newrelic-cli/internal/testcobra"
+)
+
+func TestAlertsCommand(t *testing.T) {
+	assert.Equal(t, "alerts", Command.Name())
+
+	testcobra.CheckCobraMetadata(t, Command)
+	testcobra.CheckCobraRequiredFlags(t, Command, []string{})
+}
+
+func TestAlertsSubcommands(t *testing.T) {
+	for _, cmd := range []struct {
+		path     []string
+		required []string
+	}{
+		{[]string{"policy"}, []string{}},
+		{[]string{"policy", "list"}, []string{}},
+		{[]string{"policy", "get"}, []string{"id"}},
+		{[]string{"policy", "create"}, []string{"name"}},
+		{[]string{"policy", "update"}, []string{"id"}},
+		{[]string{"policy", "delete"}, []string{"id"}},
+		{[]string{"condition"}, []string{}},
+		{[]string{"condition", "nrql"}, []string{}},
+		{[]string{"condition", "nrql", "list"}, []string{}},
+		{[]string{"condition", "nrql", "get"}, []string{"id"}},
+		{[]string{"condition", "nrql", "create"}, []string{"file"}},
+		{[]string{"condition", "nrql", "update"}, []string{"id"}},
+		{[]string{"condition", "nrql", "delete"}, []string{"id"}},
+		{[]string{"muting-rule"}, []string{}},
+		{[]string{"muting-rule", "list"}, []string{}},
+		{[]string{"muting-rule", "get"}, []string{"id"}},
+		{[]string{"muting-rule", "create"}, []string{"file"}},
+		{[]string{"muting-rule", "update"}, []string{"id", "file"}},
+		{[]string{"muting-rule", "delete"}, []string{"id"}},
+	} {
+		c, _, err := Command.Find(cmd.path)
+		assert.NoError(t, err)
+		assert.Equal(t, cmd.path[len(cmd.path)-1], c.Name())
+
+		testcobra.CheckCobraMetadata(t, c)
+		testcobra.CheckCobraRequiredFlags(t, c, cmd.required)
+	}
+}
+
+func TestParseIncidentPreference(t *testing.T) {
+	p, err := parseIncidentPreference(" per_condition ")
+	assert.NoError(t, err)
+	assert.Equal(t, "PER_CONDITION", p)
+
+	_, err = parseIncidentPreference("PER_ACCOUNT")
+	assert.Error(t, err)
+}
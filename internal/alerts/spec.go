@@ -0,0 +1,172 @@
+package alerts
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/ghodss/yaml"
+)
+
+// Conditions and muting rules are defined in YAML files with the fields of
+// their NerdGraph input, such as:
+//
+//	name: High error rate
+//	enabled: true
+//	nrql:
+//	  query: SELECT percentage(count(*), WHERE error IS true) FROM Transaction
+//	terms:
+//	  - priority: critical
+//	    operator: above
+//	    threshold: 5
+//	    thresholdDuration: 300
+//	    thresholdOccurrences: all
+//
+// Specs are kept as maps, so that they can be merged onto the current
+// definition of a condition, and are decoded into the client input types
+// through their JSON.
+
+// enumKeys are the keys whose values are NerdGraph enums, which can be
+// written in lower case in specs.
+var enumKeys = map[string]bool{
+	"aggregationMethod":    true,
+	"baselineDirection":    true,
+	"fillOption":           true,
+	"incidentPreference":   true,
+	"operator":             true,
+	"priority":             true,
+	"repeat":               true,
+	"thresholdOccurrences": true,
+	"type":                 true,
+}
+
+// readSpec reads a YAML or JSON spec file.
+func readSpec(path string) (map[string]interface{}, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	j, err := yaml.YAMLToJSON(data)
+	if err != nil {
+		return nil, fmt.Errorf("could not parse %s: %s", path, err)
+	}
+
+	var spec map[string]interface{}
+	if err := json.Unmarshal(j, &spec); err != nil || spec == nil {
+		return nil, fmt.Errorf("could not parse %s: a YAML or JSON object is required", path)
+	}
+
+	normalizeEnums(spec)
+
+	return spec, nil
+}
+
+// normalizeEnums upper cases the enum values of the spec.
+func normalizeEnums(v interface{}) {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for key, value := range v {
+			if s, ok := value.(string); ok && enumKeys[key] {
+				v[key] = strings.ToUpper(s)
+				continue
+			}
+			normalizeEnums(value)
+		}
+	case []interface{}:
+		for _, value := range v {
+			normalizeEnums(value)
+		}
+	}
+}
+
+// mergeSpec merges src onto dst.  Objects are merged, other values of src,
+// lists included, replace the values of dst.
+func mergeSpec(dst map[string]interface{}, src map[string]interface{}) {
+	for key, value := range src {
+		srcMap, srcIsMap := value.(map[string]interface{})
+		dstMap, dstIsMap := dst[key].(map[string]interface{})
+		if srcIsMap && dstIsMap {
+			mergeSpec(dstMap, srcMap)
+			continue
+		}
+		dst[key] = value
+	}
+}
+
+// toSpec returns the spec of a value, such as a condition returned by
+// NerdGraph.
+func toSpec(v interface{}) (map[string]interface{}, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	spec := map[string]interface{}{}
+	if err := json.Unmarshal(b, &spec); err != nil {
+		return nil, err
+	}
+
+	return spec, nil
+}
+
+// decodeSpec decodes the spec into out, a client input type.  Fields of the
+// spec unknown to the input are ignored.
+func decodeSpec(spec map[string]interface{}, out interface{}) error {
+	b, err := json.Marshal(spec)
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(b, out)
+}
+
+// conditionType returns the type of the NRQL condition of the spec, STATIC
+// when the spec has no type.
+func conditionType(spec map[string]interface{}) (string, error) {
+	t, _ := spec["type"].(string)
+	switch strings.ToUpper(t) {
+	case "", "STATIC":
+		return "STATIC", nil
+	case "BASELINE":
+		return "BASELINE", nil
+	default:
+		return "", fmt.Errorf("unsupported condition type %s, must be static or baseline", t)
+	}
+}
+
+// specPolicyID returns the policy ID of the spec, if any.
+func specPolicyID(spec map[string]interface{}) string {
+	switch id := spec["policyId"].(type) {
+	case string:
+		return id
+	case float64:
+		return fmt.Sprintf("%.0f", id)
+	}
+
+	return ""
+}
+
+// setThreshold sets the threshold of the term of the priority.
+func setThreshold(spec map[string]interface{}, priority string, threshold float64) error {
+	terms, _ := spec["terms"].([]interface{})
+	for _, t := range terms {
+		term, ok := t.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		if p, _ := term["priority"].(string); strings.EqualFold(p, priority) {
+			term["threshold"] = threshold
+			return nil
+		}
+	}
+
+	return fmt.Errorf("the condition has no %s term", strings.ToLower(priority))
+}
+
+// containsFold returns whether s contains substr, ignoring case.
+func containsFold(s string, substr string) bool {
+	return strings.Contains(strings.ToLower(s), strings.ToLower(substr))
+}
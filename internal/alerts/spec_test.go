@@ -0,0 +1,150 @@
+//go:build unit
+
+package alerts
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const conditionYAML = `
+type: baseline
+policyId: 123456
+name: High error rate
+nrql:
+  query: SELECT count(*) FROM TransactionError
+terms:
+  - priority: critical
+    operator: above
+    threshold: 5
+    thresholdOccurrences: all
+  - priority: warning
+    operator: above
+    threshold: 3
+    thresholdOccurrences: at_least_once
+baselineDirection: upper_only
+`
+
+func writeSpecFile(t *testing.T, content string) string {
+	path := filepath.Join(t.TempDir(), "spec.yml")
+	require.NoError(t, os.WriteFile(path, []byte(content), 0600))
+
+	return path
+}
+
+func TestReadSpec(t *testing.T) {
+	spec, err := readSpec(writeSpecFile(t, conditionYAML))
+	require.NoError(t, err)
+
+	assert.Equal(t, "BASELINE", spec["type"])
+	assert.Equal(t, "UPPER_ONLY", spec["baselineDirection"])
+	assert.Equal(t, "High error rate", spec["name"])
+
+	terms := spec["terms"].([]interface{})
+	require.Len(t, terms, 2)
+	assert.Equal(t, "CRITICAL", terms[0].(map[string]interface{})["priority"])
+	assert.Equal(t, "AT_LEAST_ONCE", terms[1].(map[string]interface{})["thresholdOccurrences"])
+
+	assert.Equal(t, "123456", specPolicyID(spec))
+
+	conditionType, err := conditionType(spec)
+	assert.NoError(t, err)
+	assert.Equal(t, "BASELINE", conditionType)
+}
+
+func TestReadSpecInvalid(t *testing.T) {
+	_, err := readSpec(writeSpecFile(t, "- a\n- b\n"))
+	assert.Error(t, err)
+
+	_, err = readSpec(writeSpecFile(t, "name: [a\n"))
+	assert.Error(t, err)
+
+	_, err = readSpec(filepath.Join(t.TempDir(), "missing.yml"))
+	assert.Error(t, err)
+}
+
+func TestMergeSpec(t *testing.T) {
+	dst := map[string]interface{}{
+		"name":    "High error rate",
+		"enabled": true,
+		"nrql":    map[string]interface{}{"query": "SELECT count(*) FROM TransactionError", "evaluationOffset": 3},
+		"terms":   []interface{}{map[string]interface{}{"priority": "CRITICAL"}, map[string]interface{}{"priority": "WARNING"}},
+	}
+
+	mergeSpec(dst, map[string]interface{}{
+		"enabled": false,
+		"nrql":    map[string]interface{}{"query": "SELECT count(*) FROM Transaction"},
+		"terms":   []interface{}{map[string]interface{}{"priority": "CRITICAL"}},
+	})
+
+	assert.Equal(t, map[string]interface{}{
+		"name":    "High error rate",
+		"enabled": false,
+		"nrql":    map[string]interface{}{"query": "SELECT count(*) FROM Transaction", "evaluationOffset": 3},
+		"terms":   []interface{}{map[string]interface{}{"priority": "CRITICAL"}},
+	}, dst)
+}
+
+func TestSetThreshold(t *testing.T) {
+	spec, err := readSpec(writeSpecFile(t, conditionYAML))
+	require.NoError(t, err)
+
+	require.NoError(t, setThreshold(spec, "WARNING", 4.5))
+
+	terms := spec["terms"].([]interface{})
+	assert.Equal(t, float64(5), terms[0].(map[string]interface{})["threshold"])
+	assert.Equal(t, 4.5, terms[1].(map[string]interface{})["threshold"])
+
+	assert.Error(t, setThreshold(map[string]interface{}{}, "CRITICAL", 1))
+}
+
+func TestConditionType(t *testing.T) {
+	for _, tc := range []struct {
+		value    interface{}
+		expected string
+	}{
+		{nil, "STATIC"},
+		{"static", "STATIC"},
+		{"BASELINE", "BASELINE"},
+	} {
+		conditionType, err := conditionType(map[string]interface{}{"type": tc.value})
+		assert.NoError(t, err)
+		assert.Equal(t, tc.expected, conditionType)
+	}
+
+	_, err := conditionType(map[string]interface{}{"type": "outlier"})
+	assert.Error(t, err)
+}
+
+func TestDecodeSpec(t *testing.T) {
+	var input struct {
+		Name  string `json:"name"`
+		Terms []struct {
+			Priority  string  `json:"priority"`
+			Threshold float64 `json:"threshold"`
+		} `json:"terms"`
+	}
+
+	spec, err := readSpec(writeSpecFile(t, conditionYAML))
+	require.NoError(t, err)
+	require.NoError(t, decodeSpec(spec, &input))
+
+	assert.Equal(t, "High error rate", input.Name)
+	require.Len(t, input.Terms, 2)
+	assert.Equal(t, "CRITICAL", input.Terms[0].Priority)
+	assert.Equal(t, float64(5), input.Terms[0].Threshold)
+}
+
+func TestToSpec(t *testing.T) {
+	spec, err := toSpec(struct {
+		ID   string `json:"id"`
+		Name string `json:"name"`
+	}{ID: "1", Name: "High error rate"})
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]interface{}{"id": "1", "name": "High error rate"}, spec)
+}
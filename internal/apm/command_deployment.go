@@ -1,6 +1,8 @@
 package apm
 
 import (
+	"strings"
+
 	log "github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 
@@ -9,10 +11,14 @@ import (
 	"github.com/newrelic/newrelic-cli/internal/client"
 	"github.com/newrelic/newrelic-cli/internal/output"
 	"github.com/newrelic/newrelic-cli/internal/utils"
+	"github.com/newrelic/newrelic-cli/internal/utils/git"
 )
 
 var (
-	deployment apm.Deployment
+	deployment         apm.Deployment
+	deploymentFromGit  bool
+	deploymentGitDir   string
+	deploymentGitSince string
 )
 
 var cmdDeployment = &cobra.Command{
@@ -57,9 +63,22 @@ var cmdDeploymentCreate = &cobra.Command{
 
 The create command creates a new deployment marker for a New Relic APM
 application.
+
+With --from-git the revision, change log, description, and user are read from
+the local git repository when they are not provided.  The change log lists the
+commits since the previous deployment marker, or since the previous tag when the
+previous marker's revision is not a commit in the repository, followed by a link
+to the changes on the repository host.
 `,
-	Example: "newrelic apm deployment create --applicationId <appID> --revision <deploymentRevision>",
-	PreRun:  client.RequireClient,
+	Example: `newrelic apm deployment create --applicationId <appID> --revision <deploymentRevision>
+newrelic apm deployment create --applicationId <appID> --from-git`,
+	PreRun: func(cmd *cobra.Command, args []string) {
+		client.RequireClient(cmd, args)
+
+		if deploymentFromGit {
+			utils.LogIfFatal(applyDeploymentGitMetadata(cmd))
+		}
+	},
 	Run: func(cmd *cobra.Command, args []string) {
 		if apmAppID == 0 {
 			utils.LogIfError(cmd.Help())
@@ -108,7 +127,68 @@ func init() {
 	cmdDeploymentCreate.Flags().StringVarP(&deployment.Revision, "revision", "r", "", "a freeform string representing the revision of the deployment")
 	utils.LogIfError(cmdDeploymentCreate.MarkFlagRequired("revision"))
 
+	cmdDeploymentCreate.Flags().BoolVar(&deploymentFromGit, "from-git", false, "fill in the revision, change log, description, and user from the local git repository")
+	cmdDeploymentCreate.Flags().StringVar(&deploymentGitDir, "git-dir", ".", "path to the git repository used with --from-git")
+	cmdDeploymentCreate.Flags().StringVar(&deploymentGitSince, "git-since", "", "git ref of the previous deployment used to build the change log, defaults to the revision of the previous deployment marker")
+
 	cmdDeployment.AddCommand(cmdDeploymentDelete)
 	cmdDeploymentDelete.Flags().IntVarP(&deployment.ID, "deploymentID", "d", 0, "the ID of the deployment to be deleted")
 	utils.LogIfError(cmdDeploymentDelete.MarkFlagRequired("deploymentID"))
 }
+
+// applyDeploymentGitMetadata fills in any deployment flags that were not provided
+// using metadata from the local git repository.  Flags are set through the flag
+// set so that required flag validation treats them as provided.
+func applyDeploymentGitMetadata(cmd *cobra.Command) error {
+	since := deploymentGitSince
+	if since == "" && apmAppID != 0 {
+		since = previousDeploymentRevision()
+	}
+
+	info, err := git.Read(deploymentGitDir, since)
+	if err != nil {
+		return err
+	}
+
+	changelog := info.Changelog()
+	if link := info.DeepLink(); link != "" {
+		changelog = strings.TrimSpace(changelog + "\n\n" + link)
+	}
+
+	values := map[string]string{
+		"revision":    info.Commit,
+		"change-log":  changelog,
+		"description": info.Subject,
+		"user":        info.Author,
+	}
+
+	for flag, value := range values {
+		if value == "" || cmd.Flags().Changed(flag) {
+			continue
+		}
+
+		if err := cmd.Flags().Set(flag, value); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// previousDeploymentRevision returns the revision of the most recent deployment
+// marker for the application if it refers to a commit in the local repository.
+func previousDeploymentRevision() string {
+	return git.PreviousMarkerRevision(deploymentGitDir, func() ([]string, error) {
+		deployments, err := client.NRClient.APM.ListDeploymentsWithContext(utils.SignalCtx, apmAppID)
+		if err != nil {
+			return nil, err
+		}
+
+		revisions := []string{}
+		for _, d := range deployments {
+			revisions = append(revisions, d.Revision)
+		}
+
+		return revisions, nil
+	})
+}
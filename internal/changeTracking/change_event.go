@@ -0,0 +1,284 @@
+package changeTracking
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ghodss/yaml"
+
+	"github.com/newrelic/newrelic-client-go/v2/pkg/changetracking"
+	"github.com/newrelic/newrelic-client-go/v2/pkg/nrtime"
+)
+
+// maxTimestampSkew is how far in the past or future an event timestamp may be.
+const maxTimestampSkew = 24 * time.Hour
+
+var customAttributeKeyRe = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+
+// changeEventSpec describes a single change tracking event.  It is populated
+// either from the create command's flags or from an entry in a --file.
+type changeEventSpec struct {
+	EntitySearch     string   `json:"entitySearch"`
+	Category         string   `json:"category"`
+	Type             string   `json:"type"`
+	Description      string   `json:"description,omitempty"`
+	User             string   `json:"user,omitempty"`
+	GroupID          string   `json:"groupId,omitempty"`
+	ShortDescription string   `json:"shortDescription,omitempty"`
+	Timestamp        int64    `json:"timestamp,omitempty"`
+	ValidationFlags  []string `json:"validationFlags,omitempty"`
+
+	// CustomAttributes is either a JS object string, as accepted by
+	// --customAttributes, or a map of attribute names to scalar values.
+	CustomAttributes interface{} `json:"customAttributes,omitempty"`
+
+	// Deployment fields
+	Version   string `json:"version,omitempty"`
+	Changelog string `json:"changelog,omitempty"`
+	Commit    string `json:"commit,omitempty"`
+	DeepLink  string `json:"deepLink,omitempty"`
+
+	// Feature flag fields
+	FeatureFlagID string `json:"featureFlagId,omitempty"`
+}
+
+// changeEventsFile is the document format accepted by create --file.  A file may
+// also contain a bare list of events.
+type changeEventsFile struct {
+	Events []changeEventSpec `json:"events"`
+}
+
+// readChangeEventsFile reads a YAML or JSON file of change events.
+func readChangeEventsFile(path string) ([]changeEventSpec, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	j, err := yaml.YAMLToJSON(data)
+	if err != nil {
+		return nil, fmt.Errorf("could not parse %s: %s", path, err)
+	}
+
+	var events []changeEventSpec
+	if strings.HasPrefix(strings.TrimSpace(string(j)), "[") {
+		err = json.Unmarshal(j, &events)
+	} else {
+		var f changeEventsFile
+		err = json.Unmarshal(j, &f)
+		events = f.Events
+	}
+	if err != nil {
+		return nil, fmt.Errorf("could not parse %s: %s", path, err)
+	}
+
+	if len(events) == 0 {
+		return nil, fmt.Errorf("%s does not contain any events", path)
+	}
+
+	return events, nil
+}
+
+// build validates the event and converts it into the input for the
+// changeTrackingCreateEvent mutation.
+func (s changeEventSpec) build(now time.Time) (changetracking.ChangeTrackingCreateEventInput, changetracking.ChangeTrackingDataHandlingRules, error) {
+	params := changetracking.ChangeTrackingCreateEventInput{}
+	rules := changetracking.ChangeTrackingDataHandlingRules{}
+
+	if s.EntitySearch == "" {
+		return params, rules, fmt.Errorf("entitySearch cannot be empty")
+	}
+	if s.Category == "" {
+		return params, rules, fmt.Errorf("category cannot be empty")
+	}
+	if s.Type == "" {
+		return params, rules, fmt.Errorf("type cannot be empty")
+	}
+
+	if s.Timestamp == 0 {
+		params.Timestamp = nrtime.EpochMilliseconds(now)
+	} else {
+		ts := time.UnixMilli(s.Timestamp)
+		if math.Abs(float64(now.Sub(ts))) > float64(maxTimestampSkew) {
+			return params, rules, fmt.Errorf("timestamp can not be more than 24 hours in the past or future")
+		}
+		params.Timestamp = nrtime.EpochMilliseconds(ts)
+	}
+
+	flags, err := parseValidationFlags(s.ValidationFlags)
+	if err != nil {
+		return params, rules, err
+	}
+	rules.ValidationFlags = flags
+
+	if err := s.validateCategoryFields(); err != nil {
+		return params, rules, err
+	}
+
+	params.Description = s.Description
+	params.User = s.User
+	params.GroupId = s.GroupID
+	params.ShortDescription = s.ShortDescription
+	params.EntitySearch = changetracking.ChangeTrackingEntitySearchInput{
+		Query: s.EntitySearch,
+	}
+	params.CategoryAndTypeData = &changetracking.ChangeTrackingCategoryRelatedInput{
+		Kind: &changetracking.ChangeTrackingCategoryAndTypeInput{
+			Category: s.Category,
+			Type:     s.Type,
+		},
+		CategoryFields: &changetracking.ChangeTrackingCategoryFieldsInput{},
+	}
+	s.setCategoryFields(&params)
+
+	customAttrRaw, err := s.customAttributesJS()
+	if err != nil {
+		return params, rules, err
+	}
+
+	if customAttrRaw != "" {
+		// Validate JS object format: keys must be valid JS identifiers, values must be string, bool, or number
+		// Accepts: {foo: "bar", num: 2, flag: true}
+		// This is a basic validation, not a full JS parser
+		jsObj := strings.TrimSpace(customAttrRaw)
+		if !strings.HasPrefix(jsObj, "{") || !strings.HasSuffix(jsObj, "}") {
+			return params, rules, fmt.Errorf("customAttributes must be a JS object, e.g. {foo: \"bar\", num: 2, flag: true}")
+		}
+		// Validate keys and values (simple regex)
+		kvRe := regexp.MustCompile(`([a-zA-Z_][a-zA-Z0-9_]*)\s*:\s*([^\"]+|\"[^\"]*\"|true|false|[0-9.]+)`) // key: value
+		matches := kvRe.FindAllStringSubmatch(jsObj, -1)
+		if len(matches) == 0 {
+			return params, rules, fmt.Errorf("customAttributes must contain at least one valid key: value pair")
+		}
+		// Convert JS object string to map[string]interface{} for API
+		attrs, err := changetracking.ReadCustomAttributesJS(customAttrRaw, false)
+		if err != nil {
+			return params, rules, fmt.Errorf("failed to parse customAttributes as JS object: %v", err)
+		}
+		params.CustomAttributes = attrs
+	}
+
+	return params, rules, nil
+}
+
+func (s changeEventSpec) validateCategoryFields() error {
+	cat := strings.ToUpper(s.Category)
+	if cat != "DEPLOYMENT" {
+		var invalidFields []string
+		if s.Version != "" {
+			invalidFields = append(invalidFields, "version")
+		}
+		if s.Changelog != "" {
+			invalidFields = append(invalidFields, "changelog")
+		}
+		if s.Commit != "" {
+			invalidFields = append(invalidFields, "commit")
+		}
+		if s.DeepLink != "" {
+			invalidFields = append(invalidFields, "deepLink")
+		}
+		if len(invalidFields) > 0 {
+			return fmt.Errorf("%s can only be used with DEPLOYMENT events", strings.Join(invalidFields, ", "))
+		}
+	}
+	if cat != "FEATURE FLAG" && s.FeatureFlagID != "" {
+		return fmt.Errorf("featureFlagId is only valid for FEATURE FLAG events")
+	}
+	if cat == "DEPLOYMENT" && s.Version == "" {
+		return fmt.Errorf("version is required for DEPLOYMENT events")
+	}
+	if cat == "FEATURE FLAG" && s.FeatureFlagID == "" {
+		return fmt.Errorf("featureFlagId is required for FEATURE FLAG events")
+	}
+
+	return nil
+}
+
+func (s changeEventSpec) setCategoryFields(params *changetracking.ChangeTrackingCreateEventInput) {
+	cat := strings.ToUpper(s.Category)
+	if cat == "DEPLOYMENT" {
+		params.CategoryAndTypeData.CategoryFields.Deployment = &changetracking.ChangeTrackingDeploymentFieldsInput{
+			Version:   s.Version,
+			Changelog: s.Changelog,
+			Commit:    s.Commit,
+			DeepLink:  s.DeepLink,
+		}
+	}
+	if cat == "FEATURE FLAG" {
+		params.CategoryAndTypeData.CategoryFields.FeatureFlag = &changetracking.ChangeTrackingFeatureFlagFieldsInput{
+			FeatureFlagId: s.FeatureFlagID,
+		}
+	}
+}
+
+// customAttributesJS returns the custom attributes as a JS object string.
+// Attributes given as a map are converted, with keys in sorted order.
+func (s changeEventSpec) customAttributesJS() (string, error) {
+	switch attrs := s.CustomAttributes.(type) {
+	case nil:
+		return "", nil
+	case string:
+		return attrs, nil
+	case map[string]interface{}:
+		keys := make([]string, 0, len(attrs))
+		for k := range attrs {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+
+		pairs := make([]string, 0, len(keys))
+		for _, k := range keys {
+			if !customAttributeKeyRe.MatchString(k) {
+				return "", fmt.Errorf("customAttributes key %q must be a valid JS identifier", k)
+			}
+
+			var value string
+			switch v := attrs[k].(type) {
+			case string:
+				b, _ := json.Marshal(v)
+				value = string(b)
+			case bool:
+				value = strconv.FormatBool(v)
+			case float64:
+				value = strconv.FormatFloat(v, 'f', -1, 64)
+			default:
+				return "", fmt.Errorf("customAttributes value for %q must be a string, boolean, or number", k)
+			}
+
+			pairs = append(pairs, fmt.Sprintf("%s: %s", k, value))
+		}
+
+		return "{" + strings.Join(pairs, ", ") + "}", nil
+	}
+
+	return "", fmt.Errorf("customAttributes must be a JS object string or a map of attributes")
+}
+
+var validationFlags = map[string]changetracking.ChangeTrackingValidationFlag{
+	"ALLOW_CUSTOM_CATEGORY_OR_TYPE": changetracking.ChangeTrackingValidationFlagTypes.ALLOW_CUSTOM_CATEGORY_OR_TYPE,
+	"FAIL_ON_FIELD_LENGTH":          changetracking.ChangeTrackingValidationFlagTypes.FAIL_ON_FIELD_LENGTH,
+	"FAIL_ON_REST_API_FAILURES":     changetracking.ChangeTrackingValidationFlagTypes.FAIL_ON_REST_API_FAILURES,
+}
+
+// parseValidationFlags returns the validation flags of the names, which may be
+// written in any case, failing on unknown names.
+func parseValidationFlags(names []string) ([]changetracking.ChangeTrackingValidationFlag, error) {
+	var flags []changetracking.ChangeTrackingValidationFlag
+
+	for _, name := range names {
+		flag, ok := validationFlags[strings.ToUpper(strings.TrimSpace(name))]
+		if !ok {
+			return nil, fmt.Errorf("unknown validation flag %s, must be one of ALLOW_CUSTOM_CATEGORY_OR_TYPE, FAIL_ON_FIELD_LENGTH, FAIL_ON_REST_API_FAILURES", name)
+		}
+		flags = append(flags, flag)
+	}
+
+	return flags, nil
+}
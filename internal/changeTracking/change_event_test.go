@@ -0,0 +1,190 @@
+package changeTracking
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/newrelic/newrelic-client-go/v2/pkg/changetracking"
+)
+
+func TestChangeEventSpecBuild(t *testing.T) {
+	now := time.Now()
+
+	spec := changeEventSpec{
+		EntitySearch:    "name = 'MyService'",
+		Category:        "Deployment",
+		Type:            "Basic",
+		Version:         "1.2.3",
+		Commit:          "abc123",
+		ValidationFlags: []string{"FAIL_ON_FIELD_LENGTH"},
+	}
+
+	params, rules, err := spec.build(now)
+	require.NoError(t, err)
+	assert.Equal(t, "name = 'MyService'", params.EntitySearch.Query)
+	assert.Equal(t, "1.2.3", params.CategoryAndTypeData.CategoryFields.Deployment.Version)
+	assert.Equal(t, 1, len(rules.ValidationFlags))
+
+	spec.Version = ""
+	_, _, err = spec.build(now)
+	assert.EqualError(t, err, "version is required for DEPLOYMENT events")
+
+	spec.Category = "Operational"
+	_, _, err = spec.build(now)
+	assert.EqualError(t, err, "commit can only be used with DEPLOYMENT events")
+
+	spec = changeEventSpec{EntitySearch: "x", Category: "Operational", Type: "Other", Timestamp: now.Add(-time.Hour).UnixMilli(), ValidationFlags: []string{"fail_on_field_length"}}
+	_, rules, err = spec.build(now)
+	require.NoError(t, err)
+	assert.Equal(t, 1, len(rules.ValidationFlags))
+
+	spec.Timestamp = now.Add(-48 * time.Hour).UnixMilli()
+	_, _, err = spec.build(now)
+	assert.EqualError(t, err, "timestamp can not be more than 24 hours in the past or future")
+}
+
+// TestChangeEventValidationFlagsShouldMatchInBothModes runs the same invalid
+// event through the single event and the --file paths.
+func TestChangeEventValidationFlagsShouldMatchInBothModes(t *testing.T) {
+	now := time.Now()
+	spec := changeEventSpec{EntitySearch: "x", Category: "Operational", Type: "Other", ValidationFlags: []string{"NOT_A_FLAG", "FAIL_ON_FIELD_LENGTH"}}
+
+	_, _, err := spec.build(now)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "unknown validation flag NOT_A_FLAG")
+
+	_, err = buildChangeEvents([]changeEventSpec{spec}, nil, now)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "unknown validation flag NOT_A_FLAG")
+}
+
+func TestChangeEventSpecCustomAttributes(t *testing.T) {
+	spec := changeEventSpec{
+		CustomAttributes: map[string]interface{}{
+			"region":    "us-east-1",
+			"isProd":    true,
+			"instances": float64(2),
+		},
+	}
+
+	js, err := spec.customAttributesJS()
+	require.NoError(t, err)
+	assert.Equal(t, `{instances: 2, isProd: true, region: "us-east-1"}`, js)
+
+	spec.CustomAttributes = map[string]interface{}{"nested": map[string]interface{}{"a": 1}}
+	_, err = spec.customAttributesJS()
+	assert.Error(t, err)
+
+	spec.CustomAttributes = map[string]interface{}{"not-valid": "x"}
+	_, err = spec.customAttributesJS()
+	assert.Error(t, err)
+}
+
+func TestReadChangeEventsFile(t *testing.T) {
+	dir := t.TempDir()
+
+	yamlFile := filepath.Join(dir, "changes.yaml")
+	require.NoError(t, os.WriteFile(yamlFile, []byte(`
+events:
+  - entitySearch: "name = 'checkout'"
+    category: Deployment
+    type: Basic
+    version: "1.2.3"
+  - entitySearch: "name = 'cart'"
+    category: Operational
+    type: Maintenance Window
+    customAttributes:
+      region: us-east-1
+`), 0600))
+
+	events, err := readChangeEventsFile(yamlFile)
+	require.NoError(t, err)
+	assert.Equal(t, 2, len(events))
+	assert.Equal(t, "1.2.3", events[0].Version)
+	assert.Equal(t, map[string]interface{}{"region": "us-east-1"}, events[1].CustomAttributes)
+
+	jsonFile := filepath.Join(dir, "changes.json")
+	require.NoError(t, os.WriteFile(jsonFile, []byte(`[{"entitySearch": "name = 'checkout'", "category": "Deployment", "type": "Basic"}]`), 0600))
+
+	events, err = readChangeEventsFile(jsonFile)
+	require.NoError(t, err)
+	assert.Equal(t, 1, len(events))
+
+	emptyFile := filepath.Join(dir, "empty.yaml")
+	require.NoError(t, os.WriteFile(emptyFile, []byte("events: []\n"), 0600))
+
+	_, err = readChangeEventsFile(emptyFile)
+	assert.Error(t, err)
+}
+
+func TestBuildChangeEvents(t *testing.T) {
+	specs := []changeEventSpec{
+		{EntitySearch: "a", Category: "Operational", Type: "Other"},
+		{EntitySearch: "b", Category: "Deployment", Type: "Basic"},
+		{EntitySearch: "c", Category: "Feature Flag", Type: "Basic"},
+	}
+
+	_, err := buildChangeEvents(specs, nil, time.Now())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "2 of 3 change events are invalid")
+	assert.Contains(t, err.Error(), "event 2 (b)")
+	assert.Contains(t, err.Error(), "event 3 (c)")
+
+	events, err := buildChangeEvents(specs[:1], []string{"allow_custom_category_or_type"}, time.Now())
+	require.NoError(t, err)
+	assert.Equal(t, 1, len(events[0].rules.ValidationFlags))
+
+	_, err = buildChangeEvents([]changeEventSpec{{EntitySearch: "a", Category: "Operational", Type: "Other", ValidationFlags: []string{"NOT_A_FLAG"}}}, nil, time.Now())
+	assert.Error(t, err)
+}
+
+func TestAllowFileWithoutEventFlags(t *testing.T) {
+	cmd := &cobra.Command{Use: "create"}
+	for _, name := range singleEventFlags {
+		cmd.Flags().String(name, "", "")
+		require.NoError(t, cmd.MarkFlagRequired(name))
+	}
+
+	eventsFile = ""
+	require.NoError(t, allowFileWithoutEventFlags(cmd))
+	assert.Error(t, cmd.ValidateRequiredFlags())
+
+	eventsFile = "changes.yaml"
+	defer func() { eventsFile = "" }()
+	require.NoError(t, allowFileWithoutEventFlags(cmd))
+	assert.NoError(t, cmd.ValidateRequiredFlags())
+}
+
+func TestSubmitChangeEvents(t *testing.T) {
+	specs := []changeEventSpec{
+		{EntitySearch: "a", Category: "Operational", Type: "Other"},
+		{EntitySearch: "fail", Category: "Operational", Type: "Other"},
+		{EntitySearch: "c", Category: "Operational", Type: "Other"},
+	}
+
+	events, err := buildChangeEvents(specs, nil, time.Now())
+	require.NoError(t, err)
+
+	var calls int32
+	results := submitChangeEvents(events, 2, func(params changetracking.ChangeTrackingCreateEventInput, rules changetracking.ChangeTrackingDataHandlingRules) (interface{}, error) {
+		atomic.AddInt32(&calls, 1)
+		if params.EntitySearch.Query == "fail" {
+			return nil, fmt.Errorf("no entities found")
+		}
+		return params.EntitySearch.Query, nil
+	})
+
+	assert.Equal(t, int32(3), calls)
+	assert.Equal(t, 3, len(results))
+	assert.Equal(t, "a", results[0].Event)
+	assert.Equal(t, "no entities found", results[1].Error)
+	assert.Equal(t, 3, results[2].Index)
+}
@@ -3,7 +3,6 @@ package changeTracking
 import (
 	"log"
 	"os"
-	"regexp"
 	"strings"
 	"time"
 
@@ -12,8 +11,6 @@ import (
 	"github.com/newrelic/newrelic-cli/internal/client"
 	"github.com/newrelic/newrelic-cli/internal/output"
 	"github.com/newrelic/newrelic-cli/internal/utils"
-	"github.com/newrelic/newrelic-client-go/v2/pkg/changetracking"
-	"github.com/newrelic/newrelic-client-go/v2/pkg/nrtime"
 )
 
 var (
@@ -79,7 +76,26 @@ newrelic changeTracking create \
   --validationFlags ALLOW_CUSTOM_CATEGORY_OR_TYPE \
   --validationFlags FAIL_ON_FIELD_LENGTH \
   --validationFlags FAIL_ON_REST_API_FAILURES \
-  --user "ops-team"`
+  --user "ops-team"
+
+# Many events from a file, where changes.yaml contains:
+#   events:
+#     - entitySearch: "name = 'checkout' AND type = 'SERVICE'"
+#       category: Deployment
+#       type: Basic
+#       version: "1.2.3"
+#     - entitySearch: "name = 'cart' AND type = 'SERVICE'"
+#       category: Operational
+#       type: Maintenance Window
+#       customAttributes: {region: us-east-1, isProd: true}
+#       validationFlags: [ALLOW_CUSTOM_CATEGORY_OR_TYPE]
+newrelic changeTracking create --file changes.yaml --concurrency 10
+
+# Deployment event built from the local git repository (HEAD commit, tag, author,
+# commits since the previous tag, and a compare link to the repository host)
+newrelic changeTracking create \
+  --entitySearch "name = 'MyService' AND type = 'SERVICE'" \
+  --from-git`
 
 var CmdChangeTrackingCreate = &cobra.Command{
 	Use:   "create",
@@ -93,6 +109,8 @@ Required fields:
   --category            Category of event (e.g. Deployment, Feature Flag, Operational, etc.)
   --type                Type of event (e.g. Basic, Rollback, Server Reboot, etc.)
 
+Many events can be created at once with --file, see "Creating events from a file" below.
+
 For Deployment events, the following are required/supported:
   --version             Version of the deployment (required)
   --changelog           Changelog for the deployment (URL or text)
@@ -108,9 +126,21 @@ Other supported fields:
   --groupId             String to correlate two or more events
   --shortDescription    Short description for the event
   --customAttributes    Custom attributes: use '-' for STDIN, '{...}' for inline JS object, or provide a file path
-  --validationFlags     Comma-separated list of validation flags (e.g. ALLOW_CUSTOM_CATEGORY_OR_TYPE, FAIL_ON_FIELD_LENGTH, FAIL_ON_REST_API_FAILURES)
+  --validationFlags     Comma-separated list of validation flags (ALLOW_CUSTOM_CATEGORY_OR_TYPE, FAIL_ON_FIELD_LENGTH, FAIL_ON_REST_API_FAILURES), unknown flags are rejected
   --timestamp           Time of the event (milliseconds since Unix epoch, defaults to now). Can not be more than 24 hours in the past or future
 
+Deployment events can be built from a local git repository with --from-git.  Any of
+--category, --type, --version, --commit, --changelog, --deepLink, --user and --description
+that are not provided are filled in from the repository:
+  --category / --type   Deployment / Basic
+  --version             The tag pointing at HEAD, or the short commit SHA
+  --commit              The HEAD commit SHA
+  --changelog           The commits since --git-since, since the commit of the last
+                        Deployment event recorded for the entities, or since the previous tag
+  --deepLink            A compare (or commit) link built from the origin remote URL
+  --user                The author of the HEAD commit
+  --description         The subject of the HEAD commit
+
 Custom attributes can be provided in three ways:
   1. From STDIN by passing '-' (e.g. 'echo  '{cloud_vendor: "vendor_name", region: "us-east-1", isProd: true, instances: 2}' | newrelic changeTracking create ... --customAttributes -')
   2. As an inline JS object starting with '{' (e.g. --customAttributes '{cloud_vendor: "vendor_name", region: "us-east-1", isProd: true, instances: 2}')
@@ -120,52 +150,62 @@ The JS object format must use unquoted keys and values of type string, boolean,
 
 Validation is performed before sending to the API. Keys must be valid JS identifiers, and values must be string, boolean, or number.
 
+Creating events from a file:
+  --file                A YAML or JSON file containing a list of events, or an object with an 'events' list
+  --concurrency         The number of events submitted at once (defaults to 5)
+
+Each event in the file supports the same fields as the flags above: entitySearch, category,
+type, description, user, groupId, shortDescription, timestamp, validationFlags, version,
+changelog, commit, deepLink, featureFlagId and customAttributes.  customAttributes may be a
+JS object string or a map of attribute names to string, boolean, or number values.  Events
+that do not set validationFlags use the flags given with --validationFlags.
+
+Every event is validated as a single event is, before any are submitted, and nothing is
+submitted if any event is invalid.  The result of each event is printed once all have been submitted.
+
 For more information, see: https://docs.newrelic.com/docs/change-tracking/change-tracking-events/#change-tracking-event-mutation
 `,
 	Example: cmdChangeTrackingCreateExample,
-	PreRun:  client.RequireClient,
-	Run: func(cmd *cobra.Command, args []string) {
-		params := changetracking.ChangeTrackingCreateEventInput{}
+	PreRun: func(cmd *cobra.Command, args []string) {
+		client.RequireClient(cmd, args)
 
-		if eventTimestamp == 0 {
-			params.Timestamp = nrtime.EpochMilliseconds(time.Now())
-		} else {
-			params.Timestamp = nrtime.EpochMilliseconds(time.UnixMilli(eventTimestamp))
+		if err := allowFileWithoutEventFlags(cmd); err != nil {
+			log.Fatal(err)
 		}
 
-		if eventCategory == "" {
-			log.Fatal("--category cannot be empty")
-		}
-		if eventType == "" {
-			log.Fatal("--type cannot be empty")
+		if eventFromGit {
+			if err := applyGitMetadata(cmd); err != nil {
+				log.Fatalf("Failed to read git metadata: %v", err)
+			}
 		}
-		if eventSearchQuery == "" {
-			log.Fatal("--entitySearch cannot be empty")
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		if eventsFile != "" {
+			createEventsFromFile(eventsFile)
+			return
 		}
 
-		params.Description = eventDescription
-		params.User = eventUser
-		params.GroupId = eventGroupID
-		params.ShortDescription = eventShortDescription
-		params.EntitySearch = changetracking.ChangeTrackingEntitySearchInput{
-			Query: eventSearchQuery,
+		spec := changeEventSpec{
+			EntitySearch:     eventSearchQuery,
+			Category:         eventCategory,
+			Type:             eventType,
+			Description:      eventDescription,
+			User:             eventUser,
+			GroupID:          eventGroupID,
+			ShortDescription: eventShortDescription,
+			Timestamp:        eventTimestamp,
+			ValidationFlags:  eventValidationFlags,
+			Version:          eventVersion,
+			Changelog:        eventChangelog,
+			Commit:           eventCommit,
+			DeepLink:         eventDeepLink,
+			FeatureFlagID:    eventFeatureFlagId,
 		}
-		params.CategoryAndTypeData = &changetracking.ChangeTrackingCategoryRelatedInput{
-			Kind: &changetracking.ChangeTrackingCategoryAndTypeInput{
-				Category: eventCategory,
-				Type:     eventType,
-			},
-			CategoryFields: &changetracking.ChangeTrackingCategoryFieldsInput{},
-		}
-
-		validateCategoryFields(eventCategory)
-		setCategoryFields(eventCategory, &params)
 
 		// Custom Attributes: support --customAttributes with three parsing modes:
 		// 1. If equals "-", read from STDIN
 		// 2. If starts with "{", parse as JS object
 		// 3. Otherwise, treat as file path
-		var customAttrRaw string
 		if eventCustomAttributes != "" {
 			if eventCustomAttributes == "-" {
 				// Read from STDIN
@@ -173,56 +213,24 @@ For more information, see: https://docs.newrelic.com/docs/change-tracking/change
 				if err != nil {
 					log.Fatalf("Failed to read custom attributes from STDIN: %v", err)
 				}
-				customAttrRaw = string(stdinBytes)
+				spec.CustomAttributes = string(stdinBytes)
 			} else if strings.HasPrefix(strings.TrimSpace(eventCustomAttributes), "{") {
 				// Parse as JS object directly
-				customAttrRaw = eventCustomAttributes
+				spec.CustomAttributes = eventCustomAttributes
 			} else {
 				// Treat as file path
 				fileBytes, err := os.ReadFile(eventCustomAttributes)
 				if err != nil {
 					log.Fatalf("Failed to read custom attributes file: %v", err)
 				}
-				customAttrRaw = string(fileBytes)
-			}
-		}
-
-		if customAttrRaw != "" {
-			// Validate JS object format: keys must be valid JS identifiers, values must be string, bool, or number
-			// Accepts: {foo: "bar", num: 2, flag: true}
-			// This is a basic validation, not a full JS parser
-			jsObj := strings.TrimSpace(customAttrRaw)
-			if !strings.HasPrefix(jsObj, "{") || !strings.HasSuffix(jsObj, "}") {
-				log.Fatal("customAttributes must be a JS object, e.g. {foo: \"bar\", num: 2, flag: true}")
-			}
-			// Validate keys and values (simple regex)
-			kvRe := regexp.MustCompile(`([a-zA-Z_][a-zA-Z0-9_]*)\s*:\s*([^\"]+|\"[^\"]*\"|true|false|[0-9.]+)`) // key: value
-			matches := kvRe.FindAllStringSubmatch(jsObj, -1)
-			if len(matches) == 0 {
-				log.Fatal("customAttributes must contain at least one valid key: value pair")
-			}
-			// Optionally, further validation can be added here
-			// Convert JS object string to map[string]interface{} for API
-			attrs, err := changetracking.ReadCustomAttributesJS(customAttrRaw, false)
-			if err != nil {
-				log.Fatalf("Failed to parse customAttributes as JS object: %v", err)
+				spec.CustomAttributes = string(fileBytes)
 			}
-			params.CustomAttributes = attrs
 		}
 
-		// Parse validation flags
-		var flags []changetracking.ChangeTrackingValidationFlag
-		for _, flag := range eventValidationFlags {
-			switch flag {
-			case "ALLOW_CUSTOM_CATEGORY_OR_TYPE":
-				flags = append(flags, changetracking.ChangeTrackingValidationFlagTypes.ALLOW_CUSTOM_CATEGORY_OR_TYPE)
-			case "FAIL_ON_FIELD_LENGTH":
-				flags = append(flags, changetracking.ChangeTrackingValidationFlagTypes.FAIL_ON_FIELD_LENGTH)
-			case "FAIL_ON_REST_API_FAILURES":
-				flags = append(flags, changetracking.ChangeTrackingValidationFlagTypes.FAIL_ON_REST_API_FAILURES)
-			}
+		params, dataHandlingRules, err := spec.build(time.Now())
+		if err != nil {
+			log.Fatal(err)
 		}
-		dataHandlingRules := changetracking.ChangeTrackingDataHandlingRules{ValidationFlags: flags}
 
 		result, err := client.NRClient.ChangeTracking.ChangeTrackingCreateEventWithContext(
 			utils.SignalCtx,
@@ -236,13 +244,13 @@ For more information, see: https://docs.newrelic.com/docs/change-tracking/change
 
 func init() {
 	Command.AddCommand(CmdChangeTrackingCreate)
-	CmdChangeTrackingCreate.Flags().StringVar(&eventSearchQuery, "entitySearch", "", "the NRQL entity search query for this event. Example: name = 'MyService' AND type = 'SERVICE' (required)")
+	CmdChangeTrackingCreate.Flags().StringVar(&eventSearchQuery, "entitySearch", "", "the NRQL entity search query for this event. Example: name = 'MyService' AND type = 'SERVICE' (required unless --file is used)")
 	utils.LogIfError(CmdChangeTrackingCreate.MarkFlagRequired("entitySearch"))
 
-	CmdChangeTrackingCreate.Flags().StringVar(&eventCategory, "category", "", "category of event, e.g., DEPLOYMENT, CONFIG_CHANGE, etc. category is required.")
+	CmdChangeTrackingCreate.Flags().StringVar(&eventCategory, "category", "", "category of event, e.g., DEPLOYMENT, CONFIG_CHANGE, etc. category is required unless --file is used.")
 	utils.LogIfError(CmdChangeTrackingCreate.MarkFlagRequired("category"))
 
-	CmdChangeTrackingCreate.Flags().StringVar(&eventType, "type", "", "type of event, e.g., BASIC, ROLLBACK, etc. type is required.")
+	CmdChangeTrackingCreate.Flags().StringVar(&eventType, "type", "", "type of event, e.g., BASIC, ROLLBACK, etc. type is required unless --file is used.")
 	utils.LogIfError(CmdChangeTrackingCreate.MarkFlagRequired("type"))
 
 	CmdChangeTrackingCreate.Flags().StringVar(&eventDescription, "description", "", "a description of the event")
@@ -261,52 +269,16 @@ func init() {
 
 	// Feature flag fields
 	CmdChangeTrackingCreate.Flags().StringVar(&eventFeatureFlagId, "featureFlagId", "", "ID of the feature flag")
-}
 
-func validateCategoryFields(category string) {
-	cat := strings.ToUpper(category)
-	if cat != "DEPLOYMENT" {
-		var invalidFlags []string
-		if eventVersion != "" {
-			invalidFlags = append(invalidFlags, "--version")
-		}
-		if eventChangelog != "" {
-			invalidFlags = append(invalidFlags, "--changelog")
-		}
-		if eventCommit != "" {
-			invalidFlags = append(invalidFlags, "--commit")
-		}
-		if eventDeepLink != "" {
-			invalidFlags = append(invalidFlags, "--deepLink")
-		}
-		if len(invalidFlags) > 0 {
-			log.Fatalf("%s can only be used with DEPLOYMENT events.", strings.Join(invalidFlags, ", "))
-		}
-	}
-	if cat != "FEATURE FLAG" && eventFeatureFlagId != "" {
-		log.Fatal("--featureFlagId is only valid for FEATURE FLAG events")
-	}
-	if cat == "DEPLOYMENT" && eventVersion == "" {
-		log.Fatal("--version is required for DEPLOYMENT events")
-	}
-	if cat == "FEATURE FLAG" && eventFeatureFlagId == "" {
-		log.Fatal("--featureFlagId is required for FEATURE FLAG events")
-	}
-}
+	// Git metadata
+	CmdChangeTrackingCreate.Flags().BoolVar(&eventFromGit, "from-git", false, "fill in deployment fields from the local git repository")
+	CmdChangeTrackingCreate.Flags().StringVar(&eventGitDir, "git-dir", ".", "path to the git repository used with --from-git")
+	CmdChangeTrackingCreate.Flags().StringVar(&eventGitSince, "git-since", "", "git ref of the previous deployment used to build the changelog, defaults to the commit of the last deployment event of the entities, then to the previous tag")
 
-func setCategoryFields(category string, params *changetracking.ChangeTrackingCreateEventInput) {
-	cat := strings.ToUpper(category)
-	if cat == "DEPLOYMENT" {
-		params.CategoryAndTypeData.CategoryFields.Deployment = &changetracking.ChangeTrackingDeploymentFieldsInput{
-			Version:   eventVersion,
-			Changelog: eventChangelog,
-			Commit:    eventCommit,
-			DeepLink:  eventDeepLink,
-		}
-	}
-	if cat == "FEATURE FLAG" {
-		params.CategoryAndTypeData.CategoryFields.FeatureFlag = &changetracking.ChangeTrackingFeatureFlagFieldsInput{
-			FeatureFlagId: eventFeatureFlagId,
-		}
+	// Bulk creation
+	CmdChangeTrackingCreate.Flags().StringVarP(&eventsFile, "file", "f", "", "a YAML or JSON file of change events to create")
+	CmdChangeTrackingCreate.Flags().IntVar(&eventsConcurrency, "concurrency", 5, "the number of events from --file to submit at once")
+	for _, f := range []string{"entitySearch", "category", "type", "customAttributes", "from-git"} {
+		CmdChangeTrackingCreate.MarkFlagsMutuallyExclusive("file", f)
 	}
 }
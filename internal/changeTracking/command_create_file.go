@@ -0,0 +1,161 @@
+package changeTracking
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/newrelic/newrelic-cli/internal/client"
+	"github.com/newrelic/newrelic-cli/internal/output"
+	"github.com/newrelic/newrelic-cli/internal/utils"
+	"github.com/newrelic/newrelic-client-go/v2/pkg/changetracking"
+)
+
+var (
+	eventsFile        string
+	eventsConcurrency int
+)
+
+// changeEvent is a validated event ready to be submitted.
+type changeEvent struct {
+	spec   changeEventSpec
+	params changetracking.ChangeTrackingCreateEventInput
+	rules  changetracking.ChangeTrackingDataHandlingRules
+}
+
+// changeEventResult is the outcome of creating a single event from a file.
+type changeEventResult struct {
+	Index        int         `json:"index"`
+	EntitySearch string      `json:"entitySearch"`
+	Category     string      `json:"category"`
+	Type         string      `json:"type"`
+	Event        interface{} `json:"event,omitempty"`
+	Error        string      `json:"error,omitempty"`
+}
+
+type createEventFunc func(changetracking.ChangeTrackingCreateEventInput, changetracking.ChangeTrackingDataHandlingRules) (interface{}, error)
+
+func createEventsFromFile(path string) {
+	specs, err := readChangeEventsFile(path)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	events, err := buildChangeEvents(specs, eventValidationFlags, time.Now())
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	results := submitChangeEvents(events, eventsConcurrency, func(params changetracking.ChangeTrackingCreateEventInput, rules changetracking.ChangeTrackingDataHandlingRules) (interface{}, error) {
+		return client.NRClient.ChangeTracking.ChangeTrackingCreateEventWithContext(utils.SignalCtx, params, rules)
+	})
+
+	utils.LogIfFatal(output.Print(results))
+
+	failed := 0
+	for _, r := range results {
+		if r.Error != "" {
+			failed++
+		}
+	}
+
+	if failed > 0 {
+		log.Fatalf("%d of %d change events could not be created", failed, len(results))
+	}
+}
+
+// buildChangeEvents validates every event up front, returning an error that
+// describes each invalid event.  Events without validation flags of their own
+// use defaultFlags.
+func buildChangeEvents(specs []changeEventSpec, defaultFlags []string, now time.Time) ([]changeEvent, error) {
+	events := make([]changeEvent, 0, len(specs))
+	problems := []string{}
+
+	for i, spec := range specs {
+		if len(spec.ValidationFlags) == 0 {
+			spec.ValidationFlags = defaultFlags
+		}
+
+		params, rules, err := spec.build(now)
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("  event %d (%s): %s", i+1, spec.EntitySearch, err))
+			continue
+		}
+
+		events = append(events, changeEvent{spec: spec, params: params, rules: rules})
+	}
+
+	if len(problems) > 0 {
+		return nil, fmt.Errorf("%d of %d change events are invalid, no events were created:\n%s", len(problems), len(specs), strings.Join(problems, "\n"))
+	}
+
+	return events, nil
+}
+
+// submitChangeEvents creates the events with at most concurrency requests in
+// flight, returning a result for each event in the original order.
+func submitChangeEvents(events []changeEvent, concurrency int, create createEventFunc) []changeEventResult {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	results := make([]changeEventResult, len(events))
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+
+	for i, e := range events {
+		results[i] = changeEventResult{
+			Index:        i + 1,
+			EntitySearch: e.spec.EntitySearch,
+			Category:     e.spec.Category,
+			Type:         e.spec.Type,
+		}
+
+		wg.Add(1)
+		sem <- struct{}{}
+
+		go func(i int, e changeEvent) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+
+			event, err := create(e.params, e.rules)
+			if err != nil {
+				results[i].Error = err.Error()
+				return
+			}
+
+			results[i].Event = event
+		}(i, e)
+	}
+
+	wg.Wait()
+
+	return results
+}
+
+// singleEventFlags are required to create a single event.  With --file they are
+// given for each event of the file instead.
+var singleEventFlags = []string{"entitySearch", "category", "type"}
+
+// allowFileWithoutEventFlags lifts the requirement on the single event flags
+// when events are read from --file.  It runs before cobra validates the
+// required flags.
+func allowFileWithoutEventFlags(cmd *cobra.Command) error {
+	if eventsFile == "" {
+		return nil
+	}
+
+	for _, name := range singleEventFlags {
+		if err := cmd.Flags().SetAnnotation(name, cobra.BashCompOneRequiredFlag, []string{"false"}); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,117 @@
+package changeTracking
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/newrelic/newrelic-cli/internal/client"
+	"github.com/newrelic/newrelic-cli/internal/utils"
+	"github.com/newrelic/newrelic-cli/internal/utils/git"
+	"github.com/newrelic/newrelic-client-go/v2/pkg/entities"
+	"github.com/newrelic/newrelic-client-go/v2/pkg/nrdb"
+)
+
+var (
+	eventFromGit  bool
+	eventGitDir   string
+	eventGitSince string
+)
+
+// applyGitMetadata fills in any deployment flags that were not provided on the
+// command line using metadata read from the local git repository.  Flags are set
+// through the flag set so that required flag validation treats them as provided.
+func applyGitMetadata(cmd *cobra.Command) error {
+	since := eventGitSince
+	if since == "" && eventSearchQuery != "" {
+		since = git.PreviousMarkerRevision(eventGitDir, previousDeploymentCommits)
+	}
+
+	info, err := git.Read(eventGitDir, since)
+	if err != nil {
+		return err
+	}
+
+	values := []struct {
+		flag  string
+		value string
+	}{
+		{"category", "Deployment"},
+		{"type", "Basic"},
+		{"version", info.Version()},
+		{"commit", info.Commit},
+		{"changelog", info.Changelog()},
+		{"deepLink", info.DeepLink()},
+		{"user", info.Author},
+		{"description", info.Subject},
+	}
+
+	for _, v := range values {
+		if v.value == "" || cmd.Flags().Changed(v.flag) {
+			continue
+		}
+
+		if err := cmd.Flags().Set(v.flag, v.value); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// previousDeploymentCommits returns the commits of the deployment events recorded
+// for the entities matching --entitySearch, most recent first.
+func previousDeploymentCommits() ([]string, error) {
+	results, err := client.NRClient.Entities.GetEntitySearchByQueryWithContext(
+		utils.SignalCtx,
+		entities.EntitySearchOptions{},
+		eventSearchQuery,
+		[]entities.EntitySearchSortCriteria{},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if results == nil {
+		return nil, nil
+	}
+
+	guidsByAccount := map[int][]string{}
+	for _, e := range results.Results.Entities {
+		guidsByAccount[e.GetAccountID()] = append(guidsByAccount[e.GetAccountID()], string(e.GetGUID()))
+	}
+
+	events := []nrdb.NRDBResult{}
+	for accountID, guids := range guidsByAccount {
+		result, err := client.NRClient.Nrdb.QueryWithContext(utils.SignalCtx, accountID, nrdb.NRQL(buildPreviousCommitsQuery(guids)))
+		if err != nil {
+			return nil, err
+		}
+
+		events = append(events, result.Results...)
+	}
+
+	sortEventsByTimestamp(events)
+
+	commits := []string{}
+	for _, e := range events {
+		if commit, ok := e["commit"].(string); ok && commit != "" {
+			commits = append(commits, commit)
+		}
+	}
+
+	return commits, nil
+}
+
+// buildPreviousCommitsQuery returns the NRQL query for the commits of the most
+// recent deployment events of the given entities.
+func buildPreviousCommitsQuery(guids []string) string {
+	quoted := make([]string, len(guids))
+	for i, g := range guids {
+		quoted[i] = quoteNRQL(g)
+	}
+
+	return fmt.Sprintf("SELECT timestamp, commit FROM %s WHERE entity.guid IN (%s) AND category = 'Deployment' AND commit IS NOT NULL SINCE 3 months ago LIMIT 10",
+		changeTrackingEventType, strings.Join(quoted, ", "))
+}
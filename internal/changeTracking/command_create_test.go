@@ -19,3 +19,9 @@ func TestChangeTrackingCreateCommand(t *testing.T) {
 	testcobra.CheckCobraRequiredFlags(t, CmdChangeTrackingCreate,
 		[]string{"entitySearch", "category", "type"})
 }
+
+func TestBuildPreviousCommitsQuery(t *testing.T) {
+	query := buildPreviousCommitsQuery([]string{"guid-1", "guid-2"})
+
+	assert.Equal(t, "SELECT timestamp, commit FROM ChangeTrackingEvent WHERE entity.guid IN ('guid-1', 'guid-2') AND category = 'Deployment' AND commit IS NOT NULL SINCE 3 months ago LIMIT 10", query)
+}
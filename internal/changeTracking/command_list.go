@@ -0,0 +1,182 @@
+package changeTracking
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/newrelic/newrelic-cli/internal/client"
+	configAPI "github.com/newrelic/newrelic-cli/internal/config/api"
+	"github.com/newrelic/newrelic-cli/internal/output"
+	"github.com/newrelic/newrelic-cli/internal/utils"
+	"github.com/newrelic/newrelic-client-go/v2/pkg/entities"
+	"github.com/newrelic/newrelic-client-go/v2/pkg/nrdb"
+)
+
+// changeTrackingEventType is the NRDB event type change tracking events are stored as.
+const changeTrackingEventType = "ChangeTrackingEvent"
+
+var (
+	listSince    string
+	listCategory string
+	listType     string
+	listLimit    int
+	getEventID   string
+	getSince     string
+)
+
+var CmdChangeTrackingList = &cobra.Command{
+	Use:   "list",
+	Short: "List change tracking events for New Relic entities",
+	Long: `List change tracking events for New Relic entities
+
+The list command finds the entities matching --entitySearch and returns the change
+tracking events recorded for them, most recent first.  Events can be filtered by
+category and type.  --since accepts any NRQL SINCE clause value, such as
+'1 day ago' or '2024-01-01 00:00:00'.
+`,
+	Example: `# Deployments for a service in the last day
+newrelic changeTracking list --entitySearch "name = 'MyService' AND type = 'SERVICE'" --category Deployment
+
+# Every change for an entity in the last week
+newrelic changeTracking list --entitySearch "id = '<Entity GUID>'" --since '1 week ago'`,
+	PreRun: client.RequireClient,
+	Run: func(cmd *cobra.Command, args []string) {
+		if eventSearchQuery == "" {
+			log.Fatal("--entitySearch cannot be empty")
+		}
+
+		results, err := client.NRClient.Entities.GetEntitySearchByQueryWithContext(
+			utils.SignalCtx,
+			entities.EntitySearchOptions{},
+			eventSearchQuery,
+			[]entities.EntitySearchSortCriteria{},
+		)
+		utils.LogIfFatal(err)
+
+		if results == nil || len(results.Results.Entities) == 0 {
+			log.Fatalf("no entities found for entity search query: %s", eventSearchQuery)
+		}
+
+		// Change tracking events are stored in the account of the entity they
+		// belong to, so query each account separately.
+		guidsByAccount := map[int][]string{}
+		for _, e := range results.Results.Entities {
+			guidsByAccount[e.GetAccountID()] = append(guidsByAccount[e.GetAccountID()], string(e.GetGUID()))
+		}
+
+		events := []nrdb.NRDBResult{}
+		for accountID, guids := range guidsByAccount {
+			query := buildListQuery(guids, listCategory, listType, listSince, listLimit)
+
+			result, err := client.NRClient.Nrdb.QueryWithContext(utils.SignalCtx, accountID, nrdb.NRQL(query))
+			utils.LogIfFatal(err)
+
+			events = append(events, result.Results...)
+		}
+
+		sortEventsByTimestamp(events)
+		if listLimit > 0 && len(events) > listLimit {
+			events = events[:listLimit]
+		}
+
+		utils.LogIfFatal(output.Print(events))
+	},
+}
+
+var CmdChangeTrackingGet = &cobra.Command{
+	Use:   "get",
+	Short: "Get a change tracking event by ID",
+	Long: `Get a change tracking event by ID
+
+The get command returns the change tracking event with the given ID, as returned
+by the create command.  This can be used to check that an event was recorded.
+This command requires the --accountId <int> flag, which specifies the account the
+event was recorded in.
+`,
+	Example: `newrelic changeTracking get --accountId 12345678 --id <Change Tracking ID> --since '1 week ago'`,
+	PreRun:  client.RequireClient,
+	Run: func(cmd *cobra.Command, args []string) {
+		accountID := configAPI.RequireActiveProfileAccountID()
+
+		query := buildGetQuery(getEventID, getSince)
+
+		result, err := client.NRClient.Nrdb.QueryWithContext(utils.SignalCtx, accountID, nrdb.NRQL(query))
+		utils.LogIfFatal(err)
+
+		if len(result.Results) == 0 {
+			log.Fatalf("no change tracking event found with ID %s since %s", getEventID, getSince)
+		}
+
+		utils.LogIfFatal(output.Print(result.Results[0]))
+	},
+}
+
+// buildListQuery returns the NRQL query for the change tracking events of the
+// given entities, optionally filtered by category and type.
+func buildListQuery(guids []string, category string, eventType string, since string, limit int) string {
+	quoted := make([]string, len(guids))
+	for i, g := range guids {
+		quoted[i] = quoteNRQL(g)
+	}
+
+	conditions := []string{fmt.Sprintf("entity.guid IN (%s)", strings.Join(quoted, ", "))}
+	if category != "" {
+		conditions = append(conditions, fmt.Sprintf("category = %s", quoteNRQL(category)))
+	}
+	if eventType != "" {
+		conditions = append(conditions, fmt.Sprintf("type = %s", quoteNRQL(eventType)))
+	}
+
+	limitClause := "MAX"
+	if limit > 0 {
+		limitClause = fmt.Sprint(limit)
+	}
+
+	return fmt.Sprintf("SELECT * FROM %s WHERE %s SINCE %s LIMIT %s",
+		changeTrackingEventType, strings.Join(conditions, " AND "), since, limitClause)
+}
+
+// buildGetQuery returns the NRQL query for a single change tracking event.
+func buildGetQuery(id string, since string) string {
+	return fmt.Sprintf("SELECT * FROM %s WHERE changeTrackingId = %s SINCE %s LIMIT 1",
+		changeTrackingEventType, quoteNRQL(id), since)
+}
+
+func quoteNRQL(value string) string {
+	return "'" + strings.ReplaceAll(value, "'", "\\'") + "'"
+}
+
+// sortEventsByTimestamp sorts events from the most to the least recent.
+func sortEventsByTimestamp(events []nrdb.NRDBResult) {
+	timestamp := func(e nrdb.NRDBResult) float64 {
+		if ts, ok := e["timestamp"].(float64); ok {
+			return ts
+		}
+		return 0
+	}
+
+	sort.SliceStable(events, func(i, j int) bool {
+		return timestamp(events[i]) > timestamp(events[j])
+	})
+}
+
+func init() {
+	Command.AddCommand(CmdChangeTrackingList)
+	CmdChangeTrackingList.Flags().StringVar(&eventSearchQuery, "entitySearch", "", "the entity search query for the entities to list events for. Example: name = 'MyService' AND type = 'SERVICE' (required)")
+	utils.LogIfError(CmdChangeTrackingList.MarkFlagRequired("entitySearch"))
+
+	CmdChangeTrackingList.Flags().StringVar(&listSince, "since", "1 day ago", "how far back to look for events, as a NRQL SINCE clause value")
+	CmdChangeTrackingList.Flags().StringVar(&listCategory, "category", "", "only list events of this category, e.g. Deployment")
+	CmdChangeTrackingList.Flags().StringVar(&listType, "type", "", "only list events of this type, e.g. Basic")
+	CmdChangeTrackingList.Flags().IntVar(&listLimit, "limit", 100, "the maximum number of events to return, 0 for no limit")
+
+	Command.AddCommand(CmdChangeTrackingGet)
+	CmdChangeTrackingGet.Flags().StringVar(&getEventID, "id", "", "the change tracking ID of the event (required)")
+	utils.LogIfError(CmdChangeTrackingGet.MarkFlagRequired("id"))
+
+	CmdChangeTrackingGet.Flags().StringVar(&getSince, "since", "1 week ago", "how far back to look for the event, as a NRQL SINCE clause value")
+}
@@ -0,0 +1,48 @@
+package changeTracking
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/newrelic/newrelic-cli/internal/testcobra"
+	"github.com/newrelic/newrelic-client-go/v2/pkg/nrdb"
+)
+
+func TestChangeTrackingListCommand(t *testing.T) {
+	assert.Equal(t, "list", CmdChangeTrackingList.Name())
+	testcobra.CheckCobraMetadata(t, CmdChangeTrackingList)
+	testcobra.CheckCobraRequiredFlags(t, CmdChangeTrackingList, []string{"entitySearch"})
+}
+
+func TestChangeTrackingGetCommand(t *testing.T) {
+	assert.Equal(t, "get", CmdChangeTrackingGet.Name())
+	testcobra.CheckCobraMetadata(t, CmdChangeTrackingGet)
+	testcobra.CheckCobraRequiredFlags(t, CmdChangeTrackingGet, []string{"id"})
+}
+
+func TestBuildListQuery(t *testing.T) {
+	query := buildListQuery([]string{"guid1", "guid2"}, "Deployment", "Basic", "1 day ago", 10)
+	assert.Equal(t, "SELECT * FROM ChangeTrackingEvent WHERE entity.guid IN ('guid1', 'guid2') AND category = 'Deployment' AND type = 'Basic' SINCE 1 day ago LIMIT 10", query)
+
+	query = buildListQuery([]string{"guid1"}, "", "", "1 week ago", 0)
+	assert.Equal(t, "SELECT * FROM ChangeTrackingEvent WHERE entity.guid IN ('guid1') SINCE 1 week ago LIMIT MAX", query)
+}
+
+func TestBuildGetQuery(t *testing.T) {
+	query := buildGetQuery("it's-an-id", "1 week ago")
+	assert.Equal(t, "SELECT * FROM ChangeTrackingEvent WHERE changeTrackingId = 'it\\'s-an-id' SINCE 1 week ago LIMIT 1", query)
+}
+
+func TestSortEventsByTimestamp(t *testing.T) {
+	events := []nrdb.NRDBResult{
+		{"timestamp": float64(1)},
+		{"timestamp": float64(3)},
+		{"timestamp": float64(2)},
+	}
+
+	sortEventsByTimestamp(events)
+
+	assert.Equal(t, float64(3), events[0]["timestamp"])
+	assert.Equal(t, float64(1), events[2]["timestamp"])
+}
@@ -19,6 +19,7 @@ const (
 	PluginDir          FieldKey = "plugindir"
 	PreReleaseFeatures FieldKey = "prereleasefeatures"
 	SendUsageData      FieldKey = "sendUsageData"
+	RecipeSigningKey   FieldKey = "recipeSigningKey"
 
 	DefaultProfileName = "default"
 
@@ -87,6 +88,10 @@ func InitializeCredentialsStore() {
 				EnvVar:    "NEW_RELIC_LICENSE_KEY",
 				Sensitive: true,
 			},
+			FieldDefinition{
+				Key:    RecipeSigningKey,
+				EnvVar: "NEW_RELIC_RECIPE_SIGNING_KEY",
+			},
 		),
 	)
 
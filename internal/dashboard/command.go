@@ -0,0 +1,174 @@
+package dashboard
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+
+	"github.com/newrelic/newrelic-cli/internal/client"
+	configAPI "github.com/newrelic/newrelic-cli/internal/config/api"
+	"github.com/newrelic/newrelic-cli/internal/output"
+	"github.com/newrelic/newrelic-cli/internal/utils"
+)
+
+var (
+	dashboardGUID    string
+	dashboardName    string
+	exportOutputFile string
+	importFile       string
+	accountMapping   []string
+)
+
+// Command represents the dashboard command
+var Command = &cobra.Command{
+	Use:   "dashboard",
+	Short: "Manage New Relic dashboards",
+	Long: `Manage New Relic dashboards
+
+The dashboard commands find, export and import dashboards.  Dashboards exported
+from one account can be imported into another, to promote dashboards from a
+staging account to a production account.
+`,
+	Example: "newrelic dashboard --help",
+}
+
+var cmdList = &cobra.Command{
+	Use:   "list",
+	Short: "List the dashboards of an account",
+	Long: `List the dashboards of an account
+
+The list command lists the dashboards of the account, with their GUIDs.
+`,
+	Example: "newrelic dashboard list --accountId 12345",
+	PreRun:  client.RequireClient,
+	Run: func(cmd *cobra.Command, args []string) {
+		accountID := configAPI.RequireActiveProfileAccountID()
+
+		results, err := searchDashboards(utils.SignalCtx, client.NRClient, fmt.Sprintf("accountId = '%d'", accountID))
+		utils.LogIfFatal(err)
+
+		utils.LogIfFatal(output.Print(results))
+	},
+}
+
+var cmdSearch = &cobra.Command{
+	Use:   "search",
+	Short: "Search dashboards by name",
+	Long: `Search dashboards by name
+
+The search command lists the dashboards whose name contains the given name, in
+every account of the user, or in the account given with --accountId.
+`,
+	Example: `newrelic dashboard search --name "Checkout"
+newrelic dashboard search --name "Checkout" --accountId 12345`,
+	PreRun: client.RequireClient,
+	Run: func(cmd *cobra.Command, args []string) {
+		query := fmt.Sprintf("name LIKE %s", entitySearchValue(dashboardName))
+		if accountID := configAPI.GetActiveProfileAccountID(); accountID != 0 {
+			query += fmt.Sprintf(" AND accountId = '%d'", accountID)
+		}
+
+		results, err := searchDashboards(utils.SignalCtx, client.NRClient, query)
+		utils.LogIfFatal(err)
+
+		utils.LogIfFatal(output.Print(results))
+	},
+}
+
+var cmdExport = &cobra.Command{
+	Use:   "export",
+	Short: "Export a dashboard as JSON",
+	Long: `Export a dashboard as JSON
+
+The export command writes the JSON of the dashboard with the GUID, with its
+pages, widgets and variables, in the format of the dashboard JSON of the New
+Relic UI.  The JSON can be imported with the import command, or turned into
+Terraform HCL with the utils terraform dashboard command.
+`,
+	Example: `newrelic dashboard export --guid "<dashboardGUID>" --output dashboard.json`,
+	PreRun:  client.RequireClient,
+	Run: func(cmd *cobra.Command, args []string) {
+		d, err := getDashboard(utils.SignalCtx, client.NRClient, dashboardGUID)
+		utils.LogIfFatal(err)
+
+		if exportOutputFile == "" {
+			utils.LogIfFatal(output.Print(d))
+			return
+		}
+
+		b, err := json.MarshalIndent(d, "", "  ")
+		utils.LogIfFatal(err)
+		utils.LogIfFatal(os.WriteFile(exportOutputFile, append(b, '\n'), 0600))
+
+		log.Infof("dashboard %s exported to %s", d.Name, exportOutputFile)
+	},
+}
+
+var cmdImport = &cobra.Command{
+	Use:   "import",
+	Short: "Create or update a dashboard from JSON",
+	Long: `Create or update a dashboard from JSON
+
+The import command creates the dashboard of the JSON file in the account, or
+updates the dashboard of the account with the same name when there is one.  Use
+--guid to update a given dashboard instead.
+
+The accounts queried by the widgets and variables of the dashboard are replaced
+by the account the dashboard is imported into.  Use --accountMapping to replace
+only some accounts, for dashboards querying several accounts.  Accounts named
+in the NRQL of the queries, as in WHERE accountId = 111 or accountId IN (111,
+333), are replaced too.  Other references to accounts in NRQL, such as account
+names, are kept as is.  Facet links to the pages of the dashboard are linked to
+the pages of the imported dashboard.
+`,
+	Example: `newrelic dashboard import --file dashboard.json --accountId 12345
+newrelic dashboard import -f dashboard.json --accountId 12345 --accountMapping 111=222,333=444
+newrelic dashboard import -f dashboard.json --guid "<dashboardGUID>"`,
+	PreRun: client.RequireClient,
+	Run: func(cmd *cobra.Command, args []string) {
+		accountID := configAPI.RequireActiveProfileAccountID()
+
+		mapping, err := parseAccountMapping(accountMapping)
+		utils.LogIfFatal(err)
+
+		b, err := os.ReadFile(importFile)
+		utils.LogIfFatal(err)
+
+		var d dashboard
+		if err := json.Unmarshal(b, &d); err != nil {
+			log.Fatalf("could not parse dashboard JSON %s: %s", importFile, err)
+		}
+		if d.Name == "" {
+			log.Fatalf("dashboard JSON %s has no name", importFile)
+		}
+
+		rewriteAccountIDs(&d, accountID, mapping)
+
+		result, err := importDashboard(utils.SignalCtx, client.NRClient, d, accountID, dashboardGUID)
+		utils.LogIfFatal(err)
+
+		utils.LogIfFatal(output.Print(result))
+	},
+}
+
+func init() {
+	Command.AddCommand(cmdList)
+
+	cmdSearch.Flags().StringVarP(&dashboardName, "name", "n", "", "the name, or part of the name, of the dashboards to find")
+	utils.LogIfError(cmdSearch.MarkFlagRequired("name"))
+	Command.AddCommand(cmdSearch)
+
+	cmdExport.Flags().StringVarP(&dashboardGUID, "guid", "g", "", "the GUID of the dashboard to export")
+	cmdExport.Flags().StringVarP(&exportOutputFile, "output", "o", "", "the file to write the dashboard JSON to, instead of the standard output")
+	utils.LogIfError(cmdExport.MarkFlagRequired("guid"))
+	Command.AddCommand(cmdExport)
+
+	cmdImport.Flags().StringVarP(&importFile, "file", "f", "", "the dashboard JSON file to import")
+	cmdImport.Flags().StringVarP(&dashboardGUID, "guid", "g", "", "the GUID of the dashboard to update")
+	cmdImport.Flags().StringSliceVar(&accountMapping, "accountMapping", []string{}, "the accounts to replace in queries, as <sourceAccountId>=<targetAccountId> pairs")
+	utils.LogIfError(cmdImport.MarkFlagRequired("file"))
+	Command.AddCommand(cmdImport)
+}
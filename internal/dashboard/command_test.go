@@ -0,0 +1,37 @@
+//go:build unit
+
+package dashboard
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/newrelic/newrelic-cli/internal/testcobra"
+)
+
+func TestDashboardCommand(t *testing.T) {
+	assert.Equal(t, "dashboard", Command.Name())
+
+	testcobra.CheckCobraMetadata(t, Command)
+	testcobra.CheckCobraRequiredFlags(t, Command, []string{})
+}
+
+func TestDashboardSubcommands(t *testing.T) {
+	for _, cmd := range []struct {
+		name     string
+		required []string
+	}{
+		{"list", []string{}},
+		{"search", []string{"name"}},
+		{"export", []string{"guid"}},
+		{"import", []string{"file"}},
+	} {
+		c, _, err := Command.Find([]string{cmd.name})
+		assert.NoError(t, err)
+		assert.Equal(t, cmd.name, c.Name())
+
+		testcobra.CheckCobraMetadata(t, c)
+		testcobra.CheckCobraRequiredFlags(t, c, cmd.required)
+	}
+}
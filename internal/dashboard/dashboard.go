@@ -0,0 +1,518 @@
+package dashboard
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/newrelic/newrelic-client-go/v2/newrelic"
+	"github.com/newrelic/newrelic-client-go/v2/pkg/common"
+	"github.com/newrelic/newrelic-client-go/v2/pkg/dashboards"
+)
+
+// dashboard is a dashboard in the shape of the dashboard JSON of the New Relic
+// UI, which is also the shape of the input of the create and update
+// mutations, apart from the GUIDs.
+type dashboard struct {
+	GUID        string              `json:"guid,omitempty"`
+	Name        string              `json:"name"`
+	Description string              `json:"description,omitempty"`
+	Permissions string              `json:"permissions,omitempty"`
+	Pages       []dashboardPage     `json:"pages"`
+	Variables   []dashboardVariable `json:"variables,omitempty"`
+}
+
+type dashboardPage struct {
+	GUID        string            `json:"guid,omitempty"`
+	Name        string            `json:"name"`
+	Description string            `json:"description,omitempty"`
+	Widgets     []dashboardWidget `json:"widgets"`
+}
+
+type dashboardWidget struct {
+	Title             string                 `json:"title"`
+	Layout            dashboardWidgetLayout  `json:"layout"`
+	LinkedEntityGUIDs []string               `json:"linkedEntityGuids,omitempty"`
+	Visualization     dashboardVisualization `json:"visualization"`
+	RawConfiguration  map[string]interface{} `json:"rawConfiguration"`
+}
+
+type dashboardWidgetLayout struct {
+	Column int `json:"column"`
+	Row    int `json:"row"`
+	Width  int `json:"width"`
+	Height int `json:"height"`
+}
+
+type dashboardVisualization struct {
+	ID string `json:"id"`
+}
+
+type dashboardVariable struct {
+	Name                string                      `json:"name"`
+	Title               string                      `json:"title,omitempty"`
+	Type                string                      `json:"type"`
+	IsMultiSelection    bool                        `json:"isMultiSelection"`
+	ReplacementStrategy string                      `json:"replacementStrategy,omitempty"`
+	DefaultValues       []dashboardVariableDefault  `json:"defaultValues,omitempty"`
+	Items               []dashboardVariableItem     `json:"items,omitempty"`
+	NRQLQuery           *dashboardVariableNRQLQuery `json:"nrqlQuery,omitempty"`
+	Options             *dashboardVariableOptions   `json:"options,omitempty"`
+}
+
+type dashboardVariableDefault struct {
+	Value struct {
+		String string `json:"string"`
+	} `json:"value"`
+}
+
+type dashboardVariableItem struct {
+	Title string `json:"title,omitempty"`
+	Value string `json:"value"`
+}
+
+type dashboardVariableNRQLQuery struct {
+	AccountIDs []int  `json:"accountIds"`
+	Query      string `json:"query"`
+}
+
+type dashboardVariableOptions struct {
+	IgnoreTimeRange bool `json:"ignoreTimeRange"`
+	Excluded        bool `json:"excluded"`
+	ShowApplyAction bool `json:"showApplyAction"`
+}
+
+// dashboardListItem is a dashboard found by list and search.
+type dashboardListItem struct {
+	GUID      string `json:"guid"`
+	Name      string `json:"name"`
+	AccountID int    `json:"accountId"`
+	Permalink string `json:"permalink"`
+}
+
+// importResult is a dashboard created or updated by import.
+type importResult struct {
+	GUID   string `json:"guid"`
+	Name   string `json:"name"`
+	Action string `json:"action"`
+}
+
+type dashboardError struct {
+	Description string
+	Type        string
+}
+
+type dashboardErrors []dashboardError
+
+func (e dashboardErrors) err() error {
+	if len(e) == 0 {
+		return nil
+	}
+
+	messages := []string{}
+	for _, err := range e {
+		messages = append(messages, fmt.Sprintf("%s: %s", err.Type, err.Description))
+	}
+
+	return fmt.Errorf("%s", strings.Join(messages, ", "))
+}
+
+func entitySearchValue(v string) string {
+	return "'" + strings.NewReplacer("\\", "\\\\", "'", "\\'").Replace(v) + "'"
+}
+
+// searchDashboardsQuery pages through entity search results with their cursor,
+// which the typed entity search does not take.
+const searchDashboardsQuery = `query($query: String!, $cursor: String) {
+  actor {
+    entitySearch(query: $query) {
+      results(cursor: $cursor) {
+        nextCursor
+        entities {
+          guid
+          name
+          accountId
+          permalink
+          ... on DashboardEntityOutline { dashboardParentGuid }
+        }
+      }
+    }
+  }
+}`
+
+// searchDashboards returns the dashboards matching the entity search query,
+// leaving out their pages.
+func searchDashboards(ctx context.Context, client *newrelic.NewRelic, query string) ([]dashboardListItem, error) {
+	query = "type = 'DASHBOARD' AND " + query
+	dashboards := []dashboardListItem{}
+
+	var cursor interface{}
+	for {
+		var resp struct {
+			Actor struct {
+				EntitySearch struct {
+					Results struct {
+						NextCursor string `json:"nextCursor"`
+						Entities   []struct {
+							dashboardListItem
+							DashboardParentGUID string `json:"dashboardParentGuid"`
+						} `json:"entities"`
+					} `json:"results"`
+				} `json:"entitySearch"`
+			} `json:"actor"`
+		}
+
+		vars := map[string]interface{}{"query": query, "cursor": cursor}
+		if err := client.NerdGraph.QueryWithResponseAndContext(ctx, searchDashboardsQuery, vars, &resp); err != nil {
+			return nil, err
+		}
+
+		// Pages of dashboards are dashboard entities too.
+		for _, e := range resp.Actor.EntitySearch.Results.Entities {
+			if e.DashboardParentGUID == "" {
+				dashboards = append(dashboards, e.dashboardListItem)
+			}
+		}
+
+		if resp.Actor.EntitySearch.Results.NextCursor == "" {
+			return dashboards, nil
+		}
+		cursor = resp.Actor.EntitySearch.Results.NextCursor
+	}
+}
+
+// getDashboard returns the dashboard with the GUID, with its pages, widgets
+// and variables.
+func getDashboard(ctx context.Context, client *newrelic.NewRelic, guid string) (*dashboard, error) {
+	entity, err := client.Dashboards.GetDashboardEntityWithContext(ctx, common.EntityGUID(guid))
+	if err != nil {
+		return nil, err
+	}
+
+	if entity == nil || entity.GUID == "" {
+		return nil, fmt.Errorf("no dashboard found with GUID %s", guid)
+	}
+
+	// The JSON of the dashboard entity is the dashboard JSON, apart from the
+	// linked entities of the widgets.
+	b, err := json.Marshal(entity)
+	if err != nil {
+		return nil, err
+	}
+
+	var e struct {
+		dashboard
+		Pages []struct {
+			dashboardPage
+			Widgets []struct {
+				dashboardWidget
+				LinkedEntities []struct {
+					GUID string `json:"guid"`
+				} `json:"linkedEntities"`
+			} `json:"widgets"`
+		} `json:"pages"`
+	}
+	if err := json.Unmarshal(b, &e); err != nil {
+		return nil, err
+	}
+
+	d := e.dashboard
+	d.Pages = []dashboardPage{}
+	for _, p := range e.Pages {
+		page := p.dashboardPage
+		page.Widgets = []dashboardWidget{}
+		for _, w := range p.Widgets {
+			widget := w.dashboardWidget
+			for _, linked := range w.LinkedEntities {
+				widget.LinkedEntityGUIDs = append(widget.LinkedEntityGUIDs, linked.GUID)
+			}
+			page.Widgets = append(page.Widgets, widget)
+		}
+		d.Pages = append(d.Pages, page)
+	}
+
+	return &d, nil
+}
+
+// parseAccountMapping parses source=target pairs of account IDs.
+func parseAccountMapping(pairs []string) (map[int]int, error) {
+	mapping := map[int]int{}
+	for _, pair := range pairs {
+		parts := strings.SplitN(pair, "=", 2)
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("invalid account mapping %s, must be <sourceAccountId>=<targetAccountId>", pair)
+		}
+
+		source, err := strconv.Atoi(strings.TrimSpace(parts[0]))
+		if err != nil {
+			return nil, fmt.Errorf("invalid account mapping %s, must be <sourceAccountId>=<targetAccountId>", pair)
+		}
+		target, err := strconv.Atoi(strings.TrimSpace(parts[1]))
+		if err != nil {
+			return nil, fmt.Errorf("invalid account mapping %s, must be <sourceAccountId>=<targetAccountId>", pair)
+		}
+		mapping[source] = target
+	}
+
+	return mapping, nil
+}
+
+// nrqlAccountIDPattern matches the accounts named in NRQL, as in
+// accountId = 111 or accountId IN (111, 333).
+var nrqlAccountIDPattern = regexp.MustCompile(`(?i)\baccountIds?\s*(?:=|IN\s*\()\s*'?\d+'?(?:\s*,\s*'?\d+'?)*`)
+
+var numberPattern = regexp.MustCompile(`\d+`)
+
+// rewriteNRQLAccountIDs rewrites the accounts named in the NRQL query.
+func rewriteNRQLAccountIDs(query string, rewrite func(int) int) string {
+	return nrqlAccountIDPattern.ReplaceAllStringFunc(query, func(match string) string {
+		return numberPattern.ReplaceAllStringFunc(match, func(n string) string {
+			id, err := strconv.Atoi(n)
+			if err != nil {
+				return n
+			}
+			return strconv.Itoa(rewrite(id))
+		})
+	})
+}
+
+// rewriteAccountIDs rewrites the accounts queried by the widgets and the
+// variables of the dashboard, and the accounts named in their NRQL.  Accounts
+// found in mapping are replaced by the account they map to, and every account
+// is replaced by accountID when mapping is empty.
+func rewriteAccountIDs(d *dashboard, accountID int, mapping map[int]int) {
+	rewrite := func(id int) int {
+		if len(mapping) == 0 {
+			return accountID
+		}
+		if target, ok := mapping[id]; ok {
+			return target
+		}
+		return id
+	}
+
+	rewriteAll := func(ids []int) []int {
+		rewritten := []int{}
+		seen := map[int]bool{}
+		for _, id := range ids {
+			id = rewrite(id)
+			if !seen[id] {
+				rewritten = append(rewritten, id)
+				seen[id] = true
+			}
+		}
+		return rewritten
+	}
+
+	for _, p := range d.Pages {
+		for _, w := range p.Widgets {
+			queries, ok := w.RawConfiguration["nrqlQueries"].([]interface{})
+			if !ok {
+				continue
+			}
+
+			for _, q := range queries {
+				query, ok := q.(map[string]interface{})
+				if !ok {
+					continue
+				}
+
+				if id, ok := query["accountId"].(float64); ok {
+					query["accountId"] = rewrite(int(id))
+				}
+
+				if nrql, ok := query["query"].(string); ok {
+					query["query"] = rewriteNRQLAccountIDs(nrql, rewrite)
+				}
+
+				if values, ok := query["accountIds"].([]interface{}); ok {
+					ids := []int{}
+					for _, v := range values {
+						if id, ok := v.(float64); ok {
+							ids = append(ids, int(id))
+						}
+					}
+					query["accountIds"] = rewriteAll(ids)
+				}
+			}
+		}
+	}
+
+	for _, v := range d.Variables {
+		if v.NRQLQuery != nil {
+			v.NRQLQuery.AccountIDs = rewriteAll(v.NRQLQuery.AccountIDs)
+			v.NRQLQuery.Query = rewriteNRQLAccountIDs(v.NRQLQuery.Query, rewrite)
+		}
+	}
+}
+
+// importDashboard creates the dashboard in the account, or updates the
+// dashboard with the GUID.  Without a GUID, the dashboard of the account with
+// the same name is updated when there is one.
+func importDashboard(ctx context.Context, client *newrelic.NewRelic, d dashboard, accountID int, guid string) (*importResult, error) {
+	if guid == "" {
+		query := fmt.Sprintf("accountId = '%d' AND name = %s", accountID, entitySearchValue(d.Name))
+		found, err := searchDashboards(ctx, client, query)
+		if err != nil {
+			return nil, err
+		}
+
+		// Entity search matches names loosely.
+		matches := []dashboardListItem{}
+		for _, f := range found {
+			if f.Name == d.Name {
+				matches = append(matches, f)
+			}
+		}
+
+		if len(matches) > 1 {
+			return nil, fmt.Errorf("found %d dashboards named %s in account %d, use --guid to select the dashboard to update", len(matches), d.Name, accountID)
+		}
+		if len(matches) == 1 {
+			guid = matches[0].GUID
+		}
+	}
+
+	// Facet links to the pages of the dashboard, or to the dashboard itself,
+	// are links to the GUIDs of the source dashboard.  They are removed from
+	// the input and restored with the GUIDs of the imported dashboard once it
+	// is created or updated.
+	sourceGUIDs := map[string]int{}
+	if d.GUID != "" {
+		sourceGUIDs[d.GUID] = -1
+	}
+	for i, p := range d.Pages {
+		if p.GUID != "" {
+			sourceGUIDs[p.GUID] = i
+		}
+	}
+
+	input := d
+	input.GUID = ""
+	input.Pages = []dashboardPage{}
+	selfLinks := map[[2]int][]int{}
+	for i, p := range d.Pages {
+		page := p
+		page.GUID = ""
+		page.Widgets = []dashboardWidget{}
+		for j, w := range p.Widgets {
+			widget := w
+			widget.LinkedEntityGUIDs = nil
+			for _, linked := range w.LinkedEntityGUIDs {
+				if source, ok := sourceGUIDs[linked]; ok {
+					selfLinks[[2]int{i, j}] = append(selfLinks[[2]int{i, j}], source)
+					continue
+				}
+				widget.LinkedEntityGUIDs = append(widget.LinkedEntityGUIDs, linked)
+			}
+			page.Widgets = append(page.Widgets, widget)
+		}
+		input.Pages = append(input.Pages, page)
+	}
+
+	action := "updated"
+	if guid == "" {
+		action = "created"
+	} else {
+		// Pages updated with their GUID keep their GUID, and the links to them.
+		target, err := getDashboard(ctx, client, guid)
+		if err != nil {
+			return nil, err
+		}
+		for i := range input.Pages {
+			if i < len(target.Pages) {
+				input.Pages[i].GUID = target.Pages[i].GUID
+			}
+		}
+	}
+
+	result, err := mutateDashboard(ctx, client, accountID, guid, input)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(selfLinks) > 0 {
+		for i := range input.Pages {
+			if i < len(result.Pages) {
+				input.Pages[i].GUID = result.Pages[i]
+			}
+		}
+
+		for widget, sources := range selfLinks {
+			w := &input.Pages[widget[0]].Widgets[widget[1]]
+			for _, source := range sources {
+				if source < 0 {
+					w.LinkedEntityGUIDs = append(w.LinkedEntityGUIDs, result.GUID)
+				} else if source < len(result.Pages) {
+					w.LinkedEntityGUIDs = append(w.LinkedEntityGUIDs, result.Pages[source])
+				}
+			}
+		}
+
+		if result, err = mutateDashboard(ctx, client, accountID, result.GUID, input); err != nil {
+			return nil, err
+		}
+	}
+
+	return &importResult{GUID: result.GUID, Name: result.Name, Action: action}, nil
+}
+
+type dashboardResult struct {
+	GUID  string
+	Name  string
+	Pages []string
+}
+
+// mutateDashboard creates the dashboard in the account, or updates the
+// dashboard with the GUID.
+func mutateDashboard(ctx context.Context, client *newrelic.NewRelic, accountID int, guid string, d dashboard) (*dashboardResult, error) {
+	// The dashboard JSON is the JSON of the dashboard input.
+	b, err := json.Marshal(d)
+	if err != nil {
+		return nil, err
+	}
+
+	var input dashboards.DashboardInput
+	if err := json.Unmarshal(b, &input); err != nil {
+		return nil, err
+	}
+
+	var entity dashboards.DashboardEntityResult
+	errs := dashboardErrors{}
+
+	if guid == "" {
+		result, err := client.Dashboards.DashboardCreateWithContext(ctx, accountID, input)
+		if err != nil {
+			return nil, err
+		}
+
+		entity = result.EntityResult
+		for _, e := range result.Errors {
+			errs = append(errs, dashboardError{Description: e.Description, Type: string(e.Type)})
+		}
+	} else {
+		result, err := client.Dashboards.DashboardUpdateWithContext(ctx, input, common.EntityGUID(guid))
+		if err != nil {
+			return nil, err
+		}
+
+		entity = result.EntityResult
+		for _, e := range result.Errors {
+			errs = append(errs, dashboardError{Description: e.Description, Type: string(e.Type)})
+		}
+	}
+
+	if err := errs.err(); err != nil {
+		return nil, err
+	}
+
+	result := &dashboardResult{GUID: string(entity.GUID), Name: entity.Name}
+	for _, p := range entity.Pages {
+		result.Pages = append(result.Pages, string(p.GUID))
+	}
+
+	return result, nil
+}
@@ -0,0 +1,244 @@
+//go:build unit
+
+package dashboard
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/newrelic/newrelic-cli/internal/utils"
+)
+
+const exportedDashboard = `{
+  "guid": "DASHBOARD-GUID",
+  "name": "Checkout",
+  "permissions": "PUBLIC_READ_ONLY",
+  "pages": [
+    {
+      "guid": "PAGE-GUID",
+      "name": "Overview",
+      "widgets": [
+        {
+          "title": "Errors by host",
+          "layout": { "column": 1, "row": 1, "width": 4, "height": 3 },
+          "linkedEntityGuids": ["PAGE-GUID", "OTHER-DASHBOARD-GUID"],
+          "visualization": { "id": "viz.bar" },
+          "rawConfiguration": {
+            "nrqlQueries": [
+              { "accountIds": [111, 333], "query": "SELECT count(*) FROM TransactionError FACET host" },
+              { "accountId": 111, "query": "SELECT count(*) FROM Transaction WHERE accountId = 111 FACET host" }
+            ]
+          }
+        }
+      ]
+    }
+  ],
+  "variables": [
+    {
+      "name": "host",
+      "type": "NRQL",
+      "isMultiSelection": false,
+      "nrqlQuery": { "accountIds": [111], "query": "SELECT uniques(host) FROM Transaction WHERE accountId IN (111, 333)" }
+    }
+  ]
+}`
+
+func parseDashboard(t *testing.T, s string) dashboard {
+	var d dashboard
+	require.NoError(t, json.Unmarshal([]byte(s), &d))
+	return d
+}
+
+func TestGetDashboardShouldExportLinkedEntities(t *testing.T) {
+	server := utils.NewMockNerdGraphServer(map[string]string{
+		"entity(guid": `{"actor": {"entity": {
+			"guid": "DASHBOARD-GUID", "name": "Checkout", "permissions": "PRIVATE",
+			"pages": [{"guid": "PAGE-GUID", "name": "Overview", "widgets": [{
+				"title": "Hosts", "layout": {"column": 1, "row": 1, "width": 4, "height": 3},
+				"visualization": {"id": "viz.table"}, "linkedEntities": [{"__typename": "DashboardEntityOutline", "guid": "PAGE-GUID"}],
+				"rawConfiguration": {"nrqlQueries": [{"accountIds": [111], "query": "SELECT 1"}]}
+			}]}],
+			"variables": []
+		}}}`,
+	})
+	defer server.Close()
+
+	d, err := getDashboard(context.Background(), server.Client(), "DASHBOARD-GUID")
+	require.NoError(t, err)
+	assert.Equal(t, "Checkout", d.Name)
+	require.Len(t, d.Pages, 1)
+	require.Len(t, d.Pages[0].Widgets, 1)
+	assert.Equal(t, []string{"PAGE-GUID"}, d.Pages[0].Widgets[0].LinkedEntityGUIDs)
+	assert.Equal(t, "viz.table", d.Pages[0].Widgets[0].Visualization.ID)
+
+	b, err := json.Marshal(d)
+	require.NoError(t, err)
+	assert.Contains(t, string(b), `"linkedEntityGuids":["PAGE-GUID"]`)
+	assert.NotContains(t, string(b), "linkedEntities")
+}
+
+func TestGetDashboardShouldFailWhenNotFound(t *testing.T) {
+	server := utils.NewMockNerdGraphServer(map[string]string{
+		"entity(guid": `{"actor": {"entity": null}}`,
+	})
+	defer server.Close()
+
+	_, err := getDashboard(context.Background(), server.Client(), "MISSING")
+	assert.Error(t, err)
+}
+
+func TestSearchDashboardsShouldSkipPages(t *testing.T) {
+	server := utils.NewMockNerdGraphServer(nil)
+	server.AddResponses("entitySearch",
+		`{"actor": {"entitySearch": {"results": {"nextCursor": "next", "entities": [
+			{"__typename": "DashboardEntityOutline", "guid": "DASHBOARD-GUID", "name": "Checkout", "accountId": 111},
+			{"__typename": "DashboardEntityOutline", "guid": "PAGE-GUID", "name": "Overview", "accountId": 111, "dashboardParentGuid": "DASHBOARD-GUID"}
+		]}}}}`,
+		`{"actor": {"entitySearch": {"results": {"entities": [
+			{"__typename": "DashboardEntityOutline", "guid": "OTHER-GUID", "name": "Checkout v2", "accountId": 111}
+		]}}}}`,
+	)
+	defer server.Close()
+
+	results, err := searchDashboards(context.Background(), server.Client(), "name LIKE 'Checkout'")
+	require.NoError(t, err)
+	require.Len(t, results, 2)
+	assert.Equal(t, "DASHBOARD-GUID", results[0].GUID)
+	assert.Equal(t, 111, results[0].AccountID)
+	assert.Equal(t, "OTHER-GUID", results[1].GUID)
+
+	require.Len(t, server.Requests, 2)
+	assert.Contains(t, server.Requests[0].Variables["query"], "type = 'DASHBOARD' AND name LIKE 'Checkout'")
+	assert.Nil(t, server.Requests[0].Variables["cursor"])
+	assert.Equal(t, "next", server.Requests[1].Variables["cursor"])
+}
+
+func TestEntitySearchValueShouldEscapeQuotes(t *testing.T) {
+	assert.Equal(t, `'Ops\\ team\'s'`, entitySearchValue(`Ops\ team's`))
+}
+
+func TestParseAccountMapping(t *testing.T) {
+	mapping, err := parseAccountMapping([]string{"111=222", " 333 = 444 "})
+	require.NoError(t, err)
+	assert.Equal(t, map[int]int{111: 222, 333: 444}, mapping)
+
+	_, err = parseAccountMapping([]string{"111"})
+	assert.Error(t, err)
+
+	_, err = parseAccountMapping([]string{"111=prod"})
+	assert.Error(t, err)
+}
+
+func TestRewriteAccountIDsShouldReplaceEveryAccount(t *testing.T) {
+	d := parseDashboard(t, exportedDashboard)
+
+	rewriteAccountIDs(&d, 999, map[int]int{})
+
+	queries := d.Pages[0].Widgets[0].RawConfiguration["nrqlQueries"].([]interface{})
+	assert.Equal(t, []int{999}, queries[0].(map[string]interface{})["accountIds"])
+	assert.Equal(t, 999, queries[1].(map[string]interface{})["accountId"])
+	assert.Equal(t, "SELECT count(*) FROM Transaction WHERE accountId = 999 FACET host", queries[1].(map[string]interface{})["query"])
+	assert.Equal(t, []int{999}, d.Variables[0].NRQLQuery.AccountIDs)
+	assert.Equal(t, "SELECT uniques(host) FROM Transaction WHERE accountId IN (999, 999)", d.Variables[0].NRQLQuery.Query)
+}
+
+func TestRewriteAccountIDsShouldReplaceMappedAccounts(t *testing.T) {
+	d := parseDashboard(t, exportedDashboard)
+
+	rewriteAccountIDs(&d, 999, map[int]int{111: 222})
+
+	queries := d.Pages[0].Widgets[0].RawConfiguration["nrqlQueries"].([]interface{})
+	assert.Equal(t, []int{222, 333}, queries[0].(map[string]interface{})["accountIds"])
+	assert.Equal(t, 222, queries[1].(map[string]interface{})["accountId"])
+	assert.Equal(t, "SELECT count(*) FROM Transaction WHERE accountId = 222 FACET host", queries[1].(map[string]interface{})["query"])
+	assert.Equal(t, []int{222}, d.Variables[0].NRQLQuery.AccountIDs)
+	assert.Equal(t, "SELECT uniques(host) FROM Transaction WHERE accountId IN (222, 333)", d.Variables[0].NRQLQuery.Query)
+}
+
+func TestImportDashboardShouldCreateAndRelinkPages(t *testing.T) {
+	server := utils.NewMockNerdGraphServer(map[string]string{
+		"entitySearch": `{"actor": {"entitySearch": {"results": {"entities": [
+			{"__typename": "DashboardEntityOutline", "guid": "OTHER-GUID", "name": "Checkout v2", "accountId": 999}
+		]}}}}`,
+		"dashboardCreate": `{"dashboardCreate": {"entityResult": {"guid": "NEW-GUID", "name": "Checkout", "pages": [{"guid": "NEW-PAGE-GUID"}]}}}`,
+		"dashboardUpdate": `{"dashboardUpdate": {"entityResult": {"guid": "NEW-GUID", "name": "Checkout", "pages": [{"guid": "NEW-PAGE-GUID"}]}}}`,
+	})
+	defer server.Close()
+
+	result, err := importDashboard(context.Background(), server.Client(), parseDashboard(t, exportedDashboard), 999, "")
+	require.NoError(t, err)
+	assert.Equal(t, &importResult{GUID: "NEW-GUID", Name: "Checkout", Action: "created"}, result)
+
+	mutations := server.Mutations()
+	require.Len(t, mutations, 2)
+
+	created := mutations[0].Variables
+	assert.Equal(t, float64(999), created["accountId"])
+	input := created["dashboard"].(map[string]interface{})
+	assert.NotContains(t, input, "guid")
+	page := input["pages"].([]interface{})[0].(map[string]interface{})
+	assert.NotContains(t, page, "guid")
+	widget := page["widgets"].([]interface{})[0].(map[string]interface{})
+	assert.Equal(t, []interface{}{"OTHER-DASHBOARD-GUID"}, widget["linkedEntityGuids"])
+
+	updated := mutations[1].Variables
+	assert.Equal(t, "NEW-GUID", updated["guid"])
+	page = updated["dashboard"].(map[string]interface{})["pages"].([]interface{})[0].(map[string]interface{})
+	assert.Equal(t, "NEW-PAGE-GUID", page["guid"])
+	widget = page["widgets"].([]interface{})[0].(map[string]interface{})
+	assert.Equal(t, []interface{}{"OTHER-DASHBOARD-GUID", "NEW-PAGE-GUID"}, widget["linkedEntityGuids"])
+}
+
+func TestImportDashboardShouldUpdateDashboardWithSameName(t *testing.T) {
+	d := parseDashboard(t, exportedDashboard)
+	d.Pages[0].Widgets[0].LinkedEntityGUIDs = nil
+
+	server := utils.NewMockNerdGraphServer(map[string]string{
+		"entitySearch": `{"actor": {"entitySearch": {"results": {"entities": [
+			{"__typename": "DashboardEntityOutline", "guid": "PROD-GUID", "name": "Checkout", "accountId": 999}
+		]}}}}`,
+		"entity(guid":     `{"actor": {"entity": {"guid": "PROD-GUID", "name": "Checkout", "pages": [{"guid": "PROD-PAGE-GUID", "name": "Overview", "widgets": []}]}}}`,
+		"dashboardUpdate": `{"dashboardUpdate": {"entityResult": {"guid": "PROD-GUID", "name": "Checkout", "pages": [{"guid": "PROD-PAGE-GUID"}]}}}`,
+	})
+	defer server.Close()
+
+	result, err := importDashboard(context.Background(), server.Client(), d, 999, "")
+	require.NoError(t, err)
+	assert.Equal(t, &importResult{GUID: "PROD-GUID", Name: "Checkout", Action: "updated"}, result)
+
+	mutations := server.Mutations()
+	require.Len(t, mutations, 1)
+	assert.Equal(t, "PROD-GUID", mutations[0].Variables["guid"])
+	page := mutations[0].Variables["dashboard"].(map[string]interface{})["pages"].([]interface{})[0].(map[string]interface{})
+	assert.Equal(t, "PROD-PAGE-GUID", page["guid"])
+}
+
+func TestImportDashboardShouldFailOnAmbiguousName(t *testing.T) {
+	server := utils.NewMockNerdGraphServer(map[string]string{
+		"entitySearch": `{"actor": {"entitySearch": {"results": {"entities": [
+			{"__typename": "DashboardEntityOutline", "guid": "PROD-GUID", "name": "Checkout", "accountId": 999},
+			{"__typename": "DashboardEntityOutline", "guid": "COPY-GUID", "name": "Checkout", "accountId": 999}
+		]}}}}`,
+	})
+	defer server.Close()
+
+	_, err := importDashboard(context.Background(), server.Client(), parseDashboard(t, exportedDashboard), 999, "")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "--guid")
+	assert.Empty(t, server.Mutations())
+}
+
+func TestImportDashboardShouldReturnMutationErrors(t *testing.T) {
+	server := utils.NewMockNerdGraphServer(map[string]string{
+		"dashboardCreate": `{"dashboardCreate": {"errors": [{"description": "invalid widget", "type": "INVALID_INPUT"}]}}`,
+	})
+	defer server.Close()
+
+	_, err := importDashboard(context.Background(), server.Client(), parseDashboard(t, exportedDashboard), 999, "")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "INVALID_INPUT: invalid widget")
+}
@@ -58,6 +58,12 @@ func init() {
 	utils.LogIfError(cmdPost.MarkFlagRequired("event"))
 
 	Command.AddCommand(cmdPostFile)
-	cmdPostFile.Flags().StringVarP(&file, "file", "f", "file.json", "a JSON-formatted events file to post")
+	cmdPostFile.Flags().StringVarP(&file, "file", "f", "file.json", "a JSON array or NDJSON events file to post")
 	utils.LogIfError(cmdPostFile.MarkFlagFilename("file"))
+	cmdPostFile.Flags().StringVar(&fileFormat, "format", formatAuto, "the format of the events file: auto, json, or ndjson")
+	cmdPostFile.Flags().IntVar(&batchBytes, "batchBytes", maxPayloadBytes, "the maximum size of each batch of events in bytes")
+	cmdPostFile.Flags().IntVar(&parallelism, "parallelism", 4, "the number of batches to send at once")
+	cmdPostFile.Flags().IntVar(&retries, "retries", 3, "the number of times to retry a batch that could not be sent")
+	cmdPostFile.Flags().BoolVar(&useGzip, "gzip", true, "gzip compress each batch")
+	cmdPostFile.Flags().StringVar(&checkpointFile, "checkpoint", "", "a file used to record progress so that an interrupted upload can be resumed")
 }
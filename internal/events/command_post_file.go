@@ -1,9 +1,13 @@
 package events
 
 import (
+	"context"
 	"encoding/json"
-	"io/ioutil"
+	"fmt"
+	"io"
 	"os"
+	"path/filepath"
+	"sync"
 
 	log "github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
@@ -11,15 +15,61 @@ import (
 	"github.com/newrelic/newrelic-cli/internal/client"
 	"github.com/newrelic/newrelic-cli/internal/config"
 	configAPI "github.com/newrelic/newrelic-cli/internal/config/api"
+	"github.com/newrelic/newrelic-cli/internal/output"
 	"github.com/newrelic/newrelic-cli/internal/utils"
+	nrConfig "github.com/newrelic/newrelic-client-go/v2/pkg/config"
+	nrEvents "github.com/newrelic/newrelic-client-go/v2/pkg/events"
+	"github.com/newrelic/newrelic-client-go/v2/pkg/region"
 )
 
+// maxPayloadBytes is the Event API limit on the size of a single request.
+const maxPayloadBytes = 1000000
+
+// maxReportedRejections caps the number of rejected events listed in the summary.
+const maxReportedRejections = 100
+
 var (
-	file string
+	file           string
+	fileFormat     string
+	batchBytes     int
+	parallelism    int
+	retries        int
+	useGzip        bool
+	checkpointFile string
+
+	retryDelayMs = 2000
 )
 
-type jsonElement map[string]interface{}
-type jsonArray []jsonElement
+// sendFunc posts a JSON array of events to the Event API.
+type sendFunc func(ctx context.Context, payload json.RawMessage) error
+
+type postFileOptions struct {
+	format      string
+	batchBytes  int
+	parallelism int
+	retries     int
+	skip        int
+	onProgress  func(processed int)
+}
+
+// rejectedEvent describes an event that was not sent because it was invalid.
+type rejectedEvent struct {
+	Position int    `json:"position"`
+	Reason   string `json:"reason"`
+}
+
+// postFileSummary reports the outcome of posting a file of events.
+type postFileSummary struct {
+	EventsRead     int             `json:"eventsRead"`
+	EventsSkipped  int             `json:"eventsSkipped"`
+	EventsSent     int             `json:"eventsSent"`
+	EventsRejected int             `json:"eventsRejected"`
+	EventsFailed   int             `json:"eventsFailed"`
+	Batches        int             `json:"batches"`
+	FailedBatches  int             `json:"failedBatches"`
+	Rejections     []rejectedEvent `json:"rejections,omitempty"`
+	Errors         []string        `json:"errors,omitempty"`
+}
 
 var cmdPostFile = &cobra.Command{
 	Use:   "postFile",
@@ -31,30 +81,92 @@ custom events to be posted to New Relic. These events once posted can be queried
 using NRQL via the CLI or New Relic One UI.
 The accepted payload requires the use of an ` + "`eventType`" + `field that
 represents the custom event's type.
+
+The file may be a JSON array of events or newline-delimited JSON (NDJSON) with one
+event per line.  The file is streamed rather than read into memory, so files of any
+size can be posted.  Events are grouped into batches no larger than --batchBytes and
+sent by --parallelism concurrent senders, to the region of the active profile,
+gzip compressed unless --gzip=false is given.  Failed batches are retried
+--retries times.  Events that are not JSON objects
+with an eventType are rejected and reported in the summary.
+
+With --checkpoint, progress is recorded in the given file as batches are sent.
+Running the same command again resumes after the last event recorded as sent.
+Events following a batch that could not be sent may be sent again on resume.
 `,
-	Example: `newrelic events postFile --accountId 12345 --file events.json'`,
-	PreRun:  client.RequireClient,
+	Example: `newrelic events postFile --accountId 12345 --file events.json
+newrelic events postFile --accountId 12345 --file backfill.ndjson --parallelism 8 --checkpoint backfill.checkpoint`,
+	PreRun: client.RequireClient,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		accountID := configAPI.RequireActiveProfileAccountID()
 
-		if configAPI.GetActiveProfileString(config.LicenseKey) == "" {
+		licenseKey := configAPI.GetActiveProfileString(config.LicenseKey)
+		if licenseKey == "" {
 			log.Fatal("a License key is required, set one in your default profile or use the NEW_RELIC_LICENSE_KEY environment variable")
 		}
 
+		reg, err := activeRegion()
+		if err != nil {
+			return err
+		}
+
+		if batchBytes < 1 || batchBytes > maxPayloadBytes {
+			return fmt.Errorf("--batchBytes must be between 1 and %d", maxPayloadBytes)
+		}
+
 		jsonFile, err := os.Open(file)
 		if err != nil {
 			return err
 		}
 		defer jsonFile.Close()
 
-		bytes, _ := ioutil.ReadAll(jsonFile)
-		data := getArray(bytes)
-		slices := sliceBy(data, 10)
-		for k, slice := range slices {
-			log.Debugf("Sending batch %d with %d items", k, len(slice))
-			if err := client.NRClient.Events.CreateEventWithContext(utils.SignalCtx, accountID, slice); err != nil {
+		opts := postFileOptions{
+			format:      fileFormat,
+			batchBytes:  batchBytes,
+			parallelism: parallelism,
+			retries:     retries,
+		}
+
+		if checkpointFile != "" {
+			source, err := filepath.Abs(file)
+			if err != nil {
 				return err
 			}
+
+			if opts.skip, err = readCheckpoint(checkpointFile, source); err != nil {
+				return err
+			}
+
+			if opts.skip > 0 {
+				log.Infof("resuming after %d events recorded in %s", opts.skip, checkpointFile)
+			}
+
+			opts.onProgress = func(processed int) {
+				if err := writeCheckpoint(checkpointFile, source, processed); err != nil {
+					log.Warnf("could not write checkpoint file: %s", err)
+				}
+			}
+		}
+
+		eventsClient, err := newEventsClient(licenseKey, reg, useGzip)
+		if err != nil {
+			return err
+		}
+
+		send := func(ctx context.Context, payload json.RawMessage) error {
+			return eventsClient.CreateEventWithContext(ctx, accountID, payload)
+		}
+
+		summary, err := postEvents(utils.SignalCtx, jsonFile, opts, send)
+		if summary != nil {
+			utils.LogIfError(output.Print(summary))
+		}
+		if err != nil {
+			return err
+		}
+
+		if summary.FailedBatches > 0 {
+			return fmt.Errorf("%d of %d batches could not be sent", summary.FailedBatches, summary.Batches)
 		}
 
 		log.Info("success")
@@ -62,32 +174,198 @@ represents the custom event's type.
 	},
 }
 
-func getArray(bytes []byte) *jsonArray {
-	var data jsonArray
-	err := json.Unmarshal(bytes, &data)
-	if err != nil {
-		log.Errorf("json file must be composed of an array of event data, details:%s", err)
+// activeRegion returns the region of the active profile, or the default
+// region when none is set.  An unknown region is an error rather than letting
+// the events go to the default region.
+func activeRegion() (*region.Region, error) {
+	regName := region.Default
+
+	if name := configAPI.GetActiveProfileString(config.Region); name != "" {
+		parsed, err := region.Parse(name)
+		if err != nil {
+			return nil, fmt.Errorf("invalid region %s in the active profile: %s", name, err)
+		}
+		regName = parsed
+	}
+
+	return region.Get(regName)
+}
+
+// newEventsClient returns an Event API client for the region.  The configured
+// client does not compress its requests, so batches get a client of their own
+// that gzip compresses them when compress is set.
+func newEventsClient(licenseKey string, reg *region.Region, compress bool) (*nrEvents.Events, error) {
+	cfg := nrConfig.Config{
+		InsightsInsertKey: licenseKey,
+		LicenseKey:        licenseKey,
+		Compression:       nrConfig.Compression.None,
+	}
+
+	if compress {
+		cfg.Compression = nrConfig.Compression.Gzip
+	}
+
+	if err := cfg.SetRegion(reg); err != nil {
+		return nil, err
 	}
-	return &data
+
+	eventsClient := nrEvents.New(cfg)
+	return &eventsClient, nil
+}
+
+type batchResult struct {
+	batch *eventBatch
+	err   error
 }
 
-func sliceBy(data *jsonArray, size int) []jsonArray {
-	var result []jsonArray
-	var single jsonArray
-	result = make([]jsonArray, 0)
-	count := 0
-	single = make(jsonArray, 0)
-	for _, e := range *data {
-		count++
-		single = append(single, e)
-		if count == size {
-			result = append(result, single)
-			single = make(jsonArray, 0)
-			count = 0
+// postEvents streams events from r and sends them in batches.  A summary is
+// returned even when an error stops the upload part way through.
+func postEvents(ctx context.Context, r io.Reader, opts postFileOptions, send sendFunc) (*postFileSummary, error) {
+	reader, err := newEventReader(r, opts.format)
+	if err != nil {
+		return nil, err
+	}
+
+	if opts.parallelism < 1 {
+		opts.parallelism = 1
+	}
+
+	summary := &postFileSummary{}
+	batches := make(chan *eventBatch, opts.parallelism)
+	results := make(chan batchResult, opts.parallelism)
+
+	var senders sync.WaitGroup
+	for i := 0; i < opts.parallelism; i++ {
+		senders.Add(1)
+		go func() {
+			defer senders.Done()
+			for b := range batches {
+				results <- batchResult{batch: b, err: sendWithRetries(ctx, b, opts.retries, send)}
+			}
+		}()
+	}
+
+	// The collector is the only writer of the sent and failed counts.
+	tracker := newProgressTracker(opts.skip)
+	collected := make(chan struct{})
+	go func() {
+		defer close(collected)
+		for res := range results {
+			if res.err != nil {
+				log.Errorf("batch %d with %d events could not be sent: %s", res.batch.seq, len(res.batch.events), res.err)
+				summary.FailedBatches++
+				summary.EventsFailed += len(res.batch.events)
+				summary.Errors = append(summary.Errors, fmt.Sprintf("batch %d: %s", res.batch.seq, res.err))
+			} else {
+				log.Debugf("sent batch %d with %d items", res.batch.seq, len(res.batch.events))
+				summary.EventsSent += len(res.batch.events)
+			}
+
+			processed := tracker.complete(res.batch, res.err == nil)
+			if opts.onProgress != nil {
+				opts.onProgress(processed)
+			}
+		}
+	}()
+
+	position := 0
+	current := &eventBatch{}
+	dispatch := func() {
+		if len(current.events) == 0 {
+			return
 		}
+
+		current.seq = summary.Batches
+		summary.Batches++
+		batches <- current
+		current = &eventBatch{}
 	}
-	if count > 0 {
-		result = append(result, single)
+
+	reject := func(reason string) {
+		summary.EventsRejected++
+		if len(summary.Rejections) < maxReportedRejections {
+			summary.Rejections = append(summary.Rejections, rejectedEvent{Position: position, Reason: reason})
+		}
 	}
-	return result
+
+	var readErr error
+	for ctx.Err() == nil {
+		event, err := reader.Next()
+		if err == io.EOF {
+			break
+		}
+
+		if _, ok := err.(*invalidEventError); ok {
+			position++
+			if position > opts.skip {
+				summary.EventsRead++
+				reject(err.Error())
+			}
+			continue
+		}
+
+		if err != nil {
+			readErr = fmt.Errorf("could not read event %d: %s", position+1, err)
+			break
+		}
+
+		position++
+		if position <= opts.skip {
+			summary.EventsSkipped++
+			continue
+		}
+		summary.EventsRead++
+
+		if len(event)+2 > opts.batchBytes {
+			reject(fmt.Sprintf("event is larger than the maximum batch size of %d bytes", opts.batchBytes))
+			continue
+		}
+
+		if !current.add(event, opts.batchBytes) {
+			current.end = position - 1
+			dispatch()
+			current.add(event, opts.batchBytes)
+		}
+	}
+
+	if readErr == nil && ctx.Err() == nil {
+		current.end = position
+		dispatch()
+	}
+
+	close(batches)
+	senders.Wait()
+	close(results)
+	<-collected
+
+	if readErr != nil {
+		return summary, readErr
+	}
+
+	if err := ctx.Err(); err != nil {
+		return summary, err
+	}
+
+	// Rejected events after the last batch are also complete once every batch
+	// has been sent.
+	if summary.FailedBatches == 0 && opts.onProgress != nil && position > tracker.processed {
+		opts.onProgress(position)
+	}
+
+	return summary, nil
+}
+
+func sendWithRetries(ctx context.Context, b *eventBatch, retries int, send sendFunc) error {
+	payload := b.payload()
+
+	retry := utils.NewRetry(retries+1, retryDelayMs, func() error {
+		return send(ctx, payload)
+	})
+
+	retryCtx := retry.ExecWithRetries(ctx)
+	if !retryCtx.Success {
+		return retryCtx.MostRecentError()
+	}
+
+	return nil
 }
@@ -1,11 +1,24 @@
 package events
 
 import (
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"sync"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
+	"github.com/newrelic/newrelic-cli/internal/config"
 	"github.com/newrelic/newrelic-cli/internal/testcobra"
+	"github.com/newrelic/newrelic-client-go/v2/pkg/region"
 )
 
 func TestPostFile(t *testing.T) {
@@ -15,29 +28,204 @@ func TestPostFile(t *testing.T) {
 	testcobra.CheckCobraRequiredFlags(t, cmdPostFile, []string{})
 }
 
-func TestShouldGetJSONArray(t *testing.T) {
-	input := `[{"key1":"value1"},{"key2":"value2"}]`
-	array := getArray([]byte(input))
-	assert.Equal(t, 2, len(*array))
+func readAll(t *testing.T, input string, format string) ([]string, []string) {
+	reader, err := newEventReader(strings.NewReader(input), format)
+	require.NoError(t, err)
+
+	var events, invalid []string
+	for {
+		e, err := reader.Next()
+		if err != nil {
+			if _, ok := err.(*invalidEventError); ok {
+				invalid = append(invalid, err.Error())
+				continue
+			}
+			require.Equal(t, "EOF", err.Error())
+			break
+		}
+		events = append(events, string(e))
+	}
+
+	return events, invalid
 }
 
-func TestShouldNotGetArrayWhenJSON(t *testing.T) {
-	input := `{"some":"json"}`
-	array := getArray([]byte(input))
-	assert.Equal(t, 0, len(*array))
+func TestShouldReadJSONArray(t *testing.T) {
+	input := ` [{"eventType":"A", "key1":"value1"},
+	{"eventType":"B"}, {"noType":true}, 5]`
+
+	events, invalid := readAll(t, input, formatAuto)
+	assert.Equal(t, []string{`{"eventType":"A","key1":"value1"}`, `{"eventType":"B"}`}, events)
+	assert.Equal(t, 2, len(invalid))
 }
 
-func TestShouldSliceBy(t *testing.T) {
-	input := `[{"key1":"value1"},{"key2":"value2"},{"key3":"value3"},{"key4":"value5"},{"key5":"value5"}]`
-	array := getArray([]byte(input))
-	sliced := sliceBy(array, 3)
-	assert.Equal(t, 3, len(sliced[0]))
-	assert.Equal(t, 2, len(sliced[1]))
+func TestShouldReadNDJSON(t *testing.T) {
+	input := "{\"eventType\":\"A\"}\n\n{\"eventType\":\"B\"}\nnot json\n{\"eventType\":\"C\"}"
+
+	events, invalid := readAll(t, input, formatAuto)
+	assert.Equal(t, 3, len(events))
+	assert.Equal(t, 1, len(invalid))
+	assert.True(t, strings.HasPrefix(invalid[0], "line 4:"))
 }
 
-func TestShouldSliceByMax(t *testing.T) {
-	input := `[{"key1":"value1"},{"key2":"value2"},{"key3":"value3"},{"key4":"value5"},{"key5":"value5"}]`
-	array := getArray([]byte(input))
-	sliced := sliceBy(array, 5)
-	assert.Equal(t, 5, len(sliced[0]))
+func TestShouldNotReadJSONObject(t *testing.T) {
+	reader, err := newEventReader(strings.NewReader(`{"some":"json"}`), formatJSON)
+	require.NoError(t, err)
+
+	_, err = reader.Next()
+	assert.Error(t, err)
+}
+
+func TestShouldBatchByBytes(t *testing.T) {
+	b := &eventBatch{}
+	event := json.RawMessage(`{"eventType":"A"}`)
+
+	assert.True(t, b.add(event, 40))
+	assert.True(t, b.add(event, 40))
+	assert.False(t, b.add(event, 40))
+	assert.Equal(t, len(b.payload()), b.size)
+	assert.True(t, json.Valid(b.payload()))
+}
+
+func TestProgressTracker(t *testing.T) {
+	p := newProgressTracker(0)
+
+	assert.Equal(t, 0, p.complete(&eventBatch{seq: 1, end: 20}, true))
+	assert.Equal(t, 20, p.complete(&eventBatch{seq: 0, end: 10}, true))
+	assert.Equal(t, 20, p.complete(&eventBatch{seq: 2, end: 30}, false))
+	assert.Equal(t, 20, p.complete(&eventBatch{seq: 3, end: 40}, true))
+}
+
+func TestCheckpoint(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "checkpoint")
+
+	processed, err := readCheckpoint(path, "/data/events.ndjson")
+	require.NoError(t, err)
+	assert.Equal(t, 0, processed)
+
+	require.NoError(t, writeCheckpoint(path, "/data/events.ndjson", 42))
+
+	processed, err = readCheckpoint(path, "/data/events.ndjson")
+	require.NoError(t, err)
+	assert.Equal(t, 42, processed)
+
+	_, err = readCheckpoint(path, "/data/other.ndjson")
+	assert.Error(t, err)
+}
+
+func TestPostEvents(t *testing.T) {
+	retryDelayMs = 1
+
+	var lines []string
+	for i := 0; i < 100; i++ {
+		lines = append(lines, fmt.Sprintf(`{"eventType":"Test","n":%d}`, i))
+	}
+	lines = append(lines, `{"missing":"type"}`)
+	input := strings.Join(lines, "\n")
+
+	var mu sync.Mutex
+	received := 0
+	attempts := map[string]int{}
+	send := func(ctx context.Context, payload json.RawMessage) error {
+		mu.Lock()
+		defer mu.Unlock()
+
+		// Fail the first attempt of every batch to exercise retries.
+		attempts[string(payload)]++
+		if attempts[string(payload)] == 1 {
+			return fmt.Errorf("temporary failure")
+		}
+
+		var batch []map[string]interface{}
+		require.NoError(t, json.Unmarshal(payload, &batch))
+		assert.LessOrEqual(t, len(payload), 300)
+		received += len(batch)
+		return nil
+	}
+
+	var progress []int
+	opts := postFileOptions{
+		format:      formatAuto,
+		batchBytes:  300,
+		parallelism: 3,
+		retries:     1,
+		skip:        10,
+		onProgress: func(processed int) {
+			progress = append(progress, processed)
+		},
+	}
+
+	summary, err := postEvents(context.Background(), strings.NewReader(input), opts, send)
+	require.NoError(t, err)
+
+	assert.Equal(t, 10, summary.EventsSkipped)
+	assert.Equal(t, 91, summary.EventsRead)
+	assert.Equal(t, 90, summary.EventsSent)
+	assert.Equal(t, 1, summary.EventsRejected)
+	assert.Equal(t, 0, summary.FailedBatches)
+	assert.Equal(t, 90, received)
+	assert.Equal(t, 101, progress[len(progress)-1])
+}
+
+func TestPostEventsFailedBatch(t *testing.T) {
+	retryDelayMs = 1
+
+	input := `[{"eventType":"A"},{"eventType":"B"}]`
+	send := func(ctx context.Context, payload json.RawMessage) error {
+		return fmt.Errorf("permanent failure")
+	}
+
+	summary, err := postEvents(context.Background(), strings.NewReader(input), postFileOptions{batchBytes: 1000, parallelism: 1}, send)
+	require.NoError(t, err)
+	assert.Equal(t, 1, summary.FailedBatches)
+	assert.Equal(t, 2, summary.EventsFailed)
+}
+
+func TestActiveRegion(t *testing.T) {
+	config.Init(t.TempDir())
+
+	t.Setenv("NEW_RELIC_REGION", "eu")
+	_, err := activeRegion()
+	assert.NoError(t, err)
+
+	t.Setenv("NEW_RELIC_REGION", "mars")
+	_, err = activeRegion()
+	assert.Error(t, err)
+}
+
+func TestNewEventsClientShouldGzipBatches(t *testing.T) {
+	var encoding string
+	var body []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		encoding = r.Header.Get("Content-Encoding")
+
+		reader := io.Reader(r.Body)
+		if encoding == "gzip" {
+			gz, err := gzip.NewReader(r.Body)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			reader = gz
+		}
+
+		body, _ = io.ReadAll(reader)
+		_, _ = w.Write([]byte(`{"success": true}`))
+	}))
+	defer server.Close()
+
+	reg, err := region.Get(region.US)
+	require.NoError(t, err)
+	reg.SetInsightsBaseURL(server.URL)
+
+	eventsClient, err := newEventsClient("LICENSE-KEY", reg, true)
+	require.NoError(t, err)
+	require.NoError(t, eventsClient.CreateEventWithContext(context.Background(), 12345, json.RawMessage(`[{"eventType":"A"}]`)))
+	assert.Equal(t, "gzip", encoding)
+	assert.JSONEq(t, `[{"eventType":"A"}]`, string(body))
+
+	eventsClient, err = newEventsClient("LICENSE-KEY", reg, false)
+	require.NoError(t, err)
+	require.NoError(t, eventsClient.CreateEventWithContext(context.Background(), 12345, json.RawMessage(`[{"eventType":"B"}]`)))
+	assert.Empty(t, encoding)
+	assert.JSONEq(t, `[{"eventType":"B"}]`, string(body))
 }
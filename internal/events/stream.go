@@ -0,0 +1,309 @@
+package events
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+const (
+	formatAuto   = "auto"
+	formatJSON   = "json"
+	formatNDJSON = "ndjson"
+)
+
+// invalidEventError reports an event that was read successfully but cannot be
+// sent.  Reading can continue after an invalid event.
+type invalidEventError struct {
+	reason string
+}
+
+func (e *invalidEventError) Error() string {
+	return e.reason
+}
+
+// eventReader streams events one at a time from either a JSON array of events
+// or newline-delimited JSON (NDJSON) without reading the whole source into memory.
+type eventReader struct {
+	r       *bufio.Reader
+	dec     *json.Decoder
+	ndjson  bool
+	started bool
+	line    int
+}
+
+// newEventReader returns a reader for the given format.  With the auto format
+// the source is treated as a JSON array when its first non-whitespace character
+// is '[' and as NDJSON otherwise.
+func newEventReader(r io.Reader, format string) (*eventReader, error) {
+	br := bufio.NewReaderSize(r, 1<<20)
+
+	switch format {
+	case formatJSON, formatNDJSON:
+	case formatAuto, "":
+		format = formatNDJSON
+		for {
+			b, err := br.Peek(1)
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				return nil, err
+			}
+
+			if b[0] == ' ' || b[0] == '\t' || b[0] == '\r' || b[0] == '\n' {
+				if _, err := br.ReadByte(); err != nil {
+					return nil, err
+				}
+				continue
+			}
+
+			if b[0] == '[' {
+				format = formatJSON
+			}
+			break
+		}
+	default:
+		return nil, fmt.Errorf("unknown format %s, must be one of auto, json, or ndjson", format)
+	}
+
+	er := &eventReader{r: br, ndjson: format == formatNDJSON}
+	if !er.ndjson {
+		er.dec = json.NewDecoder(br)
+	}
+
+	return er, nil
+}
+
+// Next returns the next event in compact form.  io.EOF is returned once all
+// events have been read.  An *invalidEventError is returned for events that are
+// not JSON objects with an eventType; any other error is fatal.
+func (er *eventReader) Next() (json.RawMessage, error) {
+	if er.ndjson {
+		return er.nextLine()
+	}
+
+	return er.nextElement()
+}
+
+func (er *eventReader) nextElement() (json.RawMessage, error) {
+	if !er.started {
+		er.started = true
+
+		t, err := er.dec.Token()
+		if err == io.EOF {
+			return nil, io.EOF
+		}
+		if err != nil {
+			return nil, err
+		}
+		if d, ok := t.(json.Delim); !ok || d != '[' {
+			return nil, fmt.Errorf("json file must be composed of an array of event data")
+		}
+	}
+
+	if !er.dec.More() {
+		if _, err := er.dec.Token(); err != nil {
+			return nil, err
+		}
+		return nil, io.EOF
+	}
+
+	var raw json.RawMessage
+	if err := er.dec.Decode(&raw); err != nil {
+		return nil, err
+	}
+
+	return validateEvent(raw)
+}
+
+func (er *eventReader) nextLine() (json.RawMessage, error) {
+	for {
+		line, err := er.r.ReadBytes('\n')
+		if err != nil && err != io.EOF {
+			return nil, err
+		}
+		if len(line) == 0 && err == io.EOF {
+			return nil, io.EOF
+		}
+
+		er.line++
+
+		line = bytes.TrimSpace(line)
+		if len(line) == 0 {
+			if err == io.EOF {
+				return nil, io.EOF
+			}
+			continue
+		}
+
+		event, verr := validateEvent(line)
+		if verr != nil {
+			return nil, &invalidEventError{reason: fmt.Sprintf("line %d: %s", er.line, verr)}
+		}
+
+		return event, nil
+	}
+}
+
+// validateEvent checks that raw is a JSON object with an eventType and
+// returns it in compact form.
+func validateEvent(raw []byte) (json.RawMessage, error) {
+	var e struct {
+		EventType interface{} `json:"eventType"`
+	}
+
+	if err := json.Unmarshal(raw, &e); err != nil {
+		if _, ok := err.(*json.SyntaxError); ok {
+			return nil, &invalidEventError{reason: fmt.Sprintf("invalid JSON: %s", err)}
+		}
+		return nil, &invalidEventError{reason: "event must be a JSON object"}
+	}
+
+	if s, ok := e.EventType.(string); !ok || s == "" {
+		return nil, &invalidEventError{reason: "event is missing an eventType"}
+	}
+
+	var buf bytes.Buffer
+	if err := json.Compact(&buf, raw); err != nil {
+		return nil, &invalidEventError{reason: fmt.Sprintf("invalid JSON: %s", err)}
+	}
+
+	return buf.Bytes(), nil
+}
+
+// eventBatch is a group of events sent in a single request.  Positions count
+// every event read from the source, including invalid ones, so that a batch's
+// end position can be used as a resume point.
+type eventBatch struct {
+	seq    int
+	end    int
+	size   int
+	events []json.RawMessage
+}
+
+// add appends an event if it fits within maxBytes, reporting whether it was added.
+func (b *eventBatch) add(event json.RawMessage, maxBytes int) bool {
+	size := b.size + len(event) + 1
+	if b.size == 0 {
+		size = len(event) + 2
+	}
+
+	if size > maxBytes && len(b.events) > 0 {
+		return false
+	}
+
+	b.events = append(b.events, event)
+	b.size = size
+	return true
+}
+
+// payload returns the batch encoded as a JSON array.
+func (b *eventBatch) payload() json.RawMessage {
+	var buf bytes.Buffer
+	buf.Grow(b.size)
+	buf.WriteByte('[')
+	for i, e := range b.events {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		buf.Write(e)
+	}
+	buf.WriteByte(']')
+
+	return buf.Bytes()
+}
+
+// checkpoint records how far through a source file events have been sent.
+type checkpoint struct {
+	Source    string    `json:"source"`
+	Processed int       `json:"processed"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// readCheckpoint returns the number of events already processed for source,
+// or zero when the checkpoint file does not exist.
+func readCheckpoint(path string, source string) (int, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	var c checkpoint
+	if err := json.Unmarshal(data, &c); err != nil {
+		return 0, fmt.Errorf("could not parse checkpoint file %s: %s", path, err)
+	}
+
+	if c.Source != source {
+		return 0, fmt.Errorf("checkpoint file %s belongs to %s, not %s", path, c.Source, source)
+	}
+
+	return c.Processed, nil
+}
+
+// writeCheckpoint atomically records the number of events processed for source.
+func writeCheckpoint(path string, source string, processed int) error {
+	data, err := json.Marshal(checkpoint{Source: source, Processed: processed, UpdatedAt: time.Now().UTC()})
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+
+	return os.Rename(tmp.Name(), path)
+}
+
+// progressTracker tracks which batches have been sent so that the checkpoint
+// only ever advances past events in batches that were sent successfully.
+type progressTracker struct {
+	nextSeq   int
+	processed int
+	done      map[int]int
+}
+
+func newProgressTracker(start int) *progressTracker {
+	return &progressTracker{processed: start, done: map[int]int{}}
+}
+
+// complete marks a batch as finished and returns the number of events that can
+// be considered processed.  The position never advances past a failed batch.
+func (p *progressTracker) complete(b *eventBatch, ok bool) int {
+	if ok {
+		p.done[b.seq] = b.end
+	}
+
+	for {
+		end, found := p.done[p.nextSeq]
+		if !found {
+			break
+		}
+
+		delete(p.done, p.nextSeq)
+		p.processed = end
+		p.nextSeq++
+	}
+
+	return p.processed
+}
@@ -24,6 +24,9 @@ type BundleInstaller struct {
 	statusReporter   StatusReporter
 	recipeInstaller  RecipeInstaller
 	prompter         Prompter
+	// parallelism is the number of recipes installed at the same time.  Recipes
+	// are installed one at a time, in bundle order, unless it is more than 1.
+	parallelism int
 }
 
 func NewBundleInstaller(ctx context.Context, manifest *types.DiscoveryManifest, recipeInstallerInterface RecipeInstaller, statusReporter StatusReporter) *BundleInstaller {
@@ -49,6 +52,10 @@ func (bi *BundleInstaller) InstallStopOnError(bundle *recipes.Bundle, assumeYes
 		return nil
 	}
 
+	if bi.parallelism > 1 {
+		return bi.installConcurrently(installableBundleRecipes, assumeYes, true)
+	}
+
 	for _, br := range installableBundleRecipes {
 		err := bi.InstallBundleRecipe(br, assumeYes)
 		if err != nil {
@@ -92,6 +99,13 @@ func (bi *BundleInstaller) InstallContinueOnError(bundle *recipes.Bundle, assume
 		}
 	}
 
+	if bi.parallelism > 1 {
+		if err := bi.installConcurrently(installableBundleRecipes, assumeYes, false); err != nil {
+			log.Debugf("error installing recipes: %v", err)
+		}
+		return
+	}
+
 	for _, additionalRecipe := range installableBundleRecipes {
 		err := bi.InstallBundleRecipe(additionalRecipe, assumeYes)
 		if err != nil {
@@ -130,10 +144,7 @@ func (bi *BundleInstaller) InstallBundleRecipe(bundleRecipe *recipes.BundleRecip
 	}
 
 	recipeName := bundleRecipe.Recipe.Name
-	if strings.EqualFold(recipeName, types.AgentControlRecipeName) && bundleRecipe.HasStatus(execution.RecipeStatusTypes.INSTALLED) {
-		return nil
-	}
-	if bi.installedRecipes[bundleRecipe.Recipe.Name] {
+	if bi.isInstalled(bundleRecipe) {
 		return nil
 	}
 
@@ -153,6 +164,131 @@ func (bi *BundleInstaller) InstallBundleRecipe(bundleRecipe *recipes.BundleRecip
 	return nil
 }
 
+// installConcurrently installs the bundle recipes and the recipes they depend
+// on, running up to parallelism recipes at the same time.  A recipe starts once
+// the recipes it depends on are installed, and is skipped when one of them
+// fails.  With stopOnError, no recipe starts after a failure and the first
+// error is returned once the running recipes are done.
+func (bi *BundleInstaller) installConcurrently(bundleRecipes []*recipes.BundleRecipe, assumeYes bool, stopOnError bool) error {
+	nodes := map[string]*recipes.BundleRecipe{}
+	order := []string{}
+
+	var add func(br *recipes.BundleRecipe)
+	add = func(br *recipes.BundleRecipe) {
+		if _, ok := nodes[br.Recipe.Name]; ok {
+			return
+		}
+		nodes[br.Recipe.Name] = br
+		for _, dr := range br.Dependencies {
+			add(dr)
+		}
+		order = append(order, br.Recipe.Name)
+	}
+	for _, br := range bundleRecipes {
+		add(br)
+	}
+
+	pending := map[string]int{}
+	dependents := map[string][]string{}
+	ready := []string{}
+	for _, name := range order {
+		if bi.isInstalled(nodes[name]) {
+			continue
+		}
+		for _, dr := range nodes[name].Dependencies {
+			if !bi.isInstalled(dr) {
+				pending[name]++
+				dependents[dr.Recipe.Name] = append(dependents[dr.Recipe.Name], name)
+			}
+		}
+		if pending[name] == 0 {
+			ready = append(ready, name)
+		}
+	}
+
+	type installResult struct {
+		name string
+		err  error
+	}
+
+	results := make(chan installResult)
+	skipped := map[string]bool{}
+	running := 0
+	var firstErr error
+
+	for len(ready) > 0 || running > 0 {
+		for len(ready) > 0 && running < bi.parallelism && (firstErr == nil || !stopOnError) {
+			r := nodes[ready[0]].Recipe
+			ready = ready[1:]
+			running++
+
+			log.WithFields(log.Fields{
+				"name": r.Name,
+			}).Debug("installing recipe")
+
+			installer := bi.recipeInstaller.forConcurrentInstall()
+			go func() {
+				_, err := installer.executeAndValidateWithProgress(bi.ctx, bi.manifest, r, assumeYes)
+				results <- installResult{name: r.Name, err: err}
+			}()
+		}
+
+		if running == 0 {
+			break
+		}
+
+		result := <-results
+		running--
+
+		if result.err != nil {
+			log.Debugf("Failed while executing and validating with progress for recipe name %s, detail:%s", result.name, result.err)
+			if firstErr == nil {
+				firstErr = result.err
+			}
+			bi.skipDependents(result.name, result.name, dependents, nodes, skipped)
+			continue
+		}
+
+		bi.installedRecipes[result.name] = true
+		log.Debugf("Done executing and validating with progress for recipe name %s.", result.name)
+
+		for _, d := range dependents[result.name] {
+			pending[d]--
+			if pending[d] == 0 {
+				ready = append(ready, d)
+			}
+		}
+	}
+
+	return firstErr
+}
+
+// skipDependents reports the recipes depending on a failed recipe as skipped.
+func (bi *BundleInstaller) skipDependents(name string, failed string, dependents map[string][]string, nodes map[string]*recipes.BundleRecipe, skipped map[string]bool) {
+	for _, d := range dependents[name] {
+		if skipped[d] {
+			continue
+		}
+		skipped[d] = true
+
+		e := execution.NewRecipeStatusEvent(nodes[d].Recipe)
+		e.Msg = fmt.Sprintf("skipped as %s, which it depends on, failed to install", failed)
+		bi.statusReporter.ReportStatus(execution.RecipeStatusTypes.SKIPPED, e)
+
+		bi.skipDependents(d, failed, dependents, nodes, skipped)
+	}
+}
+
+// isInstalled returns whether the recipe was installed by this installer, or
+// is an already installed agent control.
+func (bi *BundleInstaller) isInstalled(bundleRecipe *recipes.BundleRecipe) bool {
+	if strings.EqualFold(bundleRecipe.Recipe.Name, types.AgentControlRecipeName) && bundleRecipe.HasStatus(execution.RecipeStatusTypes.INSTALLED) {
+		return true
+	}
+
+	return bi.installedRecipes[bundleRecipe.Recipe.Name]
+}
+
 func (bi *BundleInstaller) getInstallableBundleRecipes(bundle *recipes.Bundle) []*recipes.BundleRecipe {
 	var bundleRecipes []*recipes.BundleRecipe
 
@@ -3,6 +3,7 @@ package install
 import (
 	"context"
 	"errors"
+	"sync"
 	"testing"
 	"time"
 
@@ -250,6 +251,67 @@ func TestInstallShouldnotInstallAnyWhenDependenciesNotAvailable(t *testing.T) {
 	assert.False(t, test.BundleInstaller.installedRecipes["d2"])
 }
 
+func TestInstallStopOnErrorShouldInstallConcurrentlyInDependencyOrder(t *testing.T) {
+	test := createBundleInstallerTest().withParallelism(2)
+	var mu sync.Mutex
+	installed := []string{}
+	test.mockRecipeInstaller.On("executeAndValidateWithProgress", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return("All good", nil).Run(func(args mock.Arguments) {
+		mu.Lock()
+		defer mu.Unlock()
+		installed = append(installed, args.Get(2).(*types.OpenInstallationRecipe).Name)
+	})
+
+	x := givenAvailableBundleRecipe("x")
+	test.addRecipeToBundle("a", execution.RecipeStatusTypes.AVAILABLE)
+	test.addRecipeToBundle("b", execution.RecipeStatusTypes.AVAILABLE)
+	test.bundle.BundleRecipes[0].Dependencies = append(test.bundle.BundleRecipes[0].Dependencies, x)
+	test.bundle.BundleRecipes[1].Dependencies = append(test.bundle.BundleRecipes[1].Dependencies, x)
+
+	err := test.BundleInstaller.InstallStopOnError(test.bundle, true)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"x"}, installed[:1])
+	assert.ElementsMatch(t, []string{"x", "a", "b"}, installed)
+	assert.Equal(t, 3, test.BundleInstaller.InstalledRecipesCount())
+}
+
+func TestInstallContinueOnErrorShouldSkipDependentsOfFailedRecipe(t *testing.T) {
+	test := createBundleInstallerTest().withParallelism(2)
+	test.mockRecipeInstaller.On("executeAndValidateWithProgress", mock.Anything, mock.Anything, recipeNamed("x"), mock.Anything).Return("", errors.New("x failed"))
+	test.mockRecipeInstaller.On("executeAndValidateWithProgress", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return("All good", nil)
+
+	test.addRecipeToBundle("a", execution.RecipeStatusTypes.AVAILABLE)
+	test.addRecipeToBundle("b", execution.RecipeStatusTypes.AVAILABLE)
+	test.bundle.BundleRecipes[0].Dependencies = append(test.bundle.BundleRecipes[0].Dependencies, givenAvailableBundleRecipe("x"))
+
+	test.BundleInstaller.InstallContinueOnError(test.bundle, true)
+
+	test.mockRecipeInstaller.AssertNumberOfCalls(t, "executeAndValidateWithProgress", 2)
+	test.mockStatusReporter.AssertCalled(t, "ReportStatus", execution.RecipeStatusTypes.SKIPPED, mock.MatchedBy(func(e execution.RecipeStatusEvent) bool {
+		return e.Recipe.Name == "a" && e.Msg == "skipped as x, which it depends on, failed to install"
+	}))
+	assert.False(t, test.BundleInstaller.installedRecipes["a"])
+	assert.True(t, test.BundleInstaller.installedRecipes["b"])
+}
+
+func TestInstallStopOnErrorShouldNotStartRecipesAfterFailure(t *testing.T) {
+	test := createBundleInstallerTest().withParallelism(2).withRecipeInstallerError()
+
+	x := givenAvailableBundleRecipe("x")
+	test.addRecipeToBundle("a", execution.RecipeStatusTypes.AVAILABLE)
+	test.addRecipeToBundle("b", execution.RecipeStatusTypes.AVAILABLE)
+	test.addRecipeToBundle("c", execution.RecipeStatusTypes.AVAILABLE)
+	for _, br := range test.bundle.BundleRecipes {
+		br.Dependencies = append(br.Dependencies, x)
+	}
+
+	err := test.BundleInstaller.InstallStopOnError(test.bundle, true)
+
+	assert.Error(t, err)
+	test.mockRecipeInstaller.AssertNumberOfCalls(t, "executeAndValidateWithProgress", 1)
+	assert.Equal(t, 0, test.BundleInstaller.InstalledRecipesCount())
+}
+
 type BundleInstallerTest struct {
 	BundleInstaller     *BundleInstaller
 	mockStatusReporter  *mockStatusReporter
@@ -308,6 +370,25 @@ func (bi *BundleInstallerTest) withRecipeInstallerSuccess() *BundleInstallerTest
 	return bi
 }
 
+func (bi *BundleInstallerTest) withParallelism(parallelism int) *BundleInstallerTest {
+	bi.BundleInstaller.parallelism = parallelism
+	return bi
+}
+
+func givenAvailableBundleRecipe(name string) *recipes.BundleRecipe {
+	br := &recipes.BundleRecipe{
+		Recipe: recipes.NewRecipeBuilder().Name(name).Build(),
+	}
+	br.AddDetectionStatus(execution.RecipeStatusTypes.AVAILABLE, 0)
+	return br
+}
+
+func recipeNamed(name string) interface{} {
+	return mock.MatchedBy(func(r *types.OpenInstallationRecipe) bool {
+		return r.Name == name
+	})
+}
+
 func (bi *BundleInstallerTest) withPrompterYesNoVal(val bool) *BundleInstallerTest {
 	bi.mockPrompter.PromptYesNoVal = val
 	return bi
@@ -6,6 +6,7 @@ import (
 	"os"
 	"strings"
 
+	"github.com/fatih/color"
 	log "github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 	"golang.org/x/net/http/httpproxy"
@@ -13,18 +14,36 @@ import (
 	"github.com/newrelic/newrelic-cli/internal/client"
 	"github.com/newrelic/newrelic-cli/internal/config"
 	configAPI "github.com/newrelic/newrelic-cli/internal/config/api"
+	"github.com/newrelic/newrelic-cli/internal/install/execution"
+	"github.com/newrelic/newrelic-cli/internal/install/recipes"
 	"github.com/newrelic/newrelic-cli/internal/install/types"
+	"github.com/newrelic/newrelic-cli/internal/output"
 	"github.com/newrelic/newrelic-cli/internal/utils"
 	nrErrors "github.com/newrelic/newrelic-client-go/v2/pkg/errors"
 	nrRegion "github.com/newrelic/newrelic-client-go/v2/pkg/region"
 )
 
 var (
-	assumeYes    bool
-	localRecipes string
-	recipeNames  []string
-	recipePaths  []string
-	tags         []string
+	allowUnsigned bool
+	assumeYes     bool
+	answersFile   string
+	dryRun        bool
+	noRollback    bool
+	offlineBundle string
+	outputEvents  string
+	parallelism   int
+	planFormat    string
+	localRecipes  string
+	recipeNames   []string
+	recipePaths   []string
+	tags          []string
+)
+
+const (
+	planFormatText = "text"
+	planFormatJSON = "json"
+
+	outputEventsNDJSON = "ndjson"
 )
 
 // processRecipeNames validates, extracts recipe names, and sets environment variables.
@@ -59,8 +78,69 @@ func processRecipeNames(recipeNames []string) ([]string, error) {
 
 // Command represents the install command.
 var Command = &cobra.Command{
-	Use:    "install",
-	Short:  "Install New Relic.",
+	Use:   "install",
+	Short: "Install New Relic.",
+	Long: `Install New Relic.
+
+With --dry-run, the host is inspected and the recipes that would be installed are
+printed along with their dependencies, the input variables they require and the
+steps they would run.  Nothing is installed and no install status is reported.
+The plan is printed as text, or as JSON with --plan-format json.
+
+With --answers, recipe input variables are read from a YAML or JSON file mapping
+recipe names to variable values.  Secret values can be read from an environment
+variable or a file rather than written into the answers file:
+
+  mysql-open-source-integration:
+    NR_CLI_DB_HOSTNAME: localhost
+    NR_CLI_DB_USERNAME: newrelic
+    NR_CLI_DB_PASSWORD:
+      fromEnv: MYSQL_PASSWORD
+    NR_CLI_SSL_KEY:
+      fromFile: /etc/newrelic/mysql-key.pem
+
+The file is checked against the input variables of the recipes to be installed
+before anything is installed.  With --assumeYes, every input variable without a
+default must have a value in the answers file or the environment.
+
+When a recipe with an uninstall section fails, the install steps that completed
+before the failure are rolled back using the recipe's uninstall tasks, and the
+outcome is reported with the recipe's failure.  Use --no-rollback to leave them
+in place for troubleshooting.
+
+With --output-events ndjson, every install event is written as a line of JSON
+with the recipe name, status, entity GUID, validation duration and error details,
+followed by a final InstallSummary line.  Events are written to standard output,
+or to a file with --output-events ndjson=path.  When events are written to
+standard output, the rest of the install output, such as progress, prompts and
+the installation summary, is written to standard error instead.
+
+With --offline-bundle, recipes and the files they download are installed from an
+archive created by newrelic install bundle create, on hosts without network
+access.  Network checks and NRQL validation are skipped, the license key must be
+set with NEW_RELIC_LICENSE_KEY or the active profile, and install events are
+recorded locally for upload with newrelic install bundle upload-events.
+
+Recipes are installed once the recipes they depend on are installed.  A
+dependency cycle, or a recipe given with --recipe depending on a recipe that is
+not available for this host, fails the install before anything is installed.
+With --parallelism, up to that many recipes that do not depend on each other are
+installed at the same time, and the progress of each recipe is printed as it
+starts and completes.  Recipes depending on a recipe that fails are skipped.
+Installing recipes concurrently requires --assumeYes.
+
+Recipes loaded with --localRecipes or --recipePath, and offline bundles, must
+have a detached signature next to them, in a file with the same name and a .sig
+extension, made with the key set with newrelic profile add --recipeSigningKey.
+Use --allow-unsigned to install recipes without a signature.  Recipes with an
+invalid signature are never installed.`,
+	Example: `newrelic install
+newrelic install -n logs-integration --dry-run
+newrelic install --dry-run --plan-format json
+newrelic install -n mysql-open-source-integration --answers answers.yaml -y
+newrelic install -y --output-events ndjson=/var/log/newrelic-install.ndjson
+newrelic install -y --parallelism 4
+newrelic install -n mysql-open-source-integration --offline-bundle newrelic-install-bundle-linux-amd64.tar.gz`,
 	PreRun: client.RequireClient,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		extractedRecipeNames, err := processRecipeNames(recipeNames)
@@ -68,30 +148,106 @@ var Command = &cobra.Command{
 			return types.NewDetailError(types.EventTypes.OtherError, err.Error())
 		}
 
+		if parallelism < 1 {
+			return fmt.Errorf("--parallelism must be at least 1")
+		}
+
 		ic := types.InstallerContext{
-			AssumeYes:    assumeYes,
-			LocalRecipes: localRecipes,
-			RecipeNames:  extractedRecipeNames,
-			RecipePaths:  recipePaths,
+			AssumeYes:        assumeYes,
+			LocalRecipes:     localRecipes,
+			RecipeNames:      extractedRecipeNames,
+			RecipePaths:      recipePaths,
+			NoRollback:       noRollback,
+			RecipeSigningKey: configAPI.GetActiveProfileString(config.RecipeSigningKey),
+			AllowUnsigned:    allowUnsigned,
+			Parallelism:      parallelism,
 		}
 
 		ic.SetTags(tags)
 
+		if answersFile != "" {
+			answers, err := types.ReadRecipeAnswers(answersFile)
+			if err != nil {
+				return err
+			}
+			ic.Answers = answers
+		}
+
 		logLevel := configAPI.GetLogLevel()
 		config.InitFileLogger(logLevel)
 
-		if err := checkNetwork(); err != nil {
-			return types.NewDetailError(types.EventTypes.UnableToConnect, err.Error())
+		if offlineBundle != "" {
+			if localRecipes != "" || len(recipePaths) > 0 {
+				return fmt.Errorf("--offline-bundle cannot be used with --localRecipes or --recipePath")
+			}
+
+			dir, signature, err := openOfflineBundle(offlineBundle, recipes.NewRecipeSignatureVerifier(ic.RecipeSigningKey, ic.AllowUnsigned))
+			if err != nil {
+				return err
+			}
+			defer os.RemoveAll(dir)
+
+			ic.OfflineBundle = dir
+			ic.OfflineBundleSignature = signature
+		}
+
+		if dryRun {
+			return printInstallPlan(NewRecipeInstaller(ic), planFormat)
 		}
 
-		detailErr := fetchLicenseKey()
+		if parallelism > 1 && !assumeYes {
+			return fmt.Errorf("--parallelism requires --assumeYes, recipes installed concurrently cannot prompt for input")
+		}
+
+		var eventsOutput *os.File
+		if outputEvents != "" {
+			eventsOutput, err = openEventsOutput(outputEvents)
+			if err != nil {
+				return err
+			}
+
+			if eventsOutput == os.Stdout {
+				var restore func()
+				eventsOutput, restore = reserveStdoutForEvents()
+				defer restore()
+			} else {
+				defer eventsOutput.Close()
+			}
+		}
+
+		var detailErr *types.DetailError
+		if ic.IsOffline() {
+			detailErr = fetchOfflineLicenseKey()
+		} else {
+			if err := checkNetwork(); err != nil {
+				return types.NewDetailError(types.EventTypes.UnableToConnect, err.Error())
+			}
+
+			detailErr = fetchLicenseKey()
+		}
 
 		if detailErr != nil {
-			log.Fatal(detailErr)
+			return detailErr
 		}
 
 		i := NewRecipeInstaller(ic)
 
+		if ic.IsOffline() {
+			eventsFile, err := recordOfflineInstallEvents(i)
+			if err != nil {
+				return err
+			}
+
+			defer func() {
+				eventsFile.Close()
+				fmt.Printf("\nInstall events were recorded to %s, upload them once connected with:\n  newrelic install bundle upload-events %s\n\n", eventsFile.Name(), eventsFile.Name())
+			}()
+		}
+
+		if eventsOutput != nil {
+			i.status.AddStatusSubscriber(execution.NewNDJSONStatusReporter(eventsOutput))
+		}
+
 		//// Do not install both infra and agent controls simultaneously: install only the 'agent-control' if targeted.
 		//if i.IsRecipeTargeted(types.AgentControlRecipeName) && i.shouldInstallCore() {
 		//	log.Debugf("'%s' is targeted, disabling infra/logs core bundle install\n", types.AgentControlRecipeName)
@@ -116,6 +272,18 @@ var Command = &cobra.Command{
 				return err
 			}
 
+			if errors.Is(err, types.ErrInvalidAnswers) {
+				return err
+			}
+
+			if errors.Is(err, types.ErrRecipeDependencies) {
+				return err
+			}
+
+			if e, ok := err.(*types.DetailError); ok && (e.EventName == types.EventTypes.RecipeUnsigned || e.EventName == types.EventTypes.RecipeSignatureInvalid) {
+				return err
+			}
+
 			if len(extractedRecipeNames) > 0 && errors.Is(err, types.ErrNoRecipesInstalled) {
 				return err
 			}
@@ -144,6 +312,69 @@ func init() {
 	Command.Flags().BoolVarP(&assumeYes, "assumeYes", "y", false, "use \"yes\" for all questions during install")
 	Command.Flags().StringVarP(&localRecipes, "localRecipes", "", "", "a path to local recipes to load instead of service other fetching")
 	Command.Flags().StringSliceVarP(&tags, "tag", "", []string{}, "the tags to add during install, can be multiple. Example: --tag tag1:test,tag2:test")
+	Command.Flags().StringVar(&answersFile, "answers", "", "a YAML or JSON file of recipe input variable values, keyed by recipe name, used instead of prompting")
+	Command.Flags().BoolVar(&dryRun, "dry-run", false, "print what would be installed without installing anything")
+	Command.Flags().StringVar(&planFormat, "plan-format", planFormatText, "the format of the --dry-run plan, one of text or json")
+	Command.Flags().BoolVar(&noRollback, "no-rollback", false, "do not roll back the completed steps of a recipe that fails to install")
+	Command.Flags().BoolVar(&allowUnsigned, "allow-unsigned", false, "install recipes from files or offline bundles without a signature, or when no recipe signing key is configured")
+	Command.Flags().StringVar(&offlineBundle, "offline-bundle", "", "install from an offline bundle created with newrelic install bundle create, without network access")
+	Command.Flags().IntVar(&parallelism, "parallelism", 1, "the number of recipes that do not depend on each other to install at the same time")
+	Command.Flags().StringVar(&outputEvents, "output-events", "", "write install events as newline delimited JSON, either ndjson for standard output or ndjson=path for a file")
+}
+
+func printInstallPlan(i *RecipeInstall, format string) error {
+	if format != planFormatText && format != planFormatJSON {
+		return fmt.Errorf("unknown plan format %s, must be one of %s or %s", format, planFormatText, planFormatJSON)
+	}
+
+	plan, err := i.Plan(utils.SignalCtx)
+	if err != nil {
+		return err
+	}
+
+	if format == planFormatJSON {
+		output.JSON(plan)
+		return nil
+	}
+
+	fmt.Print(plan.String())
+	return nil
+}
+
+// openEventsOutput opens the destination of an --output-events value, either
+// ndjson for standard output or ndjson=path for a file.
+func openEventsOutput(spec string) (*os.File, error) {
+	format, path, hasPath := strings.Cut(spec, "=")
+	if format != outputEventsNDJSON {
+		return nil, fmt.Errorf("unknown events output %s, must be %s or %s=path", spec, outputEventsNDJSON, outputEventsNDJSON)
+	}
+
+	if !hasPath || path == "-" {
+		return os.Stdout, nil
+	}
+
+	if path == "" {
+		return nil, fmt.Errorf("a path is required with %s=", outputEventsNDJSON)
+	}
+
+	return os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600)
+}
+
+// reserveStdoutForEvents keeps standard output for the events stream, so that
+// it can be read line by line.  Everything else the install writes to standard
+// output, such as the terminal status reporter, spinners, prompts and recipe
+// output, is sent to standard error until the returned function is called.
+func reserveStdoutForEvents() (*os.File, func()) {
+	stdout := os.Stdout
+	colorOutput := color.Output
+
+	os.Stdout = os.Stderr
+	color.Output = color.Error
+
+	return stdout, func() {
+		os.Stdout = stdout
+		color.Output = colorOutput
+	}
 }
 
 func validateProfile() *types.DetailError {
@@ -247,9 +478,8 @@ func fetchLicenseKey() *types.DetailError {
 	}
 
 	// fetch licenseKey via API
-	detailErr := validateProfile()
-	if detailErr != nil {
-		log.Fatal(detailErr)
+	if detailErr := validateProfile(); detailErr != nil {
+		return detailErr
 	}
 
 	accountID := configAPI.GetActiveProfileAccountID()
@@ -0,0 +1,239 @@
+package install
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+
+	"github.com/newrelic/newrelic-cli/internal/client"
+	"github.com/newrelic/newrelic-cli/internal/config"
+	configAPI "github.com/newrelic/newrelic-cli/internal/config/api"
+	"github.com/newrelic/newrelic-cli/internal/install/execution"
+	"github.com/newrelic/newrelic-cli/internal/install/recipes"
+	"github.com/newrelic/newrelic-cli/internal/install/types"
+	"github.com/newrelic/newrelic-cli/internal/utils"
+)
+
+const offlineEventsDir = "offline-events"
+
+var (
+	bundleAllowUnsigned bool
+	bundleLocalRecipes  string
+	bundleOutput        string
+	bundlePlatform      string
+	bundleRecipeNames   []string
+	bundleRecipePaths   []string
+	bundleSkipCore      bool
+)
+
+var cmdBundle = &cobra.Command{
+	Use:   "bundle",
+	Short: "Create offline install bundles for hosts without network access",
+	Long: `Create offline install bundles for hosts without network access
+
+An offline bundle packages recipes, along with every artifact their install
+tasks download, into a single archive.  Install from it with
+newrelic install --offline-bundle, which skips the network checks and records
+the install events locally for upload once the host is connected.`,
+	Example: `newrelic install bundle create -n mysql-open-source-integration --platform linux/amd64
+newrelic install --offline-bundle newrelic-install-bundle-linux-amd64.tar.gz -n mysql-open-source-integration
+newrelic install bundle upload-events ~/.newrelic/offline-events/*.ndjson`,
+}
+
+var cmdBundleCreate = &cobra.Command{
+	Use:   "create",
+	Short: "Create an offline install bundle",
+	Long: `Create an offline install bundle
+
+Packages the named recipes, the recipes they depend on and the infrastructure
+agent and logs recipes installed by default, along with every file their install
+tasks download, into a .tar.gz archive for the given os/arch platform.
+
+Downloads are found by looking for URLs in the recipes' install tasks.  URLs
+that cannot be downloaded, such as package repositories, or that depend on
+values only known at install time are listed as skipped and still require
+network access when installing.
+
+Recipes loaded with --localRecipes or --recipePath must be signed, as when
+installing them, unless --allow-unsigned is given.  Sign the bundle itself to
+install from it, for example with cosign sign-blob, writing the signature next
+to it in a file with a .sig extension.`,
+	Example: `newrelic install bundle create -n mysql-open-source-integration --platform linux/amd64
+newrelic install bundle create -n nginx-open-source-integration --platform linux/arm64 -o nginx.tar.gz --skip-core`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		platform := bundlePlatform
+		if platform == "" {
+			platform = runtime.GOOS + "/" + runtime.GOARCH
+		}
+
+		goos, goarch, err := recipes.ParseOfflineBundlePlatform(platform)
+		if err != nil {
+			return err
+		}
+
+		output := bundleOutput
+		if output == "" {
+			output = fmt.Sprintf("newrelic-install-bundle-%s-%s.tar.gz", goos, goarch)
+		}
+
+		ic := types.InstallerContext{
+			LocalRecipes:     bundleLocalRecipes,
+			RecipePaths:      bundleRecipePaths,
+			RecipeSigningKey: configAPI.GetActiveProfileString(config.RecipeSigningKey),
+			AllowUnsigned:    bundleAllowUnsigned,
+		}
+
+		bundler := recipes.NewOfflineBundler(newRecipeFetcher(ic))
+		manifest, err := bundler.Create(utils.SignalCtx, recipes.OfflineBundleOptions{
+			RecipeNames: bundleRecipeNames,
+			Platform:    platform,
+			IncludeCore: !bundleSkipCore,
+			Output:      output,
+		})
+		if err != nil {
+			return err
+		}
+
+		var size int64
+		for _, a := range manifest.Artifacts {
+			size += a.Size
+		}
+
+		fmt.Printf("Created %s for %s with %d recipe(s) and %d artifact(s), %d bytes.\n", output, manifest.Platform, len(manifest.Recipes), len(manifest.Artifacts), size)
+
+		if len(manifest.Skipped) > 0 {
+			fmt.Printf("\nThe following URLs were not bundled and still require network access:\n")
+			for _, s := range manifest.Skipped {
+				fmt.Printf("  %s: %s (%s)\n", s.Recipe, s.URL, s.Reason)
+			}
+		}
+
+		return nil
+	},
+}
+
+var cmdBundleUploadEvents = &cobra.Command{
+	Use:   "upload-events <file>...",
+	Short: "Upload the install events recorded by offline installs",
+	Long: `Upload the install events recorded by offline installs
+
+Installs from an offline bundle record their install events to a file in the
+offline-events directory of the CLI configuration, rather than sending them to
+New Relic.  Once the host, or another one with access to the files, is
+connected, upload them to report the installs.  Events recorded without an
+account ID are sent to the account of the active profile.`,
+	Example: `newrelic install bundle upload-events ~/.newrelic/offline-events/install-20240102T150405Z.ndjson`,
+	Args:    cobra.MinimumNArgs(1),
+	PreRun:  client.RequireClient,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		accountID := configAPI.GetActiveProfileAccountID()
+
+		for _, p := range args {
+			f, err := os.Open(p)
+			if err != nil {
+				return err
+			}
+
+			sent, err := execution.UploadRecordedInstallEvents(f, &client.NRClient.InstallEvents, accountID)
+			f.Close()
+			if err != nil {
+				return fmt.Errorf("uploaded %d install event(s) from %s before an error: %s", sent, p, err)
+			}
+
+			fmt.Printf("Uploaded %d install event(s) from %s.\n", sent, p)
+		}
+
+		return nil
+	},
+}
+
+// openOfflineBundle verifies the signature of the offline bundle at p and
+// extracts it into a temporary directory, after checking it was created for
+// this platform.
+func openOfflineBundle(p string, verifier *recipes.RecipeSignatureVerifier) (string, *types.RecipeSignature, error) {
+	signature, err := verifier.VerifyFile(p)
+	if err != nil {
+		return "", nil, err
+	}
+
+	dir, err := os.MkdirTemp("", "newrelic-offline-bundle")
+	if err != nil {
+		return "", nil, err
+	}
+
+	manifest, err := recipes.ExtractOfflineBundle(p, dir)
+	if err != nil {
+		os.RemoveAll(dir)
+		return "", nil, err
+	}
+
+	platform := runtime.GOOS + "/" + runtime.GOARCH
+	if !strings.EqualFold(manifest.Platform, platform) {
+		os.RemoveAll(dir)
+		return "", nil, fmt.Errorf("offline bundle %s was created for %s, this host is %s", p, manifest.Platform, platform)
+	}
+
+	for _, s := range manifest.Skipped {
+		log.Debugf("offline bundle does not include %s for %s: %s", s.URL, s.Recipe, s.Reason)
+	}
+
+	return dir, signature, nil
+}
+
+// recordOfflineInstallEvents records the install events of an offline install
+// to a new file in the offline-events directory of the CLI configuration.
+func recordOfflineInstallEvents(i *RecipeInstall) (*os.File, error) {
+	dir := filepath.Join(config.BasePath, offlineEventsDir)
+	if err := os.MkdirAll(dir, 0750); err != nil {
+		return nil, err
+	}
+
+	name := fmt.Sprintf("install-%s.ndjson", time.Now().UTC().Format("20060102T150405Z"))
+	f, err := os.OpenFile(filepath.Join(dir, name), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		return nil, err
+	}
+
+	i.status.AddStatusSubscriber(execution.NewInstallEventsReporter(execution.NewOfflineInstallEventsClient(f)))
+
+	return f, nil
+}
+
+// fetchOfflineLicenseKey sets the license key from the environment or the
+// active profile, as it cannot be fetched from New Relic without network access.
+func fetchOfflineLicenseKey() *types.DetailError {
+	licenseKey := fetchLicenseKeyFromEnvironment()
+	if licenseKey == "" {
+		licenseKey = fetchLicenseKeyFromProfile()
+	}
+
+	if licenseKey == "" {
+		return types.NewDetailError(types.EventTypes.UnableToFetchLicenseKey, "a license key is required to install from an offline bundle, set NEW_RELIC_LICENSE_KEY or the license key of your profile")
+	}
+
+	os.Setenv("NEW_RELIC_LICENSE_KEY", licenseKey)
+	log.Debug("using license key: ", utils.Obfuscate(licenseKey))
+
+	return nil
+}
+
+func init() {
+	Command.AddCommand(cmdBundle)
+	cmdBundle.AddCommand(cmdBundleCreate)
+	cmdBundle.AddCommand(cmdBundleUploadEvents)
+
+	cmdBundleCreate.Flags().StringSliceVarP(&bundleRecipeNames, "recipe", "n", []string{}, "the name of a recipe to bundle, can be repeated")
+	cmdBundleCreate.Flags().StringVar(&bundlePlatform, "platform", "", "the os/arch platform of the hosts to install on, such as linux/amd64, defaults to this host's platform")
+	cmdBundleCreate.Flags().StringVarP(&bundleOutput, "output", "o", "", "the path of the bundle to write, defaults to newrelic-install-bundle-<os>-<arch>.tar.gz")
+	cmdBundleCreate.Flags().BoolVar(&bundleSkipCore, "skip-core", false, "do not bundle the infrastructure agent and logs recipes installed by default")
+	cmdBundleCreate.Flags().StringVarP(&bundleLocalRecipes, "localRecipes", "", "", "a path to local recipes to load instead of service other fetching")
+	cmdBundleCreate.Flags().StringSliceVarP(&bundleRecipePaths, "recipePath", "c", []string{}, "the path to a recipe file to bundle")
+	cmdBundleCreate.Flags().BoolVar(&bundleAllowUnsigned, "allow-unsigned", false, "bundle recipe files without a signature, or when no recipe signing key is configured")
+	utils.LogIfError(cmdBundleCreate.MarkFlagRequired("recipe"))
+}
@@ -0,0 +1,71 @@
+package install
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/newrelic/newrelic-cli/internal/client"
+	"github.com/newrelic/newrelic-cli/internal/config"
+	configAPI "github.com/newrelic/newrelic-cli/internal/config/api"
+	"github.com/newrelic/newrelic-cli/internal/install/types"
+	"github.com/newrelic/newrelic-cli/internal/output"
+	"github.com/newrelic/newrelic-cli/internal/utils"
+)
+
+var (
+	discoverAllowUnsigned bool
+	discoverLocalRecipes  string
+	discoverRecipeNames   []string
+	discoverRecipePaths   []string
+)
+
+var cmdDiscover = &cobra.Command{
+	Use:   "discover",
+	Short: "Report what the installer finds on this host",
+	Long: `Report what the installer finds on this host
+
+Runs recipe detection without installing anything, and prints the host
+manifest, the running processes matching a recipe's processMatch and the
+detection status of every recipe along with the reason for it: the install
+targets, the process match, the preInstall.requireAtDiscovery exit code or the
+log files matching the recipe's logMatch.
+
+Recipes with discoveryMode TARGETED are only detected when named with --recipe.`,
+	Example: `newrelic install discover
+newrelic install discover --format yaml
+newrelic install discover -n mysql-open-source-integration`,
+	PreRun: client.RequireClient,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		extractedRecipeNames, err := processRecipeNames(discoverRecipeNames)
+		if err != nil {
+			return err
+		}
+
+		ic := types.InstallerContext{
+			AssumeYes:        true,
+			LocalRecipes:     discoverLocalRecipes,
+			RecipeNames:      extractedRecipeNames,
+			RecipePaths:      discoverRecipePaths,
+			RecipeSigningKey: configAPI.GetActiveProfileString(config.RecipeSigningKey),
+			AllowUnsigned:    discoverAllowUnsigned,
+		}
+
+		logLevel := configAPI.GetLogLevel()
+		config.InitFileLogger(logLevel)
+
+		report, err := NewRecipeInstaller(ic).Discover(utils.SignalCtx)
+		if err != nil {
+			return err
+		}
+
+		return output.Print(report)
+	},
+}
+
+func init() {
+	Command.AddCommand(cmdDiscover)
+
+	cmdDiscover.Flags().StringSliceVarP(&discoverRecipeNames, "recipe", "n", []string{}, "the name of a recipe to detect as if it was targeted by install")
+	cmdDiscover.Flags().StringSliceVarP(&discoverRecipePaths, "recipePath", "c", []string{}, "the path to a recipe file to detect")
+	cmdDiscover.Flags().StringVarP(&discoverLocalRecipes, "localRecipes", "", "", "a path to local recipes to load instead of service other fetching")
+	cmdDiscover.Flags().BoolVar(&discoverAllowUnsigned, "allow-unsigned", false, "detect recipe files without a signature, or when no recipe signing key is configured")
+}
@@ -0,0 +1,153 @@
+package install
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/newrelic/newrelic-cli/internal/install/recipes"
+	"github.com/newrelic/newrelic-cli/internal/install/types"
+	"github.com/newrelic/newrelic-cli/internal/output"
+	"github.com/newrelic/newrelic-cli/internal/utils"
+	"github.com/newrelic/newrelic-client-go/v2/pkg/nrdb"
+)
+
+const defaultValidationResults = `[{"count": 1}]`
+
+var (
+	recipeTestAnswersFile       string
+	recipeTestKeep              bool
+	recipeTestValidationResults string
+	recipeTestWorkDir           string
+)
+
+// RecipeCommand represents the recipe command.
+var RecipeCommand = &cobra.Command{
+	Use:   "recipe",
+	Short: "Develop and test New Relic install recipes",
+	Long: `Develop and test New Relic install recipes
+
+Recipes are YAML files describing how newrelic install detects, installs and
+validates an integration.  Use these commands to check a recipe before passing
+it to newrelic install with --recipePath or --localRecipes.`,
+}
+
+var cmdRecipeLint = &cobra.Command{
+	Use:   "lint <file>...",
+	Short: "Validate recipe files",
+	Long: `Validate recipe files
+
+Checks each recipe against the recipe schema: unknown and mistyped fields,
+installTargets values, processMatch regular expressions, logMatch globs,
+inputVars names and the go-task definitions of install and uninstall.
+
+Problems are reported as errors or warnings.  The command fails when any
+recipe has errors.`,
+	Example: `newrelic recipe lint mysql.yml
+newrelic recipe lint recipes/*.yml --format json`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		results := []recipes.LintResult{}
+		failed := 0
+		for _, path := range args {
+			result := recipes.LintRecipeFile(path)
+			if result.HasErrors() {
+				failed++
+			}
+			results = append(results, result)
+		}
+
+		if err := output.Print(results); err != nil {
+			return err
+		}
+
+		if failed > 0 {
+			return fmt.Errorf("%d of %d recipe(s) have errors", failed, len(args))
+		}
+
+		return nil
+	},
+}
+
+var cmdRecipeTest = &cobra.Command{
+	Use:   "test <file>",
+	Short: "Run a recipe in a sandboxed working directory",
+	Long: `Run a recipe in a sandboxed working directory
+
+Lints the recipe, then runs its preInstall script, its install tasks and its
+validation step from a temporary working directory.  The recipe's commands run
+on this host with the current user's permissions.
+
+The validation query is not sent to New Relic.  It is rendered with the recipe
+variables and answered with the results given by --validation-results, a JSON
+array of NRDB result rows.  Validation passes when the first row has a count
+greater than 0, and its entityGuid, if any, is reported.
+
+Input variables are read from --answers, the environment or their default, as
+with newrelic install --assumeYes.`,
+	Example: `newrelic recipe test mysql.yml
+newrelic recipe test mysql.yml --answers answers.yaml --workdir ./sandbox
+newrelic recipe test mysql.yml --validation-results '[{"count": 0}]'`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		validationResults, err := parseValidationResults(recipeTestValidationResults)
+		if err != nil {
+			return err
+		}
+
+		var answers types.RecipeAnswers
+		if recipeTestAnswersFile != "" {
+			answers, err = types.ReadRecipeAnswers(recipeTestAnswersFile)
+			if err != nil {
+				return err
+			}
+		}
+
+		t := NewRecipeTester(answers, validationResults)
+		t.WorkDir = recipeTestWorkDir
+		t.Keep = recipeTestKeep
+
+		result, err := t.TestFile(utils.SignalCtx, args[0])
+		if err != nil {
+			return err
+		}
+
+		if err := output.Print(result); err != nil {
+			return err
+		}
+
+		if !result.Passed {
+			return fmt.Errorf("recipe test failed for %s", result.Recipe)
+		}
+
+		return nil
+	},
+}
+
+// parseValidationResults reads the stubbed NRDB results for a recipe test.
+// Every row must have a numeric count, which the validator relies on.
+func parseValidationResults(s string) ([]nrdb.NRDBResult, error) {
+	results := []nrdb.NRDBResult{}
+	if err := json.Unmarshal([]byte(s), &results); err != nil {
+		return nil, fmt.Errorf("invalid --validation-results, expected a JSON array of objects: %s", err)
+	}
+
+	for n, r := range results {
+		if _, ok := r["count"].(float64); !ok {
+			return nil, fmt.Errorf("invalid --validation-results, row %d has no numeric count", n)
+		}
+	}
+
+	return results, nil
+}
+
+func init() {
+	RecipeCommand.AddCommand(cmdRecipeLint)
+	RecipeCommand.AddCommand(cmdRecipeTest)
+
+	cmdRecipeTest.Flags().StringVar(&recipeTestAnswersFile, "answers", "", "a YAML or JSON file of recipe input variable values, keyed by recipe name")
+	cmdRecipeTest.Flags().StringVar(&recipeTestWorkDir, "workdir", "", "the working directory to run the recipe from, a temporary directory is used by default")
+	cmdRecipeTest.Flags().BoolVar(&recipeTestKeep, "keep", false, "keep the temporary working directory once the test has completed")
+	cmdRecipeTest.Flags().StringVar(&recipeTestValidationResults, "validation-results", defaultValidationResults, "a JSON array of NRDB results returned for the recipe's validation query")
+}
@@ -3,14 +3,21 @@
 package install
 
 import (
+	"encoding/json"
+	"fmt"
+	"io"
 	"net/http"
 	"net/http/httptest"
 	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
+	"github.com/newrelic/newrelic-cli/internal/install/execution"
+	"github.com/newrelic/newrelic-cli/internal/install/recipes"
 	"github.com/newrelic/newrelic-cli/internal/install/types"
 	"github.com/newrelic/newrelic-cli/internal/testcobra"
 )
@@ -24,6 +31,71 @@ func TestInstallCommand(t *testing.T) {
 	testcobra.CheckCobraRequiredFlags(t, Command, []string{})
 }
 
+func TestUninstallCommand(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "uninstall", UninstallCommand.Name())
+
+	testcobra.CheckCobraMetadata(t, UninstallCommand)
+	testcobra.CheckCobraRequiredFlags(t, UninstallCommand, []string{"recipe"})
+}
+
+func TestRecipeCommand(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "recipe", RecipeCommand.Name())
+
+	testcobra.CheckCobraMetadata(t, RecipeCommand)
+	testcobra.CheckCobraRequiredFlags(t, cmdRecipeTest, []string{})
+}
+
+func TestBundleCommand(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "bundle", cmdBundle.Name())
+
+	testcobra.CheckCobraMetadata(t, cmdBundle)
+	testcobra.CheckCobraRequiredFlags(t, cmdBundleCreate, []string{"recipe"})
+}
+
+func TestOpenOfflineBundleShouldRejectMissingBundle(t *testing.T) {
+	_, _, err := openOfflineBundle(filepath.Join(t.TempDir(), "missing.tar.gz"), recipes.NewRecipeSignatureVerifier("", true))
+
+	assert.Error(t, err)
+}
+
+func TestOpenOfflineBundleShouldRejectUnsignedBundle(t *testing.T) {
+	p := filepath.Join(t.TempDir(), "bundle.tar.gz")
+	require.NoError(t, os.WriteFile(p, []byte("bundle"), 0600))
+
+	_, _, err := openOfflineBundle(p, recipes.NewRecipeSignatureVerifier("", false))
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "--allow-unsigned")
+}
+
+func TestFetchOfflineLicenseKey(t *testing.T) {
+	t.Setenv("NEW_RELIC_LICENSE_KEY", "0123456789abcdef0123456789abcdef0123NRAL")
+
+	assert.Nil(t, fetchOfflineLicenseKey())
+}
+
+func TestParseValidationResults(t *testing.T) {
+	results, err := parseValidationResults(defaultValidationResults)
+	assert.NoError(t, err)
+	assert.Equal(t, float64(1), results[0]["count"])
+
+	results, err = parseValidationResults(`[{"count": 2, "entityGuid": "MTIzNDU2"}]`)
+	assert.NoError(t, err)
+	assert.Equal(t, "MTIzNDU2", results[0]["entityGuid"])
+
+	_, err = parseValidationResults(`{"count": 1}`)
+	assert.Error(t, err)
+
+	_, err = parseValidationResults(`[{"entityGuid": "MTIzNDU2"}]`)
+	assert.Error(t, err)
+}
+
 func TestValidateProfile(t *testing.T) {
 	accountID := os.Getenv("NEW_RELIC_ACCOUNT_ID")
 	apiKey := os.Getenv("NEW_RELIC_API_KEY")
@@ -122,3 +194,67 @@ func TestProxyNetwork(t *testing.T) {
 	}
 
 }
+
+func TestOpenEventsOutput(t *testing.T) {
+	f, err := openEventsOutput("ndjson")
+	assert.NoError(t, err)
+	assert.Equal(t, os.Stdout, f)
+
+	path := t.TempDir() + "/events.ndjson"
+	f, err = openEventsOutput("ndjson=" + path)
+	assert.NoError(t, err)
+	assert.Equal(t, path, f.Name())
+	f.Close()
+
+	_, err = openEventsOutput("ndjson=")
+	assert.Error(t, err)
+
+	_, err = openEventsOutput("json")
+	assert.EqualError(t, err, "unknown events output json, must be ndjson or ndjson=path")
+}
+
+func TestReserveStdoutForEvents(t *testing.T) {
+	stdoutReader, stdoutWriter, err := os.Pipe()
+	require.NoError(t, err)
+	stderrReader, stderrWriter, err := os.Pipe()
+	require.NoError(t, err)
+
+	stdout, stderr := os.Stdout, os.Stderr
+	os.Stdout, os.Stderr = stdoutWriter, stderrWriter
+	defer func() { os.Stdout, os.Stderr = stdout, stderr }()
+
+	eventsOutput, restore := reserveStdoutForEvents()
+	assert.Equal(t, stdoutWriter, eventsOutput)
+
+	subscribers := []execution.StatusSubscriber{
+		execution.NewTerminalStatusReporter(),
+		execution.NewNDJSONStatusReporter(eventsOutput),
+	}
+	status := execution.NewInstallStatus(types.InstallerContext{}, subscribers, execution.NewMockPlatformLinkGenerator())
+	infra := types.OpenInstallationRecipe{Name: types.InfraAgentRecipeName, DisplayName: "Infrastructure Agent"}
+
+	status.InstallStarted()
+	fmt.Println("Installing New Relic")
+	status.RecipeInstalled(execution.RecipeStatusEvent{Recipe: infra, EntityGUID: "MXxJTkZSQXxOQXwx"})
+	status.InstallComplete(nil)
+
+	restore()
+	assert.Equal(t, stdoutWriter, os.Stdout)
+
+	require.NoError(t, stdoutWriter.Close())
+	require.NoError(t, stderrWriter.Close())
+
+	events, err := io.ReadAll(stdoutReader)
+	require.NoError(t, err)
+	terminal, err := io.ReadAll(stderrReader)
+	require.NoError(t, err)
+
+	lines := strings.Split(strings.TrimSpace(string(events)), "\n")
+	assert.Greater(t, len(lines), 1)
+	for _, line := range lines {
+		assert.True(t, json.Valid([]byte(line)), "not a line of JSON: %s", line)
+	}
+
+	assert.Contains(t, string(terminal), "Installing New Relic")
+	assert.Contains(t, string(terminal), "New Relic installation complete")
+}
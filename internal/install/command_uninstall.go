@@ -0,0 +1,65 @@
+package install
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/newrelic/newrelic-cli/internal/client"
+	"github.com/newrelic/newrelic-cli/internal/config"
+	configAPI "github.com/newrelic/newrelic-cli/internal/config/api"
+	"github.com/newrelic/newrelic-cli/internal/install/types"
+	"github.com/newrelic/newrelic-cli/internal/utils"
+)
+
+var (
+	uninstallAllowUnsigned bool
+	uninstallAssumeYes     bool
+	uninstallLocalRecipes  string
+	uninstallRecipeNames   []string
+	uninstallRecipePaths   []string
+)
+
+// UninstallCommand represents the uninstall command.
+var UninstallCommand = &cobra.Command{
+	Use:   "uninstall",
+	Short: "Uninstall a New Relic recipe.",
+	Long: `Uninstall a New Relic recipe.
+
+Runs the uninstall tasks of the named recipes.  Only recipes that define an
+uninstall section can be uninstalled.
+
+The same uninstall tasks are used to roll back a recipe that fails during
+newrelic install.  Each install step that completed before the failure is undone
+by the uninstall task of the same name, in reverse order.`,
+	Example: `newrelic uninstall -n logs-integration
+newrelic uninstall -n mysql-open-source-integration -y`,
+	PreRun: client.RequireClient,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		extractedRecipeNames, err := processRecipeNames(uninstallRecipeNames)
+		if err != nil {
+			return err
+		}
+
+		ic := types.InstallerContext{
+			AssumeYes:        uninstallAssumeYes,
+			LocalRecipes:     uninstallLocalRecipes,
+			RecipeNames:      extractedRecipeNames,
+			RecipePaths:      uninstallRecipePaths,
+			RecipeSigningKey: configAPI.GetActiveProfileString(config.RecipeSigningKey),
+			AllowUnsigned:    uninstallAllowUnsigned,
+		}
+
+		logLevel := configAPI.GetLogLevel()
+		config.InitFileLogger(logLevel)
+
+		return NewRecipeInstaller(ic).Uninstall(utils.SignalCtx)
+	},
+}
+
+func init() {
+	UninstallCommand.Flags().StringSliceVarP(&uninstallRecipeNames, "recipe", "n", []string{}, "the name of a recipe to uninstall")
+	UninstallCommand.Flags().StringSliceVarP(&uninstallRecipePaths, "recipePath", "c", []string{}, "the path to a recipe file to uninstall")
+	UninstallCommand.Flags().BoolVarP(&uninstallAssumeYes, "assumeYes", "y", false, "use \"yes\" for all questions during uninstall")
+	UninstallCommand.Flags().StringVarP(&uninstallLocalRecipes, "localRecipes", "", "", "a path to local recipes to load instead of service other fetching")
+	UninstallCommand.Flags().BoolVar(&uninstallAllowUnsigned, "allow-unsigned", false, "uninstall recipe files without a signature, or when no recipe signing key is configured")
+	utils.LogIfError(UninstallCommand.MarkFlagRequired("recipe"))
+}
@@ -0,0 +1,146 @@
+package container
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/newrelic/newrelic-cli/internal/install/types"
+)
+
+var (
+	// Paths read to identify containers, overridden in tests.
+	procPath          = "/proc"
+	dockerEnvPath     = "/.dockerenv"
+	podmanEnvPath     = "/run/.containerenv"
+	kubeletConfigPath = "/var/lib/kubelet"
+
+	containerIDRegex = regexp.MustCompile(`[0-9a-f]{64}`)
+	ecsTaskRegex     = regexp.MustCompile(`/ecs/[0-9a-f]{32}`)
+)
+
+// cgroupRuntimes maps the markers container runtimes leave in cgroup paths to
+// the runtime.  Systemd cgroup drivers name scopes <runtime>-<id>.scope, while
+// the cgroupfs driver uses /<runtime>/<id>.
+var cgroupRuntimes = []struct {
+	marker  string
+	runtime types.OpenInstallationContainerRuntime
+}{
+	{"cri-containerd-", types.OpenInstallationContainerRuntimeTypes.CONTAINERD},
+	{"/containerd/", types.OpenInstallationContainerRuntimeTypes.CONTAINERD},
+	{"crio-", types.OpenInstallationContainerRuntimeTypes.CRIO},
+	{"/crio/", types.OpenInstallationContainerRuntimeTypes.CRIO},
+	{"libpod-", types.OpenInstallationContainerRuntimeTypes.PODMAN},
+	{"/libpod_parent/", types.OpenInstallationContainerRuntimeTypes.PODMAN},
+	{"docker-", types.OpenInstallationContainerRuntimeTypes.DOCKER},
+	{"/docker/", types.OpenInstallationContainerRuntimeTypes.DOCKER},
+}
+
+// FromCgroup identifies the container from the content of a /proc/<pid>/cgroup
+// file.  Processes running directly on the host return the zero Container.
+func FromCgroup(content string) types.Container {
+	c := types.Container{}
+
+	for _, line := range strings.Split(content, "\n") {
+		// hierarchy-ID:controller-list:cgroup-path
+		parts := strings.SplitN(line, ":", 3)
+		if len(parts) != 3 {
+			continue
+		}
+		path := parts[2]
+
+		if strings.Contains(path, "kubepods") {
+			c.Orchestrator = types.OpenInstallationOrchestratorTypes.KUBERNETES
+		} else if ecsTaskRegex.MatchString(path) {
+			c.Orchestrator = types.OpenInstallationOrchestratorTypes.ECS
+		}
+
+		if c.Runtime == "" {
+			for _, r := range cgroupRuntimes {
+				if strings.Contains(path, r.marker) {
+					c.Runtime = r.runtime
+					break
+				}
+			}
+		}
+
+		if c.ID == "" {
+			c.ID = containerIDRegex.FindString(path)
+		}
+	}
+
+	// ECS tasks run on Docker, and Kubernetes pods on containerd unless the
+	// cgroup path says otherwise.
+	if c.ID != "" && c.Runtime == "" {
+		switch c.Orchestrator {
+		case types.OpenInstallationOrchestratorTypes.ECS:
+			c.Runtime = types.OpenInstallationContainerRuntimeTypes.DOCKER
+		case types.OpenInstallationOrchestratorTypes.KUBERNETES:
+			c.Runtime = types.OpenInstallationContainerRuntimeTypes.CONTAINERD
+		}
+	}
+
+	return c
+}
+
+// ForPID identifies the container the process with the given PID runs in.
+func ForPID(pid int32) types.Container {
+	return fromCgroupFile(fmt.Sprintf("%s/%d/cgroup", procPath, pid))
+}
+
+// Self identifies the container the CLI runs in.  With cgroup namespaces the
+// cgroup path of a containerized process is /, so the files container runtimes
+// create in their containers and the Kubernetes service environment are also
+// checked.
+func Self() types.Container {
+	c := fromCgroupFile(fmt.Sprintf("%s/self/cgroup", procPath))
+
+	if c.Runtime == "" {
+		if fileExists(dockerEnvPath) {
+			c.Runtime = types.OpenInstallationContainerRuntimeTypes.DOCKER
+		} else if fileExists(podmanEnvPath) {
+			c.Runtime = types.OpenInstallationContainerRuntimeTypes.PODMAN
+		}
+	}
+
+	if c.Orchestrator == "" && os.Getenv("KUBERNETES_SERVICE_HOST") != "" {
+		c.Orchestrator = types.OpenInstallationOrchestratorTypes.KUBERNETES
+	}
+
+	return c
+}
+
+// HostOrchestrator returns the orchestrator managing this host, detected from
+// the kubelet state directory of Kubernetes nodes, or an empty string.
+func HostOrchestrator() types.OpenInstallationOrchestrator {
+	if fileExists(kubeletConfigPath) {
+		return types.OpenInstallationOrchestratorTypes.KUBERNETES
+	}
+
+	return ""
+}
+
+// IsOther returns true when c is a container other than self, the container
+// the CLI runs in.
+func IsOther(c types.Container, self types.Container) bool {
+	if !c.IsContainerized() {
+		return false
+	}
+
+	return c.ID == "" || c.ID != self.ID
+}
+
+func fromCgroupFile(path string) types.Container {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return types.Container{}
+	}
+
+	return FromCgroup(string(content))
+}
+
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
@@ -0,0 +1,153 @@
+//go:build unit
+
+package container
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/newrelic/newrelic-cli/internal/install/types"
+)
+
+const testContainerID = "3d9a5d7bb4c8a1ed2a04c6b4d0c8b8a5f0e3f1c2b7a6d5e4f3a2b1c0d9e8f7a6"
+
+func TestFromCgroup(t *testing.T) {
+	tests := []struct {
+		name     string
+		cgroup   string
+		expected types.Container
+	}{
+		{
+			name:     "host",
+			cgroup:   "0::/user.slice/user-1000.slice/session-2.scope\n",
+			expected: types.Container{},
+		},
+		{
+			name:   "docker with cgroupfs driver",
+			cgroup: "12:memory:/docker/" + testContainerID + "\n11:cpu:/docker/" + testContainerID + "\n",
+			expected: types.Container{
+				Runtime: types.OpenInstallationContainerRuntimeTypes.DOCKER,
+				ID:      testContainerID,
+			},
+		},
+		{
+			name:   "docker with systemd driver",
+			cgroup: "0::/system.slice/docker-" + testContainerID + ".scope\n",
+			expected: types.Container{
+				Runtime: types.OpenInstallationContainerRuntimeTypes.DOCKER,
+				ID:      testContainerID,
+			},
+		},
+		{
+			name:   "kubernetes with containerd",
+			cgroup: "0::/kubepods.slice/kubepods-burstable.slice/kubepods-burstable-pod1.slice/cri-containerd-" + testContainerID + ".scope\n",
+			expected: types.Container{
+				Runtime:      types.OpenInstallationContainerRuntimeTypes.CONTAINERD,
+				ID:           testContainerID,
+				Orchestrator: types.OpenInstallationOrchestratorTypes.KUBERNETES,
+			},
+		},
+		{
+			name:   "kubernetes with cri-o",
+			cgroup: "0::/kubepods.slice/kubepods-besteffort.slice/crio-" + testContainerID + ".scope\n",
+			expected: types.Container{
+				Runtime:      types.OpenInstallationContainerRuntimeTypes.CRIO,
+				ID:           testContainerID,
+				Orchestrator: types.OpenInstallationOrchestratorTypes.KUBERNETES,
+			},
+		},
+		{
+			name:   "kubernetes with cgroupfs driver",
+			cgroup: "4:memory:/kubepods/besteffort/pod6b4e7c1a/" + testContainerID + "\n",
+			expected: types.Container{
+				Runtime:      types.OpenInstallationContainerRuntimeTypes.CONTAINERD,
+				ID:           testContainerID,
+				Orchestrator: types.OpenInstallationOrchestratorTypes.KUBERNETES,
+			},
+		},
+		{
+			name:   "ecs",
+			cgroup: "9:perf_event:/ecs/0123456789abcdef0123456789abcdef/" + testContainerID + "\n",
+			expected: types.Container{
+				Runtime:      types.OpenInstallationContainerRuntimeTypes.DOCKER,
+				ID:           testContainerID,
+				Orchestrator: types.OpenInstallationOrchestratorTypes.ECS,
+			},
+		},
+		{
+			name:   "podman",
+			cgroup: "0::/user.slice/user-1000.slice/user@1000.service/user.slice/libpod-" + testContainerID + ".scope/container\n",
+			expected: types.Container{
+				Runtime: types.OpenInstallationContainerRuntimeTypes.PODMAN,
+				ID:      testContainerID,
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, FromCgroup(tc.cgroup))
+		})
+	}
+}
+
+func TestForPID(t *testing.T) {
+	dir := givenProcPath(t)
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "42"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "42", "cgroup"), []byte("0::/system.slice/docker-"+testContainerID+".scope\n"), 0644))
+
+	assert.Equal(t, types.OpenInstallationContainerRuntimeTypes.DOCKER, ForPID(42).Runtime)
+	assert.False(t, ForPID(43).IsContainerized())
+}
+
+func TestSelfShouldCheckContainerRuntimeFiles(t *testing.T) {
+	dir := givenProcPath(t)
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "self"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "self", "cgroup"), []byte("0::/\n"), 0644))
+
+	dockerEnv := filepath.Join(t.TempDir(), ".dockerenv")
+	require.NoError(t, os.WriteFile(dockerEnv, []byte{}, 0644))
+	defer func(path string) { dockerEnvPath = path }(dockerEnvPath)
+	dockerEnvPath = dockerEnv
+
+	t.Setenv("KUBERNETES_SERVICE_HOST", "10.0.0.1")
+
+	self := Self()
+
+	assert.Equal(t, types.OpenInstallationContainerRuntimeTypes.DOCKER, self.Runtime)
+	assert.Equal(t, types.OpenInstallationOrchestratorTypes.KUBERNETES, self.Orchestrator)
+}
+
+func TestHostOrchestrator(t *testing.T) {
+	defer func(path string) { kubeletConfigPath = path }(kubeletConfigPath)
+
+	kubeletConfigPath = filepath.Join(t.TempDir(), "missing")
+	assert.Empty(t, HostOrchestrator())
+
+	kubeletConfigPath = t.TempDir()
+	assert.Equal(t, types.OpenInstallationOrchestratorTypes.KUBERNETES, HostOrchestrator())
+}
+
+func TestIsOther(t *testing.T) {
+	host := types.Container{}
+	docker := types.Container{Runtime: types.OpenInstallationContainerRuntimeTypes.DOCKER, ID: testContainerID}
+
+	assert.False(t, IsOther(host, host))
+	assert.True(t, IsOther(docker, host))
+	assert.False(t, IsOther(docker, docker))
+	assert.False(t, IsOther(host, docker))
+}
+
+func givenProcPath(t *testing.T) string {
+	dir := t.TempDir()
+
+	original := procPath
+	procPath = dir
+	t.Cleanup(func() { procPath = original })
+
+	return dir
+}
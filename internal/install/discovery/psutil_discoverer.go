@@ -8,6 +8,7 @@ import (
 	"github.com/shirou/gopsutil/v3/host"
 	log "github.com/sirupsen/logrus"
 
+	"github.com/newrelic/newrelic-cli/internal/install/container"
 	"github.com/newrelic/newrelic-cli/internal/install/types"
 )
 
@@ -33,6 +34,13 @@ func (p *PSUtilDiscoverer) Discover(ctx context.Context) (*types.DiscoveryManife
 		PlatformVersion: i.PlatformVersion,
 	}
 
+	self := container.Self()
+	m.ContainerRuntime = string(self.Runtime)
+	m.Orchestrator = string(self.Orchestrator)
+	if m.Orchestrator == "" && !self.IsContainerized() {
+		m.Orchestrator = string(container.HostOrchestrator())
+	}
+
 	log.Debugf("discovered manifest %+v", m)
 
 	m = filterValues(m)
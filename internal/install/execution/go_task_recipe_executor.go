@@ -27,6 +27,9 @@ import (
 // GoTaskRecipeExecutor is an implementation of the recipeExecutor interface that
 // uses the go-task module to execute the steps defined in each recipe.
 type GoTaskRecipeExecutor struct {
+	// Dir is the working directory recipes are run from, and where their
+	// taskfiles are written.  It defaults to the system temp directory.
+	Dir          string
 	Stderr       io.Writer
 	Stdin        io.Reader
 	Stdout       io.Writer
@@ -49,7 +52,27 @@ func (re *GoTaskRecipeExecutor) ExecutePreInstall(ctx context.Context, r types.O
 	return errors.New("not implemented")
 }
 
-func (re *GoTaskRecipeExecutor) Execute(ctx context.Context, r types.OpenInstallationRecipe, recipeVars types.RecipeVars) (retErr error) {
+func (re *GoTaskRecipeExecutor) Execute(ctx context.Context, r types.OpenInstallationRecipe, recipeVars types.RecipeVars) error {
+	log.Debugf("executing recipe %s", r.Name)
+
+	return re.run(ctx, r, r.Install, recipeVars, nil)
+}
+
+// ExecuteUninstall runs the given tasks from the recipe's uninstall section, or
+// its default task when no tasks are given.
+func (re *GoTaskRecipeExecutor) ExecuteUninstall(ctx context.Context, r types.OpenInstallationRecipe, recipeVars types.RecipeVars, tasks []string) error {
+	if r.Uninstall == "" {
+		return fmt.Errorf("recipe %s does not define an uninstall section", r.Name)
+	}
+
+	log.Debugf("uninstalling recipe %s with tasks %v", r.Name, tasks)
+
+	return re.run(ctx, r, r.Uninstall, recipeVars, tasks)
+}
+
+// run executes a go-task taskfile for the recipe.  When no tasks are given the
+// tasks named on the command line, or the default task, are run.
+func (re *GoTaskRecipeExecutor) run(ctx context.Context, r types.OpenInstallationRecipe, content string, recipeVars types.RecipeVars, tasks []string) (retErr error) {
 	defer func() {
 		if r := recover(); r != nil {
 			switch x := r.(type) {
@@ -63,10 +86,13 @@ func (re *GoTaskRecipeExecutor) Execute(ctx context.Context, r types.OpenInstall
 		}
 	}()
 
-	log.Debugf("executing recipe %s", r.Name)
+	dir := re.Dir
+	if dir == "" {
+		dir = os.TempDir()
+	}
 
 	// unmarshall task file & create/write to temp file
-	taskFile, err := createRecipeTempFile(r)
+	taskFile, err := createRecipeTempFile(dir, r.Name, content)
 	if err != nil {
 		return err
 	}
@@ -94,7 +120,7 @@ func (re *GoTaskRecipeExecutor) Execute(ctx context.Context, r types.OpenInstall
 	}
 
 	e := task.Executor{
-		Dir:        os.TempDir(),
+		Dir:        dir,
 		Entrypoint: filepath.Base(taskFile.Name()),
 		Stdin:      re.Stdin,
 		Stdout:     stdoutCapture,
@@ -105,6 +131,12 @@ func (re *GoTaskRecipeExecutor) Execute(ctx context.Context, r types.OpenInstall
 	}
 
 	calls, globals := taskargs.ParseV3()
+	if len(tasks) > 0 {
+		calls = make([]taskfile.Call, len(tasks))
+		for i, t := range tasks {
+			calls[i] = taskfile.Call{Task: t}
+		}
+	}
 	e.Taskfile.Vars.Merge(globals)
 	for k, val := range recipeVars {
 		e.Taskfile.Vars.Set(k, taskfile.Var{Static: val})
@@ -155,13 +187,13 @@ func (re *GoTaskRecipeExecutor) Execute(ctx context.Context, r types.OpenInstall
 	return nil
 }
 
-func createRecipeTempFile(r types.OpenInstallationRecipe) (*os.File, error) {
-	out := []byte(r.Install)
+func createRecipeTempFile(dir string, name string, content string) (*os.File, error) {
+	out := []byte(content)
 	err := yaml.Unmarshal(out, &taskfile.Taskfile{})
 	if err != nil {
 		return nil, fmt.Errorf("could not unmarshal taskfile: %s", err)
 	}
-	taskFile, err := ioutil.TempFile("", r.Name)
+	taskFile, err := ioutil.TempFile(dir, name)
 	if err != nil {
 		return nil, err
 	}
@@ -2,6 +2,7 @@ package execution
 
 import (
 	"fmt"
+	"sync"
 
 	"github.com/google/uuid"
 	log "github.com/sirupsen/logrus"
@@ -47,6 +48,7 @@ type InstallStatus struct {
 	statusSubscriber      []StatusSubscriber
 	successLinkConfig     types.OpenInstallationSuccessLinkConfig
 	PlatformLinkGenerator LinkGenerator
+	mu                    *sync.Mutex
 }
 
 type RecipeStatus struct {
@@ -111,11 +113,27 @@ func NewInstallStatus(context types.InstallerContext, reporters []StatusSubscrib
 		PlatformLinkGenerator: PlatformLinkGenerator,
 		HTTPSProxy:            httpproxy.FromEnvironment().HTTPSProxy,
 		CLIVersion:            cli.Version(),
+		mu:                    &sync.Mutex{},
 	}
 
 	return &s
 }
 
+// lock serializes the recipe status updates of recipes installed concurrently.
+func (s *InstallStatus) lock() func() {
+	if s.mu == nil {
+		return func() {}
+	}
+
+	s.mu.Lock()
+	return s.mu.Unlock
+}
+
+// AddStatusSubscriber adds a subscriber to be notified of the install's lifecycle events.
+func (s *InstallStatus) AddStatusSubscriber(subscriber StatusSubscriber) {
+	s.statusSubscriber = append(s.statusSubscriber, subscriber)
+}
+
 func (s *InstallStatus) DiscoveryComplete(dm types.DiscoveryManifest) {
 	s.withDiscoveryInfo(dm)
 
@@ -131,6 +149,8 @@ func (s *InstallStatus) DiscoveryComplete(dm types.DiscoveryManifest) {
 // RecipeDetected is called when a recipe is available and passes the checks in both
 // the process match and the pre-install steps of recipe execution.
 func (s *InstallStatus) RecipeDetected(event RecipeStatusEvent) {
+	defer s.lock()()
+
 	s.withRecipeEvent(event, RecipeStatusTypes.DETECTED)
 	s.Detected = append(s.Detected, &RecipeStatus{
 		Name:        event.Recipe.Name,
@@ -146,6 +166,8 @@ func (s *InstallStatus) RecipeDetected(event RecipeStatusEvent) {
 }
 
 func (s *InstallStatus) RecipeCanceled(event RecipeStatusEvent) {
+	defer s.lock()()
+
 	s.withRecipeEvent(event, RecipeStatusTypes.CANCELED)
 	for _, r := range s.statusSubscriber {
 		if err := r.RecipeCanceled(s, event); err != nil {
@@ -155,6 +177,8 @@ func (s *InstallStatus) RecipeCanceled(event RecipeStatusEvent) {
 }
 
 func (s *InstallStatus) RecipeAvailable(event RecipeStatusEvent) {
+	defer s.lock()()
+
 	s.withRecipeEvent(event, RecipeStatusTypes.AVAILABLE)
 	for _, ss := range s.statusSubscriber {
 		if err := ss.RecipeAvailable(s, event); err != nil {
@@ -164,6 +188,8 @@ func (s *InstallStatus) RecipeAvailable(event RecipeStatusEvent) {
 }
 
 func (s *InstallStatus) RecipeInstalled(event RecipeStatusEvent) {
+	defer s.lock()()
+
 	s.withRecipeEvent(event, RecipeStatusTypes.INSTALLED)
 
 	for _, r := range s.statusSubscriber {
@@ -179,6 +205,8 @@ func (s *InstallStatus) RecipeInstalled(event RecipeStatusEvent) {
 // should consider integrating, but not something that the recipe framework
 // will currently assist with.
 func (s *InstallStatus) RecipeRecommended(event RecipeStatusEvent) {
+	defer s.lock()()
+
 	s.withRecipeEvent(event, RecipeStatusTypes.RECOMMENDED)
 
 	for _, r := range s.statusSubscriber {
@@ -189,6 +217,8 @@ func (s *InstallStatus) RecipeRecommended(event RecipeStatusEvent) {
 }
 
 func (s *InstallStatus) RecipeInstalling(event RecipeStatusEvent) {
+	defer s.lock()()
+
 	s.withRecipeEvent(event, RecipeStatusTypes.INSTALLING)
 
 	for _, r := range s.statusSubscriber {
@@ -199,6 +229,8 @@ func (s *InstallStatus) RecipeInstalling(event RecipeStatusEvent) {
 }
 
 func (s *InstallStatus) RecipeFailed(event RecipeStatusEvent) {
+	defer s.lock()()
+
 	s.withRecipeEvent(event, RecipeStatusTypes.FAILED)
 
 	for _, r := range s.statusSubscriber {
@@ -209,6 +241,8 @@ func (s *InstallStatus) RecipeFailed(event RecipeStatusEvent) {
 }
 
 func (s *InstallStatus) RecipeSkipped(event RecipeStatusEvent) {
+	defer s.lock()()
+
 	s.withRecipeEvent(event, RecipeStatusTypes.SKIPPED)
 
 	for _, r := range s.statusSubscriber {
@@ -219,6 +253,8 @@ func (s *InstallStatus) RecipeSkipped(event RecipeStatusEvent) {
 }
 
 func (s *InstallStatus) RecipeUnsupported(event RecipeStatusEvent) {
+	defer s.lock()()
+
 	s.withRecipeEvent(event, RecipeStatusTypes.UNSUPPORTED)
 	s.Unsupported = append(s.Unsupported, &RecipeStatus{
 		Name:        event.Recipe.Name,
@@ -219,9 +219,9 @@ func buildRecipeStatus(status *InstallStatus, event *RecipeStatusEvent, statusTy
 		i.ValidationDurationMilliseconds = event.ValidationDurationMs
 		i.TaskPath = strings.Join(event.TaskPath, ",")
 
-		if len(event.Metadata) > 0 {
+		if m := event.metadata(); len(m) > 0 {
 			i.Metadata = map[string]interface{}{}
-			for k, v := range event.Metadata {
+			for k, v := range m {
 				i.Metadata[k] = v
 			}
 		}
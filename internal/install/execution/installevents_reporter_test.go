@@ -243,3 +243,19 @@ func TestUpdateTargedInstallEventShouldSet(t *testing.T) {
 	updateTargetedInstallEvent(status, &i)
 	require.False(t, i.TargetedInstall)
 }
+
+func TestBuildRecipeStatusShouldReportRecipeSignature(t *testing.T) {
+	status := NewInstallStatus(types.InstallerContext{}, []StatusSubscriber{}, NewMockPlatformLinkGenerator())
+	recipe := types.OpenInstallationRecipe{Name: "test-recipe"}
+	recipe.SetSignature(&types.RecipeSignature{Status: types.RecipeSignatureStatuses.VERIFIED, KeyID: "0123456789abcdef"})
+	defer recipe.SetSignature(nil)
+	event := NewRecipeStatusEvent(&recipe)
+	event.Metadata["RollbackStatus"] = "SUCCEEDED"
+
+	i := buildRecipeStatus(status, &event, nil)
+
+	require.Equal(t, "VERIFIED", i.Metadata["signatureStatus"])
+	require.Equal(t, "0123456789abcdef", i.Metadata["signatureKeyId"])
+	require.Equal(t, "SUCCEEDED", i.Metadata["RollbackStatus"])
+	require.Len(t, event.Metadata, 1)
+}
@@ -0,0 +1,21 @@
+package execution
+
+import (
+	"context"
+
+	"github.com/newrelic/newrelic-cli/internal/install/types"
+)
+
+type MockRecipeUninstaller struct {
+	UninstallErr   error
+	UninstallCalls [][]string
+}
+
+func NewMockRecipeUninstaller() *MockRecipeUninstaller {
+	return &MockRecipeUninstaller{}
+}
+
+func (m *MockRecipeUninstaller) ExecuteUninstall(ctx context.Context, r types.OpenInstallationRecipe, v types.RecipeVars, tasks []string) error {
+	m.UninstallCalls = append(m.UninstallCalls, tasks)
+	return m.UninstallErr
+}
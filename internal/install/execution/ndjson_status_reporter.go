@@ -0,0 +1,221 @@
+package execution
+
+import (
+	"encoding/json"
+	"io"
+	"sync"
+
+	"github.com/newrelic/newrelic-cli/internal/install/types"
+	"github.com/newrelic/newrelic-cli/internal/utils"
+)
+
+// InstallSummaryEvent is the name of the last event written by the
+// NDJSONStatusReporter, summarizing the result of the install.
+const InstallSummaryEvent = "InstallSummary"
+
+// NDJSONStatusReporter is an implementation of the StatusSubscriber interface
+// that writes every lifecycle callback as a single line of JSON, so that the
+// install can be followed by other tools without parsing the terminal output.
+type NDJSONStatusReporter struct {
+	w  io.Writer
+	mu sync.Mutex
+}
+
+// NDJSONEvent is a single line written by the NDJSONStatusReporter.
+type NDJSONEvent struct {
+	Event                string                   `json:"event"`
+	Timestamp            int64                    `json:"timestamp"`
+	InstallID            string                   `json:"installId"`
+	Recipe               string                   `json:"recipe,omitempty"`
+	DisplayName          string                   `json:"displayName,omitempty"`
+	Status               RecipeStatusType         `json:"status,omitempty"`
+	EntityGUID           string                   `json:"entityGuid,omitempty"`
+	ValidationDurationMs int64                    `json:"validationDurationMs,omitempty"`
+	Error                *StatusError             `json:"error,omitempty"`
+	Metadata             map[string]string        `json:"metadata,omitempty"`
+	Recipes              []string                 `json:"recipes,omitempty"`
+	Discovery            *types.DiscoveryManifest `json:"discovery,omitempty"`
+}
+
+// NDJSONSummary is the last line written by the NDJSONStatusReporter once the
+// install has completed or been canceled.
+type NDJSONSummary struct {
+	Event       string          `json:"event"`
+	Timestamp   int64           `json:"timestamp"`
+	InstallID   string          `json:"installId"`
+	Canceled    bool            `json:"canceled"`
+	Success     bool            `json:"success"`
+	Error       *StatusError    `json:"error,omitempty"`
+	EntityGUIDs []string        `json:"entityGuids,omitempty"`
+	Counts      map[string]int  `json:"counts"`
+	Recipes     []*RecipeStatus `json:"recipes"`
+	RedirectURL string          `json:"redirectUrl,omitempty"`
+	LogFilePath string          `json:"logFilePath,omitempty"`
+}
+
+// NewNDJSONStatusReporter returns a StatusSubscriber writing newline delimited JSON to w.
+func NewNDJSONStatusReporter(w io.Writer) *NDJSONStatusReporter {
+	return &NDJSONStatusReporter{w: w}
+}
+
+func (r *NDJSONStatusReporter) UpdateRequired(status *InstallStatus) error {
+	return r.writeInstallEvent("UpdateRequired", status)
+}
+
+func (r *NDJSONStatusReporter) InstallStarted(status *InstallStatus) error {
+	return r.writeInstallEvent("InstallStarted", status)
+}
+
+func (r *NDJSONStatusReporter) InstallCanceled(status *InstallStatus) error {
+	if err := r.writeInstallEvent("InstallCanceled", status); err != nil {
+		return err
+	}
+
+	return r.writeSummary(status, true)
+}
+
+func (r *NDJSONStatusReporter) InstallComplete(status *InstallStatus) error {
+	if err := r.writeInstallEvent("InstallComplete", status); err != nil {
+		return err
+	}
+
+	return r.writeSummary(status, false)
+}
+
+func (r *NDJSONStatusReporter) DiscoveryComplete(status *InstallStatus, dm types.DiscoveryManifest) error {
+	e := r.newEvent("DiscoveryComplete", status)
+	e.Discovery = &dm
+
+	return r.write(e)
+}
+
+func (r *NDJSONStatusReporter) RecipeDetected(status *InstallStatus, event RecipeStatusEvent) error {
+	return r.writeRecipeEvent("RecipeDetected", RecipeStatusTypes.DETECTED, status, event)
+}
+
+func (r *NDJSONStatusReporter) RecipeCanceled(status *InstallStatus, event RecipeStatusEvent) error {
+	return r.writeRecipeEvent("RecipeCanceled", RecipeStatusTypes.CANCELED, status, event)
+}
+
+func (r *NDJSONStatusReporter) RecipeAvailable(status *InstallStatus, event RecipeStatusEvent) error {
+	return r.writeRecipeEvent("RecipeAvailable", RecipeStatusTypes.AVAILABLE, status, event)
+}
+
+func (r *NDJSONStatusReporter) RecipeFailed(status *InstallStatus, event RecipeStatusEvent) error {
+	return r.writeRecipeEvent("RecipeFailed", RecipeStatusTypes.FAILED, status, event)
+}
+
+func (r *NDJSONStatusReporter) RecipeInstalled(status *InstallStatus, event RecipeStatusEvent) error {
+	return r.writeRecipeEvent("RecipeInstalled", RecipeStatusTypes.INSTALLED, status, event)
+}
+
+func (r *NDJSONStatusReporter) RecipeInstalling(status *InstallStatus, event RecipeStatusEvent) error {
+	return r.writeRecipeEvent("RecipeInstalling", RecipeStatusTypes.INSTALLING, status, event)
+}
+
+func (r *NDJSONStatusReporter) RecipeRecommended(status *InstallStatus, event RecipeStatusEvent) error {
+	return r.writeRecipeEvent("RecipeRecommended", RecipeStatusTypes.RECOMMENDED, status, event)
+}
+
+func (r *NDJSONStatusReporter) RecipeSkipped(status *InstallStatus, event RecipeStatusEvent) error {
+	return r.writeRecipeEvent("RecipeSkipped", RecipeStatusTypes.SKIPPED, status, event)
+}
+
+func (r *NDJSONStatusReporter) RecipeUnsupported(status *InstallStatus, event RecipeStatusEvent) error {
+	return r.writeRecipeEvent("RecipeUnsupported", RecipeStatusTypes.UNSUPPORTED, status, event)
+}
+
+func (r *NDJSONStatusReporter) RecipesSelected(status *InstallStatus, recipes []types.OpenInstallationRecipe) error {
+	e := r.newEvent("RecipesSelected", status)
+	e.Recipes = []string{}
+	for _, recipe := range recipes {
+		e.Recipes = append(e.Recipes, recipe.Name)
+	}
+
+	return r.write(e)
+}
+
+func (r *NDJSONStatusReporter) newEvent(name string, status *InstallStatus) NDJSONEvent {
+	return NDJSONEvent{
+		Event:     name,
+		Timestamp: utils.GetTimestamp(),
+		InstallID: status.InstallID,
+	}
+}
+
+func (r *NDJSONStatusReporter) writeInstallEvent(name string, status *InstallStatus) error {
+	e := r.newEvent(name, status)
+	if status.Error.Message != "" {
+		statusError := status.Error
+		e.Error = &statusError
+	}
+
+	return r.write(e)
+}
+
+func (r *NDJSONStatusReporter) writeRecipeEvent(name string, rs RecipeStatusType, status *InstallStatus, event RecipeStatusEvent) error {
+	e := r.newEvent(name, status)
+	e.Recipe = event.Recipe.Name
+	e.DisplayName = event.Recipe.DisplayName
+	e.Status = rs
+	e.EntityGUID = event.EntityGUID
+	e.ValidationDurationMs = event.ValidationDurationMs
+
+	if m := event.metadata(); len(m) > 0 {
+		e.Metadata = m
+	}
+
+	if event.Msg != "" || event.OptimizedMessage != "" {
+		e.Error = &StatusError{
+			Message:          event.Msg,
+			TaskPath:         event.TaskPath,
+			OptimizedMessage: event.OptimizedMessage,
+		}
+	}
+
+	return r.write(e)
+}
+
+func (r *NDJSONStatusReporter) writeSummary(status *InstallStatus, canceled bool) error {
+	s := NDJSONSummary{
+		Event:       InstallSummaryEvent,
+		Timestamp:   utils.GetTimestamp(),
+		InstallID:   status.InstallID,
+		Canceled:    canceled,
+		Success:     status.WasSuccessful(),
+		EntityGUIDs: status.EntityGUIDs,
+		Counts:      map[string]int{},
+		Recipes:     []*RecipeStatus{},
+		RedirectURL: status.RedirectURL,
+		LogFilePath: status.LogFilePath,
+	}
+
+	if status.Error.Message != "" {
+		statusError := status.Error
+		s.Error = &statusError
+	}
+
+	for _, rs := range status.Statuses {
+		if rs.Status == RecipeStatusTypes.DETECTED || rs.Status == RecipeStatusTypes.RECOMMENDED {
+			continue
+		}
+
+		s.Recipes = append(s.Recipes, rs)
+		s.Counts[string(rs.Status)]++
+	}
+
+	return r.write(s)
+}
+
+func (r *NDJSONStatusReporter) write(v interface{}) error {
+	line, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	_, err = r.w.Write(append(line, '\n'))
+	return err
+}
@@ -0,0 +1,102 @@
+package execution
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/newrelic/newrelic-cli/internal/install/types"
+)
+
+func TestNDJSONStatusReporter_interface(t *testing.T) {
+	var r StatusSubscriber = NewNDJSONStatusReporter(&bytes.Buffer{})
+	require.NotNil(t, r)
+}
+
+func TestNDJSONStatusReporter_ShouldWriteOneLinePerEvent(t *testing.T) {
+	buf := &bytes.Buffer{}
+	r := NewNDJSONStatusReporter(buf)
+	status := NewInstallStatus(types.InstallerContext{}, []StatusSubscriber{r}, NewMockPlatformLinkGenerator())
+	infra := types.OpenInstallationRecipe{Name: types.InfraAgentRecipeName, DisplayName: "Infrastructure Agent"}
+	mysql := types.OpenInstallationRecipe{Name: "mysql-open-source-integration", DisplayName: "MySQL Integration"}
+
+	status.InstallStarted()
+	status.RecipeInstalling(RecipeStatusEvent{Recipe: infra})
+	status.RecipeInstalled(RecipeStatusEvent{Recipe: infra, EntityGUID: "MXxJTkZSQXxOQXwx", ValidationDurationMs: 1500})
+	status.RecipeFailed(RecipeStatusEvent{
+		Recipe:           mysql,
+		Msg:              "execution failed for mysql-open-source-integration: exit status 1",
+		TaskPath:         []string{"default", "setup"},
+		OptimizedMessage: "exit status 1",
+		Metadata:         map[string]string{"RollbackStatus": "SUCCEEDED"},
+	})
+	status.InstallComplete(nil)
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	require.Len(t, lines, 6)
+
+	events := []NDJSONEvent{}
+	for _, line := range lines[:5] {
+		e := NDJSONEvent{}
+		require.NoError(t, json.Unmarshal([]byte(line), &e))
+		require.Equal(t, status.InstallID, e.InstallID)
+		events = append(events, e)
+	}
+
+	require.Equal(t, "InstallStarted", events[0].Event)
+	require.Equal(t, "RecipeInstalling", events[1].Event)
+	require.Equal(t, RecipeStatusTypes.INSTALLING, events[1].Status)
+	require.Equal(t, "RecipeInstalled", events[2].Event)
+	require.Equal(t, "MXxJTkZSQXxOQXwx", events[2].EntityGUID)
+	require.Equal(t, int64(1500), events[2].ValidationDurationMs)
+	require.Nil(t, events[2].Error)
+	require.Equal(t, "RecipeFailed", events[3].Event)
+	require.Equal(t, "mysql-open-source-integration", events[3].Recipe)
+	require.Equal(t, []string{"default", "setup"}, events[3].Error.TaskPath)
+	require.Equal(t, "exit status 1", events[3].Error.OptimizedMessage)
+	require.Equal(t, "SUCCEEDED", events[3].Metadata["RollbackStatus"])
+	require.Equal(t, "InstallComplete", events[4].Event)
+
+	summary := NDJSONSummary{}
+	require.NoError(t, json.Unmarshal([]byte(lines[5]), &summary))
+	require.Equal(t, InstallSummaryEvent, summary.Event)
+	require.True(t, summary.Success)
+	require.False(t, summary.Canceled)
+	require.Equal(t, map[string]int{"INSTALLED": 1, "FAILED": 1}, summary.Counts)
+	require.Len(t, summary.Recipes, 2)
+}
+
+func TestNDJSONStatusReporter_ShouldSummarizeCanceledInstall(t *testing.T) {
+	buf := &bytes.Buffer{}
+	r := NewNDJSONStatusReporter(buf)
+	status := NewInstallStatus(types.InstallerContext{}, []StatusSubscriber{r}, NewMockPlatformLinkGenerator())
+	status.Statuses = []*RecipeStatus{{Name: "test-recipe", Status: RecipeStatusTypes.AVAILABLE}}
+
+	status.InstallCanceled()
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	require.Len(t, lines, 2)
+
+	summary := NDJSONSummary{}
+	require.NoError(t, json.Unmarshal([]byte(lines[1]), &summary))
+	require.True(t, summary.Canceled)
+	require.False(t, summary.Success)
+	require.Equal(t, map[string]int{"CANCELED": 1}, summary.Counts)
+}
+
+func TestNDJSONStatusReporter_ShouldIncludeInstallError(t *testing.T) {
+	buf := &bytes.Buffer{}
+	r := NewNDJSONStatusReporter(buf)
+	status := NewInstallStatus(types.InstallerContext{}, []StatusSubscriber{r}, NewMockPlatformLinkGenerator())
+
+	status.InstallComplete(errors.New("no recipes found"))
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	summary := NDJSONSummary{}
+	require.NoError(t, json.Unmarshal([]byte(lines[len(lines)-1]), &summary))
+	require.Equal(t, "no recipes found", summary.Error.Message)
+}
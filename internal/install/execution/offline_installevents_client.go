@@ -0,0 +1,109 @@
+package execution
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync"
+
+	"github.com/newrelic/newrelic-client-go/v2/pkg/installevents"
+)
+
+// RecordedInstallEvent is a single install event recorded by the
+// OfflineInstallEventsClient, holding either a recipe event or an install status.
+type RecordedInstallEvent struct {
+	AccountID     int                                           `json:"accountId"`
+	RecipeEvent   *installevents.InstallationRecipeStatus       `json:"recipeEvent,omitempty"`
+	InstallStatus *installevents.InstallationInstallStatusInput `json:"installStatus,omitempty"`
+}
+
+// OfflineInstallEventsClient is an implementation of the InstallEventsClient
+// interface that writes install events as newline delimited JSON instead of
+// sending them, so that installs on hosts without network access can be
+// reported once the events are uploaded with UploadRecordedInstallEvents.
+type OfflineInstallEventsClient struct {
+	w  io.Writer
+	mu sync.Mutex
+}
+
+// NewOfflineInstallEventsClient returns an InstallEventsClient recording install events to w.
+func NewOfflineInstallEventsClient(w io.Writer) *OfflineInstallEventsClient {
+	return &OfflineInstallEventsClient{w: w}
+}
+
+func (c *OfflineInstallEventsClient) InstallationCreateRecipeEvent(accountID int, status installevents.InstallationRecipeStatus) (*installevents.InstallationRecipeEvent, error) {
+	err := c.record(RecordedInstallEvent{AccountID: accountID, RecipeEvent: &status})
+	if err != nil {
+		return nil, err
+	}
+
+	return &installevents.InstallationRecipeEvent{}, nil
+}
+
+func (c *OfflineInstallEventsClient) InstallationCreateInstallStatus(accountID int, status installevents.InstallationInstallStatusInput) (*installevents.InstallationInstallStatus, error) {
+	err := c.record(RecordedInstallEvent{AccountID: accountID, InstallStatus: &status})
+	if err != nil {
+		return nil, err
+	}
+
+	return &installevents.InstallationInstallStatus{}, nil
+}
+
+func (c *OfflineInstallEventsClient) record(e RecordedInstallEvent) error {
+	line, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	_, err = c.w.Write(append(line, '\n'))
+	return err
+}
+
+// UploadRecordedInstallEvents sends the install events recorded by an
+// OfflineInstallEventsClient in the order they were recorded.  Events recorded
+// without an account ID are sent to accountID.  It returns the number of
+// events sent before any error.
+func UploadRecordedInstallEvents(r io.Reader, client InstallEventsClient, accountID int) (int, error) {
+	scanner := bufio.NewScanner(r)
+	// Recipe events carry the recipe metadata, which can exceed the default token size.
+	scanner.Buffer(make([]byte, 0, 64*1024), 4*1024*1024)
+
+	sent := 0
+	line := 0
+	for scanner.Scan() {
+		line++
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+
+		var e RecordedInstallEvent
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			return sent, fmt.Errorf("could not parse recorded install event on line %d: %s", line, err)
+		}
+
+		if e.AccountID == 0 {
+			e.AccountID = accountID
+		}
+
+		var err error
+		switch {
+		case e.RecipeEvent != nil:
+			_, err = client.InstallationCreateRecipeEvent(e.AccountID, *e.RecipeEvent)
+		case e.InstallStatus != nil:
+			_, err = client.InstallationCreateInstallStatus(e.AccountID, *e.InstallStatus)
+		default:
+			return sent, fmt.Errorf("recorded install event on line %d has no recipe event or install status", line)
+		}
+		if err != nil {
+			return sent, fmt.Errorf("could not upload recorded install event on line %d: %s", line, err)
+		}
+
+		sent++
+	}
+
+	return sent, scanner.Err()
+}
@@ -0,0 +1,67 @@
+//go:build unit
+
+package execution
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/newrelic/newrelic-cli/internal/install/types"
+)
+
+func TestOfflineInstallEventsClientShouldRecordEvents(t *testing.T) {
+	buf := &bytes.Buffer{}
+	r := NewInstallEventsReporter(NewOfflineInstallEventsClient(buf))
+	status := NewInstallStatus(types.InstallerContext{}, []StatusSubscriber{}, NewMockPlatformLinkGenerator())
+
+	require.NoError(t, r.InstallStarted(status))
+	require.NoError(t, r.RecipeInstalled(status, RecipeStatusEvent{Recipe: types.OpenInstallationRecipe{Name: "test-recipe"}, EntityGUID: "testGuid"}))
+	require.NoError(t, r.InstallComplete(status))
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	require.Len(t, lines, 3)
+	assert.Contains(t, lines[0], `"installStatus"`)
+	assert.Contains(t, lines[1], `"recipeEvent"`)
+	assert.Contains(t, lines[1], "test-recipe")
+	assert.Contains(t, lines[2], `"installStatus"`)
+}
+
+func TestUploadRecordedInstallEvents(t *testing.T) {
+	buf := &bytes.Buffer{}
+	r := NewInstallEventsReporter(NewOfflineInstallEventsClient(buf))
+	status := NewInstallStatus(types.InstallerContext{}, []StatusSubscriber{}, NewMockPlatformLinkGenerator())
+	require.NoError(t, r.InstallStarted(status))
+	require.NoError(t, r.RecipeInstalled(status, RecipeStatusEvent{Recipe: types.OpenInstallationRecipe{Name: "test-recipe"}}))
+	buf.WriteString("\n")
+
+	c := NewMockInstallEventsClient()
+	sent, err := UploadRecordedInstallEvents(buf, c, 12345)
+
+	require.NoError(t, err)
+	assert.Equal(t, 2, sent)
+	assert.Equal(t, 1, c.CreateInstallStatusCallCount)
+	assert.Equal(t, 1, c.CreateInstallEventCallCount)
+}
+
+func TestUploadRecordedInstallEventsShouldStopOnError(t *testing.T) {
+	c := NewMockInstallEventsClient()
+	c.CreateInstallStatusErr = errors.New("unauthorized")
+
+	sent, err := UploadRecordedInstallEvents(strings.NewReader(`{"accountId":1,"installStatus":{}}`+"\n"), c, 0)
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "unauthorized")
+	assert.Equal(t, 0, sent)
+}
+
+func TestUploadRecordedInstallEventsShouldRejectInvalidLines(t *testing.T) {
+	_, err := UploadRecordedInstallEvents(strings.NewReader("{}\n"), NewMockInstallEventsClient(), 0)
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "line 1")
+}
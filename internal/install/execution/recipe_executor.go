@@ -13,3 +13,8 @@ type RecipeExecutor interface {
 	GetOutput() *OutputParser
 	GetRecipeOutput() []string
 }
+
+// RecipeUninstaller is responsible for execution of the uninstall steps defined in a recipe.
+type RecipeUninstaller interface {
+	ExecuteUninstall(ctx context.Context, r types.OpenInstallationRecipe, vars types.RecipeVars, tasks []string) error
+}
@@ -32,7 +32,10 @@ const (
 	EnvInstallCustomAttributes    = "INSTALL_CUSTOM_ATTRIBUTES"
 )
 
-type RecipeVarProvider struct{}
+type RecipeVarProvider struct {
+	// Answers supplies input variable values from an install answers file.
+	Answers types.RecipeAnswers
+}
 
 func NewRecipeVarProvider() *RecipeVarProvider {
 	return &RecipeVarProvider{}
@@ -54,7 +57,7 @@ func (re *RecipeVarProvider) Prepare(m types.DiscoveryManifest, r types.OpenInst
 		return types.RecipeVars{}, err
 	}
 
-	inputVarsResult, err := varsFromInput(r.InputVars, assumeYes)
+	inputVarsResult, err := varsFromInput(r.InputVars, re.Answers[r.Name], assumeYes)
 	if err != nil {
 		return types.RecipeVars{}, err
 	}
@@ -101,17 +104,31 @@ func varsFromSystemInfo(m types.DiscoveryManifest) types.RecipeVars {
 	vars["PLATFORM_VERSION"] = m.PlatformVersion
 	vars["KERNEL_ARCH"] = m.KernelArch
 	vars["KERNEL_VERSION"] = m.KernelVersion
+	vars["CONTAINER_RUNTIME"] = m.ContainerRuntime
+	vars["ORCHESTRATOR"] = m.Orchestrator
 
 	return vars
 }
 
-func varsFromInput(inputVars []types.OpenInstallationRecipeInputVariable, assumeYes bool) (types.RecipeVars, error) {
+// varsFromInput resolves each input variable from, in order, the answers
+// file, the environment, and then the default or a prompt.
+func varsFromInput(inputVars []types.OpenInstallationRecipeInputVariable, answers map[string]string, assumeYes bool) (types.RecipeVars, error) {
 	vars := make(types.RecipeVars)
 
 	vars["NEW_RELIC_ASSUME_YES"] = fmt.Sprintf("%t", assumeYes)
 
 	for _, envConfig := range inputVars {
 		var err error
+
+		if answer, ok := answers[envConfig.Name]; ok {
+			log.WithFields(log.Fields{
+				"name": envConfig.Name,
+			}).Debug("using value from answers file")
+
+			vars[envConfig.Name] = answer
+			continue
+		}
+
 		envValue := os.Getenv(envConfig.Name)
 
 		if envValue != "" {
@@ -187,9 +204,9 @@ func varFromPrompt(envConfig types.OpenInstallationRecipeInputVariable) (string,
 
 }
 
-func varFromEnv() types.RecipeVars {
-	vars := make(types.RecipeVars)
-
+// DownloadURL returns the base URL recipes download New Relic artifacts from,
+// overridden by NEW_RELIC_DOWNLOAD_URL when it is an allowed https URL.
+func DownloadURL() string {
 	downloadURL := "https://download.newrelic.com/"
 	envDownloadURL := os.Getenv("NEW_RELIC_DOWNLOAD_URL")
 	if envDownloadURL != "" {
@@ -208,7 +225,14 @@ func varFromEnv() types.RecipeVars {
 			log.Warnf("Could not parse download URL: %s, detail: %s", envDownloadURL, err.Error())
 		}
 	}
-	vars["NEW_RELIC_DOWNLOAD_URL"] = downloadURL
+
+	return downloadURL
+}
+
+func varFromEnv() types.RecipeVars {
+	vars := make(types.RecipeVars)
+
+	vars["NEW_RELIC_DOWNLOAD_URL"] = DownloadURL()
 	vars["NEW_RELIC_CLI_LOG_FILE_PATH"] = config.GetDefaultLogFilePath()
 	vars["NR_CLI_CLUSTERNAME"] = os.Getenv("NR_CLI_CLUSTERNAME")
 	vars["NR_CLI_FLEET_ID"] = os.Getenv("NR_CLI_FLEET_ID")
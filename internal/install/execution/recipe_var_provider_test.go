@@ -182,6 +182,34 @@ func TestRecipeVarProvider_UserInputVars(t *testing.T) {
 	require.Contains(t, "123", v["a-default"])
 }
 
+func TestRecipeVarProvider_AnswersFileVars(t *testing.T) {
+	e := NewRecipeVarProvider()
+	e.Answers = types.RecipeAnswers{
+		"mysql": {"username": "from-answers"},
+		"other": {"password": "not-for-mysql"},
+	}
+
+	os.Setenv("username", "from-env")
+	os.Setenv("password", "also-from-env")
+	defer os.Unsetenv("username")
+	defer os.Unsetenv("password")
+
+	r := types.OpenInstallationRecipe{
+		Name: "mysql",
+		InputVars: []types.OpenInstallationRecipeInputVariable{
+			{Name: "username"},
+			{Name: "password"},
+			{Name: "port", Default: "3306"},
+		},
+	}
+
+	v, err := e.Prepare(types.DiscoveryManifest{}, r, true)
+	require.NoError(t, err)
+	require.Equal(t, "from-answers", v["username"])
+	require.Equal(t, "also-from-env", v["password"])
+	require.Equal(t, "3306", v["port"])
+}
+
 func TestRecipeVarProvider_CommandLineEnvarsDirectlyPassedToRecipeContext(t *testing.T) {
 	e := NewRecipeVarProvider()
 
@@ -35,3 +35,24 @@ type RecipeStatusEvent struct {
 func NewRecipeStatusEvent(recipe *types.OpenInstallationRecipe) RecipeStatusEvent {
 	return RecipeStatusEvent{Recipe: *recipe, Metadata: map[string]string{}}
 }
+
+// metadata returns the metadata of the event, along with the result of
+// verifying the signature of the recipe, if it was loaded from a file.
+func (e RecipeStatusEvent) metadata() map[string]string {
+	sig := e.Recipe.Signature()
+	if sig == nil {
+		return e.Metadata
+	}
+
+	m := map[string]string{}
+	for k, v := range e.Metadata {
+		m[k] = v
+	}
+
+	m["signatureStatus"] = string(sig.Status)
+	if sig.KeyID != "" {
+		m["signatureKeyId"] = sig.KeyID
+	}
+
+	return m
+}
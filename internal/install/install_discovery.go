@@ -0,0 +1,245 @@
+package install
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/newrelic/newrelic-cli/internal/install/execution"
+	"github.com/newrelic/newrelic-cli/internal/install/recipes"
+	"github.com/newrelic/newrelic-cli/internal/install/types"
+)
+
+// DiscoveryReport describes what the installer finds on this host: the host
+// manifest, the running processes matching a recipe and the detection status
+// of every recipe.
+type DiscoveryReport struct {
+	Manifest         types.DiscoveryManifest `json:"manifest"`
+	MatchedProcesses []DiscoveredProcess     `json:"matchedProcesses"`
+	Recipes          []*DiscoveredRecipe     `json:"recipes"`
+}
+
+// DiscoveredProcess is a running process matching a recipe's processMatch.
+type DiscoveredProcess struct {
+	PID             int32  `json:"pid"`
+	Name            string `json:"name"`
+	Cmd             string `json:"cmd"`
+	MatchingPattern string `json:"matchingPattern"`
+	Recipe          string `json:"recipe"`
+	// Container is set for processes running in another container, which do
+	// not make a recipe available on this host.
+	Container *types.Container `json:"container,omitempty"`
+}
+
+// DiscoveredRecipe is the detection status of a single recipe, and why it was
+// given that status.
+type DiscoveredRecipe struct {
+	Name        string                     `json:"name"`
+	DisplayName string                     `json:"displayName,omitempty"`
+	Status      execution.RecipeStatusType `json:"status"`
+	Reason      string                     `json:"reason"`
+	DurationMs  int64                      `json:"durationMs"`
+	LogFiles    []string                   `json:"logFiles,omitempty"`
+}
+
+// Discover runs recipe detection without installing anything.
+func (i *RecipeInstall) Discover(ctx context.Context) (*DiscoveryReport, error) {
+	m, err := i.discoverer.Discover(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("there was an error discovering system info: %s", err)
+	}
+
+	var loaded []*types.OpenInstallationRecipe
+	repo := recipes.NewRecipeRepository(func() ([]*types.OpenInstallationRecipe, error) {
+		loaded, err = i.recipeFetcher.FetchRecipes(ctx)
+		return loaded, err
+	}, m)
+
+	supported, err := repo.FindAll()
+	if err != nil {
+		return nil, err
+	}
+
+	availableRecipes, unavailableRecipes, err := i.detectRecipes(ctx, repo)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &DiscoveryReport{
+		Manifest:         *m,
+		MatchedProcesses: []DiscoveredProcess{},
+		Recipes:          []*DiscoveredRecipe{},
+	}
+
+	matches := findProcessMatches(ctx, i.processEvaluator, supported)
+	matchesByRecipe := map[string][]types.MatchedProcess{}
+	for _, mp := range matches {
+		name, _ := mp.Name()
+		cmd, _ := mp.Cmd()
+		dp := DiscoveredProcess{
+			PID:             mp.PID(),
+			Name:            name,
+			Cmd:             cmd,
+			MatchingPattern: mp.MatchingPattern,
+			Recipe:          mp.MatchingRecipe.Name,
+		}
+		if mp.Container.IsContainerized() {
+			c := mp.Container
+			dp.Container = &c
+		}
+		report.MatchedProcesses = append(report.MatchedProcesses, dp)
+		matchesByRecipe[mp.MatchingRecipe.Name] = append(matchesByRecipe[mp.MatchingRecipe.Name], mp)
+	}
+
+	detected := map[string]bool{}
+	for _, d := range append(availableRecipes, unavailableRecipes...) {
+		detected[d.Recipe.Name] = true
+
+		dr := &DiscoveredRecipe{
+			Name:        d.Recipe.Name,
+			DisplayName: d.Recipe.DisplayName,
+			Status:      d.Status,
+			DurationMs:  d.DurationMs,
+			LogFiles:    findLogFiles(d.Recipe),
+		}
+		dr.Reason = i.detectionReason(d.Recipe, d.Status, matchesByRecipe[d.Recipe.Name], dr.LogFiles)
+		report.Recipes = append(report.Recipes, dr)
+	}
+
+	// Recipes without an install target matching this host are filtered out by
+	// the repository before detection.
+	for _, r := range loaded {
+		if detected[r.Name] {
+			continue
+		}
+		detected[r.Name] = true
+
+		report.Recipes = append(report.Recipes, &DiscoveredRecipe{
+			Name:        r.Name,
+			DisplayName: r.DisplayName,
+			Status:      execution.RecipeStatusTypes.UNSUPPORTED,
+			Reason:      fmt.Sprintf("no install target matches %s, supports %s", describeManifest(*m), describeInstallTargets(r.InstallTargets)),
+		})
+	}
+
+	sort.Slice(report.Recipes, func(a, b int) bool {
+		return report.Recipes[a].Name < report.Recipes[b].Name
+	})
+
+	return report, nil
+}
+
+func findProcessMatches(ctx context.Context, pe recipes.ProcessEvaluatorInterface, supported []*types.OpenInstallationRecipe) []types.MatchedProcess {
+	withProcessMatch := []types.OpenInstallationRecipe{}
+	for _, r := range supported {
+		if len(r.ProcessMatch) > 0 {
+			withProcessMatch = append(withProcessMatch, *r)
+		}
+	}
+
+	if len(withProcessMatch) == 0 {
+		return []types.MatchedProcess{}
+	}
+
+	processes := pe.GetOrLoadProcesses(ctx)
+
+	return recipes.NewRegexProcessMatchFinder().FindMatchesMultiple(ctx, processes, withProcessMatch)
+}
+
+// findLogFiles returns the files on this host matching the recipe's logMatch globs.
+func findLogFiles(r *types.OpenInstallationRecipe) []string {
+	files := []string{}
+	for _, l := range r.LogMatch {
+		if l.File == "" {
+			continue
+		}
+
+		matches, err := filepath.Glob(l.File)
+		if err != nil {
+			continue
+		}
+		files = append(files, matches...)
+	}
+
+	return files
+}
+
+// detectionReason explains the detection status of a recipe, mirroring the
+// checks made by the recipe detector: the discovery mode, the process match
+// and the preInstall script.
+func (i *RecipeInstall) detectionReason(r *types.OpenInstallationRecipe, status execution.RecipeStatusType, matches []types.MatchedProcess, logFiles []string) string {
+	script := r.PreInstall.RequireAtDiscovery != ""
+
+	hostMatches := []types.MatchedProcess{}
+	for _, mp := range matches {
+		if !mp.Container.IsContainerized() {
+			hostMatches = append(hostMatches, mp)
+		}
+	}
+
+	switch status {
+	case execution.RecipeStatusTypes.DETECTED:
+		return "preInstall.requireAtDiscovery exited with code 132, the software was detected but its requirements are not met"
+	case execution.RecipeStatusTypes.UNSUPPORTED:
+		return "preInstall.requireAtDiscovery exited with code 131, the host is not supported"
+	case execution.RecipeStatusTypes.AVAILABLE:
+		reasons := []string{}
+		if len(hostMatches) > 0 {
+			pids := []string{}
+			for _, mp := range hostMatches {
+				pids = append(pids, fmt.Sprintf("%d", mp.PID()))
+			}
+			reasons = append(reasons, fmt.Sprintf("process %s matches processMatch %s", strings.Join(pids, ", "), hostMatches[0].MatchingPattern))
+		}
+		if script {
+			reasons = append(reasons, "preInstall.requireAtDiscovery succeeded")
+		}
+		if len(logFiles) > 0 {
+			reasons = append(reasons, fmt.Sprintf("%d log file(s) match logMatch", len(logFiles)))
+		}
+		if len(reasons) == 0 {
+			reasons = append(reasons, "install target matches this host")
+		}
+		return strings.Join(reasons, "; ")
+	}
+
+	if len(r.PreInstall.DiscoveryMode) == 1 &&
+		r.PreInstall.DiscoveryMode[0] == types.OpenInstallationDiscoveryModeTypes.TARGETED &&
+		!i.IsRecipeTargeted(r.Name) {
+		return "discoveryMode is TARGETED and the recipe was not requested with --recipe"
+	}
+
+	if len(r.ProcessMatch) > 0 && len(hostMatches) == 0 {
+		if len(matches) > 0 {
+			return fmt.Sprintf("processMatch %s only matches processes running in other containers", strings.Join(r.ProcessMatch, ", "))
+		}
+		return fmt.Sprintf("no running process matches processMatch %s", strings.Join(r.ProcessMatch, ", "))
+	}
+
+	if script {
+		return "preInstall.requireAtDiscovery exited with an error"
+	}
+
+	return "not detected"
+}
+
+func describeInstallTargets(targets []types.OpenInstallationRecipeInstallTarget) string {
+	if len(targets) == 0 {
+		return "any host"
+	}
+
+	described := []string{}
+	for _, t := range targets {
+		parts := []string{}
+		for _, p := range []string{string(t.Type), string(t.Os), string(t.Platform), string(t.PlatformFamily), t.PlatformVersion, t.KernelArch, string(t.ContainerRuntime), string(t.Orchestrator)} {
+			if p != "" {
+				parts = append(parts, strings.ToLower(p))
+			}
+		}
+		described = append(described, strings.Join(parts, " "))
+	}
+
+	return strings.Join(described, ", ")
+}
@@ -0,0 +1,94 @@
+package install
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/newrelic/newrelic-cli/internal/install/execution"
+	"github.com/newrelic/newrelic-cli/internal/install/recipes"
+	"github.com/newrelic/newrelic-cli/internal/install/types"
+)
+
+func TestDiscoverShouldReportEveryRecipe(t *testing.T) {
+	mysql := recipes.NewRecipeBuilder().Name("mysql").TargetOs(types.OpenInstallationOperatingSystemTypes.LINUX).ProcessMatch("mysqld").Build()
+	redis := recipes.NewRecipeBuilder().Name("redis").TargetOs(types.OpenInstallationOperatingSystemTypes.LINUX).ProcessMatch("redis-server").Build()
+	nginx := recipes.NewRecipeBuilder().Name("nginx").TargetOs(types.OpenInstallationOperatingSystemTypes.LINUX).WithPreInstallScript("exit 132").Build()
+	iis := recipes.NewRecipeBuilder().Name("iis").TargetOs(types.OpenInstallationOperatingSystemTypes.WINDOWS).Build()
+
+	recipeInstall := NewRecipeInstallBuilder().
+		WithFetchRecipesVal([]*types.OpenInstallationRecipe{mysql, redis, nginx, iis}).
+		WithRunningProcess("/usr/sbin/mysqld --daemonize", "mysqld").
+		WithRecipeDetectionResult(
+			&recipes.RecipeDetectionResult{Recipe: mysql, Status: execution.RecipeStatusTypes.AVAILABLE},
+			&recipes.RecipeDetectionResult{Recipe: redis, Status: execution.RecipeStatusTypes.NULL},
+			&recipes.RecipeDetectionResult{Recipe: nginx, Status: execution.RecipeStatusTypes.DETECTED},
+		).
+		Build()
+
+	report, err := recipeInstall.Discover(context.Background())
+
+	require.NoError(t, err)
+	assert.Equal(t, "linux", report.Manifest.OS)
+
+	require.Len(t, report.MatchedProcesses, 1)
+	assert.Equal(t, "mysqld", report.MatchedProcesses[0].Name)
+	assert.Equal(t, "mysql", report.MatchedProcesses[0].Recipe)
+	assert.Equal(t, "mysqld", report.MatchedProcesses[0].MatchingPattern)
+
+	require.Len(t, report.Recipes, 4)
+	statuses := map[string]*DiscoveredRecipe{}
+	for _, r := range report.Recipes {
+		statuses[r.Name] = r
+	}
+
+	assert.Equal(t, execution.RecipeStatusTypes.AVAILABLE, statuses["mysql"].Status)
+	assert.Contains(t, statuses["mysql"].Reason, "matches processMatch mysqld")
+	assert.Equal(t, execution.RecipeStatusTypes.NULL, statuses["redis"].Status)
+	assert.Contains(t, statuses["redis"].Reason, "no running process matches processMatch redis-server")
+	assert.Equal(t, execution.RecipeStatusTypes.DETECTED, statuses["nginx"].Status)
+	assert.Contains(t, statuses["nginx"].Reason, "exited with code 132")
+	assert.Equal(t, execution.RecipeStatusTypes.UNSUPPORTED, statuses["iis"].Status)
+	assert.Contains(t, statuses["iis"].Reason, "no install target matches")
+	assert.Contains(t, statuses["iis"].Reason, "windows")
+}
+
+func TestDiscoverShouldReturnDiscoveryError(t *testing.T) {
+	recipeInstall := NewRecipeInstallBuilder().WithDiscovererError(errors.New("no host info")).Build()
+
+	_, err := recipeInstall.Discover(context.Background())
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "no host info")
+}
+
+func TestDetectionReasonShouldExplainTargetedDiscoveryMode(t *testing.T) {
+	r := recipes.NewRecipeBuilder().Name("targeted").
+		WithDiscoveryMode([]types.OpenInstallationDiscoveryMode{types.OpenInstallationDiscoveryModeTypes.TARGETED}).
+		ProcessMatch("targetd").
+		Build()
+	recipeInstall := NewRecipeInstallBuilder().Build()
+
+	reason := recipeInstall.detectionReason(r, execution.RecipeStatusTypes.NULL, nil, nil)
+
+	assert.Contains(t, reason, "discoveryMode is TARGETED")
+}
+
+func TestDetectionReasonShouldExplainContainerizedMatches(t *testing.T) {
+	r := recipes.NewRecipeBuilder().Name("redis").ProcessMatch("redis-server").Build()
+	recipeInstall := NewRecipeInstallBuilder().Build()
+	matches := []types.MatchedProcess{
+		{
+			GenericProcess:  recipes.NewMockProcess("redis-server *:6379", "redis-server", 1),
+			MatchingPattern: "redis-server",
+			Container:       types.Container{Runtime: types.OpenInstallationContainerRuntimeTypes.DOCKER},
+		},
+	}
+
+	reason := recipeInstall.detectionReason(r, execution.RecipeStatusTypes.NULL, matches, nil)
+
+	assert.Contains(t, reason, "only matches processes running in other containers")
+}
@@ -0,0 +1,363 @@
+package install
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+
+	"github.com/newrelic/newrelic-cli/internal/install/execution"
+	"github.com/newrelic/newrelic-cli/internal/install/recipes"
+	"github.com/newrelic/newrelic-cli/internal/install/types"
+)
+
+// defaultTaskName is the go-task entrypoint the recipe executor runs.
+const defaultTaskName = "default"
+
+// InstallPlan describes what an install would do on this host without
+// executing any recipe.
+type InstallPlan struct {
+	Discovery   types.DiscoveryManifest `json:"discovery"`
+	Recipes     []*PlannedRecipe        `json:"recipes"`
+	Unsupported []string                `json:"unsupported,omitempty"`
+}
+
+// PlannedRecipe is a recipe from one of the install bundles, listed in the
+// order it would be installed.  Dependencies are listed before the recipes
+// that require them.
+type PlannedRecipe struct {
+	Name         string                                      `json:"name"`
+	DisplayName  string                                      `json:"displayName,omitempty"`
+	Bundle       recipes.BundleType                          `json:"bundle"`
+	Status       execution.RecipeStatusType                  `json:"status"`
+	WillInstall  bool                                        `json:"willInstall"`
+	Dependencies []string                                    `json:"dependencies,omitempty"`
+	InputVars    []types.OpenInstallationRecipeInputVariable `json:"inputVars,omitempty"`
+	Steps        []PlannedStep                               `json:"steps,omitempty"`
+	StepsError   string                                      `json:"stepsError,omitempty"`
+}
+
+// PlannedStep is a single command from a recipe's go-task definition.
+type PlannedStep struct {
+	Task    string `json:"task"`
+	Command string `json:"command"`
+}
+
+func newInstallPlan(m types.DiscoveryManifest) *InstallPlan {
+	return &InstallPlan{
+		Discovery: m,
+		Recipes:   []*PlannedRecipe{},
+	}
+}
+
+// addBundle adds the bundle's recipes, and their dependencies, that are not
+// already part of the plan.
+func (p *InstallPlan) addBundle(bundle *recipes.Bundle) {
+	for _, br := range bundle.BundleRecipes {
+		p.addBundleRecipe(bundle.Type, br)
+	}
+}
+
+func (p *InstallPlan) addBundleRecipe(bundleType recipes.BundleType, br *recipes.BundleRecipe) {
+	for _, dep := range br.Dependencies {
+		p.addBundleRecipe(bundleType, dep)
+	}
+
+	if p.GetRecipe(br.Recipe.Name) != nil {
+		return
+	}
+
+	pr := &PlannedRecipe{
+		Name:         br.Recipe.Name,
+		DisplayName:  br.Recipe.DisplayName,
+		Bundle:       bundleType,
+		Dependencies: br.Recipe.Dependencies,
+		InputVars:    br.Recipe.InputVars,
+	}
+
+	if len(br.DetectedStatuses) > 0 {
+		pr.Status = br.DetectedStatuses[len(br.DetectedStatuses)-1].Status
+	}
+	pr.WillInstall = pr.Status == execution.RecipeStatusTypes.AVAILABLE
+
+	if br.Recipe.Install != "" {
+		steps, err := planSteps(br.Recipe.Install)
+		if err != nil {
+			pr.StepsError = err.Error()
+		}
+		pr.Steps = steps
+	}
+
+	p.Recipes = append(p.Recipes, pr)
+}
+
+// GetRecipe returns the planned recipe with the given name, or nil.
+func (p *InstallPlan) GetRecipe(name string) *PlannedRecipe {
+	for _, r := range p.Recipes {
+		if r.Name == name {
+			return r
+		}
+	}
+
+	return nil
+}
+
+// String renders the plan for display in a terminal.
+func (p *InstallPlan) String() string {
+	var sb strings.Builder
+
+	fmt.Fprintf(&sb, "Install plan for %s\n", describeManifest(p.Discovery))
+
+	toInstall := []*PlannedRecipe{}
+	skipped := []*PlannedRecipe{}
+	for _, r := range p.Recipes {
+		if r.WillInstall {
+			toInstall = append(toInstall, r)
+		} else {
+			skipped = append(skipped, r)
+		}
+	}
+
+	if len(toInstall) == 0 {
+		sb.WriteString("\nNo recipes would be installed.\n")
+	}
+
+	for n, r := range toInstall {
+		fmt.Fprintf(&sb, "\n%d. %s\n", n+1, recipeLabel(r))
+		fmt.Fprintf(&sb, "   Bundle: %s\n", strings.ToLower(string(r.Bundle)))
+
+		if len(r.Dependencies) > 0 {
+			fmt.Fprintf(&sb, "   Dependencies: %s\n", strings.Join(r.Dependencies, ", "))
+		}
+
+		if len(r.InputVars) > 0 {
+			sb.WriteString("   Input variables:\n")
+			for _, v := range r.InputVars {
+				fmt.Fprintf(&sb, "     %s\n", describeInputVar(v))
+			}
+		}
+
+		if r.StepsError != "" {
+			fmt.Fprintf(&sb, "   Steps: could not be read: %s\n", r.StepsError)
+		} else if len(r.Steps) > 0 {
+			sb.WriteString("   Steps:\n")
+			for _, s := range r.Steps {
+				lines := strings.Split(s.Command, "\n")
+				fmt.Fprintf(&sb, "     [%s] %s\n", s.Task, lines[0])
+				for _, l := range lines[1:] {
+					fmt.Fprintf(&sb, "     %s %s\n", strings.Repeat(" ", len(s.Task)+2), l)
+				}
+			}
+		}
+	}
+
+	if len(skipped) > 0 {
+		sb.WriteString("\nNot installed:\n")
+		for _, r := range skipped {
+			fmt.Fprintf(&sb, "  %s: %s\n", recipeLabel(r), describeStatus(r.Status))
+		}
+	}
+
+	if len(p.Unsupported) > 0 {
+		fmt.Fprintf(&sb, "\nUnsupported on this host: %s\n", strings.Join(p.Unsupported, ", "))
+	}
+
+	return sb.String()
+}
+
+func describeManifest(m types.DiscoveryManifest) string {
+	parts := []string{m.OS}
+	if m.Platform != "" {
+		parts = append(parts, m.Platform)
+	}
+	if m.PlatformVersion != "" {
+		parts = append(parts, m.PlatformVersion)
+	}
+
+	s := strings.Join(parts, " ")
+	if m.KernelArch != "" {
+		s = fmt.Sprintf("%s (%s)", s, m.KernelArch)
+	}
+	if m.ContainerRuntime != "" {
+		s = fmt.Sprintf("%s in a %s container", s, strings.ToLower(m.ContainerRuntime))
+	}
+	if m.Orchestrator != "" {
+		s = fmt.Sprintf("%s managed by %s", s, strings.ToLower(m.Orchestrator))
+	}
+	if m.Hostname != "" {
+		s = fmt.Sprintf("%s on %s", m.Hostname, s)
+	}
+
+	return s
+}
+
+func recipeLabel(r *PlannedRecipe) string {
+	if r.DisplayName == "" || r.DisplayName == r.Name {
+		return r.Name
+	}
+
+	return fmt.Sprintf("%s (%s)", r.DisplayName, r.Name)
+}
+
+func describeInputVar(v types.OpenInstallationRecipeInputVariable) string {
+	details := []string{}
+	if v.Secret {
+		details = append(details, "secret")
+	}
+	if v.Default != "" {
+		details = append(details, fmt.Sprintf("default %q", v.Default))
+	} else {
+		details = append(details, "required")
+	}
+
+	s := fmt.Sprintf("%s (%s)", v.Name, strings.Join(details, ", "))
+	if v.Prompt != "" {
+		s = fmt.Sprintf("%s: %s", s, strings.TrimSpace(v.Prompt))
+	}
+
+	return s
+}
+
+func describeStatus(status execution.RecipeStatusType) string {
+	switch status {
+	case execution.RecipeStatusTypes.INSTALLED:
+		return "already installed"
+	case execution.RecipeStatusTypes.NULL, "":
+		return "skipped"
+	}
+
+	return strings.ToLower(string(status))
+}
+
+// planTaskfile is the subset of a go-task taskfile needed to list the steps
+// a recipe would run.
+type planTaskfile struct {
+	Tasks map[string]planTask `yaml:"tasks"`
+}
+
+type planTask struct {
+	Deps []interface{} `yaml:"deps"`
+	Cmds []interface{} `yaml:"cmds"`
+}
+
+// planSteps lists the commands of a recipe's go-task definition in the order
+// they are run, starting from the default task.  Tasks called from other tasks
+// are expanded in place.
+func planSteps(install string) ([]PlannedStep, error) {
+	tf := planTaskfile{}
+	if err := yaml.Unmarshal([]byte(install), &tf); err != nil {
+		return nil, fmt.Errorf("could not unmarshal taskfile: %s", err)
+	}
+
+	if _, ok := tf.Tasks[defaultTaskName]; !ok {
+		names := make([]string, 0, len(tf.Tasks))
+		for name := range tf.Tasks {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		return nil, fmt.Errorf("taskfile has no %s task, found: %s", defaultTaskName, strings.Join(names, ", "))
+	}
+
+	steps := []PlannedStep{}
+	err := tf.expand(defaultTaskName, map[string]bool{}, &steps)
+
+	return steps, err
+}
+
+func (tf planTaskfile) expand(name string, calling map[string]bool, steps *[]PlannedStep) error {
+	task, ok := tf.Tasks[name]
+	if !ok {
+		return fmt.Errorf("task %s is not defined", name)
+	}
+
+	if calling[name] {
+		return fmt.Errorf("task %s calls itself", name)
+	}
+	calling[name] = true
+	defer delete(calling, name)
+
+	for _, d := range task.Deps {
+		if dep := dependencyName(d); dep != "" {
+			if err := tf.expand(dep, calling, steps); err != nil {
+				return err
+			}
+		}
+	}
+
+	for _, c := range task.Cmds {
+		if called := taskReference(c); called != "" {
+			if err := tf.expand(called, calling, steps); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if cmd := commandString(c); cmd != "" {
+			*steps = append(*steps, PlannedStep{Task: name, Command: cmd})
+		}
+	}
+
+	return nil
+}
+
+// calledTasks returns the names of the tasks a task depends on or calls, in
+// the order they are run.
+func (tf planTaskfile) calledTasks(name string) []string {
+	called := []string{}
+
+	task, ok := tf.Tasks[name]
+	if !ok {
+		return called
+	}
+
+	for _, d := range task.Deps {
+		if dep := dependencyName(d); dep != "" {
+			called = append(called, dep)
+		}
+	}
+
+	for _, c := range task.Cmds {
+		if t := taskReference(c); t != "" {
+			called = append(called, t)
+		}
+	}
+
+	return called
+}
+
+// dependencyName returns the name of the task a dependency refers to, given
+// either as a bare name or as an object with a task key.
+func dependencyName(v interface{}) string {
+	if name, ok := v.(string); ok {
+		return name
+	}
+
+	return taskReference(v)
+}
+
+// taskReference returns the name of the task a command calls, or an empty
+// string if it does not call a task.
+func taskReference(v interface{}) string {
+	if t, ok := v.(map[interface{}]interface{}); ok {
+		if name, ok := t["task"].(string); ok {
+			return name
+		}
+	}
+
+	return ""
+}
+
+func commandString(v interface{}) string {
+	switch t := v.(type) {
+	case string:
+		return strings.TrimSpace(t)
+	case map[interface{}]interface{}:
+		if cmd, ok := t["cmd"].(string); ok {
+			return strings.TrimSpace(cmd)
+		}
+	}
+
+	return ""
+}
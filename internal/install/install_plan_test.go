@@ -0,0 +1,203 @@
+package install
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/newrelic/newrelic-cli/internal/install/execution"
+	"github.com/newrelic/newrelic-cli/internal/install/recipes"
+	"github.com/newrelic/newrelic-cli/internal/install/types"
+)
+
+func TestPlanShouldNotInstallOrReportStatus(t *testing.T) {
+	infra := &recipes.RecipeDetectionResult{
+		Recipe: recipes.NewRecipeBuilder().Name(types.InfraAgentRecipeName).Build(),
+		Status: execution.RecipeStatusTypes.AVAILABLE,
+	}
+	other := &recipes.RecipeDetectionResult{
+		Recipe: recipes.NewRecipeBuilder().Name("other").DependencyName(types.InfraAgentRecipeName).Build(),
+		Status: execution.RecipeStatusTypes.AVAILABLE,
+	}
+	statusReporter := execution.NewMockStatusReporter()
+	recipeInstall := NewRecipeInstallBuilder().
+		WithRecipeDetectionResult(infra).
+		WithRecipeDetectionResult(other).
+		WithStatusReporter(statusReporter).
+		WithRecipeExecutionError(errors.New("recipes must not be executed")).
+		Build()
+
+	plan, err := recipeInstall.Plan(context.Background())
+
+	require.NoError(t, err)
+	require.Len(t, plan.Recipes, 2)
+	assert.Equal(t, types.InfraAgentRecipeName, plan.Recipes[0].Name)
+	assert.Equal(t, recipes.BundleTypes.CORE, plan.Recipes[0].Bundle)
+	assert.True(t, plan.Recipes[0].WillInstall)
+	assert.Equal(t, "other", plan.Recipes[1].Name)
+	assert.Equal(t, recipes.BundleTypes.ADDITIONALGUIDED, plan.Recipes[1].Bundle)
+	assert.Equal(t, []string{types.InfraAgentRecipeName}, plan.Recipes[1].Dependencies)
+	assert.Equal(t, 0, statusReporter.RecipeDetectedCallCount, "Detection Count")
+	assert.Equal(t, 0, statusReporter.RecipeInstallingCallCount, "Installing Count")
+	assert.Equal(t, 0, statusReporter.RecipeFailedCallCount, "Failed Count")
+	assert.Equal(t, 0, statusReporter.RecipeInstalledCallCount, "InstalledCount")
+}
+
+func TestPlanShouldListUnsupportedTargetedRecipes(t *testing.T) {
+	r := &recipes.RecipeDetectionResult{
+		Recipe: recipes.NewRecipeBuilder().Name("supported").Build(),
+		Status: execution.RecipeStatusTypes.AVAILABLE,
+	}
+	recipeInstall := NewRecipeInstallBuilder().
+		WithRecipeDetectionResult(r).
+		WithTargetRecipeName("supported").
+		WithTargetRecipeName("missing").
+		withShouldInstallCore(func() bool { return false }).
+		Build()
+
+	plan, err := recipeInstall.Plan(context.Background())
+
+	require.NoError(t, err)
+	require.Len(t, plan.Recipes, 1)
+	assert.Equal(t, recipes.BundleTypes.ADDITIONALTARGETED, plan.Recipes[0].Bundle)
+	assert.Equal(t, []string{"missing"}, plan.Unsupported)
+}
+
+func TestPlanShouldFailOnRecipeDependencyCycle(t *testing.T) {
+	a := &recipes.RecipeDetectionResult{
+		Recipe: recipes.NewRecipeBuilder().Name("a").DependencyName("b").Build(),
+		Status: execution.RecipeStatusTypes.AVAILABLE,
+	}
+	b := &recipes.RecipeDetectionResult{
+		Recipe: recipes.NewRecipeBuilder().Name("b").DependencyName("a").Build(),
+		Status: execution.RecipeStatusTypes.AVAILABLE,
+	}
+	recipeInstall := NewRecipeInstallBuilder().
+		WithRecipeDetectionResult(a).
+		WithRecipeDetectionResult(b).
+		Build()
+
+	_, err := recipeInstall.Plan(context.Background())
+
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, types.ErrRecipeDependencies))
+	assert.Contains(t, err.Error(), "recipe dependency cycle: a -> b -> a")
+}
+
+func TestPlanShouldFailOnMissingDependencyOfTargetedRecipe(t *testing.T) {
+	r := &recipes.RecipeDetectionResult{
+		Recipe: recipes.NewRecipeBuilder().Name("mysql").DependencyName("mysql-agent").Build(),
+		Status: execution.RecipeStatusTypes.AVAILABLE,
+	}
+	recipeInstall := NewRecipeInstallBuilder().
+		WithRecipeDetectionResult(r).
+		WithTargetRecipeName("mysql").
+		Build()
+
+	_, err := recipeInstall.Plan(context.Background())
+
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, types.ErrRecipeDependencies))
+	assert.Contains(t, err.Error(), "recipe mysql depends on mysql-agent, which is not available for this host")
+}
+
+func TestPlanShouldReturnDiscoveryError(t *testing.T) {
+	recipeInstall := NewRecipeInstallBuilder().WithDiscovererError(errors.New("no host info")).Build()
+
+	_, err := recipeInstall.Plan(context.Background())
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "no host info")
+}
+
+func TestPlanStepsShouldExpandCalledTasksInOrder(t *testing.T) {
+	install := `
+version: "3"
+tasks:
+  default:
+    cmds:
+      - task: assert_pre_req
+      - task: install
+  assert_pre_req:
+    cmds:
+      - |
+        if [ $(id -u) -ne 0 ]; then
+          exit 1
+        fi
+  install:
+    deps: [download]
+    cmds:
+      - cmd: apt-get install -y newrelic-infra
+      - echo done
+  download:
+    cmds:
+      - curl -s https://example.com/key.gpg
+`
+
+	steps, err := planSteps(install)
+
+	require.NoError(t, err)
+	assert.Equal(t, []PlannedStep{
+		{Task: "assert_pre_req", Command: "if [ $(id -u) -ne 0 ]; then\n  exit 1\nfi"},
+		{Task: "download", Command: "curl -s https://example.com/key.gpg"},
+		{Task: "install", Command: "apt-get install -y newrelic-infra"},
+		{Task: "install", Command: "echo done"},
+	}, steps)
+}
+
+func TestPlanStepsShouldErrorWithoutDefaultTask(t *testing.T) {
+	_, err := planSteps("tasks:\n  setup:\n    cmds:\n      - echo setup\n")
+
+	assert.EqualError(t, err, "taskfile has no default task, found: setup")
+}
+
+func TestPlanStepsShouldErrorOnRecursiveTasks(t *testing.T) {
+	_, err := planSteps("tasks:\n  default:\n    cmds:\n      - task: default\n")
+
+	assert.EqualError(t, err, "task default calls itself")
+}
+
+func TestInstallPlanStringShouldDescribeRecipes(t *testing.T) {
+	plan := newInstallPlan(types.DiscoveryManifest{OS: "linux", Platform: "ubuntu", PlatformVersion: "22.04", KernelArch: "x86_64"})
+	plan.Recipes = []*PlannedRecipe{
+		{
+			Name:         "mysql-open-source-integration",
+			DisplayName:  "MySQL Integration",
+			Bundle:       recipes.BundleTypes.ADDITIONALGUIDED,
+			Status:       execution.RecipeStatusTypes.AVAILABLE,
+			WillInstall:  true,
+			Dependencies: []string{types.InfraAgentRecipeName},
+			InputVars: []types.OpenInstallationRecipeInputVariable{
+				{Name: "NR_CLI_DB_PASSWORD", Prompt: "MySQL password", Secret: true},
+				{Name: "NR_CLI_DB_PORT", Default: "3306"},
+			},
+			Steps: []PlannedStep{{Task: "setup", Command: "echo one\necho two"}},
+		},
+		{
+			Name:   "agent-control",
+			Bundle: recipes.BundleTypes.CORE,
+			Status: execution.RecipeStatusTypes.INSTALLED,
+		},
+	}
+
+	expected := `Install plan for linux ubuntu 22.04 (x86_64)
+
+1. MySQL Integration (mysql-open-source-integration)
+   Bundle: additionalguided
+   Dependencies: infrastructure-agent-installer
+   Input variables:
+     NR_CLI_DB_PASSWORD (secret, required): MySQL password
+     NR_CLI_DB_PORT (default "3306")
+   Steps:
+     [setup] echo one
+             echo two
+
+Not installed:
+  agent-control: already installed
+`
+
+	assert.Equal(t, expected, plan.String())
+}
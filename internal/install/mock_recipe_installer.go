@@ -48,6 +48,10 @@ func (mri *mockRecipeInstaller) executeAndValidateWithProgress(ctx context.Conte
 	return args.String(0), args.Error(1)
 }
 
+func (mri *mockRecipeInstaller) forConcurrentInstall() RecipeInstaller {
+	return mri
+}
+
 type mockRecipeInstaller struct {
 	mock.Mock
 }
@@ -24,6 +24,7 @@ type RecipeInstallBuilder struct {
 	recipeLogForwarder *execution.MockRecipeLogForwarder
 	recipeVarProvider  *execution.MockRecipeVarProvider
 	recipeExecutor     *execution.MockRecipeExecutor
+	recipeUninstaller  *execution.MockRecipeUninstaller
 	progressIndicator  *ux.SpinnerProgressIndicator
 	agentValidator     *validation.MockAgentValidator
 	recipeValidator    *validation.MockRecipeValidator
@@ -53,6 +54,7 @@ func NewRecipeInstallBuilder() *RecipeInstallBuilder {
 	rib.recipeVarProvider = execution.NewMockRecipeVarProvider()
 	rib.recipeVarProvider.Vars = map[string]string{}
 	rib.recipeExecutor = execution.NewMockRecipeExecutor()
+	rib.recipeUninstaller = execution.NewMockRecipeUninstaller()
 	rib.progressIndicator = ux.NewSpinnerProgressIndicator()
 	rib.agentValidator = &validation.MockAgentValidator{}
 	rib.recipeValidator = &validation.MockRecipeValidator{}
@@ -120,6 +122,16 @@ func (rib *RecipeInstallBuilder) WithRecipeExecutionError(err error) *RecipeInst
 	return rib
 }
 
+func (rib *RecipeInstallBuilder) WithRecipeUninstaller(uninstaller *execution.MockRecipeUninstaller) *RecipeInstallBuilder {
+	rib.recipeUninstaller = uninstaller
+	return rib
+}
+
+func (rib *RecipeInstallBuilder) WithNoRollback() *RecipeInstallBuilder {
+	rib.installerContext.NoRollback = true
+	return rib
+}
+
 func (rib *RecipeInstallBuilder) WithOutput(value string) *RecipeInstallBuilder {
 	rib.recipeExecutor.SetOutput(value)
 	return rib
@@ -179,6 +191,7 @@ func (rib *RecipeInstallBuilder) Build() *RecipeInstall {
 	recipeInstall.recipeLogForwarder = rib.recipeLogForwarder
 	recipeInstall.recipeVarPreparer = rib.recipeVarProvider
 	recipeInstall.recipeExecutor = rib.recipeExecutor
+	recipeInstall.recipeUninstaller = rib.recipeUninstaller
 	recipeInstall.progressIndicator = rib.progressIndicator
 	recipeInstall.agentValidator = rib.agentValidator
 	recipeInstall.recipeValidator = rib.recipeValidator
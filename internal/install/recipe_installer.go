@@ -7,6 +7,7 @@ import (
 	"os"
 	"regexp"
 	"strconv"
+	"sync"
 	"time"
 
 	"github.com/fatih/color"
@@ -29,7 +30,10 @@ import (
 
 const validationTimeout = 5 * time.Minute
 
-var infraAgentEntityKey string
+var (
+	infraAgentEntityKey   string
+	infraAgentEntityKeyMu sync.Mutex
+)
 
 type RecipeInstall struct {
 	types.InstallerContext
@@ -37,6 +41,7 @@ type RecipeInstall struct {
 	manifestValidator      *discovery.ManifestValidator
 	recipeFetcher          recipes.RecipeFetcher
 	recipeExecutor         execution.RecipeExecutor
+	recipeUninstaller      execution.RecipeUninstaller
 	recipeValidator        RecipeValidator
 	recipeFileFetcher      RecipeFileFetcher
 	recipeLogForwarder     execution.LogForwarder
@@ -51,6 +56,8 @@ type RecipeInstall struct {
 	progressIndicator      ux.ProgressIndicator
 	recipeDetectorFactory  func(ctx context.Context, repo *recipes.RecipeRepository, ic *types.InstallerContext) RecipeStatusDetector
 	processEvaluator       recipes.ProcessEvaluatorInterface
+	concurrentProgress     ux.ProgressIndicator
+	concurrent             bool
 }
 
 type RecipeInstallFunc func(ctx context.Context, i *RecipeInstall, m *types.DiscoveryManifest, r *types.OpenInstallationRecipe, recipes []types.OpenInstallationRecipe) error
@@ -58,17 +65,7 @@ type RecipeInstallFunc func(ctx context.Context, i *RecipeInstall, m *types.Disc
 func NewRecipeInstaller(ic types.InstallerContext) *RecipeInstall {
 	nrClient := client.NRClient
 
-	var recipeFetcher recipes.RecipeFetcher
-
-	if ic.LocalRecipes != "" {
-		recipeFetcher = &recipes.LocalRecipeFetcher{
-			Path: ic.LocalRecipes,
-		}
-	} else if len(ic.RecipePaths) > 0 {
-		recipeFetcher = recipes.NewRecipeFileFetcher(ic.RecipePaths)
-	} else {
-		recipeFetcher = recipes.NewEmbeddedRecipeFetcher()
-	}
+	recipeFetcher := newRecipeFetcher(ic)
 
 	mv := discovery.NewManifestValidator()
 	ff := recipes.NewRecipeFileFetcher([]string{})
@@ -79,9 +76,10 @@ func NewRecipeInstaller(ic types.InstallerContext) *RecipeInstall {
 		execution.NewTerminalStatusReporter(),
 	}
 
-	// Only add InstallEventsReporter if an API key is available
+	// Only add InstallEventsReporter if an API key is available.  Offline
+	// installs record their events locally instead.
 	apiKey := configAPI.GetActiveProfileString(config.APIKey)
-	if apiKey != "" {
+	if apiKey != "" && !ic.IsOffline() {
 		ers = append(ers, execution.NewInstallEventsReporter(&nrClient.InstallEvents))
 	}
 
@@ -94,6 +92,7 @@ func NewRecipeInstaller(ic types.InstallerContext) *RecipeInstall {
 	cv := diagnose.NewConfigValidator(nrClient)
 	p := ux.NewPromptUIPrompter()
 	rvp := execution.NewRecipeVarProvider()
+	rvp.Answers = ic.Answers
 	av := validation.NewAgentValidator()
 
 	i := RecipeInstall{
@@ -101,6 +100,7 @@ func NewRecipeInstaller(ic types.InstallerContext) *RecipeInstall {
 		manifestValidator:  mv,
 		recipeFetcher:      recipeFetcher,
 		recipeExecutor:     re,
+		recipeUninstaller:  execution.NewGoTaskRecipeExecutor(),
 		recipeValidator:    v,
 		recipeFileFetcher:  ff,
 		recipeLogForwarder: lf,
@@ -124,7 +124,9 @@ func NewRecipeInstaller(ic types.InstallerContext) *RecipeInstall {
 	}
 
 	i.bundleInstallerFactory = func(ctx context.Context, manifest *types.DiscoveryManifest, recipeInstallerInterface RecipeInstaller, statusReporter StatusReporter) RecipeBundleInstaller {
-		return NewBundleInstaller(ctx, manifest, recipeInstallerInterface, statusReporter)
+		bi := NewBundleInstaller(ctx, manifest, recipeInstallerInterface, statusReporter)
+		bi.parallelism = i.Parallelism
+		return bi
 	}
 	i.recipeDetectorFactory = func(ctx context.Context, repo *recipes.RecipeRepository, ic *types.InstallerContext) RecipeStatusDetector {
 		return recipes.NewRecipeDetector(ctx, repo, i.processEvaluator, ic)
@@ -133,6 +135,35 @@ func NewRecipeInstaller(ic types.InstallerContext) *RecipeInstall {
 	return &i
 }
 
+// newRecipeFetcher returns the RecipeFetcher for the recipe source selected in
+// the installer context.
+func newRecipeFetcher(ic types.InstallerContext) recipes.RecipeFetcher {
+	if ic.IsOffline() {
+		f := recipes.NewOfflineBundleRecipeFetcher(ic.OfflineBundle)
+		f.Signature = ic.OfflineBundleSignature
+		return f
+	}
+
+	// Recipes loaded from files are run as root, so they must be signed.
+	// Embedded recipes are part of the CLI release.
+	verifier := recipes.NewRecipeSignatureVerifier(ic.RecipeSigningKey, ic.AllowUnsigned)
+
+	if ic.LocalRecipes != "" {
+		return &recipes.LocalRecipeFetcher{
+			Path:     ic.LocalRecipes,
+			Verifier: verifier,
+		}
+	}
+
+	if len(ic.RecipePaths) > 0 {
+		f := recipes.NewRecipeFileFetcher(ic.RecipePaths)
+		f.Verifier = verifier
+		return f
+	}
+
+	return recipes.NewEmbeddedRecipeFetcher()
+}
+
 var getLatestCliVersionReleased = func(ctx context.Context) (string, error) {
 	return cli.GetLatestReleaseVersion(ctx)
 }
@@ -233,11 +264,12 @@ Our Data Privacy Notice: https://newrelic.com/termsandconditions/services-notice
 	errChan := make(chan error)
 	var err error
 
-	// Test connection to platform if accountID and apiKey are provided.
+	// Test connection to platform if accountID and apiKey are provided, unless
+	// installing from an offline bundle.
 	accountID := configAPI.GetActiveProfileAccountID()
 	apiKey := configAPI.GetActiveProfileString(config.APIKey)
 
-	if accountID != 0 && apiKey != "" {
+	if accountID != 0 && apiKey != "" && !i.IsOffline() {
 		err = i.connectToPlatform()
 		if err != nil {
 			i.status.InstallComplete(err)
@@ -247,7 +279,7 @@ Our Data Privacy Notice: https://newrelic.com/termsandconditions/services-notice
 
 	// If not in a dev environment, check to see if
 	// the installed CLI is up to date.
-	if !cli.IsDevEnvironment() {
+	if !cli.IsDevEnvironment() && !i.IsOffline() {
 		if err = i.promptIfNotLatestCLIVersion(ctx); err != nil {
 			i.status.InstallComplete(err)
 			return err
@@ -318,18 +350,17 @@ func (i *RecipeInstall) install(ctx context.Context) error {
 
 	i.printStartInstallingMessage(repo)
 
-	recipeDetector := i.recipeDetectorFactory(ctx, repo, &i.InstallerContext)
-	availableRecipes, unavailableRecipes, err := recipeDetector.GetDetectedRecipes()
+	availableRecipes, unavailableRecipes, err := i.detectRecipes(ctx, repo)
 	if err != nil {
 		return err
 	}
 
-	if _, ok := availableRecipes.GetRecipeDetection(types.AgentControlRecipeName); i.hostHasAgentControlProcess() && !ok {
-		availableRecipes = append(availableRecipes, &recipes.RecipeDetectionResult{
-			Recipe:     &types.OpenInstallationRecipe{Name: types.AgentControlRecipeName},
-			Status:     execution.RecipeStatusTypes.AVAILABLE,
-			DurationMs: 0,
-		})
+	if err = i.validateAnswers(repo, availableRecipes); err != nil {
+		return err
+	}
+
+	if err = i.validateRecipeGraph(availableRecipes); err != nil {
+		return err
 	}
 
 	i.reportRecipeStatuses(availableRecipes, unavailableRecipes)
@@ -360,6 +391,141 @@ func (i *RecipeInstall) install(ctx context.Context) error {
 	return nil
 }
 
+// Plan runs discovery, detection and bundling and returns the recipes that
+// would be installed, without executing any of them or reporting any status.
+func (i *RecipeInstall) Plan(ctx context.Context) (*InstallPlan, error) {
+	m, err := i.discoverer.Discover(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("there was an error discovering system info: %s", err)
+	}
+
+	if err = i.assertDiscoveryValid(ctx, m); err != nil {
+		return nil, fmt.Errorf("there was an error discovering system info: %s", err)
+	}
+
+	repo := recipes.NewRecipeRepository(func() ([]*types.OpenInstallationRecipe, error) {
+		return i.recipeFetcher.FetchRecipes(ctx)
+	}, m)
+
+	availableRecipes, _, err := i.detectRecipes(ctx, repo)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = i.validateAnswers(repo, availableRecipes); err != nil {
+		return nil, err
+	}
+
+	if err = i.validateRecipeGraph(availableRecipes); err != nil {
+		return nil, err
+	}
+
+	plan := newInstallPlan(*m)
+	bundler := i.bundlerFactory(ctx, availableRecipes)
+	bun := i.checkSuper(bundler)
+
+	if i.shouldInstallCore() {
+		coreBundle := bundler.CreateCoreBundle()
+		bundleRecipeProcessing(coreBundle, bun)
+		plan.addBundle(coreBundle)
+	}
+
+	var additionalBundle *recipes.Bundle
+	if i.RecipeNamesProvided() {
+		additionalBundle = bundler.CreateAdditionalTargetedBundle(i.RecipeNames)
+		bundleRecipeProcessing(additionalBundle, bun)
+
+		for _, name := range i.RecipeNames {
+			br := additionalBundle.GetBundleRecipe(name)
+			if br == nil || !br.HasStatus(execution.RecipeStatusTypes.AVAILABLE) {
+				plan.Unsupported = append(plan.Unsupported, name)
+			}
+		}
+	} else {
+		additionalBundle = bundler.CreateAdditionalGuidedBundle()
+	}
+	plan.addBundle(additionalBundle)
+
+	return plan, nil
+}
+
+// detectRecipes returns the available and unavailable recipes for this host.
+func (i *RecipeInstall) detectRecipes(ctx context.Context, repo *recipes.RecipeRepository) (recipes.RecipeDetectionResults, recipes.RecipeDetectionResults, error) {
+	recipeDetector := i.recipeDetectorFactory(ctx, repo, &i.InstallerContext)
+	availableRecipes, unavailableRecipes, err := recipeDetector.GetDetectedRecipes()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if _, ok := availableRecipes.GetRecipeDetection(types.AgentControlRecipeName); i.hostHasAgentControlProcess() && !ok {
+		availableRecipes = append(availableRecipes, &recipes.RecipeDetectionResult{
+			Recipe:     &types.OpenInstallationRecipe{Name: types.AgentControlRecipeName},
+			Status:     execution.RecipeStatusTypes.AVAILABLE,
+			DurationMs: 0,
+		})
+	}
+
+	return availableRecipes, unavailableRecipes, nil
+}
+
+// validateRecipeGraph checks the dependencies of the available recipes before
+// they are bundled: a dependency cycle, or a targeted recipe depending on a
+// recipe that is not available for this host, fails the install.
+func (i *RecipeInstall) validateRecipeGraph(availableRecipes recipes.RecipeDetectionResults) error {
+	if err := recipes.NewRecipeGraph(availableRecipes).Validate(i.RecipeNames); err != nil {
+		return fmt.Errorf("%w: %s", types.ErrRecipeDependencies, err)
+	}
+
+	return nil
+}
+
+// validateAnswers checks the answers file, if any, against the input variables
+// of the recipes that would be installed, before anything is installed.
+func (i *RecipeInstall) validateAnswers(repo *recipes.RecipeRepository, availableRecipes recipes.RecipeDetectionResults) error {
+	if i.Answers == nil {
+		return nil
+	}
+
+	known, err := repo.FindAll()
+	if err != nil {
+		return err
+	}
+
+	selected := []*types.OpenInstallationRecipe{}
+	seen := map[string]bool{}
+
+	var selectRecipe func(name string)
+	selectRecipe = func(name string) {
+		d, ok := availableRecipes.GetRecipeDetection(name)
+		if !ok || seen[name] {
+			return
+		}
+		seen[name] = true
+
+		for _, dep := range d.Recipe.Dependencies {
+			selectRecipe(dep)
+		}
+		selected = append(selected, d.Recipe)
+	}
+
+	if i.RecipeNamesProvided() {
+		for _, name := range i.RecipeNames {
+			selectRecipe(name)
+		}
+		if i.shouldInstallCore() {
+			for _, name := range []string{types.InfraAgentRecipeName, types.LoggingRecipeName} {
+				selectRecipe(name)
+			}
+		}
+	} else {
+		for _, d := range availableRecipes {
+			selectRecipe(d.Recipe.Name)
+		}
+	}
+
+	return i.Answers.Validate(known, selected, i.RecipeNames, i.AssumeYes)
+}
+
 // TODO: remove?
 func (i *RecipeInstall) printStartInstallingMessage(repo *recipes.RecipeRepository) {
 	message := "\n\nInstalling New Relic"
@@ -615,6 +781,9 @@ func (i *RecipeInstall) executeAndValidate(ctx context.Context, m *types.Discove
 		i.optInToSendLogsAndUpdateRecipeMetadata(r.DisplayName)
 		// FIX: This should rerun the executed command
 		i.askToReRunInDebugMode()
+		// Undo the steps that completed before the failure.  The outcome is added to the
+		// recipe metadata, so this must also occur before we build the RecipeStatusEvent
+		i.rollback(ctx, r, vars, err)
 
 		se := execution.RecipeStatusEvent{
 			Recipe:           *r,
@@ -651,8 +820,7 @@ func (i *RecipeInstall) executeAndValidate(ctx context.Context, m *types.Discove
 	// Once retrieved from the 'Metadata' output of the infrastructure agent, it is then made globally available for the rest of the subsequent
 	// recipes to be installed so they can use it for validation purposes.
 	if r.Name == types.InfraAgentRecipeName {
-		infraAgentEntityKey = i.recipeExecutor.GetOutput().Metadata()["INFRA_KEY"]
-		if infraAgentEntityKey == "" {
+		if setInfraAgentEntityKey(i.recipeExecutor.GetOutput().Metadata()["INFRA_KEY"]) == "" {
 			log.Debug("empty infrastructure agent entity key")
 		}
 	}
@@ -694,7 +862,8 @@ func (i *RecipeInstall) optInToSendLogsAndUpdateRecipeMetadata(displayName strin
 	i.progressIndicator.Fail("Installing " + displayName)
 	recipeOutput := i.recipeExecutor.GetRecipeOutput()
 	logCaptureEnabledForRecipe := i.recipeExecutor.GetOutput().IsCapturedCliOutput()
-	if len(recipeOutput) > 0 && logCaptureEnabledForRecipe {
+	// Recipes installed concurrently cannot share standard input to prompt.
+	if len(recipeOutput) > 0 && logCaptureEnabledForRecipe && !i.IsOffline() && !i.concurrent {
 		userOptIn := i.recipeLogForwarder.PromptUserToSendLogs(os.Stdin)
 		i.recipeLogForwarder.SetUserOptedIn(userOptIn)
 		i.recipeExecutor.GetOutput().AddMetadata("SendLogsOptIn", strconv.FormatBool(userOptIn))
@@ -757,7 +926,10 @@ func (i *RecipeInstall) validateRecipeViaAllMethods(ctx context.Context, r *type
 
 	hasValidationNRQL := r.ValidationNRQL != ""
 
-	if hasValidationNRQL {
+	// Offline installs cannot query the data received by New Relic.
+	if hasValidationNRQL && i.IsOffline() {
+		log.Debugf("offline install, skipping validationNRQL")
+	} else if hasValidationNRQL {
 		validationFuncs = append(validationFuncs, func() (string, error) {
 			return i.recipeValidator.ValidateRecipe(timeoutCtx, *m, *r, vars)
 		})
@@ -814,8 +986,8 @@ func (i *RecipeInstall) executeAndValidateWithProgress(ctx context.Context, m *t
 		}
 
 		vars["assumeYes"] = fmt.Sprintf("%v", assumeYes)
-		if infraAgentEntityKey != "" {
-			vars["INFRA_KEY"] = infraAgentEntityKey
+		if key := getInfraAgentEntityKey(); key != "" {
+			vars["INFRA_KEY"] = key
 		}
 
 		entityGUID, err := i.executeAndValidate(ctx, m, r, vars, assumeYes)
@@ -850,6 +1022,25 @@ func (i *RecipeInstall) executeAndValidateWithProgress(ctx context.Context, m *t
 	}
 }
 
+// forConcurrentInstall returns a copy of the installer for installing a recipe
+// while other recipes are installed.  The copy has its own recipe executor and
+// log forwarder, and its progress is printed as plain lines shared with the
+// other copies.
+func (i *RecipeInstall) forConcurrentInstall() RecipeInstaller {
+	if i.concurrentProgress == nil {
+		i.concurrentProgress = ux.NewConcurrentProgress()
+	}
+
+	c := *i
+	c.recipeExecutor = execution.NewGoTaskRecipeExecutor()
+	c.recipeUninstaller = execution.NewGoTaskRecipeExecutor()
+	c.recipeLogForwarder = execution.NewRecipeLogForwarder()
+	c.progressIndicator = i.concurrentProgress
+	c.concurrent = true
+
+	return &c
+}
+
 func (i *RecipeInstall) finishHandlingFailure(recipeName string) {
 	if i.recipeLogForwarder.HasUserOptedIn() {
 		i.progressIndicator.Start("Sending logs to New Relic")
@@ -861,3 +1052,16 @@ func (i *RecipeInstall) finishHandlingFailure(recipeName string) {
 func (i *RecipeInstall) hostHasAgentControlProcess() bool {
 	return i.processEvaluator.FindProcess(types.AgentControlProcessName)
 }
+
+func getInfraAgentEntityKey() string {
+	infraAgentEntityKeyMu.Lock()
+	defer infraAgentEntityKeyMu.Unlock()
+	return infraAgentEntityKey
+}
+
+func setInfraAgentEntityKey(key string) string {
+	infraAgentEntityKeyMu.Lock()
+	defer infraAgentEntityKeyMu.Unlock()
+	infraAgentEntityKey = key
+	return key
+}
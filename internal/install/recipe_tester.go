@@ -0,0 +1,280 @@
+package install
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/newrelic/newrelic-cli/internal/install/discovery"
+	"github.com/newrelic/newrelic-cli/internal/install/execution"
+	"github.com/newrelic/newrelic-cli/internal/install/recipes"
+	"github.com/newrelic/newrelic-cli/internal/install/types"
+	"github.com/newrelic/newrelic-cli/internal/install/validation"
+	"github.com/newrelic/newrelic-cli/internal/utils"
+	"github.com/newrelic/newrelic-client-go/v2/pkg/nrdb"
+)
+
+// Steps run by the RecipeTester, in order.
+const (
+	recipeTestStepLint       = "lint"
+	recipeTestStepPreInstall = "preInstall"
+	recipeTestStepInstall    = "install"
+	recipeTestStepValidation = "validation"
+)
+
+// RecipeTestStepStatus is the outcome of a single recipe test step.
+type RecipeTestStepStatus string
+
+var RecipeTestStepStatuses = struct {
+	PASSED  RecipeTestStepStatus
+	FAILED  RecipeTestStepStatus
+	SKIPPED RecipeTestStepStatus
+}{
+	PASSED:  "PASSED",
+	FAILED:  "FAILED",
+	SKIPPED: "SKIPPED",
+}
+
+// RecipeTestResult is the outcome of running a recipe with the RecipeTester.
+type RecipeTestResult struct {
+	Recipe  string            `json:"recipe"`
+	WorkDir string            `json:"workDir,omitempty"`
+	Passed  bool              `json:"passed"`
+	Steps   []*RecipeTestStep `json:"steps"`
+}
+
+// RecipeTestStep is a single step of a recipe test run.
+type RecipeTestStep struct {
+	Name       string               `json:"name"`
+	Status     RecipeTestStepStatus `json:"status"`
+	Message    string               `json:"message,omitempty"`
+	Query      string               `json:"query,omitempty"`
+	EntityGUID string               `json:"entityGuid,omitempty"`
+	DurationMs int64                `json:"durationMs"`
+
+	started time.Time
+}
+
+// RecipeTester runs a recipe's preInstall, install and validation steps from a
+// sandboxed working directory.  Validation queries are answered with stubbed
+// results instead of querying NRDB, so recipes can be tested without reporting
+// any data.
+type RecipeTester struct {
+	// WorkDir is the sandbox the recipe is run from.  A temporary directory is
+	// created, and removed afterwards, when it is empty.
+	WorkDir string
+	// Keep leaves a temporary sandbox in place once the test has completed.
+	Keep bool
+	// ValidationResults are returned for the recipe's validation query.
+	ValidationResults []nrdb.NRDBResult
+
+	discoverer        Discoverer
+	recipeVarPreparer RecipeVarPreparer
+	executorFactory   func(dir string) execution.RecipeExecutor
+	preInstallFactory func(dir string) execution.RecipeExecutor
+}
+
+// NewRecipeTester returns a new instance of RecipeTester.
+func NewRecipeTester(answers types.RecipeAnswers, validationResults []nrdb.NRDBResult) *RecipeTester {
+	rvp := execution.NewRecipeVarProvider()
+	rvp.Answers = answers
+
+	return &RecipeTester{
+		ValidationResults: validationResults,
+		discoverer:        discovery.NewPSUtilDiscoverer(),
+		recipeVarPreparer: rvp,
+		executorFactory: func(dir string) execution.RecipeExecutor {
+			re := execution.NewGoTaskRecipeExecutor()
+			re.Dir = dir
+			return re
+		},
+		preInstallFactory: func(dir string) execution.RecipeExecutor {
+			se := execution.NewShRecipeExecutor()
+			se.Dir = dir
+			return se
+		},
+	}
+}
+
+// TestFile lints and runs the recipe at the given path.
+func (t *RecipeTester) TestFile(ctx context.Context, path string) (*RecipeTestResult, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return t.Test(ctx, path, content)
+}
+
+// Test lints and runs the given recipe definition.  Each step only runs when
+// the steps before it have passed.
+func (t *RecipeTester) Test(ctx context.Context, path string, content []byte) (*RecipeTestResult, error) {
+	result := &RecipeTestResult{
+		Recipe: path,
+		Steps:  []*RecipeTestStep{},
+	}
+
+	r, problems := recipes.LintRecipe(content)
+	lint := result.start(recipeTestStepLint)
+	if (recipes.LintResult{Problems: problems}).HasErrors() {
+		lint.fail(fmt.Errorf("%d problem(s) found, run newrelic recipe lint for details", len(problems)))
+		return result.finish(), nil
+	}
+	lint.pass()
+	result.Recipe = r.Name
+
+	workDir, cleanup, err := t.sandbox(r.Name)
+	if err != nil {
+		return nil, err
+	}
+	defer cleanup()
+	result.WorkDir = workDir
+
+	m, err := t.discoverer.Discover(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("there was an error discovering system info: %s", err)
+	}
+
+	vars, err := t.recipeVarPreparer.Prepare(*m, *r, true)
+	if err != nil {
+		return nil, err
+	}
+	vars["assumeYes"] = "true"
+
+	preInstall := result.start(recipeTestStepPreInstall)
+	if r.PreInstall.RequireAtDiscovery == "" {
+		preInstall.skip("recipe has no preInstall.requireAtDiscovery script")
+	} else if err := t.preInstallFactory(workDir).ExecutePreInstall(ctx, *r, vars); err != nil {
+		preInstall.fail(describePreInstallError(err))
+		return result.finish(), nil
+	} else {
+		preInstall.pass()
+	}
+
+	install := result.start(recipeTestStepInstall)
+	if err := t.executorFactory(workDir).Execute(ctx, *r, vars); err != nil {
+		install.fail(err)
+		return result.finish(), nil
+	}
+	install.pass()
+
+	step := result.start(recipeTestStepValidation)
+	if r.ValidationNRQL == "" {
+		step.skip("recipe has no validationNrql")
+		return result.finish(), nil
+	}
+
+	client := &stubNRDBClient{results: t.ValidationResults}
+	v := validation.NewPollingRecipeValidator(client)
+	v.MaxAttempts = 1
+	v.IntervalMilliSeconds = 1
+
+	entityGUID, err := v.ValidateRecipe(ctx, *m, *r, vars)
+	if len(client.queries) > 0 {
+		step.Query = client.queries[0]
+	}
+	if err != nil {
+		step.fail(err)
+		return result.finish(), nil
+	}
+	step.EntityGUID = entityGUID
+	step.pass()
+
+	return result.finish(), nil
+}
+
+// sandbox returns the working directory to run the recipe from, and a function
+// removing it once the test has completed.
+func (t *RecipeTester) sandbox(name string) (string, func(), error) {
+	if t.WorkDir != "" {
+		if err := os.MkdirAll(t.WorkDir, 0750); err != nil {
+			return "", nil, fmt.Errorf("could not create working directory: %s", err)
+		}
+
+		return t.WorkDir, func() {}, nil
+	}
+
+	dir, err := os.MkdirTemp("", fmt.Sprintf("recipe-test-%s-", name))
+	if err != nil {
+		return "", nil, fmt.Errorf("could not create working directory: %s", err)
+	}
+
+	return dir, func() {
+		if t.Keep {
+			return
+		}
+
+		if err := os.RemoveAll(dir); err != nil {
+			log.Debugf("could not remove working directory %s: %s", dir, err)
+		}
+	}, nil
+}
+
+func describePreInstallError(err error) error {
+	if utils.IsExitStatusCode(131, err) {
+		return fmt.Errorf("recipe is not supported on this host: %s", err)
+	}
+
+	if utils.IsExitStatusCode(132, err) {
+		return fmt.Errorf("recipe was detected but is not available to install: %s", err)
+	}
+
+	return err
+}
+
+func (r *RecipeTestResult) start(name string) *RecipeTestStep {
+	s := &RecipeTestStep{
+		Name:    name,
+		started: time.Now(),
+	}
+	r.Steps = append(r.Steps, s)
+
+	return s
+}
+
+func (r *RecipeTestResult) finish() *RecipeTestResult {
+	r.Passed = true
+	for _, s := range r.Steps {
+		if s.Status == RecipeTestStepStatuses.FAILED {
+			r.Passed = false
+		}
+	}
+
+	return r
+}
+
+func (s *RecipeTestStep) pass() {
+	s.end(RecipeTestStepStatuses.PASSED, "")
+}
+
+func (s *RecipeTestStep) skip(reason string) {
+	s.end(RecipeTestStepStatuses.SKIPPED, reason)
+}
+
+func (s *RecipeTestStep) fail(err error) {
+	s.end(RecipeTestStepStatuses.FAILED, err.Error())
+}
+
+func (s *RecipeTestStep) end(status RecipeTestStepStatus, message string) {
+	s.Status = status
+	s.Message = message
+	s.DurationMs = time.Since(s.started).Milliseconds()
+}
+
+// stubNRDBClient answers every query with the same results, recording the
+// queries it was sent.
+type stubNRDBClient struct {
+	results []nrdb.NRDBResult
+	queries []string
+}
+
+func (c *stubNRDBClient) QueryWithContext(ctx context.Context, accountID int, nrql nrdb.NRQL) (*nrdb.NRDBResultContainer, error) {
+	c.queries = append(c.queries, string(nrql))
+
+	return &nrdb.NRDBResultContainer{
+		Results: c.results,
+	}, nil
+}
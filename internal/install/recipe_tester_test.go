@@ -0,0 +1,156 @@
+package install
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/newrelic/newrelic-cli/internal/install/discovery"
+	"github.com/newrelic/newrelic-cli/internal/install/execution"
+	"github.com/newrelic/newrelic-cli/internal/install/types"
+	"github.com/newrelic/newrelic-client-go/v2/pkg/nrdb"
+)
+
+const testRecipeTesterRecipe = `
+name: test-recipe
+displayName: Test Recipe
+installTargets:
+  - type: host
+    os: linux
+preInstall:
+  requireAtDiscovery: |
+    exit 0
+install:
+  version: "3"
+  tasks:
+    default:
+      cmds:
+        - touch installed
+validationNrql: "SELECT count(*) FROM SystemSample WHERE hostname = '{{.HOSTNAME}}'"
+`
+
+func newTestRecipeTester(t *testing.T, executor execution.RecipeExecutor, results []nrdb.NRDBResult) *RecipeTester {
+	rvp := execution.NewMockRecipeVarProvider()
+	rvp.Vars = types.RecipeVars{"HOSTNAME": "test-host"}
+
+	return &RecipeTester{
+		WorkDir:           t.TempDir(),
+		ValidationResults: results,
+		discoverer:        discovery.NewMockDiscoverer(),
+		recipeVarPreparer: rvp,
+		executorFactory: func(dir string) execution.RecipeExecutor {
+			return executor
+		},
+		preInstallFactory: func(dir string) execution.RecipeExecutor {
+			return executor
+		},
+	}
+}
+
+func stepStatuses(result *RecipeTestResult) map[string]RecipeTestStepStatus {
+	statuses := map[string]RecipeTestStepStatus{}
+	for _, s := range result.Steps {
+		statuses[s.Name] = s.Status
+	}
+
+	return statuses
+}
+
+func TestRecipeTesterShouldPassAllSteps(t *testing.T) {
+	tester := newTestRecipeTester(t, execution.NewMockRecipeExecutor(), []nrdb.NRDBResult{
+		{"count": float64(1), "entityGuid": "MTIzNDU2"},
+	})
+
+	result, err := tester.Test(context.Background(), "test.yml", []byte(testRecipeTesterRecipe))
+
+	require.NoError(t, err)
+	assert.True(t, result.Passed)
+	assert.Equal(t, "test-recipe", result.Recipe)
+	assert.Equal(t, map[string]RecipeTestStepStatus{
+		recipeTestStepLint:       RecipeTestStepStatuses.PASSED,
+		recipeTestStepPreInstall: RecipeTestStepStatuses.PASSED,
+		recipeTestStepInstall:    RecipeTestStepStatuses.PASSED,
+		recipeTestStepValidation: RecipeTestStepStatuses.PASSED,
+	}, stepStatuses(result))
+
+	validation := result.Steps[3]
+	assert.Equal(t, "MTIzNDU2", validation.EntityGUID)
+	assert.Equal(t, "SELECT count(*) FROM SystemSample WHERE hostname = 'test-host'", validation.Query)
+}
+
+func TestRecipeTesterShouldFailValidationWithoutCount(t *testing.T) {
+	tester := newTestRecipeTester(t, execution.NewMockRecipeExecutor(), []nrdb.NRDBResult{
+		{"count": float64(0)},
+	})
+
+	result, err := tester.Test(context.Background(), "test.yml", []byte(testRecipeTesterRecipe))
+
+	require.NoError(t, err)
+	assert.False(t, result.Passed)
+	assert.Equal(t, RecipeTestStepStatuses.FAILED, stepStatuses(result)[recipeTestStepValidation])
+}
+
+func TestRecipeTesterShouldStopAtFailedStep(t *testing.T) {
+	executor := execution.NewMockRecipeExecutor()
+	executor.ExecuteErr = errors.New("exit status 131")
+	tester := newTestRecipeTester(t, executor, []nrdb.NRDBResult{{"count": float64(1)}})
+
+	result, err := tester.Test(context.Background(), "test.yml", []byte(testRecipeTesterRecipe))
+
+	require.NoError(t, err)
+	assert.False(t, result.Passed)
+	assert.Equal(t, map[string]RecipeTestStepStatus{
+		recipeTestStepLint:       RecipeTestStepStatuses.PASSED,
+		recipeTestStepPreInstall: RecipeTestStepStatuses.FAILED,
+	}, stepStatuses(result))
+	assert.Contains(t, result.Steps[1].Message, "not supported on this host")
+}
+
+func TestRecipeTesterShouldStopAtLintErrors(t *testing.T) {
+	tester := newTestRecipeTester(t, execution.NewMockRecipeExecutor(), nil)
+
+	result, err := tester.Test(context.Background(), "test.yml", []byte("name: test-recipe\nprocessMatch: testd\n"))
+
+	require.NoError(t, err)
+	assert.False(t, result.Passed)
+	assert.Equal(t, "test.yml", result.Recipe)
+	require.Len(t, result.Steps, 1)
+	assert.Equal(t, RecipeTestStepStatuses.FAILED, result.Steps[0].Status)
+}
+
+func TestRecipeTesterShouldRunInstallFromWorkDir(t *testing.T) {
+	tester := newTestRecipeTester(t, nil, []nrdb.NRDBResult{{"count": float64(1)}})
+	tester.executorFactory = func(dir string) execution.RecipeExecutor {
+		re := execution.NewGoTaskRecipeExecutor()
+		re.Dir = dir
+		return re
+	}
+	tester.preInstallFactory = func(dir string) execution.RecipeExecutor {
+		se := execution.NewShRecipeExecutor()
+		se.Dir = dir
+		return se
+	}
+
+	result, err := tester.Test(context.Background(), "test.yml", []byte(testRecipeTesterRecipe))
+
+	require.NoError(t, err)
+	assert.True(t, result.Passed)
+	assert.FileExists(t, filepath.Join(tester.WorkDir, "installed"))
+}
+
+func TestRecipeTesterShouldRemoveTemporaryWorkDir(t *testing.T) {
+	tester := newTestRecipeTester(t, execution.NewMockRecipeExecutor(), []nrdb.NRDBResult{{"count": float64(1)}})
+	tester.WorkDir = ""
+
+	result, err := tester.Test(context.Background(), "test.yml", []byte(testRecipeTesterRecipe))
+
+	require.NoError(t, err)
+	require.NotEmpty(t, result.WorkDir)
+	_, err = os.Stat(result.WorkDir)
+	assert.True(t, os.IsNotExist(err))
+}
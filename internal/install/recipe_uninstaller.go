@@ -0,0 +1,177 @@
+package install
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+	"gopkg.in/yaml.v2"
+
+	"github.com/newrelic/newrelic-cli/internal/install/recipes"
+	"github.com/newrelic/newrelic-cli/internal/install/types"
+)
+
+// Metadata added to a failed recipe's status event describing its rollback.
+const (
+	rollbackStatusKey = "RollbackStatus"
+	rollbackTasksKey  = "RollbackTasks"
+	rollbackErrorKey  = "RollbackError"
+
+	rollbackSucceeded = "SUCCEEDED"
+	rollbackFailed    = "FAILED"
+	rollbackDisabled  = "DISABLED"
+)
+
+// Uninstall runs the uninstall section of each targeted recipe.
+func (i *RecipeInstall) Uninstall(ctx context.Context) error {
+	m, err := i.discoverer.Discover(ctx)
+	if err != nil {
+		return fmt.Errorf("there was an error discovering system info: %s", err)
+	}
+
+	repo := recipes.NewRecipeRepository(func() ([]*types.OpenInstallationRecipe, error) {
+		return i.recipeFetcher.FetchRecipes(ctx)
+	}, m)
+
+	toUninstall := []*types.OpenInstallationRecipe{}
+	for _, name := range i.RecipeNames {
+		r := repo.FindRecipeByName(name)
+		if r == nil {
+			return fmt.Errorf("could not find a recipe named %s for this system", name)
+		}
+
+		if r.Uninstall == "" {
+			return fmt.Errorf("recipe %s does not support uninstall", name)
+		}
+
+		toUninstall = append(toUninstall, r)
+	}
+
+	for _, r := range toUninstall {
+		if !i.AssumeYes {
+			ok, err := i.prompter.PromptYesNo(fmt.Sprintf("Uninstall %s?", r.DisplayName))
+			if err != nil {
+				return err
+			}
+
+			if !ok {
+				log.Debugf("skipping uninstall of %s", r.Name)
+				continue
+			}
+		}
+
+		vars, err := i.recipeVarPreparer.Prepare(*m, *r, i.AssumeYes)
+		if err != nil {
+			return err
+		}
+		vars["assumeYes"] = fmt.Sprintf("%v", i.AssumeYes)
+
+		msg := fmt.Sprintf("Uninstalling %s", r.DisplayName)
+		i.progressIndicator.ShowSpinner(i.AssumeYes)
+		i.progressIndicator.Start(msg)
+
+		if err := i.recipeUninstaller.ExecuteUninstall(ctx, *r, vars, nil); err != nil {
+			i.progressIndicator.Fail(msg)
+			return fmt.Errorf("uninstall failed for %s: %s", r.Name, err)
+		}
+
+		i.progressIndicator.Success(msg)
+	}
+
+	return nil
+}
+
+// rollback runs the uninstall tasks matching the install steps a failed recipe
+// had started, recording the outcome in the recipe's output metadata so that it
+// is reported with the recipe's failure event.
+func (i *RecipeInstall) rollback(ctx context.Context, r *types.OpenInstallationRecipe, vars types.RecipeVars, installErr error) {
+	if r.Uninstall == "" || i.recipeUninstaller == nil {
+		return
+	}
+
+	output := i.recipeExecutor.GetOutput()
+
+	if i.NoRollback {
+		output.AddMetadata(rollbackStatusKey, rollbackDisabled)
+		return
+	}
+
+	var failedTaskPath []string
+	if e, ok := installErr.(types.GoTaskError); ok {
+		failedTaskPath = e.TaskPath()
+	}
+
+	tasks, err := rollbackTasks(r.Install, r.Uninstall, failedTaskPath)
+	if err == nil && len(tasks) == 0 {
+		log.Debugf("no uninstall tasks match the started install steps of %s, skipping rollback", r.Name)
+		return
+	}
+
+	msg := fmt.Sprintf("Rolling back %s", r.DisplayName)
+	if err == nil {
+		log.Debugf("rolling back %s with uninstall tasks %v", r.Name, tasks)
+
+		i.progressIndicator.Start(msg)
+		err = i.recipeUninstaller.ExecuteUninstall(ctx, *r, vars, tasks)
+	}
+
+	if err != nil {
+		log.Debugf("rollback of %s failed: %s", r.Name, err)
+
+		i.progressIndicator.Fail(msg)
+		output.AddMetadata(rollbackStatusKey, rollbackFailed)
+		output.AddMetadata(rollbackErrorKey, err.Error())
+		return
+	}
+
+	i.progressIndicator.Success(msg)
+	output.AddMetadata(rollbackStatusKey, rollbackSucceeded)
+	output.AddMetadata(rollbackTasksKey, strings.Join(tasks, ","))
+}
+
+// rollbackTasks returns the uninstall tasks that reverse the install steps
+// started before a failure, in reverse order.  Install steps are the tasks the
+// install default task calls, and a step is reversed by the uninstall task of
+// the same name.  When no uninstall task matches, the uninstall default task
+// is used.  The step that failed is identified by the go-task task path of the
+// failure; without one every step is considered started.
+func rollbackTasks(install string, uninstall string, failedTaskPath []string) ([]string, error) {
+	in := planTaskfile{}
+	if err := yaml.Unmarshal([]byte(install), &in); err != nil {
+		return nil, fmt.Errorf("could not unmarshal install taskfile: %s", err)
+	}
+
+	un := planTaskfile{}
+	if err := yaml.Unmarshal([]byte(uninstall), &un); err != nil {
+		return nil, fmt.Errorf("could not unmarshal uninstall taskfile: %s", err)
+	}
+
+	started := in.calledTasks(defaultTaskName)
+	if len(failedTaskPath) > 1 && failedTaskPath[0] == defaultTaskName {
+		for n, step := range started {
+			if step == failedTaskPath[1] {
+				started = started[:n+1]
+				break
+			}
+		}
+	}
+
+	tasks := []string{}
+	seen := map[string]bool{}
+	for n := len(started) - 1; n >= 0; n-- {
+		step := started[n]
+		if _, ok := un.Tasks[step]; ok && !seen[step] {
+			seen[step] = true
+			tasks = append(tasks, step)
+		}
+	}
+
+	if len(tasks) == 0 {
+		if _, ok := un.Tasks[defaultTaskName]; ok {
+			tasks = append(tasks, defaultTaskName)
+		}
+	}
+
+	return tasks, nil
+}
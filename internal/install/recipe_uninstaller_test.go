@@ -0,0 +1,214 @@
+package install
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/newrelic/newrelic-cli/internal/install/execution"
+	"github.com/newrelic/newrelic-cli/internal/install/recipes"
+	"github.com/newrelic/newrelic-cli/internal/install/types"
+)
+
+const (
+	testRollbackInstall = `
+version: "3"
+tasks:
+  default:
+    cmds:
+      - task: assert_pre_req
+      - task: setup
+      - task: configure
+      - task: restart
+  assert_pre_req:
+    cmds:
+      - echo checking
+  setup:
+    cmds:
+      - echo setup
+  configure:
+    cmds:
+      - echo configure
+  restart:
+    cmds:
+      - echo restart
+`
+	testRollbackUninstall = `
+version: "3"
+tasks:
+  default:
+    cmds:
+      - task: configure
+      - task: setup
+  setup:
+    cmds:
+      - echo remove package
+  configure:
+    cmds:
+      - echo remove config
+`
+)
+
+func TestRollbackTasksShouldReverseStartedSteps(t *testing.T) {
+	tasks, err := rollbackTasks(testRollbackInstall, testRollbackUninstall, []string{"default", "configure"})
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{"configure", "setup"}, tasks)
+}
+
+func TestRollbackTasksShouldOnlyReverseStepsBeforeTheFailure(t *testing.T) {
+	tasks, err := rollbackTasks(testRollbackInstall, testRollbackUninstall, []string{"default", "setup"})
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{"setup"}, tasks)
+}
+
+func TestRollbackTasksShouldReverseEveryStepWithoutTaskPath(t *testing.T) {
+	tasks, err := rollbackTasks(testRollbackInstall, testRollbackUninstall, nil)
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{"configure", "setup"}, tasks)
+}
+
+func TestRollbackTasksShouldFallBackToUninstallDefault(t *testing.T) {
+	tasks, err := rollbackTasks(testRollbackInstall, "tasks:\n  default:\n    cmds:\n      - echo remove\n", []string{"default", "setup"})
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{"default"}, tasks)
+}
+
+func TestInstallShouldRollBackFailedRecipe(t *testing.T) {
+	r := &recipes.RecipeDetectionResult{
+		Recipe: recipes.NewRecipeBuilder().Name("other").
+			InstallGoTaskScript(testRollbackInstall).
+			UninstallGoTaskScript(testRollbackUninstall).
+			Build(),
+		Status: execution.RecipeStatusTypes.AVAILABLE,
+	}
+	installErr := types.NewGoTaskGeneralError(errors.New(`task: Failed to run task "default": task: Failed to run task "configure": exit status 1`))
+	uninstaller := execution.NewMockRecipeUninstaller()
+	statusReporter := execution.NewMockStatusReporter()
+	recipeInstall := NewRecipeInstallBuilder().WithStatusReporter(statusReporter).
+		WithRecipeDetectionResult(r).WithRecipeExecutionError(installErr).
+		WithRecipeUninstaller(uninstaller).withShouldInstallCore(func() bool { return false }).Build()
+	recipeInstall.AssumeYes = true
+
+	err := recipeInstall.Install()
+
+	assert.Error(t, err)
+	assert.Equal(t, [][]string{{"configure", "setup"}}, uninstaller.UninstallCalls)
+	assert.Equal(t, 1, statusReporter.RecipeFailedCallCount, "Failed Count")
+	metadata := recipeInstall.recipeExecutor.GetOutput().Metadata()
+	assert.Equal(t, rollbackSucceeded, metadata[rollbackStatusKey])
+	assert.Equal(t, "configure,setup", metadata[rollbackTasksKey])
+}
+
+func TestInstallShouldReportFailedRollback(t *testing.T) {
+	r := &recipes.RecipeDetectionResult{
+		Recipe: recipes.NewRecipeBuilder().Name("other").
+			InstallGoTaskScript(testRollbackInstall).
+			UninstallGoTaskScript(testRollbackUninstall).
+			Build(),
+		Status: execution.RecipeStatusTypes.AVAILABLE,
+	}
+	uninstaller := execution.NewMockRecipeUninstaller()
+	uninstaller.UninstallErr = errors.New("package is locked")
+	recipeInstall := NewRecipeInstallBuilder().
+		WithRecipeDetectionResult(r).WithRecipeExecutionError(errors.New("install failed")).
+		WithRecipeUninstaller(uninstaller).withShouldInstallCore(func() bool { return false }).Build()
+	recipeInstall.AssumeYes = true
+
+	err := recipeInstall.Install()
+
+	assert.Error(t, err)
+	assert.Len(t, uninstaller.UninstallCalls, 1)
+	metadata := recipeInstall.recipeExecutor.GetOutput().Metadata()
+	assert.Equal(t, rollbackFailed, metadata[rollbackStatusKey])
+	assert.Equal(t, "package is locked", metadata[rollbackErrorKey])
+}
+
+func TestInstallShouldNotRollBackWhenDisabled(t *testing.T) {
+	r := &recipes.RecipeDetectionResult{
+		Recipe: recipes.NewRecipeBuilder().Name("other").
+			InstallGoTaskScript(testRollbackInstall).
+			UninstallGoTaskScript(testRollbackUninstall).
+			Build(),
+		Status: execution.RecipeStatusTypes.AVAILABLE,
+	}
+	uninstaller := execution.NewMockRecipeUninstaller()
+	recipeInstall := NewRecipeInstallBuilder().
+		WithRecipeDetectionResult(r).WithRecipeExecutionError(errors.New("install failed")).
+		WithRecipeUninstaller(uninstaller).WithNoRollback().withShouldInstallCore(func() bool { return false }).Build()
+	recipeInstall.AssumeYes = true
+
+	err := recipeInstall.Install()
+
+	assert.Error(t, err)
+	assert.Empty(t, uninstaller.UninstallCalls)
+	assert.Equal(t, rollbackDisabled, recipeInstall.recipeExecutor.GetOutput().Metadata()[rollbackStatusKey])
+}
+
+func TestUninstallShouldRunUninstallTasks(t *testing.T) {
+	r := recipes.NewRecipeBuilder().Name("other").
+		TargetOs(types.OpenInstallationOperatingSystemTypes.LINUX).
+		UninstallGoTaskScript(testRollbackUninstall).
+		Build()
+	uninstaller := execution.NewMockRecipeUninstaller()
+	recipeInstall := NewRecipeInstallBuilder().WithFetchRecipesVal([]*types.OpenInstallationRecipe{r}).
+		WithTargetRecipeName("other").WithRecipeUninstaller(uninstaller).Build()
+	recipeInstall.AssumeYes = true
+
+	err := recipeInstall.Uninstall(context.Background())
+
+	require.NoError(t, err)
+	assert.Equal(t, [][]string{nil}, uninstaller.UninstallCalls)
+}
+
+func TestUninstallShouldErrorWithoutUninstallSection(t *testing.T) {
+	r := recipes.NewRecipeBuilder().Name("other").
+		TargetOs(types.OpenInstallationOperatingSystemTypes.LINUX).
+		Build()
+	uninstaller := execution.NewMockRecipeUninstaller()
+	recipeInstall := NewRecipeInstallBuilder().WithFetchRecipesVal([]*types.OpenInstallationRecipe{r}).
+		WithTargetRecipeName("other").WithRecipeUninstaller(uninstaller).Build()
+	recipeInstall.AssumeYes = true
+
+	err := recipeInstall.Uninstall(context.Background())
+
+	assert.EqualError(t, err, "recipe other does not support uninstall")
+	assert.Empty(t, uninstaller.UninstallCalls)
+}
+
+func TestConcurrentInstallsShouldRollBackIndependently(t *testing.T) {
+	recipeInstall := NewRecipeInstallBuilder().Build()
+	recipeInstall.recipeUninstaller = execution.NewGoTaskRecipeExecutor()
+	installErr := types.NewGoTaskGeneralError(errors.New(`task: Failed to run task "default": task: Failed to run task "configure": exit status 1`))
+
+	installers := make([]*RecipeInstall, 2)
+	var wg sync.WaitGroup
+	for n := range installers {
+		installers[n] = recipeInstall.forConcurrentInstall().(*RecipeInstall)
+		r := recipes.NewRecipeBuilder().Name(fmt.Sprintf("failed-%d", n)).
+			InstallGoTaskScript(testRollbackInstall).
+			UninstallGoTaskScript(testRollbackUninstall).
+			Build()
+
+		wg.Add(1)
+		go func(i *RecipeInstall) {
+			defer wg.Done()
+			i.rollback(context.Background(), r, types.RecipeVars{"assumeYes": "true"}, installErr)
+		}(installers[n])
+	}
+	wg.Wait()
+
+	for _, i := range installers {
+		metadata := i.recipeExecutor.GetOutput().Metadata()
+		assert.Equal(t, rollbackSucceeded, metadata[rollbackStatusKey])
+		assert.Equal(t, "configure,setup", metadata[rollbackTasksKey])
+	}
+}
@@ -15,6 +15,8 @@ import (
 
 type LocalRecipeFetcher struct {
 	Path string
+	// Verifier checks the signature of each recipe file, when set.
+	Verifier *RecipeSignatureVerifier
 }
 
 func (f *LocalRecipeFetcher) FetchRecipes(ctx context.Context) ([]*types.OpenInstallationRecipe, error) {
@@ -25,7 +27,7 @@ func (f *LocalRecipeFetcher) FetchRecipes(ctx context.Context) ([]*types.OpenIns
 		return nil, fmt.Errorf("unable to load recipes from empty path spec")
 	}
 
-	recipes, err = loadRecipesFromDir(ctx, f.Path)
+	recipes, err = loadRecipesFromDir(ctx, f.Path, f.Verifier)
 	if err != nil {
 		return nil, err
 	}
@@ -37,7 +39,7 @@ func (f *LocalRecipeFetcher) FetchLibraryVersion(ctx context.Context) string {
 	return ""
 }
 
-func loadRecipesFromDir(ctx context.Context, path string) ([]*types.OpenInstallationRecipe, error) {
+func loadRecipesFromDir(ctx context.Context, path string, verifier *RecipeSignatureVerifier) ([]*types.OpenInstallationRecipe, error) {
 	recipePaths := []string{}
 
 	log.WithFields(log.Fields{
@@ -71,11 +73,20 @@ func loadRecipesFromDir(ctx context.Context, path string) ([]*types.OpenInstalla
 			continue
 		}
 
+		var signature *types.RecipeSignature
+		if verifier != nil {
+			signature, err = verifier.VerifyFileContent(path, content)
+			if err != nil {
+				return nil, err
+			}
+		}
+
 		err = yaml.Unmarshal(content, &r)
 		if err != nil {
 			log.Error(err)
 			continue
 		}
+		r.SetSignature(signature)
 
 		recipes = append(recipes, &r)
 	}
@@ -0,0 +1,574 @@
+package recipes
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"gopkg.in/yaml.v2"
+
+	"github.com/newrelic/newrelic-cli/internal/cli"
+	"github.com/newrelic/newrelic-cli/internal/install/execution"
+	"github.com/newrelic/newrelic-cli/internal/install/types"
+)
+
+const (
+	// OfflineBundleManifestFile is the name of the file describing the content
+	// of an offline bundle.
+	OfflineBundleManifestFile = "bundle.json"
+	offlineBundleRecipesDir   = "recipes"
+	offlineBundleArtifactsDir = "artifacts"
+)
+
+var (
+	// The Go architectures bundles can be created for, and the kernel
+	// architecture discovery reports for each of them.
+	offlineBundleKernelArchs = map[string]string{
+		"amd64": "x86_64",
+		"arm64": "aarch64",
+		"386":   "i386",
+		"arm":   "armv7l",
+	}
+	offlineBundleOperatingSystems = []string{"linux", "windows", "darwin"}
+
+	artifactURLRegex = regexp.MustCompile(`https?://[^\s"'<>()|;,` + "`" + `]+`)
+	recipeVarRegex   = regexp.MustCompile(`{{\s*\.([A-Za-z0-9_]+)\s*}}`)
+)
+
+// OfflineBundleManifest describes the recipes and artifacts packaged in an
+// offline bundle.
+type OfflineBundleManifest struct {
+	Platform       string                  `json:"platform"`
+	CreatedAt      time.Time               `json:"createdAt"`
+	CLIVersion     string                  `json:"cliVersion,omitempty"`
+	LibraryVersion string                  `json:"libraryVersion,omitempty"`
+	Recipes        []string                `json:"recipes"`
+	Artifacts      []OfflineBundleArtifact `json:"artifacts"`
+	Skipped        []OfflineBundleSkipped  `json:"skipped,omitempty"`
+}
+
+// OfflineBundleArtifact is a file downloaded by a recipe, packaged so that it
+// can be installed without network access.
+type OfflineBundleArtifact struct {
+	URL    string `json:"url"`
+	File   string `json:"file"`
+	SHA256 string `json:"sha256"`
+	Size   int64  `json:"size"`
+}
+
+// OfflineBundleSkipped is a URL found in a recipe that could not be packaged.
+// Installing the recipe from the bundle still requires network access to it.
+type OfflineBundleSkipped struct {
+	Recipe string `json:"recipe"`
+	URL    string `json:"url"`
+	Reason string `json:"reason"`
+}
+
+// OfflineBundleOptions configures the creation of an offline bundle.
+type OfflineBundleOptions struct {
+	// RecipeNames are the recipes to package, along with their dependencies.
+	RecipeNames []string
+	// Platform is the os/arch the bundle installs on, such as linux/amd64.
+	Platform string
+	// IncludeCore also packages the infrastructure agent and logs recipes
+	// installed by default.
+	IncludeCore bool
+	// Output is the path of the .tar.gz file to write.
+	Output string
+}
+
+// OfflineBundler packages recipes and every artifact they download into a
+// single archive, for hosts without network access.
+type OfflineBundler struct {
+	fetcher     RecipeFetcher
+	downloadURL string
+	download    func(ctx context.Context, url string, w io.Writer) error
+}
+
+// NewOfflineBundler returns an OfflineBundler packaging recipes loaded by fetcher.
+func NewOfflineBundler(fetcher RecipeFetcher) *OfflineBundler {
+	return &OfflineBundler{
+		fetcher:     fetcher,
+		downloadURL: execution.DownloadURL(),
+		download:    downloadArtifact,
+	}
+}
+
+// ParseOfflineBundlePlatform splits an os/arch platform such as linux/amd64.
+func ParseOfflineBundlePlatform(platform string) (string, string, error) {
+	goos, goarch, ok := strings.Cut(strings.ToLower(platform), "/")
+	if !ok || goos == "" || goarch == "" {
+		return "", "", fmt.Errorf("invalid platform %s, must be os/arch such as linux/amd64", platform)
+	}
+
+	if !isOneOf(goos, offlineBundleOperatingSystems) {
+		return "", "", fmt.Errorf("unsupported platform os %s, must be one of %s", goos, strings.Join(offlineBundleOperatingSystems, ", "))
+	}
+
+	if _, ok := offlineBundleKernelArchs[goarch]; !ok {
+		archs := []string{}
+		for a := range offlineBundleKernelArchs {
+			archs = append(archs, a)
+		}
+		sort.Strings(archs)
+		return "", "", fmt.Errorf("unsupported platform arch %s, must be one of %s", goarch, strings.Join(archs, ", "))
+	}
+
+	return goos, goarch, nil
+}
+
+// Create writes an offline bundle of the requested recipes and returns its manifest.
+func (b *OfflineBundler) Create(ctx context.Context, opts OfflineBundleOptions) (*OfflineBundleManifest, error) {
+	goos, goarch, err := ParseOfflineBundlePlatform(opts.Platform)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(opts.RecipeNames) == 0 {
+		return nil, fmt.Errorf("at least one recipe is required")
+	}
+
+	all, err := b.fetcher.FetchRecipes(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	selected, err := selectOfflineBundleRecipes(all, opts, goos, offlineBundleKernelArchs[goarch])
+	if err != nil {
+		return nil, err
+	}
+
+	staging, err := os.MkdirTemp("", "newrelic-offline-bundle")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(staging)
+
+	manifest := &OfflineBundleManifest{
+		Platform:       goos + "/" + goarch,
+		CreatedAt:      time.Now().UTC(),
+		CLIVersion:     cli.Version(),
+		LibraryVersion: b.fetcher.FetchLibraryVersion(ctx),
+		Recipes:        []string{},
+		Artifacts:      []OfflineBundleArtifact{},
+	}
+
+	vars := types.RecipeVars{
+		"NEW_RELIC_DOWNLOAD_URL": b.downloadURL,
+		"OS":                     goos,
+		"KERNEL_ARCH":            offlineBundleKernelArchs[goarch],
+	}
+
+	downloaded := map[string]bool{}
+	for n, r := range selected {
+		r.Install = expandRecipeVars(r.Install, vars)
+
+		file := path.Join(offlineBundleRecipesDir, fmt.Sprintf("%03d-%s.yml", n, r.Name))
+		if err = writeOfflineBundleRecipe(filepath.Join(staging, filepath.FromSlash(file)), r); err != nil {
+			return nil, err
+		}
+		manifest.Recipes = append(manifest.Recipes, file)
+
+		urls, unresolved := findArtifactURLs(r.Install)
+		for _, u := range unresolved {
+			manifest.Skipped = append(manifest.Skipped, OfflineBundleSkipped{Recipe: r.Name, URL: u, Reason: "the URL depends on values only known at install time"})
+		}
+
+		for _, u := range urls {
+			if downloaded[u] {
+				continue
+			}
+			downloaded[u] = true
+
+			artifact, err := b.addArtifact(ctx, staging, u)
+			if err != nil {
+				log.Debugf("could not bundle %s for recipe %s: %s", u, r.Name, err)
+				manifest.Skipped = append(manifest.Skipped, OfflineBundleSkipped{Recipe: r.Name, URL: u, Reason: err.Error()})
+				continue
+			}
+			manifest.Artifacts = append(manifest.Artifacts, *artifact)
+		}
+	}
+
+	if err = writeOfflineBundleArchive(opts.Output, staging, manifest); err != nil {
+		return nil, err
+	}
+
+	return manifest, nil
+}
+
+// selectOfflineBundleRecipes returns every recipe supporting the platform with
+// one of the requested names or a name they depend on.  Several recipes can
+// share a name, one per platform, and are all packaged so that the installer
+// can pick the one matching the host.
+func selectOfflineBundleRecipes(all []*types.OpenInstallationRecipe, opts OfflineBundleOptions, goos string, arch string) ([]*types.OpenInstallationRecipe, error) {
+	byName := map[string][]*types.OpenInstallationRecipe{}
+	for _, r := range all {
+		if offlineBundleSupportsPlatform(r, goos, arch) {
+			byName[r.Name] = append(byName[r.Name], r)
+		}
+	}
+
+	names := append([]string{}, opts.RecipeNames...)
+	if opts.IncludeCore {
+		for _, name := range []string{types.InfraAgentRecipeName, types.LoggingRecipeName} {
+			if len(byName[name]) > 0 {
+				names = append(names, name)
+			}
+		}
+	}
+
+	selected := []*types.OpenInstallationRecipe{}
+	seen := map[string]bool{}
+	for len(names) > 0 {
+		name := names[0]
+		names = names[1:]
+		if seen[name] {
+			continue
+		}
+		seen[name] = true
+
+		matching := byName[name]
+		if len(matching) == 0 {
+			return nil, fmt.Errorf("recipe %s is not available for %s/%s", name, goos, arch)
+		}
+
+		for _, r := range matching {
+			selected = append(selected, r)
+			names = append(names, r.Dependencies...)
+		}
+	}
+
+	return selected, nil
+}
+
+func offlineBundleSupportsPlatform(r *types.OpenInstallationRecipe, goos string, arch string) bool {
+	if len(r.InstallTargets) == 0 {
+		return true
+	}
+
+	hostMap := map[string]string{oS: goos, kernelArch: arch}
+	for _, t := range r.InstallTargets {
+		if t.Os != "" && !matchRecipeCriteria(hostMap, oS, string(t.Os)) {
+			continue
+		}
+		if t.KernelArch != "" && !matchRecipeCriteria(hostMap, kernelArch, t.KernelArch) {
+			continue
+		}
+		return true
+	}
+
+	return false
+}
+
+// expandRecipeVars replaces {{.NAME}} references to the given variables, leaving
+// any other template in place.
+func expandRecipeVars(content string, vars types.RecipeVars) string {
+	return recipeVarRegex.ReplaceAllStringFunc(content, func(ref string) string {
+		name := recipeVarRegex.FindStringSubmatch(ref)[1]
+		if v, ok := vars[name]; ok {
+			return v
+		}
+		return ref
+	})
+}
+
+// findArtifactURLs returns the URLs in a recipe's install tasks, separating the
+// ones still depending on template or shell variables.
+func findArtifactURLs(content string) ([]string, []string) {
+	urls := []string{}
+	unresolved := []string{}
+	seen := map[string]bool{}
+
+	for _, u := range artifactURLRegex.FindAllString(content, -1) {
+		// Drop punctuation ending a sentence or a YAML key.
+		u = strings.TrimRight(u, ".:")
+		if seen[u] {
+			continue
+		}
+		seen[u] = true
+
+		if strings.Contains(u, "{{") || strings.Contains(u, "$") {
+			unresolved = append(unresolved, u)
+			continue
+		}
+		urls = append(urls, u)
+	}
+
+	return urls, unresolved
+}
+
+// addArtifact downloads u into the staging directory, under a path made of its
+// host and URL path.
+func (b *OfflineBundler) addArtifact(ctx context.Context, staging string, u string) (*OfflineBundleArtifact, error) {
+	parsed, err := url.Parse(u)
+	if err != nil {
+		return nil, err
+	}
+
+	if parsed.Path == "" || strings.HasSuffix(parsed.Path, "/") {
+		return nil, fmt.Errorf("the URL is not a file")
+	}
+
+	file := path.Join(offlineBundleArtifactsDir, parsed.Host, path.Clean("/"+parsed.Path))
+	dest := filepath.Join(staging, filepath.FromSlash(file))
+	if err = os.MkdirAll(filepath.Dir(dest), 0750); err != nil {
+		return nil, err
+	}
+
+	f, err := os.OpenFile(dest, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	counter := &countingWriter{}
+	if err = b.download(ctx, u, io.MultiWriter(f, h, counter)); err != nil {
+		os.Remove(dest)
+		return nil, err
+	}
+
+	return &OfflineBundleArtifact{
+		URL:    u,
+		File:   file,
+		SHA256: hex.EncodeToString(h.Sum(nil)),
+		Size:   counter.n,
+	}, nil
+}
+
+type countingWriter struct {
+	n int64
+}
+
+func (w *countingWriter) Write(p []byte) (int, error) {
+	w.n += int64(len(p))
+	return len(p), nil
+}
+
+func downloadArtifact(ctx context.Context, u string, w io.Writer) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("download failed with status %s", resp.Status)
+	}
+
+	_, err = io.Copy(w, resp.Body)
+	return err
+}
+
+func writeOfflineBundleRecipe(p string, r *types.OpenInstallationRecipe) error {
+	data, err := yaml.Marshal(r)
+	if err != nil {
+		return fmt.Errorf("could not write recipe %s: %s", r.Name, err)
+	}
+
+	if err = os.MkdirAll(filepath.Dir(p), 0750); err != nil {
+		return err
+	}
+
+	return os.WriteFile(p, data, 0600)
+}
+
+func writeOfflineBundleArchive(p string, staging string, manifest *OfflineBundleManifest) (err error) {
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	f, err := os.OpenFile(p, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if cerr := f.Close(); err == nil {
+			err = cerr
+		}
+	}()
+
+	gw := gzip.NewWriter(f)
+	tw := tar.NewWriter(gw)
+
+	// Write the manifest first so that readers can stream the archive.
+	hdr := &tar.Header{
+		Name:    OfflineBundleManifestFile,
+		Mode:    0600,
+		Size:    int64(len(data)),
+		ModTime: manifest.CreatedAt,
+	}
+	if err = tw.WriteHeader(hdr); err != nil {
+		return err
+	}
+	if _, err = tw.Write(data); err != nil {
+		return err
+	}
+
+	names := append([]string{}, manifest.Recipes...)
+	for _, a := range manifest.Artifacts {
+		names = append(names, a.File)
+	}
+
+	for _, name := range names {
+		if err = addOfflineBundleFile(tw, filepath.Join(staging, filepath.FromSlash(name)), name, manifest.CreatedAt); err != nil {
+			return err
+		}
+	}
+
+	if err = tw.Close(); err != nil {
+		return err
+	}
+
+	return gw.Close()
+}
+
+func addOfflineBundleFile(tw *tar.Writer, src string, name string, modTime time.Time) error {
+	f, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return err
+	}
+
+	hdr := &tar.Header{
+		Name:    name,
+		Mode:    0600,
+		Size:    info.Size(),
+		ModTime: modTime,
+	}
+	if err = tw.WriteHeader(hdr); err != nil {
+		return err
+	}
+
+	_, err = io.Copy(tw, f)
+	return err
+}
+
+// ExtractOfflineBundle extracts the offline bundle archive at p into dir and
+// returns its manifest, after checking the checksum of every artifact.
+func ExtractOfflineBundle(p string, dir string) (*OfflineBundleManifest, error) {
+	f, err := os.Open(p)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	gr, err := gzip.NewReader(f)
+	if err != nil {
+		return nil, fmt.Errorf("%s is not an offline bundle: %s", p, err)
+	}
+	defer gr.Close()
+
+	tr := tar.NewReader(gr)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("could not read offline bundle %s: %s", p, err)
+		}
+
+		if hdr.Typeflag != tar.TypeReg {
+			continue
+		}
+
+		name := path.Clean(hdr.Name)
+		if path.IsAbs(name) || name == ".." || strings.HasPrefix(name, "../") {
+			return nil, fmt.Errorf("offline bundle %s contains an invalid path %s", p, hdr.Name)
+		}
+
+		if err = extractOfflineBundleFile(tr, filepath.Join(dir, filepath.FromSlash(name))); err != nil {
+			return nil, err
+		}
+	}
+
+	manifest, err := ReadOfflineBundleManifest(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, a := range manifest.Artifacts {
+		sum, err := fileSHA256(filepath.Join(dir, filepath.FromSlash(a.File)))
+		if err != nil {
+			return nil, fmt.Errorf("offline bundle %s is missing the artifact for %s: %s", p, a.URL, err)
+		}
+		if sum != a.SHA256 {
+			return nil, fmt.Errorf("offline bundle %s has an invalid checksum for %s", p, a.URL)
+		}
+	}
+
+	return manifest, nil
+}
+
+func extractOfflineBundleFile(r io.Reader, dest string) error {
+	if err := os.MkdirAll(filepath.Dir(dest), 0750); err != nil {
+		return err
+	}
+
+	f, err := os.OpenFile(dest, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	_, err = io.Copy(f, r)
+	return err
+}
+
+// ReadOfflineBundleManifest reads the manifest of an offline bundle extracted into dir.
+func ReadOfflineBundleManifest(dir string) (*OfflineBundleManifest, error) {
+	data, err := os.ReadFile(filepath.Join(dir, OfflineBundleManifestFile))
+	if err != nil {
+		return nil, fmt.Errorf("not an offline bundle, %s not found: %s", OfflineBundleManifestFile, err)
+	}
+
+	var manifest OfflineBundleManifest
+	if err = json.Unmarshal(data, &manifest); err != nil {
+		return nil, fmt.Errorf("could not parse offline bundle manifest: %s", err)
+	}
+
+	return &manifest, nil
+}
+
+func fileSHA256(p string) (string, error) {
+	f, err := os.Open(p)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	if _, err = io.Copy(h, f); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
@@ -0,0 +1,82 @@
+package recipes
+
+import (
+	"context"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/newrelic/newrelic-cli/internal/install/types"
+)
+
+// OfflineBundleRecipeFetcher loads the recipes of an offline bundle extracted
+// by ExtractOfflineBundle, downloading their artifacts from the bundle rather
+// than the network.
+type OfflineBundleRecipeFetcher struct {
+	LocalRecipeFetcher
+	// Dir is the directory the offline bundle was extracted into.
+	Dir string
+	// Signature is the result of verifying the signature of the bundle,
+	// reported for each of its recipes.
+	Signature *types.RecipeSignature
+}
+
+// NewOfflineBundleRecipeFetcher returns a RecipeFetcher for the offline bundle extracted into dir.
+func NewOfflineBundleRecipeFetcher(dir string) *OfflineBundleRecipeFetcher {
+	return &OfflineBundleRecipeFetcher{
+		LocalRecipeFetcher: LocalRecipeFetcher{
+			Path: filepath.Join(dir, offlineBundleRecipesDir),
+		},
+		Dir: dir,
+	}
+}
+
+func (f *OfflineBundleRecipeFetcher) FetchRecipes(ctx context.Context) ([]*types.OpenInstallationRecipe, error) {
+	manifest, err := ReadOfflineBundleManifest(f.Dir)
+	if err != nil {
+		return nil, err
+	}
+
+	recipes, err := f.LocalRecipeFetcher.FetchRecipes(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// Replace the longest URLs first, so that a URL prefixing another one
+	// does not break the replacement of the longer one.
+	artifacts := append([]OfflineBundleArtifact{}, manifest.Artifacts...)
+	sort.SliceStable(artifacts, func(i, j int) bool {
+		return len(artifacts[i].URL) > len(artifacts[j].URL)
+	})
+
+	for _, r := range recipes {
+		r.SetSignature(f.Signature)
+		for _, a := range artifacts {
+			r.Install = strings.ReplaceAll(r.Install, a.URL, fileURL(filepath.Join(f.Dir, filepath.FromSlash(a.File))))
+		}
+	}
+
+	return recipes, nil
+}
+
+func (f *OfflineBundleRecipeFetcher) FetchLibraryVersion(ctx context.Context) string {
+	manifest, err := ReadOfflineBundleManifest(f.Dir)
+	if err != nil {
+		log.Debugf("Unable to read library version, detail: %s", err)
+		return ""
+	}
+
+	return manifest.LibraryVersion
+}
+
+// fileURL returns the file:// URL of an absolute path, on POSIX and Windows.
+func fileURL(p string) string {
+	p = filepath.ToSlash(p)
+	if !strings.HasPrefix(p, "/") {
+		p = "/" + p
+	}
+
+	return "file://" + p
+}
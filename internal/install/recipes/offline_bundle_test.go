@@ -0,0 +1,200 @@
+//go:build unit
+
+package recipes
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/newrelic/newrelic-cli/internal/install/types"
+)
+
+const offlineBundleTestInstall = `version: "3"
+tasks:
+  default:
+    cmds:
+      - curl -o /tmp/testd.tar.gz {{.NEW_RELIC_DOWNLOAD_URL}}testd/testd-{{ .KERNEL_ARCH }}.tar.gz
+      - curl -o /tmp/testd.conf https://example.com/testd.conf.
+      - curl -o /tmp/missing https://example.com/missing
+      - curl -o /tmp/versioned https://example.com/testd-{{.TESTD_VERSION}}.tar.gz
+      - echo "deb {{.NEW_RELIC_DOWNLOAD_URL}}testd/apt/ stable main"
+`
+
+func TestParseOfflineBundlePlatform(t *testing.T) {
+	goos, goarch, err := ParseOfflineBundlePlatform("Linux/AMD64")
+	require.NoError(t, err)
+	assert.Equal(t, "linux", goos)
+	assert.Equal(t, "amd64", goarch)
+
+	for _, p := range []string{"linux", "linux/", "plan9/amd64", "linux/mips"} {
+		_, _, err = ParseOfflineBundlePlatform(p)
+		assert.Error(t, err, p)
+	}
+}
+
+func TestOfflineBundleShouldPackageRecipesAndArtifacts(t *testing.T) {
+	testd := NewRecipeBuilder().Name("testd").TargetOsArch(types.OpenInstallationOperatingSystemTypes.LINUX, "x86_64").DependencyName("dependency").Build()
+	testd.Install = offlineBundleTestInstall
+	testdArm := NewRecipeBuilder().Name("testd").TargetOsArch(types.OpenInstallationOperatingSystemTypes.LINUX, "aarch64").Build()
+	testdWindows := NewRecipeBuilder().Name("testd").TargetOs(types.OpenInstallationOperatingSystemTypes.WINDOWS).Build()
+	dependency := NewRecipeBuilder().Name("dependency").Build()
+	infra := NewRecipeBuilder().Name(types.InfraAgentRecipeName).TargetOs(types.OpenInstallationOperatingSystemTypes.LINUX).Build()
+
+	fetcher := NewMockRecipeFetcher()
+	fetcher.FetchRecipesVal = []*types.OpenInstallationRecipe{testd, testdArm, testdWindows, dependency, infra}
+	fetcher.LibraryVersion = "1.2.3"
+
+	downloaded := []string{}
+	bundler := givenOfflineBundler(fetcher, func(u string) (string, error) {
+		downloaded = append(downloaded, u)
+		if strings.HasSuffix(u, "missing") {
+			return "", errors.New("download failed with status 404 Not Found")
+		}
+		return "content of " + u, nil
+	})
+
+	output := filepath.Join(t.TempDir(), "bundle.tar.gz")
+	manifest, err := bundler.Create(context.Background(), OfflineBundleOptions{
+		RecipeNames: []string{"testd"},
+		Platform:    "linux/amd64",
+		IncludeCore: true,
+		Output:      output,
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, "linux/amd64", manifest.Platform)
+	assert.Equal(t, "1.2.3", manifest.LibraryVersion)
+	assert.Equal(t, []string{"recipes/000-testd.yml", "recipes/001-infrastructure-agent-installer.yml", "recipes/002-dependency.yml"}, manifest.Recipes)
+	assert.ElementsMatch(t, []string{
+		"https://download.newrelic.com/testd/testd-x86_64.tar.gz",
+		"https://example.com/testd.conf",
+		"https://example.com/missing",
+	}, downloaded)
+
+	require.Len(t, manifest.Artifacts, 2)
+	assert.Equal(t, "https://download.newrelic.com/testd/testd-x86_64.tar.gz", manifest.Artifacts[0].URL)
+	assert.Equal(t, "artifacts/download.newrelic.com/testd/testd-x86_64.tar.gz", manifest.Artifacts[0].File)
+	assert.Equal(t, int64(len("content of https://download.newrelic.com/testd/testd-x86_64.tar.gz")), manifest.Artifacts[0].Size)
+
+	skipped := []string{}
+	for _, s := range manifest.Skipped {
+		skipped = append(skipped, s.URL)
+	}
+	assert.ElementsMatch(t, []string{
+		"https://example.com/testd-{{.TESTD_VERSION}}.tar.gz",
+		"https://example.com/missing",
+		"https://download.newrelic.com/testd/apt/",
+	}, skipped)
+
+	dir := t.TempDir()
+	extracted, err := ExtractOfflineBundle(output, dir)
+	require.NoError(t, err)
+	assert.Equal(t, manifest.Artifacts, extracted.Artifacts)
+
+	f := NewOfflineBundleRecipeFetcher(dir)
+	assert.Equal(t, "1.2.3", f.FetchLibraryVersion(context.Background()))
+
+	recipes, err := f.FetchRecipes(context.Background())
+	require.NoError(t, err)
+	require.Len(t, recipes, 3)
+
+	var bundled *types.OpenInstallationRecipe
+	for _, r := range recipes {
+		if r.Name == "testd" {
+			bundled = r
+		}
+	}
+	require.NotNil(t, bundled)
+	assert.Equal(t, []string{"dependency"}, bundled.Dependencies)
+	assert.Contains(t, bundled.Install, fileURL(filepath.Join(dir, "artifacts", "download.newrelic.com", "testd", "testd-x86_64.tar.gz")))
+	assert.Contains(t, bundled.Install, fileURL(filepath.Join(dir, "artifacts", "example.com", "testd.conf")))
+	assert.Contains(t, bundled.Install, "https://example.com/missing")
+	assert.Contains(t, bundled.Install, "{{.TESTD_VERSION}}")
+}
+
+func TestOfflineBundleShouldRequireAvailableRecipes(t *testing.T) {
+	fetcher := NewMockRecipeFetcher()
+	fetcher.FetchRecipesVal = []*types.OpenInstallationRecipe{
+		NewRecipeBuilder().Name("testd").TargetOs(types.OpenInstallationOperatingSystemTypes.WINDOWS).Build(),
+	}
+	bundler := givenOfflineBundler(fetcher, nil)
+
+	_, err := bundler.Create(context.Background(), OfflineBundleOptions{
+		RecipeNames: []string{"testd"},
+		Platform:    "linux/amd64",
+		Output:      filepath.Join(t.TempDir(), "bundle.tar.gz"),
+	})
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "recipe testd is not available for linux/x86_64")
+}
+
+func TestExtractOfflineBundleShouldCheckArtifacts(t *testing.T) {
+	testd := NewRecipeBuilder().Name("testd").Build()
+	testd.Install = offlineBundleTestInstall
+	fetcher := NewMockRecipeFetcher()
+	fetcher.FetchRecipesVal = []*types.OpenInstallationRecipe{testd}
+	bundler := givenOfflineBundler(fetcher, func(u string) (string, error) {
+		return "content", nil
+	})
+
+	output := filepath.Join(t.TempDir(), "bundle.tar.gz")
+	manifest, err := bundler.Create(context.Background(), OfflineBundleOptions{
+		RecipeNames: []string{"testd"},
+		Platform:    "linux/amd64",
+		Output:      output,
+	})
+	require.NoError(t, err)
+
+	// Tamper with an artifact after the checksums were recorded.
+	dir := t.TempDir()
+	_, err = ExtractOfflineBundle(output, dir)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, filepath.FromSlash(manifest.Artifacts[0].File)), []byte("tampered"), 0600))
+	manifest.Recipes = nil
+	require.NoError(t, writeOfflineBundleArchive(output, dir, manifest))
+
+	_, err = ExtractOfflineBundle(output, t.TempDir())
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid checksum")
+}
+
+func TestExtractOfflineBundleShouldRejectOtherArchives(t *testing.T) {
+	p := filepath.Join(t.TempDir(), "bundle.tar.gz")
+	require.NoError(t, os.WriteFile(p, []byte("not a bundle"), 0600))
+
+	_, err := ExtractOfflineBundle(p, t.TempDir())
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "is not an offline bundle")
+}
+
+func givenOfflineBundler(fetcher RecipeFetcher, content func(u string) (string, error)) *OfflineBundler {
+	return &OfflineBundler{
+		fetcher:     fetcher,
+		downloadURL: "https://download.newrelic.com/",
+		download: func(ctx context.Context, u string, w io.Writer) error {
+			if content == nil {
+				return fmt.Errorf("unexpected download of %s", u)
+			}
+
+			c, err := content(u)
+			if err != nil {
+				return err
+			}
+
+			_, err = io.WriteString(w, c)
+			return err
+		},
+	}
+}
@@ -85,7 +85,7 @@ func (pe *ProcessEvaluator) DetectionStatus(ctx context.Context, r *types.OpenIn
 	}
 
 	processes := pe.GetOrLoadProcesses(ctx)
-	matches := pe.processMatchFinder.FindMatches(ctx, processes, *r)
+	matches := hostProcessMatches(pe.processMatchFinder.FindMatches(ctx, processes, *r))
 	if len(matches) == 0 {
 		if slices.Contains(recipeNames, r.Name) {
 			log.Errorf("Unsupported (%s): Unable to match any of the following processes:\n", r.DisplayName)
@@ -100,6 +100,21 @@ func (pe *ProcessEvaluator) DetectionStatus(ctx context.Context, r *types.OpenIn
 	return execution.RecipeStatusTypes.AVAILABLE
 }
 
+// hostProcessMatches drops the matches of processes running in other
+// containers, which recipes installing on this host should not detect.
+func hostProcessMatches(matches []types.MatchedProcess) []types.MatchedProcess {
+	host := []types.MatchedProcess{}
+	for _, m := range matches {
+		if m.Container.IsContainerized() {
+			log.Tracef("skipping process %d matching %s, it runs in %s container %s", m.PID(), m.MatchingPattern, m.Container.Runtime, m.Container.ID)
+			continue
+		}
+		host = append(host, m)
+	}
+
+	return host
+}
+
 func (pe *ProcessEvaluator) FindProcess(process string) bool {
 	for _, p := range pe.cachedProcess {
 		name, _ := p.Name()
@@ -38,6 +38,20 @@ func TestProcessEvaluatorShouldNotDetect_NoMatch(t *testing.T) {
 	require.Equal(t, execution.RecipeStatusTypes.NULL, status)
 }
 
+func TestProcessEvaluatorShouldNotDetect_MatchInOtherContainer(t *testing.T) {
+	recipe := NewRecipeBuilder().ProcessMatch("abc").Build()
+	finder := NewMockProcessMatchFinder()
+	finder.matchedProcesses = append(finder.matchedProcesses, types.MatchedProcess{
+		GenericProcess: NewMockProcess("abc", "abc", 1),
+		Container:      types.Container{Runtime: types.OpenInstallationContainerRuntimeTypes.DOCKER},
+	})
+	processEvaluator := newProcessEvaluator(finder, AnyProcesses, false)
+
+	status := processEvaluator.DetectionStatus(context.Background(), recipe, []string{})
+
+	require.Equal(t, execution.RecipeStatusTypes.NULL, status)
+}
+
 func AnyProcesses(ctx context.Context) []types.GenericProcess {
 	return []types.GenericProcess{}
 }
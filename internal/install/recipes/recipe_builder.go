@@ -8,16 +8,17 @@ import (
 )
 
 type RecipeBuilder struct {
-	id                  string
-	name                string
-	requireAtDiscovery  string
-	DiscoveryMode       []types.OpenInstallationDiscoveryMode
-	goTaskInstallScript string
-	processMatches      []string
-	targets             []types.OpenInstallationRecipeInstallTarget
-	vars                map[string]string
-	dependencies        []*BundleRecipe
-	dependencyNames     []string
+	id                    string
+	name                  string
+	requireAtDiscovery    string
+	DiscoveryMode         []types.OpenInstallationDiscoveryMode
+	goTaskInstallScript   string
+	goTaskUninstallScript string
+	processMatches        []string
+	targets               []types.OpenInstallationRecipeInstallTarget
+	vars                  map[string]string
+	dependencies          []*BundleRecipe
+	dependencyNames       []string
 }
 
 func NewRecipeBuilder() *RecipeBuilder {
@@ -88,6 +89,16 @@ func (b *RecipeBuilder) TargetOsArch(os types.OpenInstallationOperatingSystem, a
 	return b.TargetOsPlatformVersionArch(os, "", arch)
 }
 
+func (b *RecipeBuilder) TargetOsContainer(os types.OpenInstallationOperatingSystem, runtime types.OpenInstallationContainerRuntime, orchestrator types.OpenInstallationOrchestrator) *RecipeBuilder {
+	t := types.OpenInstallationRecipeInstallTarget{
+		Os:               os,
+		ContainerRuntime: runtime,
+		Orchestrator:     orchestrator,
+	}
+	b.targets = append(b.targets, t)
+	return b
+}
+
 func (b *RecipeBuilder) Vars(key string, value string) *RecipeBuilder {
 	b.vars[key] = value
 	return b
@@ -98,6 +109,11 @@ func (b *RecipeBuilder) InstallGoTaskScript(script string) *RecipeBuilder {
 	return b
 }
 
+func (b *RecipeBuilder) UninstallGoTaskScript(script string) *RecipeBuilder {
+	b.goTaskUninstallScript = script
+	return b
+}
+
 func (b *RecipeBuilder) InstallShell(script string) *RecipeBuilder {
 	goTaskWrap := fmt.Sprintf(`
 version: '3'
@@ -128,7 +144,8 @@ func (b *RecipeBuilder) Build() *types.OpenInstallationRecipe {
 			RequireAtDiscovery: b.requireAtDiscovery,
 			DiscoveryMode:      b.DiscoveryMode,
 		},
-		Install: b.goTaskInstallScript,
+		Install:   b.goTaskInstallScript,
+		Uninstall: b.goTaskUninstallScript,
 	}
 	for key, value := range b.vars {
 		r.SetRecipeVar(key, value)
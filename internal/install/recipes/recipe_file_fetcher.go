@@ -19,6 +19,8 @@ type RecipeFileFetcher struct {
 	HTTPGetFunc  func(string) (*http.Response, error)
 	readFileFunc func(string) ([]byte, error)
 	Paths        []string
+	// Verifier checks the signature of each recipe file, when set.
+	Verifier *RecipeSignatureVerifier
 }
 
 func NewRecipeFileFetcher(paths []string) *RecipeFileFetcher {
@@ -46,12 +48,18 @@ func (rff *RecipeFileFetcher) FetchRecipes(ctx context.Context) ([]*types.OpenIn
 			log.Debugf("Loading recipe from URL:%s", recipeURL)
 			recipe, err = rff.FetchRecipeFile(recipeURL)
 			if err != nil {
+				if _, ok := err.(*types.DetailError); ok {
+					return recipesFromPath, err
+				}
 				return recipesFromPath, fmt.Errorf("could not fetch file %s: %s", recipePath, err)
 			}
 		} else {
 			log.Debugf("Loading recipe from path:%s", recipePath)
 			recipe, err = rff.LoadRecipeFile(recipePath)
 			if err != nil {
+				if _, ok := err.(*types.DetailError); ok {
+					return recipesFromPath, err
+				}
 				return recipesFromPath, fmt.Errorf("could not load file %s: %s", recipePath, err)
 			}
 		}
@@ -97,7 +105,21 @@ func (rff *RecipeFileFetcher) FetchRecipeFile(recipeURL *url.URL) (*types.OpenIn
 		return nil, err
 	}
 
-	return NewRecipeFile(string(body))
+	var signature *types.RecipeSignature
+	if rff.Verifier != nil {
+		signature, err = rff.Verifier.VerifyURLContent(rff.HTTPGetFunc, recipeURL.String(), body)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	recipe, err := NewRecipeFile(string(body))
+	if err != nil {
+		return nil, err
+	}
+	recipe.SetSignature(signature)
+
+	return recipe, nil
 }
 
 func (rff *RecipeFileFetcher) LoadRecipeFile(filename string) (*types.OpenInstallationRecipe, error) {
@@ -106,5 +128,19 @@ func (rff *RecipeFileFetcher) LoadRecipeFile(filename string) (*types.OpenInstal
 		return nil, err
 	}
 
-	return NewRecipeFile(string(out))
+	var signature *types.RecipeSignature
+	if rff.Verifier != nil {
+		signature, err = rff.Verifier.VerifyFileContent(filename, out)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	recipe, err := NewRecipeFile(string(out))
+	if err != nil {
+		return nil, err
+	}
+	recipe.SetSignature(signature)
+
+	return recipe, nil
 }
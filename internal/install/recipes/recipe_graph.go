@@ -0,0 +1,170 @@
+package recipes
+
+import (
+	"fmt"
+	"strings"
+)
+
+// RecipeGraph is the dependency graph of a set of recipes, keyed by recipe
+// name.  A dependency in the form 'recipe-a || recipe-b' is satisfied by the
+// first of those recipes found in the graph.
+type RecipeGraph struct {
+	names        []string
+	dependencies map[string][]string
+	missing      map[string][]string
+}
+
+// NewRecipeGraph builds the dependency graph of the available recipes.
+func NewRecipeGraph(availableRecipes RecipeDetectionResults) *RecipeGraph {
+	g := &RecipeGraph{
+		dependencies: map[string][]string{},
+		missing:      map[string][]string{},
+	}
+
+	for _, d := range availableRecipes {
+		if _, ok := g.dependencies[d.Recipe.Name]; ok {
+			continue
+		}
+		g.names = append(g.names, d.Recipe.Name)
+		g.dependencies[d.Recipe.Name] = []string{}
+	}
+
+	resolved := map[string]bool{}
+	for _, d := range availableRecipes {
+		name := d.Recipe.Name
+		if resolved[name] {
+			continue
+		}
+		resolved[name] = true
+
+		for _, dep := range d.Recipe.Dependencies {
+			if r, ok := g.resolve(dep); ok {
+				g.dependencies[name] = append(g.dependencies[name], r)
+			} else {
+				g.missing[name] = append(g.missing[name], dep)
+			}
+		}
+	}
+
+	return g
+}
+
+func (g *RecipeGraph) resolve(dep string) (string, bool) {
+	for _, alt := range strings.Split(dep, "||") {
+		alt = strings.TrimSpace(alt)
+		if _, ok := g.dependencies[alt]; ok {
+			return alt, true
+		}
+	}
+
+	return "", false
+}
+
+// Has returns whether the recipe is in the graph.
+func (g *RecipeGraph) Has(name string) bool {
+	_, ok := g.dependencies[name]
+	return ok
+}
+
+// Dependencies returns the names of the recipes the recipe directly depends on.
+func (g *RecipeGraph) Dependencies(name string) []string {
+	return g.dependencies[name]
+}
+
+// MissingDependencies returns the dependencies of the recipe that are not in
+// the graph.
+func (g *RecipeGraph) MissingDependencies(name string) []string {
+	return g.missing[name]
+}
+
+// Validate returns an error when the graph has a dependency cycle, or when one
+// of the recipes in names, or a recipe they depend on, has a dependency that
+// is not in the graph.  Recipes in names that are not in the graph are
+// ignored, they are reported as unsupported.
+func (g *RecipeGraph) Validate(names []string) error {
+	if _, err := g.TopologicalOrder(); err != nil {
+		return err
+	}
+
+	visited := map[string]bool{}
+	var check func(name string) error
+	check = func(name string) error {
+		if visited[name] {
+			return nil
+		}
+		visited[name] = true
+
+		if missing := g.missing[name]; len(missing) > 0 {
+			return fmt.Errorf("recipe %s depends on %s, which is not available for this host", name, strings.Join(missing, ", "))
+		}
+
+		for _, dep := range g.dependencies[name] {
+			if err := check(dep); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+
+	for _, name := range names {
+		if !g.Has(name) {
+			continue
+		}
+		if err := check(name); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// TopologicalOrder returns the recipes of the graph with every recipe after
+// the recipes it depends on, or an error naming the recipes of a dependency
+// cycle.
+func (g *RecipeGraph) TopologicalOrder() ([]string, error) {
+	const (
+		visiting = iota + 1
+		done
+	)
+
+	state := map[string]int{}
+	order := []string{}
+	path := []string{}
+
+	var visit func(name string) error
+	visit = func(name string) error {
+		switch state[name] {
+		case done:
+			return nil
+		case visiting:
+			cycle := []string{name}
+			for i := len(path) - 1; i >= 0 && path[i] != name; i-- {
+				cycle = append([]string{path[i]}, cycle...)
+			}
+			cycle = append([]string{name}, cycle...)
+			return fmt.Errorf("recipe dependency cycle: %s", strings.Join(cycle, " -> "))
+		}
+
+		state[name] = visiting
+		path = append(path, name)
+		for _, dep := range g.dependencies[name] {
+			if err := visit(dep); err != nil {
+				return err
+			}
+		}
+		path = path[:len(path)-1]
+		state[name] = done
+		order = append(order, name)
+
+		return nil
+	}
+
+	for _, name := range g.names {
+		if err := visit(name); err != nil {
+			return nil, err
+		}
+	}
+
+	return order, nil
+}
@@ -0,0 +1,74 @@
+//go:build unit
+
+package recipes
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/newrelic/newrelic-cli/internal/install/execution"
+	"github.com/newrelic/newrelic-cli/internal/install/types"
+)
+
+func TestRecipeGraphShouldOrderRecipesAfterDependencies(t *testing.T) {
+	g := NewRecipeGraph(givenRecipeGraphResults(
+		NewRecipeBuilder().Name("mysql").DependencyName(types.InfraAgentRecipeName).Build(),
+		NewRecipeBuilder().Name(types.LoggingRecipeName).DependencyName(types.InfraAgentRecipeName).Build(),
+		NewRecipeBuilder().Name(types.InfraAgentRecipeName).Build(),
+		NewRecipeBuilder().Name("redis").Build(),
+	))
+
+	order, err := g.TopologicalOrder()
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{types.InfraAgentRecipeName, "mysql", types.LoggingRecipeName, "redis"}, order)
+	assert.NoError(t, g.Validate([]string{"mysql", "redis", "unknown"}))
+}
+
+func TestRecipeGraphShouldResolveDualDependencies(t *testing.T) {
+	g := NewRecipeGraph(givenRecipeGraphResults(
+		NewRecipeBuilder().Name("mysql").DependencyName(types.InfraAgentRecipeName+" || "+types.AgentControlRecipeName).Build(),
+		NewRecipeBuilder().Name(types.AgentControlRecipeName).Build(),
+	))
+
+	assert.Equal(t, []string{types.AgentControlRecipeName}, g.Dependencies("mysql"))
+	assert.Empty(t, g.MissingDependencies("mysql"))
+}
+
+func TestRecipeGraphShouldDetectCycles(t *testing.T) {
+	g := NewRecipeGraph(givenRecipeGraphResults(
+		NewRecipeBuilder().Name("a").DependencyName("b").Build(),
+		NewRecipeBuilder().Name("b").DependencyName("c").Build(),
+		NewRecipeBuilder().Name("c").DependencyName("a").Build(),
+	))
+
+	_, err := g.TopologicalOrder()
+	require.Error(t, err)
+	assert.Equal(t, "recipe dependency cycle: a -> b -> c -> a", err.Error())
+	assert.Error(t, g.Validate(nil))
+}
+
+func TestRecipeGraphShouldDetectMissingDependencies(t *testing.T) {
+	g := NewRecipeGraph(givenRecipeGraphResults(
+		NewRecipeBuilder().Name("mysql").DependencyName("mysql-agent").Build(),
+		NewRecipeBuilder().Name("mysql-agent").DependencyName("mysql-client").Build(),
+		NewRecipeBuilder().Name("redis").DependencyName("redis-agent").Build(),
+	))
+
+	assert.Equal(t, []string{"redis-agent"}, g.MissingDependencies("redis"))
+	assert.NoError(t, g.Validate(nil))
+
+	err := g.Validate([]string{"mysql"})
+	require.Error(t, err)
+	assert.Equal(t, "recipe mysql-agent depends on mysql-client, which is not available for this host", err.Error())
+}
+
+func givenRecipeGraphResults(recipes ...*types.OpenInstallationRecipe) RecipeDetectionResults {
+	results := RecipeDetectionResults{}
+	for _, r := range recipes {
+		results = append(results, &RecipeDetectionResult{Recipe: r, Status: execution.RecipeStatusTypes.AVAILABLE})
+	}
+	return results
+}
@@ -0,0 +1,502 @@
+package recipes
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/go-task/task/v3/taskfile"
+	"gopkg.in/yaml.v2"
+
+	"github.com/newrelic/newrelic-cli/internal/install/types"
+)
+
+// LintSeverity is the severity of a problem found in a recipe.
+type LintSeverity string
+
+var LintSeverities = struct {
+	ERROR   LintSeverity
+	WARNING LintSeverity
+}{
+	ERROR:   "error",
+	WARNING: "warning",
+}
+
+// LintProblem is a problem found in a recipe file.
+type LintProblem struct {
+	Severity LintSeverity `json:"severity"`
+	Field    string       `json:"field,omitempty"`
+	Message  string       `json:"message"`
+}
+
+func (p LintProblem) String() string {
+	if p.Field == "" {
+		return fmt.Sprintf("%s: %s", p.Severity, p.Message)
+	}
+
+	return fmt.Sprintf("%s: %s: %s", p.Severity, p.Field, p.Message)
+}
+
+// LintResult holds the problems found in a single recipe file.
+type LintResult struct {
+	Path     string        `json:"path"`
+	Recipe   string        `json:"recipe,omitempty"`
+	Problems []LintProblem `json:"problems"`
+}
+
+// HasErrors reports whether any of the problems is an error rather than a warning.
+func (r LintResult) HasErrors() bool {
+	for _, p := range r.Problems {
+		if p.Severity == LintSeverities.ERROR {
+			return true
+		}
+	}
+
+	return false
+}
+
+type recipeFieldKind int
+
+const (
+	scalarField recipeFieldKind = iota
+	listField
+	mapField
+)
+
+// recipeFields are the top level fields of a recipe file and the kind of
+// YAML value each one holds.
+var recipeFields = map[string]recipeFieldKind{
+	"id":                    scalarField,
+	"name":                  scalarField,
+	"displayName":           scalarField,
+	"description":           scalarField,
+	"file":                  scalarField,
+	"repository":            scalarField,
+	"stability":             scalarField,
+	"validationNrql":        scalarField,
+	"validationUrl":         scalarField,
+	"validationIntegration": scalarField,
+	"dependencies":          listField,
+	"installTargets":        listField,
+	"keywords":              listField,
+	"processMatch":          listField,
+	"logMatch":              listField,
+	"inputVars":             listField,
+	"install":               mapField,
+	"uninstall":             mapField,
+	"preInstall":            mapField,
+	"postInstall":           mapField,
+	"successLinkConfig":     mapField,
+}
+
+var (
+	installTargetTypes = []string{
+		string(types.OpenInstallationTargetTypeTypes.APPLICATION),
+		string(types.OpenInstallationTargetTypeTypes.CLOUD),
+		string(types.OpenInstallationTargetTypeTypes.DOCKER),
+		string(types.OpenInstallationTargetTypeTypes.HOST),
+		string(types.OpenInstallationTargetTypeTypes.KUBERNETES),
+		string(types.OpenInstallationTargetTypeTypes.SERVERLESS),
+	}
+	installTargetOperatingSystems = []string{
+		string(types.OpenInstallationOperatingSystemTypes.DARWIN),
+		string(types.OpenInstallationOperatingSystemTypes.LINUX),
+		string(types.OpenInstallationOperatingSystemTypes.WINDOWS),
+	}
+	installTargetPlatforms = []string{
+		string(types.OpenInstallationPlatformTypes.AMAZON),
+		string(types.OpenInstallationPlatformTypes.CENTOS),
+		string(types.OpenInstallationPlatformTypes.DEBIAN),
+		string(types.OpenInstallationPlatformTypes.FEDORA),
+		string(types.OpenInstallationPlatformTypes.ORACLE),
+		string(types.OpenInstallationPlatformTypes.REDHAT),
+		string(types.OpenInstallationPlatformTypes.SUSE),
+		string(types.OpenInstallationPlatformTypes.UBUNTU),
+		string(types.OpenInstallationPlatformTypes.ROCKY),
+		string(types.OpenInstallationPlatformTypes.ALMALINUX),
+	}
+	installTargetPlatformFamilies = []string{
+		string(types.OpenInstallationPlatformFamilyTypes.DEBIAN),
+		string(types.OpenInstallationPlatformFamilyTypes.RHEL),
+		string(types.OpenInstallationPlatformFamilyTypes.SUSE),
+	}
+	installTargetContainerRuntimes = []string{
+		string(types.OpenInstallationContainerRuntimeTypes.NONE),
+		string(types.OpenInstallationContainerRuntimeTypes.CONTAINERD),
+		string(types.OpenInstallationContainerRuntimeTypes.CRIO),
+		string(types.OpenInstallationContainerRuntimeTypes.DOCKER),
+		string(types.OpenInstallationContainerRuntimeTypes.PODMAN),
+	}
+	installTargetOrchestrators = []string{
+		string(types.OpenInstallationOrchestratorTypes.NONE),
+		string(types.OpenInstallationOrchestratorTypes.ECS),
+		string(types.OpenInstallationOrchestratorTypes.KUBERNETES),
+	}
+
+	inputVarNameRegex = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+)
+
+// LintRecipeFile checks the recipe file at path against the recipe schema.
+func LintRecipeFile(path string) LintResult {
+	result := LintResult{Path: path}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		result.Problems = []LintProblem{{Severity: LintSeverities.ERROR, Message: err.Error()}}
+		return result
+	}
+
+	r, problems := LintRecipe(content)
+	if r != nil {
+		result.Recipe = r.Name
+	}
+	result.Problems = problems
+
+	return result
+}
+
+// LintRecipe checks the content of a recipe file against the recipe schema:
+// the kind of each field, install targets, processMatch regular expressions,
+// logMatch file globs, input variables, and the go-task definitions of the
+// install and uninstall sections.  The recipe is returned when it could be
+// read, even if problems were found.
+func LintRecipe(content []byte) (*types.OpenInstallationRecipe, []LintProblem) {
+	l := &recipeLinter{problems: []LintProblem{}}
+
+	raw := map[string]interface{}{}
+	if err := yaml.Unmarshal(content, &raw); err != nil {
+		l.errorf("", "could not parse YAML: %s", err)
+		return nil, l.problems
+	}
+
+	l.lintFieldKinds(raw)
+	if l.hasErrors() {
+		return nil, l.problems
+	}
+
+	r, err := unmarshalRecipe(content)
+	if err != nil {
+		l.errorf("", "could not read recipe: %s", err)
+		return nil, l.problems
+	}
+
+	if r.Name == "" {
+		l.errorf("name", "is required")
+	}
+	if r.DisplayName == "" {
+		l.warnf("displayName", "is not set, the recipe name will be shown to users")
+	}
+
+	l.lintStability(r)
+	l.lintInstallTargets(r)
+	l.lintProcessMatch(r)
+	l.lintLogMatch(r)
+	l.lintInputVars(r)
+	l.lintPreInstall(r)
+
+	if r.Install == "" {
+		l.errorf("install", "is required")
+	} else {
+		l.lintTaskfile("install", r.Install)
+	}
+
+	if r.Uninstall != "" {
+		l.lintTaskfile("uninstall", r.Uninstall)
+	}
+
+	l.lintValidation(r)
+
+	return r, l.problems
+}
+
+// unmarshalRecipe reads a recipe, turning a panic from an unexpected value into an error.
+func unmarshalRecipe(content []byte) (r *types.OpenInstallationRecipe, err error) {
+	defer func() {
+		if p := recover(); p != nil {
+			r = nil
+			err = fmt.Errorf("%v", p)
+		}
+	}()
+
+	r = &types.OpenInstallationRecipe{}
+	err = yaml.Unmarshal(content, r)
+
+	return r, err
+}
+
+type recipeLinter struct {
+	problems []LintProblem
+}
+
+func (l *recipeLinter) hasErrors() bool {
+	return LintResult{Problems: l.problems}.HasErrors()
+}
+
+func (l *recipeLinter) errorf(field string, format string, a ...interface{}) {
+	l.problems = append(l.problems, LintProblem{Severity: LintSeverities.ERROR, Field: field, Message: fmt.Sprintf(format, a...)})
+}
+
+func (l *recipeLinter) warnf(field string, format string, a ...interface{}) {
+	l.problems = append(l.problems, LintProblem{Severity: LintSeverities.WARNING, Field: field, Message: fmt.Sprintf(format, a...)})
+}
+
+func (l *recipeLinter) lintFieldKinds(raw map[string]interface{}) {
+	names := make([]string, 0, len(raw))
+	for name := range raw {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		kind, ok := recipeFields[name]
+		if !ok {
+			l.warnf(name, "is not a recipe field and will be ignored")
+			continue
+		}
+
+		switch v := raw[name].(type) {
+		case []interface{}:
+			if kind != listField {
+				l.errorf(name, "must not be a list")
+			} else if name == "dependencies" || name == "keywords" || name == "processMatch" {
+				for n, item := range v {
+					if _, isString := item.(string); !isString {
+						l.errorf(fmt.Sprintf("%s[%d]", name, n), "must be a string")
+					}
+				}
+			} else {
+				for n, item := range v {
+					if _, isMap := item.(map[interface{}]interface{}); !isMap {
+						l.errorf(fmt.Sprintf("%s[%d]", name, n), "must be an object")
+					}
+				}
+			}
+		case map[interface{}]interface{}:
+			if kind != mapField {
+				l.errorf(name, "must not be an object")
+			}
+		case nil:
+			l.errorf(name, "must have a value")
+		default:
+			if kind != scalarField {
+				if kind == listField {
+					l.errorf(name, "must be a list")
+				} else {
+					l.errorf(name, "must be an object")
+				}
+			}
+		}
+	}
+}
+
+func (l *recipeLinter) lintStability(r *types.OpenInstallationRecipe) {
+	stabilities := []string{
+		string(types.OpenInstallationStabilityTypes.STABLE),
+		string(types.OpenInstallationStabilityTypes.EXPERIMENTAL),
+		string(types.OpenInstallationStabilityTypes.DISABLED),
+	}
+
+	if r.Stability != "" && !isOneOf(string(r.Stability), stabilities) {
+		l.errorf("stability", "unknown stability %s", r.Stability)
+	}
+}
+
+func (l *recipeLinter) lintInstallTargets(r *types.OpenInstallationRecipe) {
+	if len(r.InstallTargets) == 0 {
+		l.warnf("installTargets", "is empty, the recipe will not be available on any host")
+	}
+
+	for n, t := range r.InstallTargets {
+		field := fmt.Sprintf("installTargets[%d]", n)
+
+		if t.Type == "" {
+			l.warnf(field+".type", "is not set")
+		} else if !isOneOf(string(t.Type), installTargetTypes) {
+			l.errorf(field+".type", "unknown target type %s", t.Type)
+		}
+
+		if t.Os != "" && !isOneOf(string(t.Os), installTargetOperatingSystems) {
+			l.errorf(field+".os", "unknown operating system %s", t.Os)
+		}
+
+		if t.Platform != "" && !isOneOf(string(t.Platform), installTargetPlatforms) {
+			l.errorf(field+".platform", "unknown platform %s", t.Platform)
+		}
+
+		if t.PlatformFamily != "" && !isOneOf(string(t.PlatformFamily), installTargetPlatformFamilies) {
+			l.errorf(field+".platformFamily", "unknown platform family %s", t.PlatformFamily)
+		}
+
+		if t.ContainerRuntime != "" && !strings.HasPrefix(string(t.ContainerRuntime), "(") && !isOneOf(string(t.ContainerRuntime), installTargetContainerRuntimes) {
+			l.errorf(field+".containerRuntime", "unknown container runtime %s", t.ContainerRuntime)
+		}
+
+		if t.Orchestrator != "" && !strings.HasPrefix(string(t.Orchestrator), "(") && !isOneOf(string(t.Orchestrator), installTargetOrchestrators) {
+			l.errorf(field+".orchestrator", "unknown orchestrator %s", t.Orchestrator)
+		}
+
+		// Values starting with a parenthesis are matched as regular expressions.
+		criteria := map[string]string{
+			"kernelArch":       t.KernelArch,
+			"kernelVersion":    t.KernelVersion,
+			"platformVersion":  t.PlatformVersion,
+			"containerRuntime": string(t.ContainerRuntime),
+			"orchestrator":     string(t.Orchestrator),
+		}
+		for _, name := range []string{"kernelArch", "kernelVersion", "platformVersion", "containerRuntime", "orchestrator"} {
+			if v := criteria[name]; strings.HasPrefix(v, "(") {
+				if _, err := regexp.Compile(v); err != nil {
+					l.errorf(field+"."+name, "invalid regular expression: %s", err)
+				}
+			}
+		}
+	}
+}
+
+func (l *recipeLinter) lintProcessMatch(r *types.OpenInstallationRecipe) {
+	for n, pm := range r.ProcessMatch {
+		field := fmt.Sprintf("processMatch[%d]", n)
+
+		if strings.TrimSpace(pm) == "" {
+			l.errorf(field, "must not be empty")
+			continue
+		}
+
+		if _, err := regexp.Compile(pm); err != nil {
+			l.errorf(field, "invalid regular expression: %s", err)
+		}
+	}
+}
+
+func (l *recipeLinter) lintLogMatch(r *types.OpenInstallationRecipe) {
+	for n, lm := range r.LogMatch {
+		field := fmt.Sprintf("logMatch[%d]", n)
+
+		if lm.Name == "" {
+			l.errorf(field+".name", "is required")
+		}
+
+		if lm.File == "" && lm.Systemd == "" {
+			l.errorf(field, "one of file or systemd is required")
+		}
+
+		if lm.File != "" {
+			if _, err := filepath.Match(lm.File, ""); err != nil {
+				l.errorf(field+".file", "invalid glob %s: %s", lm.File, err)
+			}
+		}
+
+		if lm.Pattern != "" {
+			if _, err := regexp.Compile(lm.Pattern); err != nil {
+				l.errorf(field+".pattern", "invalid regular expression: %s", err)
+			}
+		}
+	}
+}
+
+func (l *recipeLinter) lintInputVars(r *types.OpenInstallationRecipe) {
+	seen := map[string]bool{}
+
+	for n, v := range r.InputVars {
+		field := fmt.Sprintf("inputVars[%d]", n)
+
+		if v.Name == "" {
+			l.errorf(field+".name", "is required")
+			continue
+		}
+
+		if !inputVarNameRegex.MatchString(v.Name) {
+			l.errorf(field+".name", "%s is not a valid environment variable name", v.Name)
+		}
+
+		if seen[v.Name] {
+			l.errorf(field+".name", "%s is declared more than once", v.Name)
+		}
+		seen[v.Name] = true
+
+		if v.Prompt == "" && v.Default == "" {
+			l.warnf(field, "%s has neither a prompt nor a default", v.Name)
+		}
+	}
+}
+
+func (l *recipeLinter) lintPreInstall(r *types.OpenInstallationRecipe) {
+	if r.PreInstall.RequireAtDiscovery != "" && strings.TrimSpace(r.PreInstall.RequireAtDiscovery) == "" {
+		l.errorf("preInstall.requireAtDiscovery", "must not be blank")
+	}
+}
+
+// lintTaskfile checks that a go-task definition can be read by go-task, has a
+// default task, and only calls tasks that it defines.
+func (l *recipeLinter) lintTaskfile(field string, content string) {
+	tf := taskfile.Taskfile{}
+	if err := yaml.Unmarshal([]byte(content), &tf); err != nil {
+		l.errorf(field, "invalid go-task definition: %s", err)
+		return
+	}
+
+	if tf.Version == "" {
+		l.errorf(field+".version", "is required")
+	} else if v, err := tf.ParsedVersion(); err != nil {
+		l.errorf(field+".version", "%s", err)
+	} else if v < 3 {
+		l.errorf(field+".version", "must be 3 or later")
+	}
+
+	if _, ok := tf.Tasks["default"]; !ok {
+		l.errorf(field+".tasks", "a default task is required")
+	}
+
+	names := make([]string, 0, len(tf.Tasks))
+	for name := range tf.Tasks {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		task := tf.Tasks[name]
+		if task == nil {
+			continue
+		}
+
+		for _, d := range task.Deps {
+			if d != nil && d.Task != "" && tf.Tasks[d.Task] == nil {
+				l.errorf(fmt.Sprintf("%s.tasks.%s.deps", field, name), "task %s is not defined", d.Task)
+			}
+		}
+
+		for _, c := range task.Cmds {
+			if c != nil && c.Task != "" && tf.Tasks[c.Task] == nil {
+				l.errorf(fmt.Sprintf("%s.tasks.%s.cmds", field, name), "task %s is not defined", c.Task)
+			}
+		}
+	}
+}
+
+func (l *recipeLinter) lintValidation(r *types.OpenInstallationRecipe) {
+	if r.ValidationNRQL == "" && r.ValidationURL == "" && r.ValidationIntegration == "" {
+		l.warnf("validationNrql", "no validation is defined, installs cannot confirm data is reported")
+	}
+
+	if r.ValidationNRQL != "" {
+		if _, err := template.New("validationNrql").Parse(string(r.ValidationNRQL)); err != nil {
+			l.errorf("validationNrql", "invalid template: %s", err)
+		}
+	}
+}
+
+// isOneOf reports whether value matches one of the allowed values, ignoring
+// case as install targets are matched to hosts without regard to case.
+func isOneOf(value string, allowed []string) bool {
+	for _, a := range allowed {
+		if strings.EqualFold(value, a) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,177 @@
+//go:build unit
+
+package recipes
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const lintValidRecipe = `
+name: test-integration
+displayName: Test Integration
+installTargets:
+  - type: host
+    os: linux
+    platformVersion: "(20|22)\\.04"
+processMatch:
+  - /usr/sbin/testd
+logMatch:
+  - name: test
+    file: /var/log/test/*.log
+inputVars:
+  - name: TEST_PORT
+    prompt: Port
+    default: 8080
+install:
+  version: "3"
+  tasks:
+    default:
+      cmds:
+        - task: setup
+    setup:
+      cmds:
+        - echo setup
+uninstall:
+  version: "3"
+  tasks:
+    default:
+      cmds:
+        - echo remove
+validationNrql: "SELECT count(*) FROM SystemSample WHERE hostname LIKE '{{.HOSTNAME}}%' SINCE 10 minutes ago"
+`
+
+func lintMessages(problems []LintProblem) []string {
+	messages := []string{}
+	for _, p := range problems {
+		messages = append(messages, p.String())
+	}
+
+	return messages
+}
+
+func TestLintRecipeShouldAcceptValidRecipe(t *testing.T) {
+	r, problems := LintRecipe([]byte(lintValidRecipe))
+
+	require.NotNil(t, r)
+	assert.Equal(t, "test-integration", r.Name)
+	assert.Empty(t, problems)
+}
+
+func TestLintRecipeShouldReportInvalidFields(t *testing.T) {
+	content := `
+name: test-integration
+displayName: Test Integration
+stability: beta
+installTargets:
+  - type: vm
+    os: linux
+    platform: gentoo
+    platformVersion: "(20"
+    containerRuntime: lxc
+    orchestrator: nomad
+processMatch:
+  - "(testd"
+logMatch:
+  - file: /var/log/[test.log
+inputVars:
+  - name: TEST-PORT
+    prompt: Port
+  - name: TEST-PORT
+    default: 1
+install:
+  version: "3"
+  tasks:
+    default:
+      deps: [download]
+      cmds:
+        - task: setup
+validationNrql: "SELECT count(*) FROM SystemSample WHERE hostname = {{.HOSTNAME"
+`
+
+	_, problems := LintRecipe([]byte(content))
+
+	assert.ElementsMatch(t, []string{
+		"error: stability: unknown stability beta",
+		"error: installTargets[0].type: unknown target type vm",
+		"error: installTargets[0].platform: unknown platform gentoo",
+		"error: installTargets[0].containerRuntime: unknown container runtime lxc",
+		"error: installTargets[0].orchestrator: unknown orchestrator nomad",
+		"error: installTargets[0].platformVersion: invalid regular expression: error parsing regexp: missing closing ): `(20`",
+		"error: processMatch[0]: invalid regular expression: error parsing regexp: missing closing ): `(testd`",
+		"error: logMatch[0].name: is required",
+		"error: logMatch[0].file: invalid glob /var/log/[test.log: syntax error in pattern",
+		"error: inputVars[0].name: TEST-PORT is not a valid environment variable name",
+		"error: inputVars[1].name: TEST-PORT is not a valid environment variable name",
+		"error: inputVars[1].name: TEST-PORT is declared more than once",
+		"error: install.tasks.default.deps: task download is not defined",
+		"error: install.tasks.default.cmds: task setup is not defined",
+		"error: validationNrql: invalid template: template: validationNrql:1: unclosed action",
+	}, lintMessages(problems))
+}
+
+func TestLintRecipeShouldReportFieldKinds(t *testing.T) {
+	content := `
+name: test-integration
+processMatch: testd
+install: echo install
+installer:
+  version: "3"
+`
+
+	r, problems := LintRecipe([]byte(content))
+
+	assert.Nil(t, r)
+	assert.ElementsMatch(t, []string{
+		"error: install: must be an object",
+		"warning: installer: is not a recipe field and will be ignored",
+		"error: processMatch: must be a list",
+	}, lintMessages(problems))
+}
+
+func TestLintRecipeShouldRequireDefaultTaskAndVersion(t *testing.T) {
+	content := `
+name: test-integration
+displayName: Test Integration
+installTargets:
+  - type: host
+install:
+  tasks:
+    setup:
+      cmds:
+        - echo setup
+validationNrql: "SELECT count(*) FROM SystemSample"
+`
+
+	_, problems := LintRecipe([]byte(content))
+
+	assert.ElementsMatch(t, []string{
+		"error: install.version: is required",
+		"error: install.tasks: a default task is required",
+	}, lintMessages(problems))
+}
+
+func TestLintRecipeShouldReportInvalidYAML(t *testing.T) {
+	r, problems := LintRecipe([]byte("name: [test"))
+
+	assert.Nil(t, r)
+	require.Len(t, problems, 1)
+	assert.Contains(t, problems[0].Message, "could not parse YAML")
+}
+
+func TestLintRecipeFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "test.yml")
+	require.NoError(t, os.WriteFile(path, []byte(lintValidRecipe), 0600))
+
+	result := LintRecipeFile(path)
+
+	assert.Equal(t, "test-integration", result.Recipe)
+	assert.False(t, result.HasErrors())
+
+	result = LintRecipeFile(filepath.Join(t.TempDir(), "missing.yml"))
+	assert.True(t, result.HasErrors())
+}
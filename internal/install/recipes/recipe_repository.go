@@ -13,12 +13,14 @@ import (
 )
 
 var (
-	kernelArch      = "KernelArch"
-	kernelVersion   = "KernelVersion"
-	oS              = "OS"
-	platform        = "Platform"
-	platformFamily  = "PlatformFamily"
-	platformVersion = "PlatformVersion"
+	kernelArch       = "KernelArch"
+	kernelVersion    = "KernelVersion"
+	oS               = "OS"
+	platform         = "Platform"
+	platformFamily   = "PlatformFamily"
+	platformVersion  = "PlatformVersion"
+	containerRuntime = "ContainerRuntime"
+	orchestrator     = "Orchestrator"
 )
 
 type Finder interface {
@@ -262,24 +264,37 @@ func matchRecipeCriteria(hostMap map[string]string, rkey string, rvalue string)
 
 func getHostMap(m *types.DiscoveryManifest) map[string]string {
 	hostMap := map[string]string{
-		kernelArch:      m.KernelArch,
-		kernelVersion:   m.KernelVersion,
-		oS:              m.OS,
-		platform:        m.Platform,
-		platformFamily:  m.PlatformFamily,
-		platformVersion: m.PlatformVersion,
+		kernelArch:       m.KernelArch,
+		kernelVersion:    m.KernelVersion,
+		oS:               m.OS,
+		platform:         m.Platform,
+		platformFamily:   m.PlatformFamily,
+		platformVersion:  m.PlatformVersion,
+		containerRuntime: valueOrNone(m.ContainerRuntime),
+		orchestrator:     valueOrNone(m.Orchestrator),
 	}
 	return hostMap
 }
 
 func getRecipeTargetMap(rit types.OpenInstallationRecipeInstallTarget) map[string]string {
 	targetMap := map[string]string{
-		kernelArch:      rit.KernelArch,
-		kernelVersion:   rit.KernelVersion,
-		oS:              string(rit.Os),
-		platform:        string(rit.Platform),
-		platformFamily:  string(rit.PlatformFamily),
-		platformVersion: rit.PlatformVersion,
+		kernelArch:       rit.KernelArch,
+		kernelVersion:    rit.KernelVersion,
+		oS:               string(rit.Os),
+		platform:         string(rit.Platform),
+		platformFamily:   string(rit.PlatformFamily),
+		platformVersion:  rit.PlatformVersion,
+		containerRuntime: string(rit.ContainerRuntime),
+		orchestrator:     string(rit.Orchestrator),
 	}
 	return targetMap
 }
+
+// valueOrNone lets install targets match hosts that are not containerized, or
+// not orchestrated, with NONE.
+func valueOrNone(value string) string {
+	if value == "" {
+		return string(types.OpenInstallationContainerRuntimeTypes.NONE)
+	}
+	return value
+}
@@ -186,6 +186,32 @@ func TestRecipeRepository_ShouldEnrichLogRecipe(t *testing.T) {
 	require.Equal(t, exist, true)
 }
 
+func TestRecipeRepository_ShouldFindHostRecipeWhenNotContainerized(t *testing.T) {
+	Setup()
+	givenCachedRecipeOsContainer("id1", "my-recipe", types.OpenInstallationContainerRuntimeTypes.NONE, "")
+	givenCachedRecipeOsContainer("id2", "other-recipe", types.OpenInstallationContainerRuntimeTypes.DOCKER, "")
+	discoveryManifest.OS = "linux"
+
+	results, _ := repository.FindAll()
+
+	require.Len(t, results, 1)
+	require.Equal(t, results[0].ID, "id1")
+}
+
+func TestRecipeRepository_ShouldFindContainerRecipe(t *testing.T) {
+	Setup()
+	givenCachedRecipeOsContainer("id1", "my-recipe", types.OpenInstallationContainerRuntimeTypes.NONE, "")
+	givenCachedRecipeOsContainer("id2", "other-recipe", types.OpenInstallationContainerRuntimeTypes.CONTAINERD, types.OpenInstallationOrchestratorTypes.KUBERNETES)
+	discoveryManifest.OS = "linux"
+	discoveryManifest.ContainerRuntime = "CONTAINERD"
+	discoveryManifest.Orchestrator = "KUBERNETES"
+
+	results, _ := repository.FindAll()
+
+	require.Len(t, results, 1)
+	require.Equal(t, results[0].ID, "id2")
+}
+
 func TestRecipeRepository_matchRecipeCriteria_Basic(t *testing.T) {
 	Setup()
 	discoveryManifest.Platform = "linux"
@@ -254,6 +280,12 @@ func givenCachedRecipeOs(id string, name string, os types.OpenInstallationOperat
 	return r
 }
 
+func givenCachedRecipeOsContainer(id string, name string, runtime types.OpenInstallationContainerRuntime, orchestrator types.OpenInstallationOrchestrator) *types.OpenInstallationRecipe {
+	r := NewRecipeBuilder().ID(id).Name(name).TargetOsContainer(types.OpenInstallationOperatingSystemTypes.LINUX, runtime, orchestrator).Build()
+	recipeCache = append(recipeCache, r)
+	return r
+}
+
 func givenCachedRecipeOsPlatform(id string, name string, os types.OpenInstallationOperatingSystem, platform types.OpenInstallationPlatform) *types.OpenInstallationRecipe {
 	r := NewRecipeBuilder().ID(id).Name(name).TargetOsPlatform(os, platform).Build()
 	recipeCache = append(recipeCache, r)
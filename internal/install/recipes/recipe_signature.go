@@ -0,0 +1,212 @@
+package recipes
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/newrelic/newrelic-cli/internal/install/types"
+)
+
+// RecipeSignatureExtension is appended to the path or URL of a recipe file,
+// or of an offline bundle, to find its detached signature.
+const RecipeSignatureExtension = ".sig"
+
+// RecipeSignatureVerifier verifies the detached signatures of recipe files and
+// offline bundles.
+//
+// Signatures are made over the exact bytes of the file, either with an ed25519
+// key, or with an ECDSA P-256 key over the SHA-256 digest of the file as done
+// by cosign sign-blob.  The signature file holds the signature encoded in
+// base64, or the raw signature bytes.
+type RecipeSignatureVerifier struct {
+	// Key is the public key verifying signatures: a PEM encoded public key, the
+	// path to one, or a base64 encoded ed25519 public key.
+	Key string
+	// AllowUnsigned accepts files without a signature, or that cannot be
+	// verified as no key is configured.
+	AllowUnsigned bool
+}
+
+// NewRecipeSignatureVerifier returns a RecipeSignatureVerifier checking
+// signatures with key.
+func NewRecipeSignatureVerifier(key string, allowUnsigned bool) *RecipeSignatureVerifier {
+	return &RecipeSignatureVerifier{
+		Key:           key,
+		AllowUnsigned: allowUnsigned,
+	}
+}
+
+// VerifyFile verifies the file at p with the signature next to it.
+func (v *RecipeSignatureVerifier) VerifyFile(p string) (*types.RecipeSignature, error) {
+	content, err := os.ReadFile(p)
+	if err != nil {
+		return nil, err
+	}
+
+	return v.VerifyFileContent(p, content)
+}
+
+// VerifyFileContent verifies content read from the file at p with the
+// signature next to it.
+func (v *RecipeSignatureVerifier) VerifyFileContent(p string, content []byte) (*types.RecipeSignature, error) {
+	signature, err := os.ReadFile(p + RecipeSignatureExtension)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			return nil, err
+		}
+		signature = nil
+	}
+
+	return v.Verify(p, content, signature)
+}
+
+// VerifyURLContent verifies content downloaded from u with the signature
+// downloaded from the same URL with the signature extension.
+func (v *RecipeSignatureVerifier) VerifyURLContent(get func(string) (*http.Response, error), u string, content []byte) (*types.RecipeSignature, error) {
+	response, err := get(u + RecipeSignatureExtension)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+
+	var signature []byte
+	switch {
+	case response.StatusCode == http.StatusNotFound:
+	case response.StatusCode < 200 || response.StatusCode > 299:
+		return nil, fmt.Errorf("received non-2xx Status code %d when retrieving the signature of %s", response.StatusCode, u)
+	default:
+		if signature, err = io.ReadAll(response.Body); err != nil {
+			return nil, err
+		}
+	}
+
+	return v.Verify(u, content, signature)
+}
+
+// Verify verifies content loaded from source with signature, nil when source
+// has no signature.  Files without a signature, or that cannot be verified as
+// no key is configured, are rejected unless unsigned files are allowed.  Files
+// with an invalid signature are always rejected.
+func (v *RecipeSignatureVerifier) Verify(source string, content []byte, signature []byte) (*types.RecipeSignature, error) {
+	if signature == nil {
+		if !v.AllowUnsigned {
+			return nil, types.NewDetailError(types.EventTypes.RecipeUnsigned,
+				fmt.Sprintf("%s is not signed, add a detached signature in %s%s or use --allow-unsigned", source, source, RecipeSignatureExtension))
+		}
+
+		log.Warnf("%s is not signed", source)
+		return &types.RecipeSignature{Status: types.RecipeSignatureStatuses.UNSIGNED}, nil
+	}
+
+	if v.Key == "" {
+		if !v.AllowUnsigned {
+			return nil, types.NewDetailError(types.EventTypes.RecipeUnsigned,
+				fmt.Sprintf("cannot verify the signature of %s, no recipe signing key is configured for the profile, set one with newrelic profile add --recipeSigningKey or use --allow-unsigned", source))
+		}
+
+		log.Warnf("cannot verify the signature of %s, no recipe signing key is configured", source)
+		return &types.RecipeSignature{Status: types.RecipeSignatureStatuses.UNVERIFIED}, nil
+	}
+
+	key, err := ParseRecipeSigningKey(v.Key)
+	if err != nil {
+		return nil, err
+	}
+
+	if !verifySignature(key, content, decodeSignature(signature)) {
+		return nil, types.NewDetailError(types.EventTypes.RecipeSignatureInvalid,
+			fmt.Sprintf("invalid signature for %s, it was not signed by the recipe signing key %s or was modified", source, recipeSigningKeyID(key)))
+	}
+
+	log.Debugf("verified the signature of %s", source)
+
+	return &types.RecipeSignature{
+		Status: types.RecipeSignatureStatuses.VERIFIED,
+		KeyID:  recipeSigningKeyID(key),
+	}, nil
+}
+
+// ParseRecipeSigningKey parses a PEM encoded ed25519 or ECDSA public key, the
+// path to one, or a base64 encoded ed25519 public key.
+func ParseRecipeSigningKey(s string) (crypto.PublicKey, error) {
+	s = strings.TrimSpace(s)
+	data := []byte(s)
+
+	if !strings.HasPrefix(s, "-----BEGIN") {
+		if raw, err := base64.StdEncoding.DecodeString(s); err == nil && len(raw) == ed25519.PublicKeySize {
+			return ed25519.PublicKey(raw), nil
+		}
+
+		content, err := os.ReadFile(s)
+		if err != nil {
+			return nil, fmt.Errorf("invalid recipe signing key, not a PEM encoded public key, a path to one or a base64 encoded ed25519 public key: %s", err)
+		}
+		data = content
+	}
+
+	block, _ := pem.Decode(data)
+	if block == nil || block.Type != "PUBLIC KEY" {
+		return nil, fmt.Errorf("invalid recipe signing key, expected a PEM encoded PUBLIC KEY")
+	}
+
+	key, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("invalid recipe signing key: %s", err)
+	}
+
+	switch key.(type) {
+	case ed25519.PublicKey, *ecdsa.PublicKey:
+		return key, nil
+	}
+
+	return nil, fmt.Errorf("unsupported recipe signing key type %T, expected an ed25519 or ECDSA public key", key)
+}
+
+func verifySignature(key crypto.PublicKey, content []byte, signature []byte) bool {
+	switch k := key.(type) {
+	case ed25519.PublicKey:
+		return ed25519.Verify(k, content, signature)
+	case *ecdsa.PublicKey:
+		digest := sha256.Sum256(content)
+		return ecdsa.VerifyASN1(k, digest[:], signature)
+	}
+
+	return false
+}
+
+// decodeSignature returns the raw bytes of a signature encoded in base64, as
+// written by cosign and most signing tools, or already raw.
+func decodeSignature(signature []byte) []byte {
+	trimmed := bytes.TrimSpace(signature)
+	if decoded, err := base64.StdEncoding.DecodeString(string(trimmed)); err == nil {
+		return decoded
+	}
+
+	return signature
+}
+
+// recipeSigningKeyID returns a short fingerprint of a key: the start of the
+// SHA-256 digest of its PKIX encoding.
+func recipeSigningKeyID(key crypto.PublicKey) string {
+	der, err := x509.MarshalPKIXPublicKey(key)
+	if err != nil {
+		return ""
+	}
+
+	digest := sha256.Sum256(der)
+	return hex.EncodeToString(digest[:8])
+}
@@ -0,0 +1,201 @@
+//go:build unit
+
+package recipes
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/newrelic/newrelic-cli/internal/install/types"
+)
+
+func TestRecipeSignatureVerifierShouldVerifyEd25519Signatures(t *testing.T) {
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	p := givenRecipeFile(t, testRecipeFileString)
+	givenSignatureFile(t, p, base64.StdEncoding.EncodeToString(ed25519.Sign(priv, []byte(testRecipeFileString))))
+
+	signature, err := NewRecipeSignatureVerifier(givenPEMPublicKey(t, pub), false).VerifyFile(p)
+
+	require.NoError(t, err)
+	assert.Equal(t, types.RecipeSignatureStatuses.VERIFIED, signature.Status)
+	assert.Len(t, signature.KeyID, 16)
+}
+
+func TestRecipeSignatureVerifierShouldVerifyRawSignatures(t *testing.T) {
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	p := givenRecipeFile(t, testRecipeFileString)
+	givenSignatureFile(t, p, string(ed25519.Sign(priv, []byte(testRecipeFileString))))
+
+	signature, err := NewRecipeSignatureVerifier(base64.StdEncoding.EncodeToString(pub), false).VerifyFile(p)
+
+	require.NoError(t, err)
+	assert.Equal(t, types.RecipeSignatureStatuses.VERIFIED, signature.Status)
+}
+
+func TestRecipeSignatureVerifierShouldVerifyCosignSignatures(t *testing.T) {
+	priv, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	digest := sha256.Sum256([]byte(testRecipeFileString))
+	sig, err := ecdsa.SignASN1(rand.Reader, priv, digest[:])
+	require.NoError(t, err)
+	p := givenRecipeFile(t, testRecipeFileString)
+	givenSignatureFile(t, p, base64.StdEncoding.EncodeToString(sig)+"\n")
+
+	keyFile := filepath.Join(t.TempDir(), "cosign.pub")
+	require.NoError(t, os.WriteFile(keyFile, []byte(givenPEMPublicKey(t, &priv.PublicKey)), 0600))
+
+	signature, err := NewRecipeSignatureVerifier(keyFile, false).VerifyFile(p)
+
+	require.NoError(t, err)
+	assert.Equal(t, types.RecipeSignatureStatuses.VERIFIED, signature.Status)
+}
+
+func TestRecipeSignatureVerifierShouldRejectInvalidSignatures(t *testing.T) {
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	p := givenRecipeFile(t, testRecipeFileString+"\n# modified")
+	givenSignatureFile(t, p, base64.StdEncoding.EncodeToString(ed25519.Sign(priv, []byte(testRecipeFileString))))
+
+	_, err = NewRecipeSignatureVerifier(givenPEMPublicKey(t, pub), true).VerifyFile(p)
+
+	require.Error(t, err)
+	e, ok := err.(*types.DetailError)
+	require.True(t, ok)
+	assert.Equal(t, types.EventTypes.RecipeSignatureInvalid, e.EventName)
+}
+
+func TestRecipeSignatureVerifierShouldRequireSignatures(t *testing.T) {
+	pub, _, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	p := givenRecipeFile(t, testRecipeFileString)
+
+	_, err = NewRecipeSignatureVerifier(givenPEMPublicKey(t, pub), false).VerifyFile(p)
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "--allow-unsigned")
+	e, ok := err.(*types.DetailError)
+	require.True(t, ok)
+	assert.Equal(t, types.EventTypes.RecipeUnsigned, e.EventName)
+
+	signature, err := NewRecipeSignatureVerifier(givenPEMPublicKey(t, pub), true).VerifyFile(p)
+
+	require.NoError(t, err)
+	assert.Equal(t, types.RecipeSignatureStatuses.UNSIGNED, signature.Status)
+}
+
+func TestRecipeSignatureVerifierShouldRequireKey(t *testing.T) {
+	p := givenRecipeFile(t, testRecipeFileString)
+	givenSignatureFile(t, p, "c2lnbmF0dXJl")
+
+	_, err := NewRecipeSignatureVerifier("", false).VerifyFile(p)
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "no recipe signing key is configured")
+
+	signature, err := NewRecipeSignatureVerifier("", true).VerifyFile(p)
+
+	require.NoError(t, err)
+	assert.Equal(t, types.RecipeSignatureStatuses.UNVERIFIED, signature.Status)
+}
+
+func TestParseRecipeSigningKeyShouldRejectInvalidKeys(t *testing.T) {
+	for _, key := range []string{"not a key", base64.StdEncoding.EncodeToString([]byte("too short")), "-----BEGIN PUBLIC KEY-----\nAAAA\n-----END PUBLIC KEY-----"} {
+		_, err := ParseRecipeSigningKey(key)
+		assert.Error(t, err, key)
+	}
+}
+
+func TestLocalRecipeFetcherShouldVerifySignatures(t *testing.T) {
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	p := givenRecipeFile(t, testRecipeFileString)
+	f := LocalRecipeFetcher{
+		Path:     filepath.Dir(p),
+		Verifier: NewRecipeSignatureVerifier(givenPEMPublicKey(t, pub), false),
+	}
+
+	_, err = f.FetchRecipes(context.Background())
+	require.Error(t, err)
+
+	givenSignatureFile(t, p, base64.StdEncoding.EncodeToString(ed25519.Sign(priv, []byte(testRecipeFileString))))
+	recipes, err := f.FetchRecipes(context.Background())
+
+	require.NoError(t, err)
+	require.Len(t, recipes, 1)
+	assert.Equal(t, "testName", recipes[0].Name)
+	assert.Equal(t, types.RecipeSignatureStatuses.VERIFIED, recipes[0].Signature().Status)
+}
+
+func TestRecipeFileFetcherShouldVerifyURLSignatures(t *testing.T) {
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	signatures := map[string]string{
+		"https://localhost/signed.yml.sig": base64.StdEncoding.EncodeToString(ed25519.Sign(priv, []byte(testRecipeFileString))),
+	}
+
+	ff := NewRecipeFileFetcher([]string{})
+	ff.Verifier = NewRecipeSignatureVerifier(givenPEMPublicKey(t, pub), false)
+	ff.HTTPGetFunc = func(u string) (*http.Response, error) {
+		body, ok := signatures[u]
+		statusCode := http.StatusOK
+		switch {
+		case ok:
+		case filepath.Ext(u) == RecipeSignatureExtension:
+			statusCode = http.StatusNotFound
+		default:
+			body = testRecipeFileString
+		}
+
+		return &http.Response{
+			StatusCode: statusCode,
+			Body:       io.NopCloser(bytes.NewBufferString(body)),
+		}, nil
+	}
+
+	u, err := url.Parse("https://localhost/signed.yml")
+	require.NoError(t, err)
+	recipe, err := ff.FetchRecipeFile(u)
+	require.NoError(t, err)
+	assert.Equal(t, types.RecipeSignatureStatuses.VERIFIED, recipe.Signature().Status)
+
+	ff.Paths = []string{"https://localhost/unsigned.yml"}
+	_, err = ff.FetchRecipes(context.Background())
+	require.Error(t, err)
+	_, ok := err.(*types.DetailError)
+	assert.True(t, ok)
+}
+
+func givenRecipeFile(t *testing.T, content string) string {
+	p := filepath.Join(t.TempDir(), "recipe.yml")
+	require.NoError(t, os.WriteFile(p, []byte(content), 0600))
+	return p
+}
+
+func givenSignatureFile(t *testing.T, p string, signature string) {
+	require.NoError(t, os.WriteFile(p+RecipeSignatureExtension, []byte(signature), 0600))
+}
+
+func givenPEMPublicKey(t *testing.T, key interface{}) string {
+	der, err := x509.MarshalPKIXPublicKey(key)
+	require.NoError(t, err)
+	return string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}))
+}
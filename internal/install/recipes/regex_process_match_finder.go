@@ -6,13 +6,22 @@ import (
 
 	log "github.com/sirupsen/logrus"
 
+	"github.com/newrelic/newrelic-cli/internal/install/container"
 	"github.com/newrelic/newrelic-cli/internal/install/types"
 )
 
-type RegexProcessMatchFinder struct{}
+type RegexProcessMatchFinder struct {
+	// containerOf identifies the container a process runs in, so that matches
+	// in other containers are not mistaken for services running on this host.
+	containerOf func(pid int32) types.Container
+	self        types.Container
+}
 
 func NewRegexProcessMatchFinder() *RegexProcessMatchFinder {
-	f := RegexProcessMatchFinder{}
+	f := RegexProcessMatchFinder{
+		containerOf: container.ForPID,
+		self:        container.Self(),
+	}
 
 	return &f
 }
@@ -72,6 +81,7 @@ func (f *RegexProcessMatchFinder) findMatches(r types.OpenInstallationRecipe, pr
 			mp.GenericProcess = process
 			mp.MatchingPattern = pattern
 			mp.MatchingRecipe = r
+			mp.Container = f.otherContainer(process)
 			log.Tracef("Process matching pattern %s with %s for recipe %s.", pattern, cmd, r.DisplayName)
 
 			matches = append(matches, *mp)
@@ -80,3 +90,18 @@ func (f *RegexProcessMatchFinder) findMatches(r types.OpenInstallationRecipe, pr
 
 	return matches
 }
+
+// otherContainer returns the container a process runs in when it is not the
+// container the CLI runs in.
+func (f *RegexProcessMatchFinder) otherContainer(process types.GenericProcess) types.Container {
+	if f.containerOf == nil {
+		return types.Container{}
+	}
+
+	c := f.containerOf(process.PID())
+	if !container.IsOther(c, f.self) {
+		return types.Container{}
+	}
+
+	return c
+}
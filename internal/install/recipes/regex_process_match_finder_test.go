@@ -352,3 +352,38 @@ func TestShouldFilterOutNewRelicInstallWindowsNoQuote(t *testing.T) {
 	require.NotNil(t, filtered)
 	require.Empty(t, filtered)
 }
+
+func TestFindMatchesShouldIdentifyProcessesInOtherContainers(t *testing.T) {
+	r := types.OpenInstallationRecipe{
+		Name:         "redis-open-source-integration",
+		ProcessMatch: []string{"redis-server"},
+	}
+
+	processes := []types.GenericProcess{
+		NewMockProcess("redis-server *:6379", "redis-server", 1),
+		NewMockProcess("redis-server *:6380", "redis-server", 2),
+		NewMockProcess("redis-server *:6381", "redis-server", 3),
+	}
+
+	docker := types.Container{Runtime: types.OpenInstallationContainerRuntimeTypes.DOCKER, ID: "other"}
+	self := types.Container{Runtime: types.OpenInstallationContainerRuntimeTypes.DOCKER, ID: "self"}
+	f := &RegexProcessMatchFinder{
+		containerOf: func(pid int32) types.Container {
+			switch pid {
+			case 2:
+				return docker
+			case 3:
+				return self
+			}
+			return types.Container{}
+		},
+		self: self,
+	}
+
+	matches := f.FindMatches(context.Background(), processes, r)
+
+	require.Len(t, matches, 3)
+	require.False(t, matches[0].Container.IsContainerized())
+	require.Equal(t, docker, matches[1].Container)
+	require.False(t, matches[2].Container.IsContainerized())
+}
@@ -0,0 +1,52 @@
+package types
+
+// OpenInstallationContainerRuntime - Container runtime a process runs in
+type OpenInstallationContainerRuntime string
+
+var OpenInstallationContainerRuntimeTypes = struct {
+	// Not running in a container
+	NONE OpenInstallationContainerRuntime
+	// containerd, including Kubernetes nodes using the containerd CRI plugin
+	CONTAINERD OpenInstallationContainerRuntime
+	// CRI-O
+	CRIO OpenInstallationContainerRuntime
+	// Docker
+	DOCKER OpenInstallationContainerRuntime
+	// Podman
+	PODMAN OpenInstallationContainerRuntime
+}{
+	NONE:       "NONE",
+	CONTAINERD: "CONTAINERD",
+	CRIO:       "CRIO",
+	DOCKER:     "DOCKER",
+	PODMAN:     "PODMAN",
+}
+
+// OpenInstallationOrchestrator - Orchestrator managing a host or container
+type OpenInstallationOrchestrator string
+
+var OpenInstallationOrchestratorTypes = struct {
+	// Not managed by an orchestrator
+	NONE OpenInstallationOrchestrator
+	// Amazon Elastic Container Service
+	ECS OpenInstallationOrchestrator
+	// Kubernetes
+	KUBERNETES OpenInstallationOrchestrator
+}{
+	NONE:       "NONE",
+	ECS:        "ECS",
+	KUBERNETES: "KUBERNETES",
+}
+
+// Container identifies the container a process runs in.  The zero value is a
+// process running directly on the host.
+type Container struct {
+	Runtime      OpenInstallationContainerRuntime `json:"runtime,omitempty"`
+	ID           string                           `json:"id,omitempty"`
+	Orchestrator OpenInstallationOrchestrator     `json:"orchestrator,omitempty"`
+}
+
+// IsContainerized returns true when the process runs in a container.
+func (c Container) IsContainerized() bool {
+	return c.Runtime != "" || c.ID != ""
+}
@@ -16,6 +16,10 @@ type DiscoveryManifest struct {
 	PlatformFamily  string `json:"platformFamily"`
 	PlatformVersion string `json:"platformVersion"`
 	IsUnsupported   bool   `json:"isUnsupported"`
+	// ContainerRuntime is the container runtime the CLI runs in, empty on a host.
+	ContainerRuntime string `json:"containerRuntime,omitempty"`
+	// Orchestrator manages the host, or the container the CLI runs in.
+	Orchestrator string `json:"orchestrator,omitempty"`
 }
 
 // GenericProcess is an abstracted representation of a process.
@@ -29,6 +33,9 @@ type MatchedProcess struct {
 	GenericProcess
 	MatchingPattern string
 	MatchingRecipe  OpenInstallationRecipe
+	// Container is set when the process runs in a container other than the
+	// one the CLI runs in.
+	Container Container
 }
 
 func (d *DiscoveryManifest) ConstrainRecipes(allRecipes []OpenInstallationRecipe) []OpenInstallationRecipe {
@@ -18,6 +18,8 @@ var (
 	ErrLicenseKey             = errors.New("the configured license key is invalid for the configured account. Please set a valid license key with the `newrelic profile` command. For more details visit https://docs.newrelic.com/docs/apis/intro-apis/new-relic-api-keys/#ingest-license-key")
 	ErrAgentControl           = errors.New("agent control is installed, preventing the installation of this recipe")
 	ErrNoRecipesInstalled     = errors.New("no recipes were installed")
+	ErrInvalidAnswers         = errors.New("invalid install answers")
+	ErrRecipeDependencies     = errors.New("invalid recipe dependencies")
 )
 
 type EventType string
@@ -41,6 +43,8 @@ var EventTypes = struct {
 	UnableToLocatePostedData   EventType
 	InvalidUserAPIKeyFormat    EventType
 	InvalidRegion              EventType
+	RecipeUnsigned             EventType
+	RecipeSignatureInvalid     EventType
 }{
 	InstallStarted:             "InstallStarted",
 	AccountIDMissing:           "AccountIDMissing",
@@ -60,6 +64,8 @@ var EventTypes = struct {
 	OtherError:                 "OtherError",
 	InvalidUserAPIKeyFormat:    "InvalidUserAPIKeyFormat",
 	InvalidRegion:              "InvalidRegion",
+	RecipeUnsigned:             "RecipeUnsigned",
+	RecipeSignatureInvalid:     "RecipeSignatureInvalid",
 }
 
 func TryParseEventType(e string) (EventType, bool) {
@@ -94,6 +100,10 @@ func TryParseEventType(e string) (EventType, bool) {
 		return EventTypes.InvalidUserAPIKeyFormat, true
 	case "InvalidRegion":
 		return EventTypes.InvalidRegion, true
+	case "RecipeUnsigned":
+		return EventTypes.RecipeUnsigned, true
+	case "RecipeSignatureInvalid":
+		return EventTypes.RecipeSignatureInvalid, true
 	}
 
 	return "", false
@@ -21,7 +21,28 @@ type InstallerContext struct {
 	RecipePaths []string
 	// LocalRecipes is the path to a local recipe directory from which to load recipes.
 	LocalRecipes string
-	deployedBy   string
+	// Answers holds recipe input variable values read from an answers file.
+	Answers RecipeAnswers
+	// NoRollback leaves the completed steps of a failed recipe in place instead
+	// of running the recipe's uninstall tasks.
+	NoRollback bool
+	// OfflineBundle is the directory an offline bundle was extracted into.
+	// Recipes and their artifacts are loaded from it and nothing is sent to
+	// New Relic during the install.
+	OfflineBundle string
+	// OfflineBundleSignature is the result of verifying the signature of the
+	// offline bundle, reported for each recipe loaded from it.
+	OfflineBundleSignature *RecipeSignature
+	// RecipeSigningKey is the public key verifying the signatures of recipes
+	// loaded from local or remote files and of offline bundles.
+	RecipeSigningKey string
+	// AllowUnsigned installs recipes without a signature, or that cannot be
+	// verified as no signing key is configured.  Invalid signatures always fail.
+	AllowUnsigned bool
+	// Parallelism is the number of recipes installed at the same time, once the
+	// recipes they depend on are installed.
+	Parallelism int
+	deployedBy  string
 }
 
 func (i *InstallerContext) RecipePathsProvided() bool {
@@ -32,6 +53,10 @@ func (i *InstallerContext) RecipeNamesProvided() bool {
 	return len(i.RecipeNames) > 0
 }
 
+func (i *InstallerContext) IsOffline() bool {
+	return i.OfflineBundle != ""
+}
+
 func (i *InstallerContext) IsRecipeTargeted(name string) bool {
 	for _, r := range i.RecipeNames {
 		if r == name {
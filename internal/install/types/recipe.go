@@ -1,6 +1,7 @@
 package types
 
 import (
+	"encoding/json"
 	"fmt"
 	"strconv"
 	"strings"
@@ -42,12 +43,18 @@ func (r *OpenInstallationRecipe) UnmarshalYAML(unmarshal func(interface{}) error
 	r.ID = toStringByFieldName("id", recipe)
 	r.InputVars = expandInputVars(recipe)
 
-	installAsString, err := expandInstalllMapToString(recipe)
+	installAsString, err := expandTaskfileToString("install", recipe)
 	if err != nil {
 		return err
 	}
 	r.Install = installAsString
 
+	uninstallAsString, err := expandTaskfileToString("uninstall", recipe)
+	if err != nil {
+		return err
+	}
+	r.Uninstall = uninstallAsString
+
 	r.InstallTargets = expandInstallTargets(recipe)
 
 	if v, ok := recipe["keywords"]; ok {
@@ -87,6 +94,42 @@ func (r *OpenInstallationRecipe) UnmarshalYAML(unmarshal func(interface{}) error
 	return err
 }
 
+// MarshalYAML writes OpenInstallationRecipe.Install and Uninstall as taskfile
+// definitions rather than strings, so that a marshaled recipe can be read back
+// as a recipe file.
+func (r OpenInstallationRecipe) MarshalYAML() (interface{}, error) {
+	// Recipe fields are tagged for JSON only.
+	data, err := json.Marshal(r)
+	if err != nil {
+		return nil, err
+	}
+
+	recipe := map[string]interface{}{}
+	if err = json.Unmarshal(data, &recipe); err != nil {
+		return nil, err
+	}
+
+	for k, v := range recipe {
+		if v == nil || v == "" {
+			delete(recipe, k)
+		}
+	}
+
+	for field, taskfile := range map[string]string{"install": r.Install, "uninstall": r.Uninstall} {
+		if taskfile == "" {
+			continue
+		}
+
+		tasks := yaml.MapSlice{}
+		if err = yaml.Unmarshal([]byte(taskfile), &tasks); err != nil {
+			return nil, fmt.Errorf("recipe.%s must be a taskfile definition: %s", field, err)
+		}
+		recipe[field] = tasks
+	}
+
+	return recipe, nil
+}
+
 func (r *OpenInstallationRecipe) ToShortDisplayString() string {
 	output := r.Name
 	targets := ""
@@ -183,6 +226,14 @@ func expandInstallTargets(recipe map[string]interface{}) []OpenInstallationRecip
 			vOut.Type = OpenInstallationTargetType(v.(string))
 		}
 
+		if v, ok := v["containerRuntime"]; ok {
+			vOut.ContainerRuntime = OpenInstallationContainerRuntime(v.(string))
+		}
+
+		if v, ok := v["orchestrator"]; ok {
+			vOut.Orchestrator = OpenInstallationOrchestrator(v.(string))
+		}
+
 		dataOut[i] = vOut
 	}
 
@@ -361,24 +412,28 @@ func toStringByFieldName(fieldName string, data map[string]interface{}) string {
 	return out
 }
 
-func expandInstalllMapToString(recipeIn map[string]interface{}) (string, error) {
-	installIn, ok := recipeIn["install"]
+// expandTaskfileToString returns the go-task taskfile in the named recipe field as a YAML string.
+func expandTaskfileToString(field string, recipeIn map[string]interface{}) (string, error) {
+	taskfileIn, ok := recipeIn[field]
 	if !ok {
 		return "", nil
 	}
 
-	installOut := map[string]interface{}{}
-	installMap := installIn.(map[interface{}]interface{})
-	for k, v := range installMap {
-		installOut[k.(string)] = v
+	taskfileOut := map[string]interface{}{}
+	taskfileMap, ok := taskfileIn.(map[interface{}]interface{})
+	if !ok {
+		return "", fmt.Errorf("recipe.%s must be a taskfile definition", field)
+	}
+	for k, v := range taskfileMap {
+		taskfileOut[k.(string)] = v
 	}
 
-	installAsString, err := yaml.Marshal(installOut)
+	taskfileAsString, err := yaml.Marshal(taskfileOut)
 	if err != nil {
-		return "", fmt.Errorf("error unmarshaling recipe.install to string: %s", err)
+		return "", fmt.Errorf("error unmarshaling recipe.%s to string: %s", field, err)
 	}
 
-	return string(installAsString), nil
+	return string(taskfileAsString), nil
 }
 
 func interfaceSliceToStringSlice(slice []interface{}) []string {
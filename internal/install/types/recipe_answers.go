@@ -0,0 +1,217 @@
+package types
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/ghodss/yaml"
+	log "github.com/sirupsen/logrus"
+)
+
+// RecipeAnswers holds values for recipe input variables, keyed by recipe name
+// and then by variable name.  They are read from an install answers file so
+// that recipes can be installed without prompting.
+type RecipeAnswers map[string]map[string]string
+
+// answerSource is the object form of an answers file value, used for secrets
+// that should not be written into the file itself.
+type answerSource struct {
+	Value    *string `json:"value,omitempty"`
+	FromEnv  string  `json:"fromEnv,omitempty"`
+	FromFile string  `json:"fromFile,omitempty"`
+}
+
+// ReadRecipeAnswers reads a YAML or JSON answers file.  The file maps recipe
+// names to input variable values.  A value is either a scalar or an object with
+// one of `value`, `fromEnv` naming an environment variable, or `fromFile`
+// naming a file whose contents are the value.  Relative file paths are
+// resolved from the directory of the answers file.
+//
+//	mysql-open-source-integration:
+//	  NR_CLI_DB_USERNAME: newrelic
+//	  NR_CLI_DB_PASSWORD:
+//	    fromEnv: MYSQL_PASSWORD
+func ReadRecipeAnswers(path string) (RecipeAnswers, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	j, err := yaml.YAMLToJSON(data)
+	if err != nil {
+		return nil, fmt.Errorf("could not parse answers file %s: %s", path, err)
+	}
+
+	raw := map[string]map[string]json.RawMessage{}
+	if err = json.Unmarshal(j, &raw); err != nil {
+		return nil, fmt.Errorf("could not parse answers file %s, it must map recipe names to input variable values: %s", path, err)
+	}
+
+	answers := RecipeAnswers{}
+	problems := []string{}
+
+	for recipeName, values := range raw {
+		answers[recipeName] = map[string]string{}
+
+		for name, v := range values {
+			value, err := resolveAnswer(v, filepath.Dir(path))
+			if err != nil {
+				problems = append(problems, fmt.Sprintf("  %s %s: %s", recipeName, name, err))
+				continue
+			}
+
+			answers[recipeName][name] = value
+		}
+	}
+
+	if len(problems) > 0 {
+		sort.Strings(problems)
+		return nil, fmt.Errorf("%w in %s:\n%s", ErrInvalidAnswers, path, strings.Join(problems, "\n"))
+	}
+
+	return answers, nil
+}
+
+func resolveAnswer(raw json.RawMessage, dir string) (string, error) {
+	if bytes.HasPrefix(bytes.TrimSpace(raw), []byte("{")) {
+		var src answerSource
+		if err := json.Unmarshal(raw, &src); err != nil {
+			return "", err
+		}
+
+		return src.resolve(dir)
+	}
+
+	var v interface{}
+	if err := json.Unmarshal(raw, &v); err != nil {
+		return "", err
+	}
+
+	switch t := v.(type) {
+	case string:
+		return t, nil
+	case bool:
+		return strconv.FormatBool(t), nil
+	case float64:
+		return strconv.FormatFloat(t, 'f', -1, 64), nil
+	case nil:
+		return "", fmt.Errorf("value cannot be empty")
+	}
+
+	return "", fmt.Errorf("value must be a string, number, boolean, or an object with value, fromEnv, or fromFile")
+}
+
+func (s answerSource) resolve(dir string) (string, error) {
+	set := 0
+	for _, isSet := range []bool{s.Value != nil, s.FromEnv != "", s.FromFile != ""} {
+		if isSet {
+			set++
+		}
+	}
+	if set != 1 {
+		return "", fmt.Errorf("exactly one of value, fromEnv, or fromFile must be given")
+	}
+
+	switch {
+	case s.Value != nil:
+		return *s.Value, nil
+	case s.FromEnv != "":
+		value, ok := os.LookupEnv(s.FromEnv)
+		if !ok {
+			return "", fmt.Errorf("environment variable %s is not set", s.FromEnv)
+		}
+		return value, nil
+	}
+
+	path := s.FromFile
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(dir, path)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+
+	return strings.TrimRight(string(data), "\r\n"), nil
+}
+
+// Get returns the answer for a recipe's input variable.
+func (a RecipeAnswers) Get(recipeName string, name string) (string, bool) {
+	value, ok := a[recipeName][name]
+	return value, ok
+}
+
+// Validate checks the answers against the input variables the recipes
+// declare.  Answers for variables a recipe does not declare are an error, as
+// are, when nothing will be prompted for, selected recipes' input variables
+// without an answer, environment variable, or default.  Answers for recipes
+// that are not in known are ignored with a warning, so one file can serve
+// hosts with different recipes available, unless the recipe is one of the
+// targeted recipe names, which is most likely a typo in the file.
+func (a RecipeAnswers) Validate(known []*OpenInstallationRecipe, selected []*OpenInstallationRecipe, targeted []string, assumeYes bool) error {
+	byName := map[string]*OpenInstallationRecipe{}
+	for _, r := range known {
+		byName[r.Name] = r
+	}
+
+	isTargeted := map[string]bool{}
+	for _, name := range targeted {
+		isTargeted[name] = true
+	}
+
+	problems := []string{}
+	for recipeName, values := range a {
+		r, ok := byName[recipeName]
+		if !ok {
+			if isTargeted[recipeName] {
+				problems = append(problems, fmt.Sprintf("  %s is not a recipe available for this host", recipeName))
+			} else {
+				log.Warnf("ignoring the answers for %s, which is not a recipe available for this host", recipeName)
+			}
+			continue
+		}
+
+		for name := range values {
+			if !r.HasInputVar(name) {
+				problems = append(problems, fmt.Sprintf("  %s does not have an input variable named %s", recipeName, name))
+			}
+		}
+	}
+	sort.Strings(problems)
+
+	if assumeYes {
+		for _, r := range selected {
+			for _, v := range r.InputVars {
+				if _, ok := a.Get(r.Name, v.Name); ok || v.Default != "" || os.Getenv(v.Name) != "" {
+					continue
+				}
+
+				problems = append(problems, fmt.Sprintf("  %s requires a value for %s", r.Name, v.Name))
+			}
+		}
+	}
+
+	if len(problems) > 0 {
+		return fmt.Errorf("%w:\n%s", ErrInvalidAnswers, strings.Join(problems, "\n"))
+	}
+
+	return nil
+}
+
+// HasInputVar reports whether the recipe declares an input variable with the given name.
+func (r *OpenInstallationRecipe) HasInputVar(name string) bool {
+	for _, v := range r.InputVars {
+		if v.Name == name {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,140 @@
+package types
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/require"
+)
+
+func writeAnswersFile(t *testing.T, dir string, content string) string {
+	path := filepath.Join(dir, "answers.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(content), 0600))
+	return path
+}
+
+func TestReadRecipeAnswers(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "key.pem"), []byte("secret-key\n"), 0600))
+	t.Setenv("TEST_ANSWERS_PASSWORD", "hunter2")
+
+	path := writeAnswersFile(t, dir, `
+mysql-open-source-integration:
+  NR_CLI_DB_HOSTNAME: localhost
+  NR_CLI_DB_PORT: 3306
+  NR_CLI_SSL: true
+  NR_CLI_DB_PASSWORD:
+    fromEnv: TEST_ANSWERS_PASSWORD
+  NR_CLI_SSL_KEY:
+    fromFile: key.pem
+  NR_CLI_DB_USERNAME:
+    value: newrelic
+`)
+
+	answers, err := ReadRecipeAnswers(path)
+
+	require.NoError(t, err)
+	require.Equal(t, RecipeAnswers{
+		"mysql-open-source-integration": {
+			"NR_CLI_DB_HOSTNAME": "localhost",
+			"NR_CLI_DB_PORT":     "3306",
+			"NR_CLI_SSL":         "true",
+			"NR_CLI_DB_PASSWORD": "hunter2",
+			"NR_CLI_SSL_KEY":     "secret-key",
+			"NR_CLI_DB_USERNAME": "newrelic",
+		},
+	}, answers)
+}
+
+func TestReadRecipeAnswersShouldReportEveryInvalidValue(t *testing.T) {
+	dir := t.TempDir()
+	os.Unsetenv("TEST_ANSWERS_MISSING")
+
+	path := writeAnswersFile(t, dir, `
+mysql-open-source-integration:
+  NR_CLI_DB_PASSWORD:
+    fromEnv: TEST_ANSWERS_MISSING
+  NR_CLI_DB_USERNAME:
+    value: newrelic
+    fromEnv: USER
+  NR_CLI_DB_PORT:
+`)
+
+	_, err := ReadRecipeAnswers(path)
+
+	require.Error(t, err)
+	require.True(t, errors.Is(err, ErrInvalidAnswers))
+	require.Contains(t, err.Error(), "NR_CLI_DB_PASSWORD: environment variable TEST_ANSWERS_MISSING is not set")
+	require.Contains(t, err.Error(), "NR_CLI_DB_USERNAME: exactly one of value, fromEnv, or fromFile must be given")
+	require.Contains(t, err.Error(), "NR_CLI_DB_PORT: value cannot be empty")
+}
+
+func TestReadRecipeAnswersShouldRejectOtherLayouts(t *testing.T) {
+	path := writeAnswersFile(t, t.TempDir(), "- mysql-open-source-integration\n")
+
+	_, err := ReadRecipeAnswers(path)
+
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "it must map recipe names to input variable values")
+}
+
+func TestRecipeAnswersValidate(t *testing.T) {
+	mysql := &OpenInstallationRecipe{
+		Name: "mysql-open-source-integration",
+		InputVars: []OpenInstallationRecipeInputVariable{
+			{Name: "NR_CLI_DB_USERNAME"},
+			{Name: "NR_CLI_DB_PASSWORD", Secret: true},
+			{Name: "NR_CLI_DB_PORT", Default: "3306"},
+		},
+	}
+	known := []*OpenInstallationRecipe{mysql}
+	os.Unsetenv("NR_CLI_DB_PASSWORD")
+
+	answers := RecipeAnswers{
+		"mysql-open-source-integration": {"NR_CLI_DB_USERNAME": "newrelic", "NR_CLI_DB_PASSWORD": "hunter2"},
+		"windows-only-recipe":           {"ANYTHING": "value"},
+	}
+	require.NoError(t, answers.Validate(known, known, nil, true))
+
+	answers = RecipeAnswers{
+		"mysql-open-source-integration": {"NR_CLI_DB_USERNAME": "newrelic", "NR_CLI_DB_USER": "typo"},
+	}
+	err := answers.Validate(known, known, nil, false)
+	require.True(t, errors.Is(err, ErrInvalidAnswers))
+	require.Contains(t, err.Error(), "mysql-open-source-integration does not have an input variable named NR_CLI_DB_USER")
+	require.NotContains(t, err.Error(), "requires a value")
+
+	err = answers.Validate(known, known, nil, true)
+	require.Contains(t, err.Error(), "mysql-open-source-integration requires a value for NR_CLI_DB_PASSWORD")
+	require.NotContains(t, err.Error(), "NR_CLI_DB_PORT")
+
+	t.Setenv("NR_CLI_DB_PASSWORD", "from-env")
+	answers = RecipeAnswers{}
+	err = answers.Validate(known, known, nil, true)
+	require.Contains(t, err.Error(), "requires a value for NR_CLI_DB_USERNAME")
+	require.NotContains(t, err.Error(), "NR_CLI_DB_PASSWORD")
+}
+
+func TestRecipeAnswersValidateShouldReportUnknownRecipes(t *testing.T) {
+	mysql := &OpenInstallationRecipe{Name: "mysql-open-source-integration", InputVars: []OpenInstallationRecipeInputVariable{{Name: "NR_CLI_DB_USERNAME"}}}
+	known := []*OpenInstallationRecipe{mysql}
+	answers := RecipeAnswers{
+		"mysql-open-source-integraton": {"NR_CLI_DB_USERNAME": "newrelic"},
+	}
+
+	hook := test.NewGlobal()
+	defer hook.Reset()
+
+	require.NoError(t, answers.Validate(known, known, nil, false))
+	require.Len(t, hook.Entries, 1)
+	require.Equal(t, logrus.WarnLevel, hook.LastEntry().Level)
+	require.Contains(t, hook.LastEntry().Message, "mysql-open-source-integraton")
+
+	err := answers.Validate(known, known, []string{"mysql-open-source-integraton"}, false)
+	require.True(t, errors.Is(err, ErrInvalidAnswers))
+	require.Contains(t, err.Error(), "mysql-open-source-integraton is not a recipe available for this host")
+}
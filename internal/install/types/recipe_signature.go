@@ -0,0 +1,58 @@
+package types
+
+import "sync"
+
+// RecipeSignatureStatus - Result of verifying the detached signature of a recipe
+type RecipeSignatureStatus string
+
+var RecipeSignatureStatuses = struct {
+	// The signature was verified with the signing key of the profile
+	VERIFIED RecipeSignatureStatus
+	// The recipe has a signature, but no signing key is configured to verify it
+	UNVERIFIED RecipeSignatureStatus
+	// The recipe has no signature
+	UNSIGNED RecipeSignatureStatus
+}{
+	VERIFIED:   "VERIFIED",
+	UNVERIFIED: "UNVERIFIED",
+	UNSIGNED:   "UNSIGNED",
+}
+
+// RecipeSignature is the result of verifying the detached signature of a
+// recipe file, or of the offline bundle it was loaded from.
+type RecipeSignature struct {
+	Status RecipeSignatureStatus `json:"status"`
+	// KeyID identifies the key the signature was verified with.
+	KeyID string `json:"keyId,omitempty"`
+}
+
+// The signatures are runtime state, kept apart from the recipe types so they
+// stay free of fields that are not in the recipe schema.  They are keyed by
+// recipe name, as the installer passes recipes around by value.
+var recipeSignatures = struct {
+	sync.RWMutex
+	byName map[string]*RecipeSignature
+}{byName: map[string]*RecipeSignature{}}
+
+// SetSignature records the result of verifying the signature of the recipe.
+// A nil signature clears any recorded result.
+func (r *OpenInstallationRecipe) SetSignature(s *RecipeSignature) {
+	recipeSignatures.Lock()
+	defer recipeSignatures.Unlock()
+
+	if s == nil {
+		delete(recipeSignatures.byName, r.Name)
+		return
+	}
+
+	recipeSignatures.byName[r.Name] = s
+}
+
+// Signature returns the result of verifying the signature of the recipe, or
+// nil if it was not loaded from a file.
+func (r *OpenInstallationRecipe) Signature() *RecipeSignature {
+	recipeSignatures.RLock()
+	defer recipeSignatures.RUnlock()
+
+	return recipeSignatures.byName[r.Name]
+}
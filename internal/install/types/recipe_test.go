@@ -5,6 +5,7 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v2"
 )
 
 func TestToStringByFieldName(t *testing.T) {
@@ -113,3 +114,76 @@ func Test_shouldExpandDiscoveryMode(t *testing.T) {
 	dm = expandDiscoveryMode(m)
 	require.Equal(t, 1, len(dm), "One good value should be parsed")
 }
+
+func Test_shouldUnmarshalUninstall(t *testing.T) {
+	recipe := OpenInstallationRecipe{}
+	err := yaml.Unmarshal([]byte(`
+name: test-recipe
+install:
+  version: "3"
+  tasks:
+    default:
+      cmds:
+        - echo install
+uninstall:
+  version: "3"
+  tasks:
+    default:
+      cmds:
+        - echo uninstall
+`), &recipe)
+
+	require.NoError(t, err)
+	require.Contains(t, recipe.Install, "echo install")
+	require.Contains(t, recipe.Uninstall, "echo uninstall")
+
+	err = yaml.Unmarshal([]byte("name: test-recipe\nuninstall: echo uninstall\n"), &recipe)
+	require.EqualError(t, err, "recipe.uninstall must be a taskfile definition")
+}
+
+func Test_shouldMarshalRecipeFile(t *testing.T) {
+	in := OpenInstallationRecipe{}
+	err := yaml.Unmarshal([]byte(`
+name: test-recipe
+displayName: Test Recipe
+processMatch:
+  - testd
+installTargets:
+  - type: host
+    os: linux
+    kernelArch: x86_64
+inputVars:
+  - name: TEST_PORT
+    default: 8080
+preInstall:
+  requireAtDiscovery: exit 0
+install:
+  version: "3"
+  tasks:
+    default:
+      cmds:
+        - curl -o /tmp/testd.tar.gz https://download.newrelic.com/testd.tar.gz
+validationNrql: SELECT count(*) FROM TestSample
+`), &in)
+	require.NoError(t, err)
+
+	data, err := yaml.Marshal(in)
+	require.NoError(t, err)
+
+	out := OpenInstallationRecipe{}
+	require.NoError(t, yaml.Unmarshal(data, &out))
+	require.Equal(t, in, out)
+	require.NotContains(t, string(data), "uninstall")
+}
+
+func TestRecipeSignatureShouldFollowRecipeCopies(t *testing.T) {
+	r := OpenInstallationRecipe{Name: "signed-recipe"}
+	require.Nil(t, r.Signature())
+
+	r.SetSignature(&RecipeSignature{Status: RecipeSignatureStatuses.VERIFIED})
+	copied := r
+	require.Equal(t, RecipeSignatureStatuses.VERIFIED, copied.Signature().Status)
+
+	r.SetSignature(nil)
+	require.Nil(t, copied.Signature())
+}
@@ -0,0 +1,75 @@
+package types
+
+// The recipe types are written by hand rather than generated with tutone, as
+// recipe files have fields the recipe schema does not.  .tutone.yml skips
+// creating them.
+
+// OpenInstallationRecipe - Installation instructions and definition of an instrumentation integration
+type OpenInstallationRecipe struct {
+	// Named list of dependencies for this recipe
+	Dependencies []string `json:"dependencies"`
+	// Description of the recipe
+	Description string `json:"description"`
+	// Friendly name of the integration
+	DisplayName string `json:"displayName,omitempty"`
+	// The full contents of the recipe file (yaml)
+	File string `json:"file"`
+	// The ID
+	ID string `json:"id,omitempty"`
+	// List of variables to prompt for input from the user
+	InputVars []OpenInstallationRecipeInputVariable `json:"inputVars"`
+	// Go-task's taskfile definition (see https://taskfile.dev/#/usage)
+	Install string `json:"install"`
+	// Optional go-task taskfile definition that reverses the install.  Tasks
+	// named after install tasks are used to roll back a failed install.
+	Uninstall string `json:"uninstall,omitempty"`
+	// Object representing the intended install target
+	InstallTargets []OpenInstallationRecipeInstallTarget `json:"installTargets"`
+	// Tags
+	Keywords []string `json:"keywords"`
+	// # Partial list of possible Log forwarding parameters
+	LogMatch []OpenInstallationLogMatch `json:"logMatch"`
+	// Short unique handle for the name of the integration
+	Name string `json:"name,omitempty"`
+	// Object representing optional post-install configuration items
+	PostInstall OpenInstallationPostInstallConfiguration `json:"postInstall,omitempty"`
+	// Object representing optional pre-install configuration items
+	PreInstall OpenInstallationPreInstallConfiguration `json:"preInstall,omitempty"`
+	// List of process definitions used to match CLI process detection
+	ProcessMatch []string `json:"processMatch"`
+	// Github repository url
+	Repository string `json:"repository"`
+	// Indicates stability level of recipe
+	Stability OpenInstallationStability `json:"stability,omitempty"`
+	// Metadata to support generating a URL after installation success
+	SuccessLinkConfig OpenInstallationSuccessLinkConfig `json:"successLinkConfig,omitempty"`
+	// NRQL the newrelic-cli uses to validate this recipe
+	// is successfully sending data to New Relic
+	ValidationNRQL NRQL `json:"validationNrql,omitempty"`
+	// validation url to validate with infra health endpoint
+	ValidationURL string `json:"validationUrl,omitempty"`
+	// integration name to validate with local validation
+	ValidationIntegration string `json:"validationIntegration,omitempty"`
+}
+
+// OpenInstallationRecipeInstallTarget - Matrix of supported installation criteria for this recipe
+type OpenInstallationRecipeInstallTarget struct {
+	// OS kernel architecture
+	KernelArch string `json:"kernelArch,omitempty"`
+	// OS kernel version
+	KernelVersion string `json:"kernelVersion,omitempty"`
+	// Operating system
+	Os OpenInstallationOperatingSystem `json:"os,omitempty"`
+	// Operating System distribution
+	Platform OpenInstallationPlatform `json:"platform,omitempty"`
+	// Operating System distribution family
+	PlatformFamily OpenInstallationPlatformFamily `json:"platformFamily,omitempty"`
+	// OS distribution version
+	PlatformVersion string `json:"platformVersion,omitempty"`
+	// Target type
+	Type OpenInstallationTargetType `json:"type,omitempty"`
+	// Container runtime the CLI runs in, NONE for a host
+	ContainerRuntime OpenInstallationContainerRuntime `json:"containerRuntime,omitempty"`
+	// Orchestrator managing the host or container, NONE when not orchestrated
+	Orchestrator OpenInstallationOrchestrator `json:"orchestrator,omitempty"`
+}
@@ -0,0 +1,49 @@
+package ux
+
+import (
+	"sync"
+)
+
+// ConcurrentProgress prints the progress of recipes installed concurrently as
+// plain lines, one at a time, since a spinner can only show a single recipe.
+type ConcurrentProgress struct {
+	mu       sync.Mutex
+	progress *PlainProgress
+}
+
+func NewConcurrentProgress() *ConcurrentProgress {
+	p := ConcurrentProgress{
+		progress: NewPlainProgress(),
+	}
+
+	return &p
+}
+
+func (p *ConcurrentProgress) Start(msg string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.progress.Start(msg)
+}
+
+func (p *ConcurrentProgress) Success(msg string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.progress.Success(msg)
+}
+
+func (p *ConcurrentProgress) Fail(msg string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.progress.Fail(msg)
+}
+
+func (p *ConcurrentProgress) Canceled(msg string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.progress.Canceled(msg)
+}
+
+func (p *ConcurrentProgress) Stop() {}
+
+func (p *ConcurrentProgress) ShowSpinner(ss bool) {
+}
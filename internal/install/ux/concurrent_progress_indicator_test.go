@@ -0,0 +1,12 @@
+package ux
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestConcurrentProgressIndicator_interface(t *testing.T) {
+	var r ProgressIndicator = NewConcurrentProgress()
+	require.NotNil(t, r)
+}
@@ -8,7 +8,8 @@ var cmdNRQLDropRules = &cobra.Command{
 	Use:   "nrqldroprules",
 	Short: "NRQL drop rules migration utilities",
 	Long: `Commands for migrating and managing NRQL drop rules during platform transitions.
-These utilities help with updating Terraform configurations and validating migrations.`,
+These utilities help with updating Terraform configurations and validating migrations,
+and with converting drop rules not managed with Terraform to Pipeline Cloud Rules.`,
 }
 
 func init() {
@@ -0,0 +1,120 @@
+package migrate
+
+import (
+	"os"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+
+	"github.com/newrelic/newrelic-cli/internal/client"
+	configAPI "github.com/newrelic/newrelic-cli/internal/config/api"
+	"github.com/newrelic/newrelic-cli/internal/utils"
+)
+
+var (
+	convertMappingFile  string
+	convertRollbackFile string
+	convertDeleteLegacy bool
+	convertRollback     bool
+	convertDryRun       bool
+)
+
+var cmdNRQLDropRulesConvert = &cobra.Command{
+	Use:   "convert",
+	Short: "Convert NRQL drop rules to Pipeline Cloud Rules without Terraform",
+	Long: `Convert NRQL drop rules to Pipeline Cloud Rules without Terraform
+
+The convert command migrates drop rules created in the New Relic UI or through
+the API, which are not managed with Terraform.  It creates a Pipeline Cloud Rule
+for each drop rule of the account, and records the conversion in the mapping
+file.  The NRQL of each rule created is read back and verified against the drop
+rule: it must delete from the events the drop rule selects, and delete the
+whole events for drop rules dropping data, or the attributes the drop rule
+selects for drop rules dropping attributes.  Running the command again converts
+the drop rules not converted yet, and verifies again the rules whose
+verification failed.
+
+The drop rules are kept until the command is run with --deleteLegacy, which
+deletes the drop rules converted to verified Pipeline Cloud Rules.  The deleted
+drop rules are recorded in the rollback file first, and --rollback recreates
+them.
+
+Drop rules dropping attributes from metric aggregates have no Pipeline Cloud
+Rule equivalent and are skipped.
+`,
+	Example: `  # Show the Pipeline Cloud Rules that would be created
+  newrelic migrate nrqldroprules convert --accountId 12345 --dryRun
+
+  # Create the Pipeline Cloud Rules
+  newrelic migrate nrqldroprules convert --accountId 12345
+
+  # Delete the converted drop rules once the Pipeline Cloud Rules are checked
+  newrelic migrate nrqldroprules convert --accountId 12345 --deleteLegacy
+
+  # Recreate the deleted drop rules
+  newrelic migrate nrqldroprules convert --accountId 12345 --rollback`,
+	PreRun: client.RequireClient,
+	Run: func(cmd *cobra.Command, args []string) {
+		accountID := configAPI.RequireActiveProfileAccountID()
+
+		m := &dropRuleMigration{
+			client:       client.NRClient,
+			accountID:    accountID,
+			mappingPath:  convertMappingFile,
+			rollbackPath: convertRollbackFile,
+			out:          os.Stdout,
+		}
+
+		if convertRollback {
+			utils.LogIfFatal(m.rollback(utils.SignalCtx, convertDryRun))
+			return
+		}
+
+		rules := listLegacyDropRules(accountID)
+
+		if convertDeleteLegacy {
+			utils.LogIfFatal(m.deleteLegacy(utils.SignalCtx, rules, convertDryRun))
+			return
+		}
+
+		utils.LogIfFatal(m.convert(utils.SignalCtx, rules, convertDryRun))
+	},
+}
+
+// listLegacyDropRules returns the drop rules of the account.
+func listLegacyDropRules(accountID int) []legacyDropRule {
+	result, err := client.NRClient.Nrqldroprules.GetList(accountID)
+	utils.LogIfFatal(err)
+
+	rules := []legacyDropRule{}
+	if result == nil {
+		return rules
+	}
+
+	for _, r := range result.Rules {
+		rules = append(rules, legacyDropRule{
+			ID:          r.ID,
+			AccountID:   accountID,
+			NRQL:        r.NRQL,
+			Action:      string(r.Action),
+			Description: r.Description,
+		})
+	}
+
+	if len(rules) == 0 {
+		log.Info("No drop rules found, associated with your account.")
+	}
+
+	return rules
+}
+
+func init() {
+	cmdNRQLDropRules.AddCommand(cmdNRQLDropRulesConvert)
+
+	cmdNRQLDropRulesConvert.Flags().StringVar(&convertMappingFile, "mappingFile", "nrqldroprules-mapping.json", "file recording the Pipeline Cloud Rule each drop rule is converted to")
+	cmdNRQLDropRulesConvert.Flags().StringVar(&convertRollbackFile, "rollbackFile", "nrqldroprules-rollback.json", "file recording the deleted drop rules, to recreate them")
+	cmdNRQLDropRulesConvert.Flags().BoolVar(&convertDeleteLegacy, "deleteLegacy", false, "delete the drop rules converted to verified Pipeline Cloud Rules")
+	cmdNRQLDropRulesConvert.Flags().BoolVar(&convertRollback, "rollback", false, "recreate the drop rules of the rollback file")
+	cmdNRQLDropRulesConvert.Flags().BoolVar(&convertDryRun, "dryRun", false, "show the changes without making them")
+	cmdNRQLDropRulesConvert.MarkFlagsMutuallyExclusive("deleteLegacy", "rollback")
+}
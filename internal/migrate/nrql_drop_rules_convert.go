@@ -0,0 +1,564 @@
+package migrate
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/newrelic/newrelic-client-go/v2/newrelic"
+	"github.com/newrelic/newrelic-client-go/v2/pkg/nrqldroprules"
+)
+
+const createPipelineCloudRuleMutation = `mutation($rule: EntityManagementPipelineCloudRuleEntityCreateInput!) {
+  entityManagementCreatePipelineCloudRule(pipelineCloudRuleEntity: $rule) {
+    entity { id name nrql }
+  }
+}`
+
+const getPipelineCloudRuleQuery = `query($id: ID!) {
+  actor {
+    entityManagement {
+      entity(id: $id) {
+        ... on EntityManagementPipelineCloudRuleEntity { id name nrql }
+      }
+    }
+  }
+}`
+
+// legacyDropRule is an NRQL drop rule, as recorded in the rollback file.
+type legacyDropRule struct {
+	ID          string `json:"id"`
+	AccountID   int    `json:"accountId"`
+	NRQL        string `json:"nrql"`
+	Action      string `json:"action"`
+	Description string `json:"description,omitempty"`
+}
+
+// dropRuleMapping records the Pipeline Cloud Rule a drop rule was converted
+// to, and whether the drop rule was deleted.
+type dropRuleMapping struct {
+	DropRule              legacyDropRule `json:"dropRule"`
+	PipelineCloudRuleID   string         `json:"pipelineCloudRuleId"`
+	PipelineCloudRuleNRQL string         `json:"pipelineCloudRuleNrql"`
+	Verified              bool           `json:"verified"`
+	LegacyDeleted         bool           `json:"legacyDeleted"`
+}
+
+type dropRuleFailure struct {
+	Reason      string
+	Description string
+}
+
+type dropRuleFailures []dropRuleFailure
+
+func (f dropRuleFailures) err() error {
+	if len(f) == 0 {
+		return nil
+	}
+
+	messages := []string{}
+	for _, failure := range f {
+		messages = append(messages, fmt.Sprintf("%s: %s", failure.Reason, failure.Description))
+	}
+
+	return errors.New(strings.Join(messages, ", "))
+}
+
+var dropRuleNRQLRegex = regexp.MustCompile(`(?is)^\s*SELECT\s+(.+?)\s+FROM\s+(.+?)\s*$`)
+
+var pipelineCloudRuleNRQLRegex = regexp.MustCompile(`(?is)^\s*DELETE\s+(?:(.+?)\s+)?FROM\s+(.+?)\s*$`)
+
+// pipelineCloudRuleNRQL returns the NRQL of the Pipeline Cloud Rule
+// equivalent to the drop rule.  Rules dropping data delete the events they
+// select, and rules dropping attributes delete the attributes they select.
+func pipelineCloudRuleNRQL(r legacyDropRule) (string, error) {
+	m := dropRuleNRQLRegex.FindStringSubmatch(r.NRQL)
+	if m == nil {
+		return "", fmt.Errorf("drop rule %s has unsupported NRQL: %s", r.ID, r.NRQL)
+	}
+
+	switch r.Action {
+	case "DROP_DATA":
+		return "DELETE FROM " + m[2], nil
+	case "DROP_ATTRIBUTES":
+		return fmt.Sprintf("DELETE %s FROM %s", m[1], m[2]), nil
+	default:
+		return "", fmt.Errorf("drop rule %s has action %s, which has no Pipeline Cloud Rule equivalent", r.ID, r.Action)
+	}
+}
+
+// nrqlKeywords are the keywords normalized to upper case when comparing NRQL.
+var nrqlKeywords = map[string]bool{
+	"SELECT": true, "DELETE": true, "FROM": true, "WHERE": true, "AND": true, "OR": true, "NOT": true,
+	"IN": true, "LIKE": true, "RLIKE": true, "IS": true, "NULL": true, "AS": true, "TRUE": true, "FALSE": true,
+}
+
+// normalizeNRQL returns the NRQL with keywords in upper case and its tokens
+// separated by single spaces, leaving quoted strings and identifiers as is.
+func normalizeNRQL(nrql string) string {
+	tokens := []string{}
+	var word strings.Builder
+
+	flush := func() {
+		if word.Len() == 0 {
+			return
+		}
+		w := word.String()
+		if nrqlKeywords[strings.ToUpper(w)] {
+			w = strings.ToUpper(w)
+		}
+		tokens = append(tokens, w)
+		word.Reset()
+	}
+
+	runes := []rune(nrql)
+	for i := 0; i < len(runes); i++ {
+		c := runes[i]
+		switch {
+		case c == '\'' || c == '"' || c == '`':
+			flush()
+			end := i + 1
+			for end < len(runes) && runes[end] != c {
+				if runes[end] == '\\' {
+					end++
+				}
+				end++
+			}
+			if end >= len(runes) {
+				end = len(runes) - 1
+			}
+			tokens = append(tokens, string(runes[i:end+1]))
+			i = end
+		case unicode.IsSpace(c):
+			flush()
+		case unicode.IsLetter(c) || unicode.IsDigit(c) || strings.ContainsRune("_.$:*", c):
+			word.WriteRune(c)
+		default:
+			flush()
+			tokens = append(tokens, string(c))
+		}
+	}
+	flush()
+
+	return strings.Join(tokens, " ")
+}
+
+// equivalentNRQL returns whether the Pipeline Cloud Rule NRQL deletes the
+// data the drop rule drops, comparing the events it deletes from with the
+// events the drop rule selects, and the attributes it deletes with the action
+// and the attributes of the drop rule.
+func equivalentNRQL(r legacyDropRule, ruleNRQL string) bool {
+	legacy := dropRuleNRQLRegex.FindStringSubmatch(r.NRQL)
+	rule := pipelineCloudRuleNRQLRegex.FindStringSubmatch(ruleNRQL)
+	if legacy == nil || rule == nil {
+		return false
+	}
+
+	if normalizeNRQL(legacy[2]) != normalizeNRQL(rule[2]) {
+		return false
+	}
+
+	switch r.Action {
+	case "DROP_DATA":
+		return rule[1] == ""
+	case "DROP_ATTRIBUTES":
+		return rule[1] != "" && sameAttributes(legacy[1], rule[1])
+	default:
+		return false
+	}
+}
+
+// sameAttributes returns whether the comma separated lists of attributes have
+// the same attributes, in any order.
+func sameAttributes(a string, b string) bool {
+	attributes := func(list string) []string {
+		names := []string{}
+		for _, name := range strings.Split(list, ",") {
+			names = append(names, normalizeNRQL(name))
+		}
+		sort.Strings(names)
+		return names
+	}
+
+	return strings.Join(attributes(a), ",") == strings.Join(attributes(b), ",")
+}
+
+// dropRuleMigration converts the drop rules of an account to Pipeline Cloud
+// Rules, recording each conversion in the mapping file, and deletes the
+// converted drop rules, recording them in the rollback file.
+type dropRuleMigration struct {
+	client       *newrelic.NewRelic
+	accountID    int
+	mappingPath  string
+	rollbackPath string
+	out          io.Writer
+}
+
+// convert creates a Pipeline Cloud Rule for each drop rule not converted yet,
+// verifying the NRQL of the rule created.  Rules converted by earlier runs
+// whose verification failed are verified again.
+func (m *dropRuleMigration) convert(ctx context.Context, rules []legacyDropRule, dryRun bool) error {
+	mappings := []dropRuleMapping{}
+	if err := readJSONFile(m.mappingPath, &mappings); err != nil {
+		return err
+	}
+
+	converted := map[string]int{}
+	for i, mapping := range mappings {
+		converted[mapping.DropRule.ID] = i
+	}
+
+	for _, r := range rules {
+		i, ok := converted[r.ID]
+		if ok && mappings[i].Verified {
+			fmt.Fprintf(m.out, "= drop rule %s: already converted to Pipeline Cloud Rule %s\n", r.ID, mappings[i].PipelineCloudRuleID)
+			continue
+		}
+
+		ruleNRQL, err := pipelineCloudRuleNRQL(r)
+		if err != nil {
+			log.Warnf("skipping %s", err)
+			continue
+		}
+
+		if dryRun {
+			fmt.Fprintf(m.out, "+ drop rule %s: %s\n", r.ID, ruleNRQL)
+			continue
+		}
+
+		if !ok {
+			id, err := m.createPipelineCloudRule(ctx, r, ruleNRQL)
+			if err != nil {
+				return fmt.Errorf("could not create a Pipeline Cloud Rule for drop rule %s: %w", r.ID, err)
+			}
+
+			// The rule is recorded before it is verified, so that it is not
+			// created again when verification fails.
+			i = len(mappings)
+			mappings = append(mappings, dropRuleMapping{DropRule: r, PipelineCloudRuleID: id})
+			if err := writeJSONFile(m.mappingPath, mappings); err != nil {
+				return err
+			}
+		}
+
+		mapping := &mappings[i]
+		created, err := m.getPipelineCloudRuleNRQL(ctx, mapping.PipelineCloudRuleID)
+		if err != nil {
+			return fmt.Errorf("could not verify Pipeline Cloud Rule %s: %w", mapping.PipelineCloudRuleID, err)
+		}
+		mapping.PipelineCloudRuleNRQL = created
+		mapping.Verified = equivalentNRQL(r, created)
+
+		if err := writeJSONFile(m.mappingPath, mappings); err != nil {
+			return err
+		}
+
+		if mapping.Verified {
+			fmt.Fprintf(m.out, "✓ drop rule %s: converted to Pipeline Cloud Rule %s\n", r.ID, mapping.PipelineCloudRuleID)
+		} else {
+			fmt.Fprintf(m.out, "⚠ drop rule %s: Pipeline Cloud Rule %s has NRQL %s, which differs from the drop rule NRQL %s\n", r.ID, mapping.PipelineCloudRuleID, created, r.NRQL)
+		}
+	}
+
+	return nil
+}
+
+func (m *dropRuleMigration) createPipelineCloudRule(ctx context.Context, r legacyDropRule, ruleNRQL string) (string, error) {
+	name := r.Description
+	if name == "" {
+		name = fmt.Sprintf("NRQL drop rule %s", r.ID)
+	}
+
+	vars := map[string]interface{}{
+		"rule": map[string]interface{}{
+			"name":        name,
+			"description": fmt.Sprintf("Converted from NRQL drop rule %s", r.ID),
+			"nrql":        ruleNRQL,
+			"scope":       map[string]interface{}{"id": strconv.Itoa(m.accountID), "type": "ACCOUNT"},
+		},
+	}
+
+	var resp struct {
+		EntityManagementCreatePipelineCloudRule struct {
+			Entity struct {
+				ID string `json:"id"`
+			} `json:"entity"`
+		} `json:"entityManagementCreatePipelineCloudRule"`
+	}
+
+	if err := m.client.NerdGraph.QueryWithResponseAndContext(ctx, createPipelineCloudRuleMutation, vars, &resp); err != nil {
+		return "", err
+	}
+
+	id := resp.EntityManagementCreatePipelineCloudRule.Entity.ID
+	if id == "" {
+		return "", fmt.Errorf("no Pipeline Cloud Rule returned")
+	}
+
+	return id, nil
+}
+
+func (m *dropRuleMigration) getPipelineCloudRuleNRQL(ctx context.Context, id string) (string, error) {
+	var resp struct {
+		Actor struct {
+			EntityManagement struct {
+				Entity *struct {
+					NRQL string `json:"nrql"`
+				} `json:"entity"`
+			} `json:"entityManagement"`
+		} `json:"actor"`
+	}
+
+	if err := m.client.NerdGraph.QueryWithResponseAndContext(ctx, getPipelineCloudRuleQuery, map[string]interface{}{"id": id}, &resp); err != nil {
+		return "", err
+	}
+
+	if resp.Actor.EntityManagement.Entity == nil {
+		return "", fmt.Errorf("no Pipeline Cloud Rule found with ID %s", id)
+	}
+
+	return resp.Actor.EntityManagement.Entity.NRQL, nil
+}
+
+// deleteLegacy deletes the drop rules converted to verified Pipeline Cloud
+// Rules, recording the definition of the drop rules deleted in the rollback
+// file.
+func (m *dropRuleMigration) deleteLegacy(ctx context.Context, rules []legacyDropRule, dryRun bool) error {
+	mappings := []dropRuleMapping{}
+	if err := readJSONFile(m.mappingPath, &mappings); err != nil {
+		return err
+	}
+	if len(mappings) == 0 {
+		return fmt.Errorf("no converted drop rules found in %s, convert the drop rules first", m.mappingPath)
+	}
+
+	current := map[string]legacyDropRule{}
+	for _, r := range rules {
+		current[r.ID] = r
+	}
+
+	toDelete := []legacyDropRule{}
+	for i, mapping := range mappings {
+		if mapping.LegacyDeleted {
+			continue
+		}
+
+		if !mapping.Verified {
+			log.Warnf("keeping drop rule %s, its Pipeline Cloud Rule %s was not verified", mapping.DropRule.ID, mapping.PipelineCloudRuleID)
+			continue
+		}
+
+		r, ok := current[mapping.DropRule.ID]
+		if !ok {
+			log.Warnf("drop rule %s no longer exists", mapping.DropRule.ID)
+			mappings[i].LegacyDeleted = true
+			continue
+		}
+
+		if dryRun {
+			fmt.Fprintf(m.out, "- drop rule %s: %s\n", r.ID, r.NRQL)
+			continue
+		}
+		toDelete = append(toDelete, r)
+	}
+
+	if dryRun || len(toDelete) == 0 {
+		if !dryRun {
+			fmt.Fprintln(m.out, "no drop rules to delete")
+		}
+		return writeJSONFile(m.mappingPath, mappings)
+	}
+
+	// The drop rules are recorded for the rollback before they are deleted,
+	// so that a rule deleted is never missing from the rollback file.
+	rollback := []legacyDropRule{}
+	if err := readJSONFile(m.rollbackPath, &rollback); err != nil {
+		return err
+	}
+	if err := writeJSONFile(m.rollbackPath, append(append([]legacyDropRule{}, rollback...), toDelete...)); err != nil {
+		return fmt.Errorf("could not record the drop rules to delete in %s: %w", m.rollbackPath, err)
+	}
+
+	ids := []string{}
+	for _, r := range toDelete {
+		ids = append(ids, r.ID)
+	}
+
+	result, err := m.client.Nrqldroprules.NRQLDropRulesDeleteWithContext(ctx, m.accountID, ids)
+	if err != nil {
+		// Which drop rules were deleted is unknown, so they stay recorded.
+		return err
+	}
+
+	deleted := map[string]bool{}
+	for _, s := range result.Successes {
+		deleted[s.ID] = true
+	}
+
+	// The drop rules not deleted still exist, and are removed from the
+	// rollback file.
+	for _, r := range toDelete {
+		if deleted[r.ID] {
+			rollback = append(rollback, r)
+			fmt.Fprintf(m.out, "✓ drop rule %s: deleted\n", r.ID)
+		}
+	}
+	if err := writeJSONFile(m.rollbackPath, rollback); err != nil {
+		return err
+	}
+
+	for i, mapping := range mappings {
+		if deleted[mapping.DropRule.ID] {
+			mappings[i].LegacyDeleted = true
+		}
+	}
+
+	if err := writeJSONFile(m.mappingPath, mappings); err != nil {
+		return err
+	}
+
+	failures := dropRuleFailures{}
+	for _, f := range result.Failures {
+		failures = append(failures, dropRuleFailure{Reason: string(f.Error.Reason), Description: f.Error.Description})
+	}
+
+	return failures.err()
+}
+
+// rollback recreates the drop rules of the rollback file, updating their IDs
+// in the mapping file.
+func (m *dropRuleMigration) rollback(ctx context.Context, dryRun bool) error {
+	rules := []legacyDropRule{}
+	if err := readJSONFile(m.rollbackPath, &rules); err != nil {
+		return err
+	}
+	if len(rules) == 0 {
+		return fmt.Errorf("no deleted drop rules found in %s", m.rollbackPath)
+	}
+
+	mappings := []dropRuleMapping{}
+	if err := readJSONFile(m.mappingPath, &mappings); err != nil {
+		return err
+	}
+
+	remaining := []legacyDropRule{}
+	for i, r := range rules {
+		if dryRun {
+			fmt.Fprintf(m.out, "+ drop rule %s: %s\n", r.ID, r.NRQL)
+			continue
+		}
+
+		id, err := m.createDropRule(ctx, r)
+		if err != nil {
+			// Keep the rules not recreated for the next rollback.
+			remaining = append(remaining, rules[i:]...)
+			if writeErr := writeJSONFile(m.rollbackPath, remaining); writeErr != nil {
+				return writeErr
+			}
+			if writeErr := writeJSONFile(m.mappingPath, mappings); writeErr != nil {
+				return writeErr
+			}
+			return fmt.Errorf("could not recreate drop rule %s: %w", r.ID, err)
+		}
+
+		for j, mapping := range mappings {
+			if mapping.DropRule.ID == r.ID {
+				mappings[j].DropRule.ID = id
+				mappings[j].LegacyDeleted = false
+			}
+		}
+		fmt.Fprintf(m.out, "✓ drop rule %s: recreated as drop rule %s\n", r.ID, id)
+	}
+
+	if dryRun {
+		return nil
+	}
+
+	if err := writeJSONFile(m.mappingPath, mappings); err != nil {
+		return err
+	}
+
+	return writeJSONFile(m.rollbackPath, remaining)
+}
+
+func (m *dropRuleMigration) createDropRule(ctx context.Context, r legacyDropRule) (string, error) {
+	input := []nrqldroprules.NRQLDropRulesCreateDropRuleInput{
+		{
+			Action:      nrqldroprules.NRQLDropRulesAction(r.Action),
+			Description: r.Description,
+			NRQL:        r.NRQL,
+		},
+	}
+
+	result, err := m.client.Nrqldroprules.NRQLDropRulesCreateWithContext(ctx, m.accountID, input)
+	if err != nil {
+		return "", err
+	}
+
+	failures := dropRuleFailures{}
+	for _, f := range result.Failures {
+		failures = append(failures, dropRuleFailure{Reason: string(f.Error.Reason), Description: f.Error.Description})
+	}
+	if err := failures.err(); err != nil {
+		return "", err
+	}
+	if len(result.Successes) == 0 {
+		return "", fmt.Errorf("no drop rule returned")
+	}
+
+	return result.Successes[0].ID, nil
+}
+
+// readJSONFile decodes the JSON file into v, leaving v as is when the file
+// does not exist.
+func readJSONFile(path string, v interface{}) error {
+	b, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	if err := json.Unmarshal(b, v); err != nil {
+		return fmt.Errorf("could not parse %s: %w", path, err)
+	}
+
+	return nil
+}
+
+// writeJSONFile encodes v to the JSON file, syncing it to disk before
+// returning, as the files record changes made to the account.
+func writeJSONFile(path string, v interface{}) error {
+	b, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+
+	if _, err := f.Write(append(b, '\n')); err != nil {
+		f.Close()
+		return err
+	}
+
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return err
+	}
+
+	return f.Close()
+}
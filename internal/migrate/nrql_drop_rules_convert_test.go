@@ -0,0 +1,257 @@
+package migrate
+
+import (
+	"bytes"
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/newrelic/newrelic-cli/internal/utils"
+)
+
+func newTestMigration(t *testing.T, server *utils.MockNerdGraphServer) (*dropRuleMigration, *bytes.Buffer) {
+	dir := t.TempDir()
+	out := &bytes.Buffer{}
+
+	return &dropRuleMigration{
+		client:       server.Client(),
+		accountID:    12345,
+		mappingPath:  filepath.Join(dir, "mapping.json"),
+		rollbackPath: filepath.Join(dir, "rollback.json"),
+		out:          out,
+	}, out
+}
+
+func readTestMappings(t *testing.T, m *dropRuleMigration) []dropRuleMapping {
+	mappings := []dropRuleMapping{}
+	require.NoError(t, readJSONFile(m.mappingPath, &mappings))
+	return mappings
+}
+
+var testDropRules = []legacyDropRule{
+	{ID: "1", AccountID: 12345, NRQL: "SELECT * FROM Log WHERE level = 'debug'", Action: "DROP_DATA"},
+	{ID: "2", AccountID: 12345, NRQL: "select password, token from Log where service='auth'", Action: "DROP_ATTRIBUTES", Description: "Drop secrets"},
+	{ID: "3", AccountID: 12345, NRQL: "SELECT containerId FROM Metric", Action: "DROP_ATTRIBUTES_FROM_METRIC_AGGREGATES"},
+}
+
+func TestPipelineCloudRuleNRQL(t *testing.T) {
+	nrql, err := pipelineCloudRuleNRQL(testDropRules[0])
+	require.NoError(t, err)
+	assert.Equal(t, "DELETE FROM Log WHERE level = 'debug'", nrql)
+
+	nrql, err = pipelineCloudRuleNRQL(testDropRules[1])
+	require.NoError(t, err)
+	assert.Equal(t, "DELETE password, token FROM Log where service='auth'", nrql)
+
+	_, err = pipelineCloudRuleNRQL(testDropRules[2])
+	assert.Error(t, err)
+
+	_, err = pipelineCloudRuleNRQL(legacyDropRule{ID: "4", NRQL: "FROM Log SELECT *", Action: "DROP_DATA"})
+	assert.Error(t, err)
+}
+
+func TestEquivalentNRQL(t *testing.T) {
+	assert.True(t, equivalentNRQL(testDropRules[1], "DELETE password,token FROM Log WHERE service = 'auth'"))
+	assert.True(t, equivalentNRQL(testDropRules[0], "delete  from Log where level='debug'"))
+
+	// Values and attribute names are compared as is.
+	assert.False(t, equivalentNRQL(testDropRules[0], "DELETE FROM Log WHERE level = 'DEBUG'"))
+	assert.False(t, equivalentNRQL(testDropRules[1], "DELETE password FROM Log WHERE service = 'auth'"))
+	assert.False(t, equivalentNRQL(testDropRules[2], "DELETE containerId FROM Metric"))
+
+	// The rule is checked against the drop rule, not the NRQL it was created with.
+	assert.True(t, equivalentNRQL(testDropRules[1], "DELETE token, password FROM Log WHERE service = 'auth'"))
+	assert.False(t, equivalentNRQL(testDropRules[0], "DELETE level FROM Log WHERE level = 'debug'"))
+	assert.False(t, equivalentNRQL(testDropRules[1], "DELETE FROM Log WHERE service = 'auth'"))
+	assert.False(t, equivalentNRQL(testDropRules[0], "DELETE FROM Log"))
+	assert.False(t, equivalentNRQL(testDropRules[0], "SELECT * FROM Log WHERE level = 'debug'"))
+}
+
+func TestConvertShouldCreateVerifyAndRecordRules(t *testing.T) {
+	server := utils.NewMockNerdGraphServer(nil)
+	defer server.Close()
+	server.AddResponses("entityManagementCreatePipelineCloudRule",
+		`{"entityManagementCreatePipelineCloudRule": {"entity": {"id": "PCR-1"}}}`,
+		`{"entityManagementCreatePipelineCloudRule": {"entity": {"id": "PCR-2"}}}`,
+	)
+	server.AddResponses("EntityManagementPipelineCloudRuleEntity {",
+		`{"actor": {"entityManagement": {"entity": {"nrql": "DELETE FROM Log WHERE level = 'debug'"}}}}`,
+		`{"actor": {"entityManagement": {"entity": {"nrql": "DELETE password FROM Log WHERE service = 'auth'"}}}}`,
+	)
+	m, out := newTestMigration(t, server)
+
+	require.NoError(t, m.convert(context.Background(), testDropRules, false))
+
+	mappings := readTestMappings(t, m)
+	require.Len(t, mappings, 2)
+	assert.Equal(t, "1", mappings[0].DropRule.ID)
+	assert.Equal(t, "PCR-1", mappings[0].PipelineCloudRuleID)
+	assert.True(t, mappings[0].Verified)
+	assert.Equal(t, "2", mappings[1].DropRule.ID)
+	assert.False(t, mappings[1].Verified)
+	assert.Contains(t, out.String(), "✓ drop rule 1: converted to Pipeline Cloud Rule PCR-1")
+	assert.Contains(t, out.String(), "⚠ drop rule 2")
+
+	rule := server.Mutations()[0].Variables["rule"].(map[string]interface{})
+	assert.Equal(t, "NRQL drop rule 1", rule["name"])
+	assert.Equal(t, "DELETE FROM Log WHERE level = 'debug'", rule["nrql"])
+	assert.Equal(t, map[string]interface{}{"id": "12345", "type": "ACCOUNT"}, rule["scope"])
+}
+
+func TestConvertShouldSkipConvertedRulesAndVerifyAgain(t *testing.T) {
+	server := utils.NewMockNerdGraphServer(map[string]string{
+		"EntityManagementPipelineCloudRuleEntity {": `{"actor": {"entityManagement": {"entity": {"nrql": "DELETE password, token FROM Log WHERE service = 'auth'"}}}}`,
+	})
+	defer server.Close()
+	m, out := newTestMigration(t, server)
+	require.NoError(t, writeJSONFile(m.mappingPath, []dropRuleMapping{
+		{DropRule: testDropRules[0], PipelineCloudRuleID: "PCR-1", Verified: true},
+		{DropRule: testDropRules[1], PipelineCloudRuleID: "PCR-2"},
+	}))
+
+	require.NoError(t, m.convert(context.Background(), testDropRules, false))
+
+	assert.Empty(t, server.Mutations())
+	mappings := readTestMappings(t, m)
+	require.Len(t, mappings, 2)
+	assert.True(t, mappings[1].Verified)
+	assert.Contains(t, out.String(), "= drop rule 1: already converted to Pipeline Cloud Rule PCR-1")
+}
+
+func TestConvertDryRunShouldNotCreateRules(t *testing.T) {
+	server := utils.NewMockNerdGraphServer(nil)
+	defer server.Close()
+	m, out := newTestMigration(t, server)
+
+	require.NoError(t, m.convert(context.Background(), testDropRules, true))
+
+	assert.Empty(t, server.Requests)
+	assert.Empty(t, readTestMappings(t, m))
+	assert.Contains(t, out.String(), "+ drop rule 2: DELETE password, token FROM Log where service='auth'")
+}
+
+func TestDeleteLegacyShouldDeleteVerifiedRulesAndRollBack(t *testing.T) {
+	server := utils.NewMockNerdGraphServer(map[string]string{
+		"nrqlDropRulesDelete": `{"nrqlDropRulesDelete": {"successes": [{"id": "1"}]}}`,
+		"nrqlDropRulesCreate": `{"nrqlDropRulesCreate": {"successes": [{"id": "10"}]}}`,
+	})
+	defer server.Close()
+	m, _ := newTestMigration(t, server)
+	require.NoError(t, writeJSONFile(m.mappingPath, []dropRuleMapping{
+		{DropRule: testDropRules[0], PipelineCloudRuleID: "PCR-1", Verified: true},
+		{DropRule: testDropRules[1], PipelineCloudRuleID: "PCR-2"},
+	}))
+
+	require.NoError(t, m.deleteLegacy(context.Background(), testDropRules, false))
+
+	mutations := server.Mutations()
+	require.Len(t, mutations, 1)
+	assert.Equal(t, []interface{}{"1"}, mutations[0].Variables["ruleIds"])
+	mappings := readTestMappings(t, m)
+	assert.True(t, mappings[0].LegacyDeleted)
+	assert.False(t, mappings[1].LegacyDeleted)
+
+	rollback := []legacyDropRule{}
+	require.NoError(t, readJSONFile(m.rollbackPath, &rollback))
+	assert.Equal(t, []legacyDropRule{testDropRules[0]}, rollback)
+
+	require.NoError(t, m.rollback(context.Background(), false))
+
+	mutations = server.Mutations()
+	require.Len(t, mutations, 2)
+	rules := mutations[1].Variables["rules"].([]interface{})
+	assert.Equal(t, "SELECT * FROM Log WHERE level = 'debug'", rules[0].(map[string]interface{})["nrql"])
+	assert.Equal(t, "DROP_DATA", rules[0].(map[string]interface{})["action"])
+
+	mappings = readTestMappings(t, m)
+	assert.Equal(t, "10", mappings[0].DropRule.ID)
+	assert.False(t, mappings[0].LegacyDeleted)
+
+	rollback = []legacyDropRule{}
+	require.NoError(t, readJSONFile(m.rollbackPath, &rollback))
+	assert.Empty(t, rollback)
+}
+
+func TestDeleteLegacyShouldRecordOnlyDeletedRules(t *testing.T) {
+	rules := []legacyDropRule{
+		testDropRules[0],
+		{ID: "5", AccountID: 12345, NRQL: "SELECT * FROM Log WHERE level = 'trace'", Action: "DROP_DATA"},
+	}
+
+	server := utils.NewMockNerdGraphServer(map[string]string{
+		"nrqlDropRulesDelete": `{"nrqlDropRulesDelete": {
+			"successes": [{"id": "1"}],
+			"failures": [{"submitted": {"ruleId": "5"}, "error": {"reason": "INVALID_INPUT", "description": "rule not found"}}]
+		}}`,
+	})
+	defer server.Close()
+	m, out := newTestMigration(t, server)
+	require.NoError(t, writeJSONFile(m.mappingPath, []dropRuleMapping{
+		{DropRule: rules[0], PipelineCloudRuleID: "PCR-1", Verified: true},
+		{DropRule: rules[1], PipelineCloudRuleID: "PCR-5", Verified: true},
+	}))
+
+	err := m.deleteLegacy(context.Background(), rules, false)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "INVALID_INPUT: rule not found")
+	assert.Contains(t, out.String(), "✓ drop rule 1: deleted")
+	assert.NotContains(t, out.String(), "drop rule 5")
+
+	rollback := []legacyDropRule{}
+	require.NoError(t, readJSONFile(m.rollbackPath, &rollback))
+	assert.Equal(t, []legacyDropRule{rules[0]}, rollback)
+
+	mappings := readTestMappings(t, m)
+	assert.True(t, mappings[0].LegacyDeleted)
+	assert.False(t, mappings[1].LegacyDeleted)
+}
+
+func TestDeleteLegacyShouldReportFailures(t *testing.T) {
+	server := utils.NewMockNerdGraphServer(map[string]string{
+		"nrqlDropRulesDelete": `{"nrqlDropRulesDelete": {"failures": [{"error": {"reason": "INVALID_INPUT", "description": "rule not found"}}]}}`,
+	})
+	defer server.Close()
+	m, _ := newTestMigration(t, server)
+	require.NoError(t, writeJSONFile(m.mappingPath, []dropRuleMapping{
+		{DropRule: testDropRules[0], PipelineCloudRuleID: "PCR-1", Verified: true},
+	}))
+
+	err := m.deleteLegacy(context.Background(), testDropRules, false)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "INVALID_INPUT: rule not found")
+	assert.False(t, readTestMappings(t, m)[0].LegacyDeleted)
+
+	rollback := []legacyDropRule{}
+	require.NoError(t, readJSONFile(m.rollbackPath, &rollback))
+	assert.Empty(t, rollback)
+}
+
+func TestDeleteLegacyShouldRequireConvertedRules(t *testing.T) {
+	server := utils.NewMockNerdGraphServer(nil)
+	defer server.Close()
+	m, _ := newTestMigration(t, server)
+
+	assert.Error(t, m.deleteLegacy(context.Background(), testDropRules, false))
+}
+
+func TestDeleteLegacyShouldNotDeleteRulesNotRecorded(t *testing.T) {
+	server := utils.NewMockNerdGraphServer(map[string]string{
+		"nrqlDropRulesDelete": `{"nrqlDropRulesDelete": {"successes": [{"id": "1"}]}}`,
+	})
+	defer server.Close()
+	m, _ := newTestMigration(t, server)
+	m.rollbackPath = filepath.Join(t.TempDir(), "missing", "rollback.json")
+	require.NoError(t, writeJSONFile(m.mappingPath, []dropRuleMapping{
+		{DropRule: testDropRules[0], PipelineCloudRuleID: "PCR-1", Verified: true},
+	}))
+
+	err := m.deleteLegacy(context.Background(), testDropRules, false)
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "could not record the drop rules to delete")
+	assert.Empty(t, server.Mutations())
+	assert.False(t, readTestMappings(t, m)[0].LegacyDeleted)
+}
@@ -0,0 +1,285 @@
+package nerdstorage
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+const (
+	backupManifestFileName = "manifest.json"
+	backupDocumentsDirName = "documents"
+)
+
+// backupManifest describes the contents of a NerdStorage collection backup.
+type backupManifest struct {
+	PackageID  string                 `json:"packageId"`
+	Scope      string                 `json:"scope"`
+	Collection string                 `json:"collection"`
+	AccountID  int                    `json:"accountId,omitempty"`
+	EntityGUID string                 `json:"entityGuid,omitempty"`
+	CreatedAt  time.Time              `json:"createdAt"`
+	Documents  []backupManifestRecord `json:"documents"`
+}
+
+// backupManifestRecord maps a document ID to the file holding its contents.
+type backupManifestRecord struct {
+	DocumentID string `json:"documentId"`
+	File       string `json:"file"`
+}
+
+// backupDocument is a single NerdStorage document and its ID.
+type backupDocument struct {
+	ID       string      `json:"id"`
+	Document interface{} `json:"document"`
+}
+
+// collectionDocuments converts a NerdStorage collection response into a list
+// of documents. Each item in the response is expected to carry an `id` and a
+// `document` field.
+func collectionDocuments(items []interface{}) ([]backupDocument, error) {
+	docs := make([]backupDocument, 0, len(items))
+
+	for _, item := range items {
+		m, ok := item.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("unexpected collection item of type %T", item)
+		}
+
+		id, ok := m["id"].(string)
+		if !ok || id == "" {
+			return nil, fmt.Errorf("collection item is missing a document ID")
+		}
+
+		docs = append(docs, backupDocument{ID: id, Document: m["document"]})
+	}
+
+	return docs, nil
+}
+
+// isArchivePath reports whether the given backup path refers to a gzipped tarball
+// rather than a directory.
+func isArchivePath(p string) bool {
+	lower := strings.ToLower(p)
+	return strings.HasSuffix(lower, ".tar.gz") || strings.HasSuffix(lower, ".tgz")
+}
+
+// documentFileName returns a file name that is safe to use for the given document ID.
+func documentFileName(documentID string) string {
+	return path.Join(backupDocumentsDirName, url.PathEscape(documentID)+".json")
+}
+
+// writeBackup writes the manifest and documents to the given path, either as a
+// directory or as a gzipped tarball depending on the path's extension.
+func writeBackup(p string, manifest backupManifest, docs []backupDocument) error {
+	files := map[string][]byte{}
+
+	manifest.Documents = make([]backupManifestRecord, 0, len(docs))
+	for _, d := range docs {
+		name := documentFileName(d.ID)
+		if _, ok := files[name]; ok {
+			return fmt.Errorf("duplicate document ID %s", d.ID)
+		}
+
+		data, err := json.MarshalIndent(d.Document, "", "  ")
+		if err != nil {
+			return fmt.Errorf("could not encode document %s: %s", d.ID, err)
+		}
+
+		files[name] = data
+		manifest.Documents = append(manifest.Documents, backupManifestRecord{DocumentID: d.ID, File: name})
+	}
+
+	m, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return err
+	}
+	files[backupManifestFileName] = m
+
+	if isArchivePath(p) {
+		return writeBackupArchive(p, manifest, files)
+	}
+
+	return writeBackupDirectory(p, files)
+}
+
+func writeBackupDirectory(dir string, files map[string][]byte) error {
+	if err := os.MkdirAll(filepath.Join(dir, backupDocumentsDirName), 0750); err != nil {
+		return err
+	}
+
+	for name, data := range files {
+		if err := os.WriteFile(filepath.Join(dir, filepath.FromSlash(name)), data, 0600); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func writeBackupArchive(p string, manifest backupManifest, files map[string][]byte) (err error) {
+	f, err := os.OpenFile(p, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if cerr := f.Close(); err == nil {
+			err = cerr
+		}
+	}()
+
+	gw := gzip.NewWriter(f)
+	tw := tar.NewWriter(gw)
+
+	// Write the manifest first so that readers can stream the archive.
+	names := []string{backupManifestFileName}
+	for _, r := range manifest.Documents {
+		names = append(names, r.File)
+	}
+
+	for _, name := range names {
+		data := files[name]
+		hdr := &tar.Header{
+			Name:    name,
+			Mode:    0600,
+			Size:    int64(len(data)),
+			ModTime: manifest.CreatedAt,
+		}
+
+		if err = tw.WriteHeader(hdr); err != nil {
+			return err
+		}
+
+		if _, err = tw.Write(data); err != nil {
+			return err
+		}
+	}
+
+	if err = tw.Close(); err != nil {
+		return err
+	}
+
+	return gw.Close()
+}
+
+// readBackup reads a backup previously written by writeBackup.
+func readBackup(p string) (*backupManifest, []backupDocument, error) {
+	var files map[string][]byte
+	var err error
+
+	if isArchivePath(p) {
+		files, err = readBackupArchive(p)
+	} else {
+		files, err = readBackupDirectory(p)
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	data, ok := files[backupManifestFileName]
+	if !ok {
+		return nil, nil, fmt.Errorf("backup %s does not contain a %s file", p, backupManifestFileName)
+	}
+
+	var manifest backupManifest
+	if err = json.Unmarshal(data, &manifest); err != nil {
+		return nil, nil, fmt.Errorf("could not parse backup manifest: %s", err)
+	}
+
+	docs := make([]backupDocument, 0, len(manifest.Documents))
+	for _, r := range manifest.Documents {
+		data, ok := files[r.File]
+		if !ok {
+			return nil, nil, fmt.Errorf("backup %s is missing the file for document %s", p, r.DocumentID)
+		}
+
+		var doc interface{}
+		if err = json.Unmarshal(data, &doc); err != nil {
+			return nil, nil, fmt.Errorf("could not parse document %s: %s", r.DocumentID, err)
+		}
+
+		docs = append(docs, backupDocument{ID: r.DocumentID, Document: doc})
+	}
+
+	return &manifest, docs, nil
+}
+
+func readBackupDirectory(dir string) (map[string][]byte, error) {
+	files := map[string][]byte{}
+
+	data, err := os.ReadFile(filepath.Join(dir, backupManifestFileName))
+	if err != nil {
+		return nil, err
+	}
+	files[backupManifestFileName] = data
+
+	entries, err := os.ReadDir(filepath.Join(dir, backupDocumentsDirName))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return files, nil
+		}
+		return nil, err
+	}
+
+	for _, e := range entries {
+		if e.IsDir() {
+			continue
+		}
+
+		name := path.Join(backupDocumentsDirName, e.Name())
+		data, err := os.ReadFile(filepath.Join(dir, filepath.FromSlash(name)))
+		if err != nil {
+			return nil, err
+		}
+		files[name] = data
+	}
+
+	return files, nil
+}
+
+func readBackupArchive(p string) (map[string][]byte, error) {
+	f, err := os.Open(p)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	gr, err := gzip.NewReader(f)
+	if err != nil {
+		return nil, err
+	}
+	defer gr.Close()
+
+	files := map[string][]byte{}
+	tr := tar.NewReader(gr)
+
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		if hdr.Typeflag != tar.TypeReg {
+			continue
+		}
+
+		data, err := io.ReadAll(tr)
+		if err != nil {
+			return nil, err
+		}
+		files[path.Clean(hdr.Name)] = data
+	}
+
+	return files, nil
+}
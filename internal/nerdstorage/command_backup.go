@@ -0,0 +1,343 @@
+package nerdstorage
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+
+	"github.com/newrelic/newrelic-cli/internal/client"
+	configAPI "github.com/newrelic/newrelic-cli/internal/config/api"
+	"github.com/newrelic/newrelic-cli/internal/output"
+	"github.com/newrelic/newrelic-cli/internal/utils"
+	"github.com/newrelic/newrelic-client-go/v2/pkg/nerdstorage"
+)
+
+const (
+	conflictSkip      = "skip"
+	conflictOverwrite = "overwrite"
+	conflictFail      = "fail"
+)
+
+const (
+	restoreActionCreate    = "create"
+	restoreActionOverwrite = "overwrite"
+	restoreActionSkip      = "skip"
+	restoreActionConflict  = "conflict"
+)
+
+var (
+	backupPath       string
+	targetAccountID  int
+	targetEntityGUID string
+	onConflict       string
+	dryRun           bool
+)
+
+// restoreResult describes what a restore did, or would do, with a single document.
+type restoreResult struct {
+	DocumentID string `json:"documentId"`
+	Action     string `json:"action"`
+	Error      string `json:"error,omitempty"`
+}
+
+var cmdBackup = &cobra.Command{
+	Use:   "backup",
+	Short: "Back up every document in a NerdStorage collection.",
+	Long: `Back up every document in a NerdStorage collection
+
+Back up every document in a NerdStorage collection to a directory or, when the
+path ends with .tar.gz or .tgz, to a gzipped tarball.  Valid scopes are ACCOUNT,
+ENTITY, and USER.  ACCOUNT scope requires a valid account ID and ENTITY scope
+requires a valid entity GUID.  A valid Nerdpack package ID is required.
+`,
+	Example: `
+  # Account scope, written to a directory
+  newrelic nerdstorage backup --scope ACCOUNT --packageId b0dee5a1-e809-4d6f-bd3c-0682cd079612 --accountId 12345678 --collection myCol --path ./myCol-backup
+
+  # Entity scope, written to a tarball
+  newrelic nerdstorage backup --scope ENTITY --packageId b0dee5a1-e809-4d6f-bd3c-0682cd079612 --entityGuid MjUyMDUyOHxFUE18QVBQTElDQVRJT058MjE1MDM3Nzk1 --collection myCol --path myCol.tar.gz
+
+  # User scope
+  newrelic nerdstorage backup --scope USER --packageId b0dee5a1-e809-4d6f-bd3c-0682cd079612 --collection myCol --path ./myCol-backup
+`,
+	PreRun: client.RequireClient,
+	Run: func(cmd *cobra.Command, args []string) {
+		manifest := backupManifest{
+			PackageID:  packageID,
+			Scope:      strings.ToUpper(scope),
+			Collection: collection,
+			CreatedAt:  time.Now().UTC(),
+		}
+
+		switch strings.ToLower(scope) {
+		case "account":
+			manifest.AccountID = configAPI.RequireActiveProfileAccountID()
+		case "entity":
+			manifest.EntityGUID = entityGUID
+		}
+
+		items, err := getCollection(scope, manifest.AccountID, manifest.EntityGUID, nerdstorage.GetCollectionInput{
+			PackageID:  packageID,
+			Collection: collection,
+		})
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		docs, err := collectionDocuments(items)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		if err := writeBackup(backupPath, manifest, docs); err != nil {
+			log.Fatalf("error writing backup: %s", err)
+		}
+
+		log.Infof("backed up %d documents to %s", len(docs), backupPath)
+		log.Info("success")
+	},
+}
+
+var cmdRestore = &cobra.Command{
+	Use:   "restore",
+	Short: "Restore a NerdStorage collection backup.",
+	Long: `Restore a NerdStorage collection backup
+
+Restore the documents in a backup created with the backup command.  By default
+the documents are written back to the scope, package, and collection recorded in
+the backup.  Use the scope, account ID, and entity GUID flags to restore into
+another account or entity.
+
+When a document already exists in the target collection the conflict policy is
+applied.  Valid policies are skip, overwrite, and fail.  With the fail policy no
+documents are written if any conflicts are found.  Use --dryRun to print the
+planned changes without writing anything.
+`,
+	Example: `
+  # Restore a backup to the location it was taken from
+  newrelic nerdstorage restore --path ./myCol-backup
+
+  # Preview restoring into another account, overwriting existing documents
+  newrelic nerdstorage restore --path myCol.tar.gz --scope ACCOUNT --targetAccountId 87654321 --onConflict overwrite --dryRun
+
+  # Restore into another entity, skipping documents that already exist
+  newrelic nerdstorage restore --path myCol.tar.gz --scope ENTITY --targetEntityGuid MjUyMDUyOHxFUE18QVBQTElDQVRJT058MjE1MDM3Nzk1 --onConflict skip
+`,
+	PreRun: client.RequireClient,
+	Run: func(cmd *cobra.Command, args []string) {
+		policy := strings.ToLower(onConflict)
+		if !utils.StringInSlice(policy, []string{conflictSkip, conflictOverwrite, conflictFail}) {
+			log.Fatal("onConflict must be one of skip, overwrite, or fail")
+		}
+
+		manifest, docs, err := readBackup(backupPath)
+		if err != nil {
+			log.Fatalf("error reading backup: %s", err)
+		}
+
+		targetScope := manifest.Scope
+		if cmd.Flags().Changed("scope") {
+			targetScope = scope
+		}
+
+		targetPackageID := manifest.PackageID
+		if packageID != "" {
+			targetPackageID = packageID
+		}
+
+		targetCollection := manifest.Collection
+		if collection != "" {
+			targetCollection = collection
+		}
+
+		var accountID int
+		var guid string
+		switch strings.ToLower(targetScope) {
+		case "account":
+			accountID = targetAccountID
+			if accountID == 0 {
+				accountID = manifest.AccountID
+			}
+			if accountID == 0 {
+				accountID = configAPI.GetActiveProfileAccountID()
+			}
+			if accountID == 0 {
+				log.Fatal("an account ID is required to restore into the ACCOUNT scope")
+			}
+		case "entity":
+			guid = targetEntityGUID
+			if guid == "" {
+				guid = manifest.EntityGUID
+			}
+			if guid == "" {
+				log.Fatal("an entity GUID is required to restore into the ENTITY scope")
+			}
+		case "user":
+		default:
+			log.Fatal("scope must be one of ACCOUNT, ENTITY, or USER")
+		}
+
+		exists := func(documentID string) (bool, error) {
+			doc, err := getDocument(targetScope, accountID, guid, nerdstorage.GetDocumentInput{
+				PackageID:  targetPackageID,
+				Collection: targetCollection,
+				DocumentID: documentID,
+			})
+			return doc != nil, err
+		}
+
+		results, err := planRestore(docs, policy, exists)
+		if dryRun && results != nil {
+			utils.LogIfFatal(output.Print(results))
+		}
+		if err != nil {
+			log.Fatal(err)
+		}
+		if dryRun {
+			return
+		}
+
+		var failed int
+		for i, r := range results {
+			if r.Action != restoreActionCreate && r.Action != restoreActionOverwrite {
+				continue
+			}
+
+			err := writeDocument(targetScope, accountID, guid, nerdstorage.WriteDocumentInput{
+				PackageID:  targetPackageID,
+				Collection: targetCollection,
+				DocumentID: docs[i].ID,
+				Document:   docs[i].Document,
+			})
+			if err != nil {
+				results[i].Error = err.Error()
+				failed++
+			}
+		}
+
+		utils.LogIfFatal(output.Print(results))
+
+		if failed > 0 {
+			log.Fatalf("%d of %d documents could not be restored", failed, len(docs))
+		}
+
+		log.Info("success")
+	},
+}
+
+// planRestore determines the action to take for each document in a backup
+// according to the given conflict policy.  The exists func reports whether a
+// document is already present in the target collection.  With the fail policy
+// an error is returned listing every conflicting document.
+func planRestore(docs []backupDocument, policy string, exists func(string) (bool, error)) ([]restoreResult, error) {
+	results := make([]restoreResult, 0, len(docs))
+	conflicts := []string{}
+
+	for _, d := range docs {
+		found, err := exists(d.ID)
+		if err != nil {
+			return nil, fmt.Errorf("could not check for existing document %s: %s", d.ID, err)
+		}
+
+		action := restoreActionCreate
+		if found {
+			switch policy {
+			case conflictOverwrite:
+				action = restoreActionOverwrite
+			case conflictSkip:
+				action = restoreActionSkip
+			default:
+				action = restoreActionConflict
+				conflicts = append(conflicts, d.ID)
+			}
+		}
+
+		results = append(results, restoreResult{DocumentID: d.ID, Action: action})
+	}
+
+	if len(conflicts) > 0 {
+		return results, fmt.Errorf("%d documents already exist in the target collection: %s", len(conflicts), strings.Join(conflicts, ", "))
+	}
+
+	return results, nil
+}
+
+func getCollection(scope string, accountID int, guid string, input nerdstorage.GetCollectionInput) ([]interface{}, error) {
+	switch strings.ToLower(scope) {
+	case "account":
+		return client.NRClient.NerdStorage.GetCollectionWithAccountScopeWithContext(utils.SignalCtx, accountID, input)
+	case "entity":
+		return client.NRClient.NerdStorage.GetCollectionWithEntityScopeWithContext(utils.SignalCtx, guid, input)
+	case "user":
+		return client.NRClient.NerdStorage.GetCollectionWithUserScopeWithContext(utils.SignalCtx, input)
+	}
+
+	return nil, fmt.Errorf("scope must be one of ACCOUNT, ENTITY, or USER")
+}
+
+func getDocument(scope string, accountID int, guid string, input nerdstorage.GetDocumentInput) (interface{}, error) {
+	switch strings.ToLower(scope) {
+	case "account":
+		return client.NRClient.NerdStorage.GetDocumentWithAccountScopeWithContext(utils.SignalCtx, accountID, input)
+	case "entity":
+		return client.NRClient.NerdStorage.GetDocumentWithEntityScopeWithContext(utils.SignalCtx, guid, input)
+	case "user":
+		return client.NRClient.NerdStorage.GetDocumentWithUserScopeWithContext(utils.SignalCtx, input)
+	}
+
+	return nil, fmt.Errorf("scope must be one of ACCOUNT, ENTITY, or USER")
+}
+
+func writeDocument(scope string, accountID int, guid string, input nerdstorage.WriteDocumentInput) error {
+	var err error
+
+	switch strings.ToLower(scope) {
+	case "account":
+		_, err = client.NRClient.NerdStorage.WriteDocumentWithAccountScopeWithContext(utils.SignalCtx, accountID, input)
+	case "entity":
+		_, err = client.NRClient.NerdStorage.WriteDocumentWithEntityScopeWithContext(utils.SignalCtx, guid, input)
+	case "user":
+		_, err = client.NRClient.NerdStorage.WriteDocumentWithUserScopeWithContext(utils.SignalCtx, input)
+	default:
+		err = fmt.Errorf("scope must be one of ACCOUNT, ENTITY, or USER")
+	}
+
+	return err
+}
+
+func init() {
+	Command.AddCommand(cmdBackup)
+	cmdBackup.Flags().StringVarP(&entityGUID, "entityGuid", "e", "", "the entity GUID")
+	cmdBackup.Flags().StringVarP(&packageID, "packageId", "p", "", "the external package ID")
+	cmdBackup.Flags().StringVarP(&collection, "collection", "c", "", "the collection name to back up")
+	cmdBackup.Flags().StringVarP(&scope, "scope", "s", "USER", "the scope to back up the collection from")
+	cmdBackup.Flags().StringVar(&backupPath, "path", "", "the directory or .tar.gz file to write the backup to")
+
+	err := cmdBackup.MarkFlagRequired("packageId")
+	utils.LogIfError(err)
+
+	err = cmdBackup.MarkFlagRequired("scope")
+	utils.LogIfError(err)
+
+	err = cmdBackup.MarkFlagRequired("collection")
+	utils.LogIfError(err)
+
+	err = cmdBackup.MarkFlagRequired("path")
+	utils.LogIfError(err)
+
+	Command.AddCommand(cmdRestore)
+	cmdRestore.Flags().StringVar(&backupPath, "path", "", "the backup directory or .tar.gz file to restore from")
+	cmdRestore.Flags().StringVarP(&packageID, "packageId", "p", "", "the external package ID to restore into, defaults to the package ID in the backup")
+	cmdRestore.Flags().StringVarP(&collection, "collection", "c", "", "the collection name to restore into, defaults to the collection in the backup")
+	cmdRestore.Flags().StringVarP(&scope, "scope", "s", "", "the scope to restore into, defaults to the scope in the backup")
+	cmdRestore.Flags().IntVar(&targetAccountID, "targetAccountId", 0, "the account ID to restore into when using the ACCOUNT scope")
+	cmdRestore.Flags().StringVar(&targetEntityGUID, "targetEntityGuid", "", "the entity GUID to restore into when using the ENTITY scope")
+	cmdRestore.Flags().StringVar(&onConflict, "onConflict", conflictFail, "what to do when a document already exists: skip, overwrite, or fail")
+	cmdRestore.Flags().BoolVar(&dryRun, "dryRun", false, "print the planned changes without writing any documents")
+
+	err = cmdRestore.MarkFlagRequired("path")
+	utils.LogIfError(err)
+}
@@ -0,0 +1,91 @@
+//go:build unit
+
+package nerdstorage
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/newrelic/newrelic-cli/internal/testcobra"
+)
+
+func TestBackup(t *testing.T) {
+	assert.Equal(t, "backup", cmdBackup.Name())
+
+	testcobra.CheckCobraMetadata(t, cmdBackup)
+	testcobra.CheckCobraRequiredFlags(t, cmdBackup, []string{"packageId", "scope", "collection", "path"})
+}
+
+func TestRestore(t *testing.T) {
+	assert.Equal(t, "restore", cmdRestore.Name())
+
+	testcobra.CheckCobraMetadata(t, cmdRestore)
+	testcobra.CheckCobraRequiredFlags(t, cmdRestore, []string{"path"})
+}
+
+func TestCollectionDocuments(t *testing.T) {
+	items := []interface{}{
+		map[string]interface{}{"id": "doc1", "document": map[string]interface{}{"field": "value"}},
+		map[string]interface{}{"id": "doc2", "document": "plain"},
+	}
+
+	docs, err := collectionDocuments(items)
+	require.NoError(t, err)
+	assert.Equal(t, 2, len(docs))
+	assert.Equal(t, "doc1", docs[0].ID)
+	assert.Equal(t, "plain", docs[1].Document)
+
+	_, err = collectionDocuments([]interface{}{map[string]interface{}{"document": "x"}})
+	assert.Error(t, err)
+}
+
+func TestBackupRoundTrip(t *testing.T) {
+	manifest := backupManifest{
+		PackageID:  "b0dee5a1-e809-4d6f-bd3c-0682cd079612",
+		Scope:      "ACCOUNT",
+		Collection: "myCol",
+		AccountID:  12345,
+		CreatedAt:  time.Now().UTC().Truncate(time.Second),
+	}
+	docs := []backupDocument{
+		{ID: "doc1", Document: map[string]interface{}{"field": "value"}},
+		{ID: "nested/doc 2", Document: []interface{}{1.0, "two"}},
+	}
+
+	for _, name := range []string{"backup", "backup.tar.gz"} {
+		p := filepath.Join(t.TempDir(), name)
+
+		require.NoError(t, writeBackup(p, manifest, docs))
+
+		m, restored, err := readBackup(p)
+		require.NoError(t, err)
+		assert.Equal(t, manifest.PackageID, m.PackageID)
+		assert.Equal(t, manifest.AccountID, m.AccountID)
+		assert.True(t, manifest.CreatedAt.Equal(m.CreatedAt))
+		assert.Equal(t, docs, restored)
+	}
+}
+
+func TestPlanRestore(t *testing.T) {
+	docs := []backupDocument{{ID: "new"}, {ID: "existing"}}
+	exists := func(id string) (bool, error) {
+		return id == "existing", nil
+	}
+
+	results, err := planRestore(docs, conflictOverwrite, exists)
+	require.NoError(t, err)
+	assert.Equal(t, restoreActionCreate, results[0].Action)
+	assert.Equal(t, restoreActionOverwrite, results[1].Action)
+
+	results, err = planRestore(docs, conflictSkip, exists)
+	require.NoError(t, err)
+	assert.Equal(t, restoreActionSkip, results[1].Action)
+
+	results, err = planRestore(docs, conflictFail, exists)
+	assert.Error(t, err)
+	assert.Equal(t, restoreActionConflict, results[1].Action)
+}
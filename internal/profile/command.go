@@ -28,6 +28,7 @@ var (
 	apiKey         string
 	accountID      int
 	licenseKey     string
+	signingKey     string
 	acceptDefaults bool
 )
 
@@ -48,6 +49,8 @@ var cmdAdd = &cobra.Command{
 The add command creates a new profile for use with the New Relic CLI.
 API key and region are required. A License key is optional, but required
 for posting custom events with the ` + "`newrelic events`" + `command.
+A recipe signing key is optional, but required for installing signed recipes
+from local or remote files, or from offline bundles.
 `,
 	Aliases: []string{
 		"configure",
@@ -60,6 +63,14 @@ for posting custom events with the ` + "`newrelic events`" + `command.
 		addIntValueToProfile(config.FlagProfileName, accountID, config.AccountID, "Account ID", fetchAccountIDs)
 		addStringValueToProfile(config.FlagProfileName, licenseKey, config.LicenseKey, "License Key", fetchLicenseKey(), nil)
 
+		// The recipe signing key is only needed to install recipes from files,
+		// so it is not prompted for.
+		if signingKey != "" {
+			if err := configAPI.SetProfileValue(config.FlagProfileName, config.RecipeSigningKey, signingKey); err != nil {
+				log.Fatal(err)
+			}
+		}
+
 		profile, err := configAPI.GetDefaultProfileName()
 		if err != nil {
 			log.Fatal(err)
@@ -278,6 +289,7 @@ func init() {
 	cmdAdd.Flags().StringVarP(&flagRegion, "region", "r", "", "the US, EU, JP, GOV, or FEDRAMP region")
 	cmdAdd.Flags().StringVarP(&apiKey, "apiKey", "", "", "your personal API key")
 	cmdAdd.Flags().StringVarP(&licenseKey, "licenseKey", "", "", "your license key")
+	cmdAdd.Flags().StringVarP(&signingKey, "recipeSigningKey", "", "", "the public key verifying recipe signatures: a PEM encoded ed25519 or ECDSA key, the path to one, or a base64 encoded ed25519 key")
 	cmdAdd.Flags().IntVarP(&accountID, "accountId", "", 0, "your account ID")
 	cmdAdd.Flags().BoolVarP(&acceptDefaults, "acceptDefaults", "y", false, "suppress prompts and accept default values")
 
@@ -0,0 +1,475 @@
+package synthetics
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/newrelic/newrelic-client-go/v2/pkg/synthetics"
+)
+
+const (
+	reportFormatJUnit = "junit"
+	reportFormatJSON  = "json"
+	reportFormatSARIF = "sarif"
+)
+
+var reportFormats = []string{reportFormatJUnit, reportFormatJSON, reportFormatSARIF}
+
+// automatedTestReport is a report of the results of an automated test batch,
+// written to Path once the batch completes or polling times out.
+type automatedTestReport struct {
+	Format string
+	Path   string
+}
+
+// parseAutomatedTestReports parses the values of the --report flag, each in
+// the form format=path.
+func parseAutomatedTestReports(values []string) ([]automatedTestReport, error) {
+	reports := []automatedTestReport{}
+
+	for _, v := range values {
+		format, path, _ := strings.Cut(v, "=")
+		format = strings.ToLower(strings.TrimSpace(format))
+
+		known := false
+		for _, f := range reportFormats {
+			known = known || f == format
+		}
+		if !known {
+			return nil, fmt.Errorf("unknown report format %s in %s, must be one of %s", format, v, strings.Join(reportFormats, ", "))
+		}
+
+		if path == "" {
+			return nil, fmt.Errorf("a path is required for the %s report, use --report %s=path", format, format)
+		}
+
+		reports = append(reports, automatedTestReport{Format: format, Path: path})
+	}
+
+	return reports, nil
+}
+
+// writeAutomatedTestReports writes the reports of a completed or timed out batch.
+func writeAutomatedTestReports(reports []automatedTestReport, testsBatchID string, batchResult synthetics.SyntheticsAutomatedTestResult) error {
+	results := getMonitorTestResults(batchResult)
+
+	for _, r := range reports {
+		if err := writeAutomatedTestReport(r, testsBatchID, batchResult, results); err != nil {
+			return fmt.Errorf("could not write the %s report to %s: %s", r.Format, r.Path, err)
+		}
+	}
+
+	return nil
+}
+
+func writeAutomatedTestReport(r automatedTestReport, testsBatchID string, batchResult synthetics.SyntheticsAutomatedTestResult, results []monitorTestResult) error {
+	f, err := os.Create(r.Path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	switch r.Format {
+	case reportFormatJUnit:
+		err = writeJUnitReport(f, testsBatchID, batchResult, results)
+	case reportFormatJSON:
+		err = writeJSONReport(f, testsBatchID, batchResult, results)
+	case reportFormatSARIF:
+		err = writeSARIFReport(f, testsBatchID, results)
+	}
+	if err != nil {
+		return err
+	}
+
+	return f.Close()
+}
+
+// monitorTestResult is the result of a monitor in an automated test batch,
+// consolidated from its jobs in each location.
+type monitorTestResult struct {
+	Name           string               `json:"name"`
+	GUID           string               `json:"guid"`
+	Type           string               `json:"type,omitempty"`
+	Result         string               `json:"result"`
+	IsBlocking     bool                 `json:"isBlocking"`
+	DurationMs     int64                `json:"durationMs"`
+	FailureMessage string               `json:"failureMessage,omitempty"`
+	Locations      []locationTestResult `json:"locations"`
+	status         synthetics.SyntheticsJobStatus
+}
+
+// locationTestResult is the result of a monitor's job in a location.
+type locationTestResult struct {
+	Location   string `json:"location"`
+	Label      string `json:"label,omitempty"`
+	Result     string `json:"result"`
+	DurationMs int64  `json:"durationMs"`
+	ResultsURL string `json:"resultsUrl,omitempty"`
+}
+
+// getMonitorTestResults groups the jobs of a batch by monitor.  A monitor
+// failed when its job failed in any location, and is pending while a job is.
+// Monitors pending when the batch timed out have timed out.
+func getMonitorTestResults(batchResult synthetics.SyntheticsAutomatedTestResult) []monitorTestResult {
+	byGUID := map[string]*monitorTestResult{}
+	guids := []string{}
+
+	for _, test := range batchResult.Tests {
+		if test.Result == "" {
+			test.Result = synthetics.SyntheticsJobStatusTypes.PENDING
+		}
+
+		guid := string(test.MonitorGUID)
+		m, ok := byGUID[guid]
+		if !ok {
+			m = &monitorTestResult{
+				Name:       test.MonitorName,
+				GUID:       guid,
+				Type:       string(test.Type),
+				IsBlocking: test.AutomatedTestMonitorConfig.IsBlocking,
+				status:     synthetics.SyntheticsJobStatusTypes.SUCCESS,
+			}
+			byGUID[guid] = m
+			guids = append(guids, guid)
+		}
+
+		durationMs := int64(test.Duration)
+		m.DurationMs += durationMs
+		m.Locations = append(m.Locations, locationTestResult{
+			Location:   test.Location,
+			Label:      test.LocationLabel,
+			Result:     string(test.Result),
+			DurationMs: durationMs,
+			ResultsURL: test.ResultsURL,
+		})
+
+		switch {
+		case test.Result == synthetics.SyntheticsJobStatusTypes.FAILED:
+			m.status = synthetics.SyntheticsJobStatusTypes.FAILED
+		case test.Result == synthetics.SyntheticsJobStatusTypes.PENDING && m.status != synthetics.SyntheticsJobStatusTypes.FAILED:
+			m.status = synthetics.SyntheticsJobStatusTypes.PENDING
+		}
+	}
+
+	results := []monitorTestResult{}
+	for _, guid := range guids {
+		m := byGUID[guid]
+		m.Result = string(m.status)
+		if m.status == synthetics.SyntheticsJobStatusTypes.PENDING && batchResult.Status == synthetics.SyntheticsAutomatedTestStatusTypes.TIMEOUT {
+			m.Result = string(batchResult.Status)
+		}
+		m.FailureMessage = m.failureMessage()
+		results = append(results, *m)
+	}
+
+	sort.SliceStable(results, func(i, j int) bool {
+		return results[i].Name < results[j].Name
+	})
+
+	return results
+}
+
+func (m monitorTestResult) failureMessage() string {
+	var locations []string
+
+	switch m.status {
+	case synthetics.SyntheticsJobStatusTypes.FAILED:
+		for _, l := range m.Locations {
+			if l.Result == string(synthetics.SyntheticsJobStatusTypes.FAILED) {
+				locations = append(locations, l.name())
+			}
+		}
+		return fmt.Sprintf("%s failed in %s", m.Name, strings.Join(locations, ", "))
+	case synthetics.SyntheticsJobStatusTypes.PENDING:
+		for _, l := range m.Locations {
+			if l.Result == string(synthetics.SyntheticsJobStatusTypes.PENDING) {
+				locations = append(locations, l.name())
+			}
+		}
+		return fmt.Sprintf("%s did not complete in %s", m.Name, strings.Join(locations, ", "))
+	}
+
+	return ""
+}
+
+func (l locationTestResult) name() string {
+	if l.Label != "" {
+		return l.Label
+	}
+
+	return l.Location
+}
+
+// locationSummary describes the result in each location, one per line.
+func (m monitorTestResult) locationSummary() string {
+	lines := []string{}
+	for _, l := range m.Locations {
+		line := fmt.Sprintf("%s: %s (%dms)", l.name(), l.Result, l.DurationMs)
+		if l.ResultsURL != "" {
+			line += " " + l.ResultsURL
+		}
+		lines = append(lines, line)
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+type junitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Name     string           `xml:"name,attr"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Errors   int              `xml:"errors,attr"`
+	Time     string           `xml:"time,attr"`
+	Suites   []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name       string          `xml:"name,attr"`
+	ID         string          `xml:"id,attr"`
+	Tests      int             `xml:"tests,attr"`
+	Failures   int             `xml:"failures,attr"`
+	Errors     int             `xml:"errors,attr"`
+	Time       string          `xml:"time,attr"`
+	Properties []junitProperty `xml:"properties>property"`
+	TestCases  []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name       string          `xml:"name,attr"`
+	ClassName  string          `xml:"classname,attr"`
+	Time       string          `xml:"time,attr"`
+	Properties []junitProperty `xml:"properties>property"`
+	Failure    *junitMessage   `xml:"failure,omitempty"`
+	Error      *junitMessage   `xml:"error,omitempty"`
+	SystemOut  string          `xml:"system-out,omitempty"`
+}
+
+type junitProperty struct {
+	Name  string `xml:"name,attr"`
+	Value string `xml:"value,attr"`
+}
+
+type junitMessage struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Text    string `xml:",chardata"`
+}
+
+// writeJUnitReport writes a test suite with a test case for each monitor.
+// Failed monitors are failures, and monitors still pending when the batch
+// timed out are errors.
+func writeJUnitReport(w io.Writer, testsBatchID string, batchResult synthetics.SyntheticsAutomatedTestResult, results []monitorTestResult) error {
+	suite := junitTestSuite{
+		Name: batchSuiteName(batchResult),
+		ID:   testsBatchID,
+		Properties: []junitProperty{
+			{Name: "batchId", Value: testsBatchID},
+			{Name: "status", Value: string(batchResult.Status)},
+		},
+	}
+
+	var totalMs int64
+	for _, m := range results {
+		tc := junitTestCase{
+			Name:      m.Name,
+			ClassName: "synthetics." + strings.ToLower(m.Type),
+			Time:      junitSeconds(m.DurationMs),
+			Properties: []junitProperty{
+				{Name: "monitorGuid", Value: m.GUID},
+				{Name: "isBlocking", Value: fmt.Sprintf("%t", m.IsBlocking)},
+			},
+			SystemOut: m.locationSummary(),
+		}
+		for _, l := range m.Locations {
+			tc.Properties = append(tc.Properties, junitProperty{Name: "location." + l.name(), Value: l.Result})
+		}
+
+		switch m.status {
+		case synthetics.SyntheticsJobStatusTypes.FAILED:
+			tc.Failure = &junitMessage{Message: m.FailureMessage, Type: blockingType(m.IsBlocking), Text: m.locationSummary()}
+			suite.Failures++
+		case synthetics.SyntheticsJobStatusTypes.PENDING:
+			tc.Error = &junitMessage{Message: m.FailureMessage, Type: string(batchResult.Status), Text: m.locationSummary()}
+			suite.Errors++
+		}
+
+		suite.TestCases = append(suite.TestCases, tc)
+		suite.Tests++
+		totalMs += m.DurationMs
+	}
+	suite.Time = junitSeconds(totalMs)
+
+	suites := junitTestSuites{
+		Name:     "New Relic Synthetics",
+		Tests:    suite.Tests,
+		Failures: suite.Failures,
+		Errors:   suite.Errors,
+		Time:     suite.Time,
+		Suites:   []junitTestSuite{suite},
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(suites); err != nil {
+		return err
+	}
+
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+func batchSuiteName(batchResult synthetics.SyntheticsAutomatedTestResult) string {
+	if batchResult.Config.BatchName != "" {
+		return batchResult.Config.BatchName
+	}
+
+	return "Synthetics automated tests"
+}
+
+func blockingType(isBlocking bool) string {
+	if isBlocking {
+		return "BLOCKING"
+	}
+
+	return "NON_BLOCKING"
+}
+
+func junitSeconds(ms int64) string {
+	return fmt.Sprintf("%.3f", float64(ms)/1000)
+}
+
+type jsonReport struct {
+	BatchID  string              `json:"batchId"`
+	Status   string              `json:"status"`
+	Summary  jsonReportSummary   `json:"summary"`
+	Monitors []monitorTestResult `json:"monitors"`
+}
+
+type jsonReportSummary struct {
+	Succeeded int `json:"succeeded"`
+	Failed    int `json:"failed"`
+	Pending   int `json:"pending"`
+}
+
+// writeJSONReport writes the status of the batch and the result of each monitor.
+func writeJSONReport(w io.Writer, testsBatchID string, batchResult synthetics.SyntheticsAutomatedTestResult, results []monitorTestResult) error {
+	report := jsonReport{
+		BatchID:  testsBatchID,
+		Status:   string(batchResult.Status),
+		Monitors: results,
+	}
+
+	for _, m := range results {
+		switch m.status {
+		case synthetics.SyntheticsJobStatusTypes.FAILED:
+			report.Summary.Failed++
+		case synthetics.SyntheticsJobStatusTypes.PENDING:
+			report.Summary.Pending++
+		default:
+			report.Summary.Succeeded++
+		}
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(report)
+}
+
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool         `json:"tool"`
+	Results []sarifResult     `json:"results"`
+	Props   map[string]string `json:"properties,omitempty"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	InformationURI string      `json:"informationUri"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID               string       `json:"id"`
+	Name             string       `json:"name"`
+	ShortDescription sarifMessage `json:"shortDescription"`
+}
+
+type sarifResult struct {
+	RuleID     string                 `json:"ruleId"`
+	Level      string                 `json:"level"`
+	Message    sarifMessage           `json:"message"`
+	Properties map[string]interface{} `json:"properties"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+// writeSARIFReport writes a result for each monitor that failed or did not
+// complete, as an error when the monitor is blocking and a warning otherwise.
+func writeSARIFReport(w io.Writer, testsBatchID string, results []monitorTestResult) error {
+	run := sarifRun{
+		Tool: sarifTool{Driver: sarifDriver{
+			Name:           "New Relic Synthetics",
+			InformationURI: "https://docs.newrelic.com/docs/synthetics/",
+			Rules:          []sarifRule{},
+		}},
+		Results: []sarifResult{},
+		Props:   map[string]string{"batchId": testsBatchID},
+	}
+
+	for _, m := range results {
+		run.Tool.Driver.Rules = append(run.Tool.Driver.Rules, sarifRule{
+			ID:               m.GUID,
+			Name:             m.Name,
+			ShortDescription: sarifMessage{Text: fmt.Sprintf("Synthetics %s monitor %s", strings.ToLower(m.Type), m.Name)},
+		})
+
+		if m.FailureMessage == "" {
+			continue
+		}
+
+		level := "warning"
+		if m.IsBlocking {
+			level = "error"
+		}
+
+		run.Results = append(run.Results, sarifResult{
+			RuleID:  m.GUID,
+			Level:   level,
+			Message: sarifMessage{Text: m.FailureMessage},
+			Properties: map[string]interface{}{
+				"isBlocking": m.IsBlocking,
+				"durationMs": m.DurationMs,
+				"locations":  m.Locations,
+			},
+		})
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(sarifLog{
+		Schema:  "https://json.schemastore.org/sarif-2.1.0.json",
+		Version: "2.1.0",
+		Runs:    []sarifRun{run},
+	})
+}
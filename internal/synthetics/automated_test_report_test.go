@@ -0,0 +1,164 @@
+//go:build unit
+
+package synthetics
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/newrelic/newrelic-client-go/v2/pkg/synthetics"
+)
+
+var testAutomatedTestResult = synthetics.SyntheticsAutomatedTestResult{
+	Config: synthetics.SyntheticsAutomatedTestConfig{BatchName: "checkout"},
+	Status: synthetics.SyntheticsAutomatedTestStatusTypes.FAILED,
+	Tests: []synthetics.SyntheticsAutomatedTestJobResult{
+		{
+			MonitorName:                "Login",
+			MonitorGUID:                "Z1",
+			Location:                   "AWS_US_EAST_1",
+			LocationLabel:              "Washington, DC, USA",
+			Result:                     synthetics.SyntheticsJobStatusTypes.SUCCESS,
+			Duration:                   1200,
+			AutomatedTestMonitorConfig: synthetics.SyntheticsAutomatedTestMonitorConfig{IsBlocking: true},
+		},
+		{
+			MonitorName:                "Login",
+			MonitorGUID:                "Z1",
+			Location:                   "AWS_EU_WEST_1",
+			LocationLabel:              "Dublin, IE",
+			Result:                     synthetics.SyntheticsJobStatusTypes.FAILED,
+			Duration:                   800,
+			ResultsURL:                 "https://one.newrelic.com/results/1",
+			AutomatedTestMonitorConfig: synthetics.SyntheticsAutomatedTestMonitorConfig{IsBlocking: true},
+		},
+		{
+			MonitorName:                "Home",
+			MonitorGUID:                "Z2",
+			Location:                   "AWS_US_EAST_1",
+			Result:                     synthetics.SyntheticsJobStatusTypes.SUCCESS,
+			Duration:                   300,
+			AutomatedTestMonitorConfig: synthetics.SyntheticsAutomatedTestMonitorConfig{IsBlocking: false},
+		},
+	},
+}
+
+func TestParseAutomatedTestReports(t *testing.T) {
+	reports, err := parseAutomatedTestReports([]string{"junit=results.xml", "JSON=results.json"})
+	require.NoError(t, err)
+	assert.Equal(t, []automatedTestReport{{Format: "junit", Path: "results.xml"}, {Format: "json", Path: "results.json"}}, reports)
+
+	_, err = parseAutomatedTestReports([]string{"html=results.html"})
+	assert.Error(t, err)
+
+	_, err = parseAutomatedTestReports([]string{"junit"})
+	assert.Error(t, err)
+}
+
+func TestGetMonitorTestResultsShouldGroupLocationsByMonitor(t *testing.T) {
+	results := getMonitorTestResults(testAutomatedTestResult)
+
+	require.Len(t, results, 2)
+	assert.Equal(t, "Home", results[0].Name)
+	assert.Equal(t, "SUCCESS", results[0].Result)
+	assert.Empty(t, results[0].FailureMessage)
+
+	assert.Equal(t, "Login", results[1].Name)
+	assert.Equal(t, "FAILED", results[1].Result)
+	assert.Equal(t, int64(2000), results[1].DurationMs)
+	assert.Equal(t, "Login failed in Dublin, IE", results[1].FailureMessage)
+	assert.Len(t, results[1].Locations, 2)
+}
+
+func TestWriteJUnitReport(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, writeJUnitReport(&buf, "batch-1", testAutomatedTestResult, getMonitorTestResults(testAutomatedTestResult)))
+
+	var suites junitTestSuites
+	require.NoError(t, xml.Unmarshal(buf.Bytes(), &suites))
+	assert.Equal(t, 2, suites.Tests)
+	assert.Equal(t, 1, suites.Failures)
+	require.Len(t, suites.Suites, 1)
+	assert.Equal(t, "checkout", suites.Suites[0].Name)
+	assert.Equal(t, "batch-1", suites.Suites[0].ID)
+
+	login := suites.Suites[0].TestCases[1]
+	assert.Equal(t, "Login", login.Name)
+	assert.Equal(t, "2.000", login.Time)
+	require.NotNil(t, login.Failure)
+	assert.Equal(t, "Login failed in Dublin, IE", login.Failure.Message)
+	assert.Equal(t, "BLOCKING", login.Failure.Type)
+	assert.Contains(t, login.Failure.Text, "Dublin, IE: FAILED (800ms) https://one.newrelic.com/results/1")
+	assert.Contains(t, login.Properties, junitProperty{Name: "isBlocking", Value: "true"})
+	assert.Contains(t, login.Properties, junitProperty{Name: "location.Washington, DC, USA", Value: "SUCCESS"})
+	assert.Nil(t, suites.Suites[0].TestCases[0].Failure)
+}
+
+func TestWriteJSONReport(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, writeJSONReport(&buf, "batch-1", testAutomatedTestResult, getMonitorTestResults(testAutomatedTestResult)))
+
+	var report jsonReport
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &report))
+	assert.Equal(t, "batch-1", report.BatchID)
+	assert.Equal(t, "FAILED", report.Status)
+	assert.Equal(t, jsonReportSummary{Succeeded: 1, Failed: 1}, report.Summary)
+	require.Len(t, report.Monitors, 2)
+	assert.False(t, report.Monitors[0].IsBlocking)
+	assert.Equal(t, "Dublin, IE", report.Monitors[1].Locations[1].Label)
+}
+
+func TestWriteSARIFReport(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, writeSARIFReport(&buf, "batch-1", getMonitorTestResults(testAutomatedTestResult)))
+
+	var log sarifLog
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &log))
+	assert.Equal(t, "2.1.0", log.Version)
+	require.Len(t, log.Runs, 1)
+	assert.Len(t, log.Runs[0].Tool.Driver.Rules, 2)
+	require.Len(t, log.Runs[0].Results, 1)
+	assert.Equal(t, "Z1", log.Runs[0].Results[0].RuleID)
+	assert.Equal(t, "error", log.Runs[0].Results[0].Level)
+}
+
+func TestWriteReportsShouldMarkPendingMonitorsTimedOut(t *testing.T) {
+	batchResult := testAutomatedTestResult
+	batchResult.Status = synthetics.SyntheticsAutomatedTestStatusTypes.TIMEOUT
+	batchResult.Tests = append([]synthetics.SyntheticsAutomatedTestJobResult{}, testAutomatedTestResult.Tests...)
+	batchResult.Tests[2].Result = ""
+	batchResult.Tests[2].Duration = 0
+
+	dir := t.TempDir()
+	reports := []automatedTestReport{
+		{Format: reportFormatJUnit, Path: filepath.Join(dir, "synthetics.xml")},
+		{Format: reportFormatJSON, Path: filepath.Join(dir, "synthetics.json")},
+	}
+	require.NoError(t, writeAutomatedTestReports(reports, "batch-1", batchResult))
+
+	b, err := os.ReadFile(reports[0].Path)
+	require.NoError(t, err)
+	var suites junitTestSuites
+	require.NoError(t, xml.Unmarshal(b, &suites))
+	assert.Equal(t, 1, suites.Errors)
+	home := suites.Suites[0].TestCases[0]
+	require.NotNil(t, home.Error)
+	assert.Equal(t, "TIMEOUT", home.Error.Type)
+	assert.Equal(t, "Home did not complete in AWS_US_EAST_1", home.Error.Message)
+
+	b, err = os.ReadFile(reports[1].Path)
+	require.NoError(t, err)
+	var report jsonReport
+	require.NoError(t, json.Unmarshal(b, &report))
+	assert.Equal(t, "TIMEOUT", report.Status)
+	assert.Equal(t, jsonReportSummary{Failed: 1, Pending: 1}, report.Summary)
+	assert.Equal(t, "TIMEOUT", report.Monitors[0].Result)
+	assert.Equal(t, "FAILED", report.Monitors[1].Result)
+}
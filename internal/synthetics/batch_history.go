@@ -0,0 +1,111 @@
+package synthetics
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/newrelic/newrelic-cli/internal/config"
+)
+
+const (
+	batchHistoryFileName = "synthetics-batches.json"
+	batchHistoryMaxSize  = 100
+)
+
+// batchRecord is a batch of automated tests started from this machine, kept so
+// that the batch can be found again and resumed with run --batchId.
+type batchRecord struct {
+	BatchID   string    `json:"batchId"`
+	AccountID int       `json:"accountId"`
+	BatchName string    `json:"batchName,omitempty"`
+	Monitors  int       `json:"monitors"`
+	Status    string    `json:"status,omitempty"`
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+func batchHistoryPath() string {
+	return filepath.Join(config.BasePath, batchHistoryFileName)
+}
+
+// readBatchHistory returns the batches recorded at path, most recent first.
+func readBatchHistory(path string) ([]batchRecord, error) {
+	records := []batchRecord{}
+
+	content, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return records, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err = json.Unmarshal(content, &records); err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(records, func(i, j int) bool {
+		return records[i].CreatedAt.After(records[j].CreatedAt)
+	})
+
+	return records, nil
+}
+
+// recordBatch adds the batch to the history at path, or updates it when it is
+// already recorded.  Only the most recent batches are kept.
+func recordBatch(path string, record batchRecord) error {
+	records, err := readBatchHistory(path)
+	if err != nil {
+		return err
+	}
+
+	record.UpdatedAt = time.Now()
+
+	found := false
+	for i, r := range records {
+		if r.BatchID != record.BatchID {
+			continue
+		}
+
+		found = true
+		if record.CreatedAt.IsZero() {
+			record.CreatedAt = r.CreatedAt
+		}
+		if record.AccountID == 0 {
+			record.AccountID = r.AccountID
+		}
+		if record.BatchName == "" {
+			record.BatchName = r.BatchName
+		}
+		if record.Monitors == 0 {
+			record.Monitors = r.Monitors
+		}
+		records[i] = record
+	}
+
+	if !found {
+		if record.CreatedAt.IsZero() {
+			record.CreatedAt = record.UpdatedAt
+		}
+		records = append([]batchRecord{record}, records...)
+	}
+
+	if len(records) > batchHistoryMaxSize {
+		records = records[:batchHistoryMaxSize]
+	}
+
+	content, err := json.MarshalIndent(records, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err = os.MkdirAll(filepath.Dir(path), 0750); err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, content, 0600)
+}
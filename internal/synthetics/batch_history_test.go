@@ -0,0 +1,56 @@
+//go:build unit
+
+package synthetics
+
+import (
+	"fmt"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadBatchHistoryShouldReturnEmptyWithoutFile(t *testing.T) {
+	records, err := readBatchHistory(filepath.Join(t.TempDir(), batchHistoryFileName))
+
+	require.NoError(t, err)
+	assert.Empty(t, records)
+}
+
+func TestRecordBatchShouldListMostRecentFirst(t *testing.T) {
+	path := filepath.Join(t.TempDir(), batchHistoryFileName)
+	created := time.Now().Add(-time.Hour)
+
+	require.NoError(t, recordBatch(path, batchRecord{BatchID: "first", AccountID: 12345, BatchName: "nightly", Monitors: 3, Status: "IN_PROGRESS", CreatedAt: created}))
+	require.NoError(t, recordBatch(path, batchRecord{BatchID: "second", AccountID: 12345, Monitors: 1, Status: "IN_PROGRESS"}))
+	require.NoError(t, recordBatch(path, batchRecord{BatchID: "first", Status: "PASSED"}))
+
+	records, err := readBatchHistory(path)
+	require.NoError(t, err)
+	require.Len(t, records, 2)
+
+	assert.Equal(t, "second", records[0].BatchID)
+	assert.Equal(t, "first", records[1].BatchID)
+	assert.Equal(t, "PASSED", records[1].Status)
+	assert.Equal(t, "nightly", records[1].BatchName)
+	assert.Equal(t, 3, records[1].Monitors)
+	assert.Equal(t, 12345, records[1].AccountID)
+	assert.WithinDuration(t, created, records[1].CreatedAt, time.Second)
+}
+
+func TestRecordBatchShouldKeepRecentBatchesOnly(t *testing.T) {
+	path := filepath.Join(t.TempDir(), batchHistoryFileName)
+	start := time.Now().Add(-time.Hour)
+
+	for i := 0; i <= batchHistoryMaxSize; i++ {
+		record := batchRecord{BatchID: fmt.Sprintf("batch-%d", i), CreatedAt: start.Add(time.Duration(i) * time.Second)}
+		require.NoError(t, recordBatch(path, record))
+	}
+
+	records, err := readBatchHistory(path)
+	require.NoError(t, err)
+	assert.Len(t, records, batchHistoryMaxSize)
+	assert.Equal(t, start.Add(batchHistoryMaxSize*time.Second).Unix(), records[0].CreatedAt.Unix())
+}
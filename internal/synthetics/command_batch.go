@@ -0,0 +1,96 @@
+package synthetics
+
+import (
+	"fmt"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+
+	"github.com/newrelic/newrelic-cli/internal/client"
+	configAPI "github.com/newrelic/newrelic-cli/internal/config/api"
+	"github.com/newrelic/newrelic-cli/internal/output"
+	"github.com/newrelic/newrelic-cli/internal/utils"
+	"github.com/newrelic/newrelic-client-go/v2/pkg/synthetics"
+)
+
+var (
+	statusBatchID string
+	batchLimit    int
+)
+
+var cmdBatch = &cobra.Command{
+	Use:     "batch",
+	Short:   "Interact with batches of Synthetics automated tests",
+	Example: "newrelic synthetics batch --help",
+	Long:    "Interact with batches of Synthetics automated tests",
+}
+
+var cmdBatchStatus = &cobra.Command{
+	Use:   "status",
+	Short: "Get the status of a batch of Synthetics automated tests",
+	Long: `Get the status of a batch of Synthetics automated tests
+
+The status command fetches the status of the batch once, along with the results of
+the monitors it comprises.  Use the run command with --batchId to poll the batch
+until it completes.
+`,
+	Example: `newrelic synthetics batch status --batchId <batchId>`,
+	PreRun:  client.RequireClient,
+	Run: func(cmd *cobra.Command, args []string) {
+		accountID := configAPI.GetActiveProfileAccountID()
+
+		batchResult, err := client.NRClient.Synthetics.GetAutomatedTestResult(accountID, statusBatchID)
+		utils.LogIfFatal(err)
+
+		exitStatus, ok := globalResultExitCodes[batchResult.Status]
+		if !ok && batchResult.Status != synthetics.SyntheticsAutomatedTestStatusTypes.IN_PROGRESS {
+			log.Fatalf("unknown status %s for batch %s", batchResult.Status, statusBatchID)
+		}
+
+		if batchResult.Status != synthetics.SyntheticsAutomatedTestStatusTypes.IN_PROGRESS {
+			if err = recordBatch(batchHistoryPath(), batchRecord{BatchID: statusBatchID, AccountID: accountID, Status: string(batchResult.Status)}); err != nil {
+				log.Warnf("could not record batch %s: %s", statusBatchID, err)
+			}
+		}
+
+		renderMonitorTestsSummary(*batchResult, exitStatus)
+	},
+}
+
+var cmdBatchList = &cobra.Command{
+	Use:   "list",
+	Short: "List recent batches of Synthetics automated tests",
+	Long: `List recent batches of Synthetics automated tests
+
+The list command lists the batches started by the run command on this machine,
+most recent first, with the status they had when last fetched.  Batches still in
+progress can be resumed with run --batchId.
+`,
+	Example: `newrelic synthetics batch list --limit 5`,
+	Run: func(cmd *cobra.Command, args []string) {
+		records, err := readBatchHistory(batchHistoryPath())
+		utils.LogIfFatal(err)
+
+		if batchLimit > 0 && len(records) > batchLimit {
+			records = records[:batchLimit]
+		}
+
+		if len(records) == 0 {
+			fmt.Println("No batches found.")
+			return
+		}
+
+		utils.LogIfFatal(output.Print(records))
+	},
+}
+
+func init() {
+	Command.AddCommand(cmdBatch)
+
+	cmdBatchStatus.Flags().StringVar(&statusBatchID, "batchId", "", "ID of the batch")
+	utils.LogIfError(cmdBatchStatus.MarkFlagRequired("batchId"))
+	cmdBatch.AddCommand(cmdBatchStatus)
+
+	cmdBatchList.Flags().IntVar(&batchLimit, "limit", 20, "maximum number of batches to list, 0 for all")
+	cmdBatch.AddCommand(cmdBatchList)
+}
@@ -20,9 +20,21 @@ import (
 var (
 	batchFile         string
 	guid              []string
-	pollingInterval   = time.Second * 30
+	batchID           string
+	pollingInterval   time.Duration
+	pollingTimeout    time.Duration
 	progressIndicator = ux.NewSpinner()
-	nrdbLatency       = time.Second * 5
+	nrdbLatency       time.Duration
+	reportFlags       []string
+)
+
+const (
+	defaultPollingInterval = time.Second * 30
+	defaultNrdbLatency     = time.Second * 5
+
+	// pollingTimeoutExitCode is the exit code of the run command when the batch
+	// is still in progress once the polling timeout has elapsed.
+	pollingTimeoutExitCode = 4
 )
 
 var cmdRun = &cobra.Command{
@@ -36,10 +48,29 @@ specifications (such as overrides), and subsequently, keeps fetching the status
 time, until the status of the batch, which reflects the consolidated status of all monitors in the batch, is either
 success, failure or timed out.
 
-The command may be used with the following flags (the arguments --batchFile and --guid are mutually exclusive).
+The command may be used with the following flags (the arguments --batchFile, --guid and --batchId are mutually exclusive).
 
 newrelic synthetics run --batchFile filename.yml
 newrelic synthetics run --guid <guid1> --guid <guid2>
+
+A batch that is already running, for instance one whose CI job was interrupted, can be resumed with --batchId,
+which polls the status of the batch without creating a new one.  The ID of each batch is printed when it is
+created, and recent batches are listed by the batch list command.
+
+newrelic synthetics run --batchId <batchId>
+
+The status of the batch is fetched every --pollingInterval, 30s by default.  With --timeout, the command stops
+polling and exits with status 4 if the batch is still in progress once the timeout has elapsed.  The batch keeps
+running and can be resumed with --batchId.
+
+Once the batch completes, the results of each monitor can be written to files for
+CI systems with --report format=path, where format is junit, json or sarif.  The
+flag may be repeated.  Reports have a test case, or result, for each monitor in
+the batch, with its duration, failure message, result in each location and
+isBlocking setting.  When --timeout elapses first, the reports are written with
+the monitors still in progress as timed out.
+
+newrelic synthetics run --batchFile filename.yml --report junit=synthetics.xml --report json=synthetics.json
 `,
 	PreRun: client.RequireClient,
 	Run: func(cmd *cobra.Command, args []string) {
@@ -49,7 +80,21 @@ newrelic synthetics run --guid <guid1> --guid <guid2>
 			testsBatchID string
 		)
 		accountID := configAPI.GetActiveProfileAccountID()
-		if batchFile != "" || len(guid) != 0 {
+
+		reports, err := parseAutomatedTestReports(reportFlags)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		if pollingInterval <= 0 {
+			log.Fatal("--pollingInterval must be greater than 0")
+		}
+
+		if batchID != "" {
+			output.Printf("Resuming Batch ID: %s", batchID)
+			getAutomatedTestResults(accountID, batchID, reports)
+
+		} else if batchFile != "" || len(guid) != 0 {
 			config, err = parseConfiguration()
 			if err != nil {
 				log.Fatal(err)
@@ -58,9 +103,20 @@ newrelic synthetics run --guid <guid1> --guid <guid2>
 			testsBatchID = createAutomatedTestBatch(config)
 			output.Printf("Generated Batch ID: %s", testsBatchID)
 
+			record := batchRecord{
+				BatchID:   testsBatchID,
+				AccountID: accountID,
+				BatchName: config.Config.BatchName,
+				Monitors:  len(config.Tests),
+				Status:    string(synthetics.SyntheticsAutomatedTestStatusTypes.IN_PROGRESS),
+			}
+			if err = recordBatch(batchHistoryPath(), record); err != nil {
+				log.Warnf("could not record batch %s: %s", testsBatchID, err)
+			}
+
 			// can be ignored if there is no initial tick by the ticker
 			time.Sleep(nrdbLatency)
-			getAutomatedTestResults(accountID, testsBatchID)
+			getAutomatedTestResults(accountID, testsBatchID, reports)
 
 		} else {
 			utils.LogIfError(cmd.Help())
@@ -73,10 +129,15 @@ newrelic synthetics run --guid <guid1> --guid <guid2>
 func init() {
 	cmdRun.Flags().StringVarP(&batchFile, "batchFile", "b", "", "Path to the YAML file comprising GUIDs of monitors and associated configuration")
 	cmdRun.Flags().StringSliceVarP(&guid, "guid", "g", nil, "List of GUIDs of monitors to include in the batch and run automated tests on")
+	cmdRun.Flags().StringVar(&batchID, "batchId", "", "ID of an existing batch to resume polling, instead of creating a new batch")
+	cmdRun.Flags().StringArrayVar(&reportFlags, "report", nil, "Write the results of the batch to a file once it completes, as format=path where format is junit, json or sarif")
+	cmdRun.Flags().DurationVar(&pollingInterval, "pollingInterval", defaultPollingInterval, "Interval between requests for the status of the batch")
+	cmdRun.Flags().DurationVar(&pollingTimeout, "timeout", 0, "Stop polling once the timeout has elapsed, 0 to poll until the batch completes")
+	cmdRun.Flags().DurationVar(&nrdbLatency, "initialDelay", defaultNrdbLatency, "Delay before the status of a new batch is first requested")
 	Command.AddCommand(cmdRun)
 
 	// MarkFlagsMutuallyExclusive allows one flag at once be invoked
-	cmdRun.MarkFlagsMutuallyExclusive("batchFile", "guid")
+	cmdRun.MarkFlagsMutuallyExclusive("batchFile", "guid", "batchId")
 }
 
 // parseConfiguration helps parse the inputs given to this command, based on the format specified (YAML or command line GUIDs)
@@ -155,12 +216,18 @@ func createAutomatedTestBatch(config StartAutomatedTestInput) string {
 }
 
 // getAutomatedTestResults performs an API call at regular intervals of time (when the pollingInterval has elapsed)
-// to fetch the consolidated status of the batch, and the results of monitors the batch comprises
-func getAutomatedTestResults(accountID int, testsBatchID string) {
+// to fetch the consolidated status of the batch, and the results of monitors the batch comprises.
+// The reports are written once the batch completes, or the pollingTimeout elapses.  Polling stops once the pollingTimeout, if any, has elapsed.
+func getAutomatedTestResults(accountID int, testsBatchID string, reports []automatedTestReport) {
 	// An infinite loop
 	ticker := time.NewTicker(pollingInterval)
 	defer ticker.Stop()
 
+	var deadline time.Time
+	if pollingTimeout > 0 {
+		deadline = time.Now().Add(pollingTimeout)
+	}
+
 	for progressIndicator.Start("Fetching the status of tests in the batch...."); true; <-ticker.C {
 		batchResult, err := client.NRClient.Synthetics.GetAutomatedTestResult(accountID, testsBatchID)
 		progressIndicator.Stop()
@@ -183,8 +250,26 @@ func getAutomatedTestResults(accountID int, testsBatchID string) {
 
 		// exit, if the status is not IN_PROGRESS
 		if batchResult.Status != synthetics.SyntheticsAutomatedTestStatusTypes.IN_PROGRESS {
+			if err = recordBatch(batchHistoryPath(), batchRecord{BatchID: testsBatchID, AccountID: accountID, Status: string(batchResult.Status)}); err != nil {
+				log.Warnf("could not record batch %s: %s", testsBatchID, err)
+			}
+			if err = writeAutomatedTestReports(reports, testsBatchID, *batchResult); err != nil {
+				log.Error(err)
+			}
 			os.Exit(*exitStatus)
 		}
+
+		if !deadline.IsZero() && time.Now().Add(pollingInterval).After(deadline) {
+			// The reports have the monitors completed so far, and those still
+			// in progress as timed out.
+			timedOut := *batchResult
+			timedOut.Status = synthetics.SyntheticsAutomatedTestStatusTypes.TIMEOUT
+			if err = writeAutomatedTestReports(reports, testsBatchID, timedOut); err != nil {
+				log.Error(err)
+			}
+			log.Errorf("Batch %s is still in progress after %s, resume polling with: newrelic synthetics run --batchId %s", testsBatchID, pollingTimeout, testsBatchID)
+			os.Exit(pollingTimeoutExitCode)
+		}
 		progressIndicator.Start("Fetching the status of tests in the batch....")
 	}
 }
@@ -0,0 +1,25 @@
+//go:build unit
+
+package synthetics
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/newrelic/newrelic-cli/internal/testcobra"
+)
+
+func TestSyntheticsBatchStatus(t *testing.T) {
+	assert.Equal(t, "status", cmdBatchStatus.Name())
+
+	testcobra.CheckCobraMetadata(t, cmdBatchStatus)
+	testcobra.CheckCobraRequiredFlags(t, cmdBatchStatus, []string{"batchId"})
+}
+
+func TestSyntheticsBatchList(t *testing.T) {
+	assert.Equal(t, "list", cmdBatchList.Name())
+
+	testcobra.CheckCobraMetadata(t, cmdBatchList)
+	testcobra.CheckCobraRequiredFlags(t, cmdBatchList, []string{})
+}
@@ -0,0 +1,204 @@
+package synthetics
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+
+	"github.com/newrelic/newrelic-cli/internal/client"
+	configAPI "github.com/newrelic/newrelic-cli/internal/config/api"
+	"github.com/newrelic/newrelic-cli/internal/output"
+	"github.com/newrelic/newrelic-cli/internal/utils"
+	"github.com/newrelic/newrelic-client-go/v2/pkg/synthetics"
+)
+
+var (
+	credentialKey         string
+	credentialDescription string
+	credentialValueFile   string
+)
+
+var credentialKeyRegex = regexp.MustCompile(`^[A-Z0-9_]+$`)
+
+var cmdCredential = &cobra.Command{
+	Use:     "credential",
+	Short:   "Manage New Relic Synthetics secure credentials",
+	Example: "newrelic synthetics credential --help",
+	Long: `Manage New Relic Synthetics secure credentials
+
+Secure credentials store values, such as passwords and API keys, used by the
+scripts of scripted monitors through $secure.KEY.  Values are read from a file
+given with --valueFile, or from stdin, so that they never appear in the shell
+history.
+`,
+}
+
+var cmdCredentialCreate = &cobra.Command{
+	Use:   "create",
+	Short: "Create a New Relic Synthetics secure credential",
+	Long: `Create a New Relic Synthetics secure credential
+
+The create command creates a secure credential with the value read from the file
+given with --valueFile, or from stdin.  Keys may only contain uppercase letters,
+numbers and underscores.
+`,
+	Example: `newrelic synthetics credential create --key API_TOKEN --description "Checkout API token" --valueFile token.txt
+vault read -field=token secret/checkout | newrelic synthetics credential create --key API_TOKEN`,
+	PreRun: client.RequireClient,
+	Run: func(cmd *cobra.Command, args []string) {
+		accountID := configAPI.RequireActiveProfileAccountID()
+		key, err := parseCredentialKey(credentialKey)
+		utils.LogIfFatal(err)
+
+		value, err := readCredentialValue(credentialValueFile, os.Stdin, utils.StdinExists())
+		utils.LogIfFatal(err)
+
+		result, err := client.NRClient.Synthetics.SyntheticsCreateSecureCredential(accountID, credentialDescription, key, synthetics.SecureValue(value))
+		utils.LogIfFatal(err)
+		utils.LogIfFatal(secureCredentialErrors(result.Errors))
+
+		utils.LogIfFatal(output.Print(result))
+	},
+}
+
+var cmdCredentialUpdate = &cobra.Command{
+	Use:   "update",
+	Short: "Update a New Relic Synthetics secure credential",
+	Long: `Update a New Relic Synthetics secure credential
+
+The update command replaces the value and description of a secure credential,
+with the value read from the file given with --valueFile, or from stdin.
+`,
+	Example: `newrelic synthetics credential update --key API_TOKEN --valueFile token.txt`,
+	PreRun:  client.RequireClient,
+	Run: func(cmd *cobra.Command, args []string) {
+		accountID := configAPI.RequireActiveProfileAccountID()
+		key, err := parseCredentialKey(credentialKey)
+		utils.LogIfFatal(err)
+
+		value, err := readCredentialValue(credentialValueFile, os.Stdin, utils.StdinExists())
+		utils.LogIfFatal(err)
+
+		result, err := client.NRClient.Synthetics.SyntheticsUpdateSecureCredential(accountID, credentialDescription, key, synthetics.SecureValue(value))
+		utils.LogIfFatal(err)
+		utils.LogIfFatal(secureCredentialErrors(result.Errors))
+
+		utils.LogIfFatal(output.Print(result))
+	},
+}
+
+var cmdCredentialDelete = &cobra.Command{
+	Use:   "delete",
+	Short: "Delete a New Relic Synthetics secure credential",
+	Long: `Delete a New Relic Synthetics secure credential
+
+The delete command deletes the secure credential with the key.  Monitors whose
+scripts use the credential will fail until it is created again.
+`,
+	Example: `newrelic synthetics credential delete --key API_TOKEN`,
+	PreRun:  client.RequireClient,
+	Run: func(cmd *cobra.Command, args []string) {
+		accountID := configAPI.RequireActiveProfileAccountID()
+		key, err := parseCredentialKey(credentialKey)
+		utils.LogIfFatal(err)
+
+		result, err := client.NRClient.Synthetics.SyntheticsDeleteSecureCredential(accountID, key)
+		utils.LogIfFatal(err)
+		utils.LogIfFatal(secureCredentialErrors(result.Errors))
+
+		log.Infof("secure credential %s deleted", key)
+	},
+}
+
+var cmdCredentialList = &cobra.Command{
+	Use:   "list",
+	Short: "List New Relic Synthetics secure credentials",
+	Long: `List New Relic Synthetics secure credentials
+
+The list command lists the keys of the secure credentials of the account.  The
+values of secure credentials cannot be read back.
+`,
+	Example: `newrelic synthetics credential list`,
+	PreRun:  client.RequireClient,
+	Run: func(cmd *cobra.Command, args []string) {
+		accountID := configAPI.RequireActiveProfileAccountID()
+
+		results, err := newMonitorClient(client.NRClient).searchSyntheticsEntities(utils.SignalCtx, "SECURE_CRED", accountID)
+		utils.LogIfFatal(err)
+
+		utils.LogIfFatal(output.Print(results))
+	},
+}
+
+// parseCredentialKey returns the key in uppercase, checking it is a valid
+// secure credential key.
+func parseCredentialKey(key string) (string, error) {
+	key = strings.ToUpper(strings.TrimSpace(key))
+	if !credentialKeyRegex.MatchString(key) {
+		return "", fmt.Errorf("invalid secure credential key %q, keys may only contain letters, numbers and underscores", key)
+	}
+
+	return key, nil
+}
+
+// readCredentialValue reads the value of a secure credential from the file at
+// path, or from stdin when path is empty.  A single trailing newline is
+// removed, as added by most editors and by echo.
+func readCredentialValue(path string, stdin io.Reader, stdinExists bool) (string, error) {
+	var content []byte
+	var err error
+
+	switch {
+	case path != "" && path != "-":
+		content, err = os.ReadFile(path)
+	case stdinExists:
+		content, err = io.ReadAll(stdin)
+	default:
+		return "", fmt.Errorf("the secure credential value must be given with --valueFile or piped to stdin")
+	}
+	if err != nil {
+		return "", err
+	}
+
+	value := strings.TrimSuffix(strings.TrimSuffix(string(content), "\n"), "\r")
+	if value == "" {
+		return "", fmt.Errorf("the secure credential value is empty")
+	}
+
+	return value, nil
+}
+
+func secureCredentialErrors(errs []synthetics.SyntheticsError) error {
+	messages := []string{}
+	for _, e := range errs {
+		messages = append(messages, fmt.Sprintf("%s: %s", e.Type, e.Description))
+	}
+
+	if len(messages) == 0 {
+		return nil
+	}
+
+	return fmt.Errorf("%s", strings.Join(messages, "; "))
+}
+
+func init() {
+	Command.AddCommand(cmdCredential)
+
+	for _, cmd := range []*cobra.Command{cmdCredentialCreate, cmdCredentialUpdate, cmdCredentialDelete} {
+		cmd.Flags().StringVarP(&credentialKey, "key", "k", "", "the key of the secure credential")
+		utils.LogIfError(cmd.MarkFlagRequired("key"))
+		cmdCredential.AddCommand(cmd)
+	}
+
+	for _, cmd := range []*cobra.Command{cmdCredentialCreate, cmdCredentialUpdate} {
+		cmd.Flags().StringVarP(&credentialDescription, "description", "d", "", "the description of the secure credential")
+		cmd.Flags().StringVar(&credentialValueFile, "valueFile", "", "the file to read the value of the secure credential from, stdin when omitted")
+	}
+
+	cmdCredential.AddCommand(cmdCredentialList)
+}
@@ -0,0 +1,75 @@
+//go:build unit
+
+package synthetics
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/newrelic/newrelic-cli/internal/testcobra"
+)
+
+func TestSyntheticsCredential(t *testing.T) {
+	testcobra.CheckCobraMetadata(t, cmdCredential)
+
+	for _, cmd := range []struct {
+		name     string
+		required []string
+	}{
+		{"create", []string{"key"}},
+		{"update", []string{"key"}},
+		{"delete", []string{"key"}},
+		{"list", []string{}},
+	} {
+		c, _, err := cmdCredential.Find([]string{cmd.name})
+		require.NoError(t, err)
+		assert.Equal(t, cmd.name, c.Name())
+
+		testcobra.CheckCobraMetadata(t, c)
+		testcobra.CheckCobraRequiredFlags(t, c, cmd.required)
+	}
+}
+
+func TestSyntheticsLocation(t *testing.T) {
+	testcobra.CheckCobraMetadata(t, cmdLocation)
+
+	testcobra.CheckCobraMetadata(t, cmdLocationList)
+	testcobra.CheckCobraRequiredFlags(t, cmdLocationList, []string{})
+	testcobra.CheckCobraMetadata(t, cmdLocationCreate)
+	testcobra.CheckCobraRequiredFlags(t, cmdLocationCreate, []string{"name"})
+	testcobra.CheckCobraMetadata(t, cmdLocationDelete)
+	testcobra.CheckCobraRequiredFlags(t, cmdLocationDelete, []string{"guid"})
+}
+
+func TestParseCredentialKeyShouldUppercaseKeys(t *testing.T) {
+	key, err := parseCredentialKey(" api_token ")
+	require.NoError(t, err)
+	assert.Equal(t, "API_TOKEN", key)
+
+	_, err = parseCredentialKey("api-token")
+	assert.Error(t, err)
+}
+
+func TestReadCredentialValueShouldReadFileOrStdin(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "token.txt")
+	require.NoError(t, os.WriteFile(path, []byte("s3cr3t\n"), 0600))
+
+	value, err := readCredentialValue(path, strings.NewReader("ignored"), true)
+	require.NoError(t, err)
+	assert.Equal(t, "s3cr3t", value)
+
+	value, err = readCredentialValue("", strings.NewReader("multi\nline\r\n"), true)
+	require.NoError(t, err)
+	assert.Equal(t, "multi\nline", value)
+
+	_, err = readCredentialValue("", strings.NewReader(""), false)
+	assert.EqualError(t, err, "the secure credential value must be given with --valueFile or piped to stdin")
+
+	_, err = readCredentialValue("-", strings.NewReader("\n"), true)
+	assert.EqualError(t, err, "the secure credential value is empty")
+}
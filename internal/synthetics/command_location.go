@@ -0,0 +1,120 @@
+package synthetics
+
+import (
+	"fmt"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+
+	"github.com/newrelic/newrelic-cli/internal/client"
+	configAPI "github.com/newrelic/newrelic-cli/internal/config/api"
+	"github.com/newrelic/newrelic-cli/internal/output"
+	"github.com/newrelic/newrelic-cli/internal/utils"
+	"github.com/newrelic/newrelic-client-go/v2/pkg/synthetics"
+)
+
+var (
+	locationName                    string
+	locationDescription             string
+	locationGUID                    string
+	locationVerifiedScriptExecution bool
+)
+
+var cmdLocation = &cobra.Command{
+	Use:     "location",
+	Short:   "Manage New Relic Synthetics private locations",
+	Example: "newrelic synthetics location --help",
+	Long:    "Manage New Relic Synthetics private locations",
+}
+
+var cmdLocationList = &cobra.Command{
+	Use:   "list",
+	Short: "List New Relic Synthetics private locations",
+	Long: `List New Relic Synthetics private locations
+
+The list command lists the private locations of the account, with the GUIDs used
+to run monitors from them.
+`,
+	Example: `newrelic synthetics location list`,
+	PreRun:  client.RequireClient,
+	Run: func(cmd *cobra.Command, args []string) {
+		accountID := configAPI.RequireActiveProfileAccountID()
+
+		results, err := newMonitorClient(client.NRClient).searchSyntheticsEntities(utils.SignalCtx, "PRIVATE_LOCATION", accountID)
+		utils.LogIfFatal(err)
+
+		utils.LogIfFatal(output.Print(results))
+	},
+}
+
+var cmdLocationCreate = &cobra.Command{
+	Use:   "create",
+	Short: "Create a New Relic Synthetics private location",
+	Long: `Create a New Relic Synthetics private location
+
+The create command creates a private location.  The key of the location, printed
+once it is created, is used to configure the job managers of the location.  Use
+--verifiedScriptExecution to require a password to run scripted monitors from the
+location.
+`,
+	Example: `newrelic synthetics location create --name "Data center" --description "Behind the firewall"`,
+	PreRun:  client.RequireClient,
+	Run: func(cmd *cobra.Command, args []string) {
+		accountID := configAPI.RequireActiveProfileAccountID()
+
+		result, err := client.NRClient.Synthetics.SyntheticsCreatePrivateLocation(accountID, locationDescription, locationName, locationVerifiedScriptExecution)
+		utils.LogIfFatal(err)
+		utils.LogIfFatal(privateLocationErrors(result.Errors))
+
+		utils.LogIfFatal(output.Print(result))
+	},
+}
+
+var cmdLocationDelete = &cobra.Command{
+	Use:   "delete",
+	Short: "Delete a New Relic Synthetics private location",
+	Long: `Delete a New Relic Synthetics private location
+
+The delete command deletes the private location with the GUID.  Locations still
+used by monitors cannot be deleted.
+`,
+	Example: `newrelic synthetics location delete --guid "<locationGUID>"`,
+	PreRun:  client.RequireClient,
+	Run: func(cmd *cobra.Command, args []string) {
+		result, err := client.NRClient.Synthetics.SyntheticsDeletePrivateLocation(synthetics.EntityGUID(locationGUID))
+		utils.LogIfFatal(err)
+		utils.LogIfFatal(privateLocationErrors(result.Errors))
+
+		log.Infof("private location %s deleted", locationGUID)
+	},
+}
+
+func privateLocationErrors(errs []synthetics.SyntheticsPrivateLocationMutationError) error {
+	messages := []string{}
+	for _, e := range errs {
+		messages = append(messages, fmt.Sprintf("%s: %s", e.Type, e.Description))
+	}
+
+	if len(messages) == 0 {
+		return nil
+	}
+
+	return fmt.Errorf("%s", strings.Join(messages, "; "))
+}
+
+func init() {
+	Command.AddCommand(cmdLocation)
+
+	cmdLocation.AddCommand(cmdLocationList)
+
+	cmdLocationCreate.Flags().StringVarP(&locationName, "name", "n", "", "the name of the private location")
+	cmdLocationCreate.Flags().StringVarP(&locationDescription, "description", "d", "", "the description of the private location")
+	cmdLocationCreate.Flags().BoolVar(&locationVerifiedScriptExecution, "verifiedScriptExecution", false, "require a password to run scripted monitors from the location")
+	utils.LogIfError(cmdLocationCreate.MarkFlagRequired("name"))
+	cmdLocation.AddCommand(cmdLocationCreate)
+
+	cmdLocationDelete.Flags().StringVar(&locationGUID, "guid", "", "the GUID of the private location")
+	utils.LogIfError(cmdLocationDelete.MarkFlagRequired("guid"))
+	cmdLocation.AddCommand(cmdLocationDelete)
+}
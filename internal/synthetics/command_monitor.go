@@ -1,11 +1,10 @@
 package synthetics
 
 import (
-	"encoding/json"
+	"context"
 	"strconv"
 	"strings"
 
-	"github.com/itchyny/gojq"
 	log "github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 
@@ -18,9 +17,15 @@ import (
 )
 
 var (
-	statusFilter string
-	monitorName  string
-	monitorID    string
+	statusFilter   string
+	monitorName    string
+	monitorID      string
+	listTypes      []string
+	listPeriods    []string
+	listLocations  []string
+	listTags       []string
+	listLimit      int
+	listLastResult bool
 )
 
 // Command represents the synthetics command
@@ -61,64 +66,143 @@ var cmdMonList = &cobra.Command{
 	Short: "List New Relic Synthetics monitors",
 	Long: `List New Relic Synthetics monitors
 
-The list command performs a query for all Synthetics monitors, optionally filtered on the status field.
+The list command searches the Synthetics monitors of all monitor types, optionally
+filtered on their type, status, period, location and tags.  Filters given more than
+once, or as comma separated values, match any of their values.  Monitors are
+fetched a page at a time, use --limit to stop after the first monitors found.
+
+With --lastResult, the result of the last check of each monitor in the last day is
+included.
+
+Monitors are listed with their entity GUID, account ID and tags.  Their SLA
+threshold, user ID, API version and creation and modification times are not
+known to entity search and are not listed.
 `,
-	Example: `newrelic synthetics monitor list --statusFilter "DISABLED, MUTED"`,
-	PreRun:  client.RequireClient,
+	Example: `newrelic synthetics monitor list --statusFilter "DISABLED, MUTED"
+newrelic synthetics monitor list --type SCRIPT_API --type SCRIPT_BROWSER --tag team:checkout --lastResult`,
+	PreRun: client.RequireClient,
 	Run: func(cmd *cobra.Command, args []string) {
-		var results []*synthetics.Monitor
-		var err error
-		var jqFilter string
+		filter, err := monitorListFilter()
+		utils.LogIfFatal(err)
 
-		results, err = client.NRClient.Synthetics.ListMonitors()
+		results, err := listMonitors(utils.SignalCtx, newMonitorClient(client.NRClient), filter, listLimit, listLastResult)
 		utils.LogIfFatal(err)
 
-		if statusFilter != "" {
-			statusFilter = strings.ToUpper(statusFilter)
-			var replacer = strings.NewReplacer(
-				" ", "",
-				"\r\n", "",
-				"\n\r", "",
-				"\r", "",
-				"\n", "",
-				"\t", "",
-			)
-			statusFilter = replacer.Replace(statusFilter)
-			statusFilter = strings.ReplaceAll(statusFilter, ",", `", "`)
-			jqFilter = `.[] | select(.status as $s | ["` + statusFilter + `"] | index($s))`
-			query, err := gojq.Parse(jqFilter)
-			if err != nil {
-				utils.LogIfFatal(err)
-			}
+		utils.LogIfFatal(output.Print(results))
+	},
+}
 
-			bytes, err := json.Marshal(results)
-			if err != nil {
-				utils.LogIfFatal(err)
-			}
+// monitorListItem is a monitor as listed by the list command, with the fields
+// of synthetics.Monitor and those only known from its entity.
+type monitorListItem struct {
+	synthetics.Monitor
+	GUID       string              `json:"guid"`
+	AccountID  int                 `json:"accountId"`
+	Tags       map[string][]string `json:"tags,omitempty"`
+	LastResult string              `json:"lastResult,omitempty"`
+}
 
-			var obj interface{}
-			err = json.Unmarshal(bytes, &obj)
-			if err != nil {
-				utils.LogIfFatal(err)
-			}
+// monitorListFilter returns the filter of the list command flags.
+func monitorListFilter() (monitorFilter, error) {
+	filter := monitorFilter{
+		AccountID: configAPI.GetActiveProfileAccountID(),
+		Types:     upperValues(listTypes),
+		Statuses:  upperValues(strings.Split(statusFilter, ",")),
+		Locations: listLocations,
+	}
+
+	for _, p := range listPeriods {
+		minutes, err := parseMonitorPeriod(p)
+		if err != nil {
+			return filter, err
+		}
+		filter.Periods = append(filter.Periods, minutes)
+	}
 
-			iter := query.Run(obj)
-			for {
-				v, ok := iter.Next()
-				if !ok {
-					break
-				}
+	tags, err := parseMonitorTags(listTags)
+	if err != nil {
+		return filter, err
+	}
+	filter.Tags = tags
 
-				if err, ok := v.(error); ok {
-					utils.LogIfFatal(err)
-				}
+	return filter, nil
+}
 
-				utils.LogIfFatal(output.Print(v))
+// listMonitors returns the monitors matching the filter, with the result of
+// their last check when lastResult is set.
+func listMonitors(ctx context.Context, c *monitorClient, filter monitorFilter, limit int, lastResult bool) ([]monitorListItem, error) {
+	entities, err := c.searchMonitorEntities(ctx, filter, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	items := []monitorListItem{}
+	guidsByAccount := map[int][]string{}
+	for _, e := range entities {
+		item := monitorListItem{
+			Monitor: synthetics.Monitor{
+				ID:        e.MonitorID,
+				Name:      e.Name,
+				Type:      synthetics.MonitorType(e.MonitorType),
+				Frequency: uint(e.Period),
+				URI:       e.MonitoredURL,
+				Locations: append(append([]string{}, e.tagValues("publicLocation")...), e.tagValues("privateLocation")...),
+				Status:    synthetics.MonitorStatusType(e.MonitorSummary.Status),
+				Options: synthetics.MonitorOptions{
+					ValidationString:       e.tag("responseValidationText"),
+					VerifySSL:              e.tag("useTlsValidation") == "true",
+					BypassHEADRequest:      e.tag("bypassHEADRequest") == "true",
+					TreatRedirectAsFailure: e.tag("redirectIsFailure") == "true",
+				},
+			},
+			GUID:      e.GUID,
+			AccountID: e.AccountID,
+		}
+
+		for _, t := range e.Tags {
+			if !monitorSystemTagKeys[t.Key] {
+				if item.Tags == nil {
+					item.Tags = map[string][]string{}
+				}
+				item.Tags[t.Key] = t.Values
 			}
-		} else {
-			utils.LogIfFatal(output.Print(results))
 		}
-	},
+
+		items = append(items, item)
+		guidsByAccount[e.AccountID] = append(guidsByAccount[e.AccountID], e.GUID)
+	}
+
+	if !lastResult {
+		return items, nil
+	}
+
+	results := map[string]string{}
+	for accountID, guids := range guidsByAccount {
+		accountResults, err := c.getLastResults(ctx, accountID, guids)
+		if err != nil {
+			return nil, err
+		}
+		for guid, result := range accountResults {
+			results[guid] = result
+		}
+	}
+
+	for i := range items {
+		items[i].LastResult = results[items[i].GUID]
+	}
+
+	return items, nil
+}
+
+func upperValues(values []string) []string {
+	upper := []string{}
+	for _, v := range values {
+		if v = strings.ToUpper(strings.TrimSpace(v)); v != "" {
+			upper = append(upper, v)
+		}
+	}
+
+	return upper
 }
 
 var cmdMonSearch = &cobra.Command{
@@ -178,6 +262,12 @@ func init() {
 	cmdMon.AddCommand(cmdMonGet)
 
 	cmdMonList.Flags().StringVarP(&statusFilter, "statusFilter", "s", "", "filter the results on the status field. Possible values ENABLED, DISABLED, MUTED. Comma separated.")
+	cmdMonList.Flags().StringSliceVar(&listTypes, "type", []string{}, "filter the results on the monitor type, such as SIMPLE, BROWSER, SCRIPT_API, SCRIPT_BROWSER, STEP_MONITOR, CERT_CHECK or BROKEN_LINKS")
+	cmdMonList.Flags().StringSliceVar(&listPeriods, "period", []string{}, "filter the results on the period, in minutes or such as EVERY_5_MINUTES")
+	cmdMonList.Flags().StringSliceVar(&listLocations, "location", []string{}, "filter the results on a public location, such as AWS_US_EAST_1, or a private location GUID")
+	cmdMonList.Flags().StringArrayVar(&listTags, "tag", []string{}, "filter the results on a tag, as key:value")
+	cmdMonList.Flags().IntVar(&listLimit, "limit", 0, "maximum number of monitors to list, 0 for all")
+	cmdMonList.Flags().BoolVar(&listLastResult, "lastResult", false, "include the result of the last check of each monitor")
 	cmdMon.AddCommand(cmdMonList)
 
 	cmdMonSearch.Flags().StringVarP(&monitorName, "name", "n", "", "search for results matching the given Synthetics monitor name")
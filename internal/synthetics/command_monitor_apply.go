@@ -0,0 +1,156 @@
+package synthetics
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/newrelic/newrelic-cli/internal/client"
+	configAPI "github.com/newrelic/newrelic-cli/internal/config/api"
+	"github.com/newrelic/newrelic-cli/internal/utils"
+)
+
+var (
+	applyMonitorPath string
+	applyDryRun      bool
+)
+
+var cmdMonApply = &cobra.Command{
+	Use:   "apply",
+	Short: "Create or update New Relic Synthetics monitors from YAML specs",
+	Long: `Create or update New Relic Synthetics monitors from YAML specs
+
+The apply command reads the monitor specs of a file, or of every .yml and .yaml
+file of a directory, as written by the export command.  Monitors are matched by
+name and account: monitors that do not exist are created, monitors that differ
+from their spec are updated and the others are left untouched, so applying the
+same specs twice makes no changes.
+
+The plan of changes is printed before it is applied.  Use --dryRun to print the
+plan only.  Specs without an accountId use the account of the active profile.
+`,
+	Example: `newrelic synthetics monitor apply -f monitors/ --dryRun
+newrelic synthetics monitor apply -f monitors/`,
+	PreRun: client.RequireClient,
+	Run: func(cmd *cobra.Command, args []string) {
+		accountID := configAPI.GetActiveProfileAccountID()
+		specs, err := readMonitorSpecs(applyMonitorPath, accountID)
+		utils.LogIfFatal(err)
+
+		c := newMonitorClient(client.NRClient)
+		plan, err := planMonitors(utils.SignalCtx, c, specs, accountID)
+		utils.LogIfFatal(err)
+
+		printMonitorPlan(os.Stdout, plan)
+		if applyDryRun {
+			return
+		}
+
+		utils.LogIfFatal(applyMonitorPlan(utils.SignalCtx, c, plan))
+	},
+}
+
+type monitorAction string
+
+const (
+	monitorActionCreate    monitorAction = "create"
+	monitorActionUpdate    monitorAction = "update"
+	monitorActionUnchanged monitorAction = "unchanged"
+)
+
+// monitorChange is the change needed to bring a monitor in line with its spec.
+type monitorChange struct {
+	Action  monitorAction
+	Spec    MonitorSpec
+	Current *MonitorSpec
+	Diff    []string
+}
+
+// planMonitors compares the specs to the existing monitors, defaulting the
+// account of the specs to accountID.
+func planMonitors(ctx context.Context, c *monitorClient, specs []MonitorSpec, accountID int) ([]monitorChange, error) {
+	plan := []monitorChange{}
+
+	for _, s := range specs {
+		if s.AccountID == 0 {
+			if accountID == 0 {
+				return nil, fmt.Errorf("monitor %s has no accountId, use --accountId or set it in your profile", s.Name)
+			}
+			s.AccountID = accountID
+		}
+
+		current, err := c.findMonitor(ctx, s.AccountID, s.Name)
+		if err != nil {
+			return nil, err
+		}
+
+		if current == nil {
+			plan = append(plan, monitorChange{Action: monitorActionCreate, Spec: s})
+			continue
+		}
+
+		if current.Type != s.Type {
+			return nil, fmt.Errorf("monitor %s has type %s and cannot be changed to %s, delete it first", s.Name, current.Type, s.Type)
+		}
+
+		change := monitorChange{Action: monitorActionUnchanged, Spec: s, Current: current}
+		if change.Diff = monitorSpecDiff(*current, s); len(change.Diff) > 0 {
+			change.Action = monitorActionUpdate
+		}
+		plan = append(plan, change)
+	}
+
+	return plan, nil
+}
+
+func printMonitorPlan(w io.Writer, plan []monitorChange) {
+	counts := map[monitorAction]int{}
+
+	for _, change := range plan {
+		counts[change.Action]++
+
+		switch change.Action {
+		case monitorActionCreate:
+			fmt.Fprintf(w, "+ create %s (%s) in account %d\n", change.Spec.Name, change.Spec.Type, change.Spec.AccountID)
+		case monitorActionUpdate:
+			fmt.Fprintf(w, "~ update %s (%s)\n", change.Spec.Name, change.Current.GUID)
+			for _, d := range change.Diff {
+				fmt.Fprintf(w, "    %s\n", d)
+			}
+		case monitorActionUnchanged:
+			fmt.Fprintf(w, "= unchanged %s (%s)\n", change.Spec.Name, change.Current.GUID)
+		}
+	}
+
+	fmt.Fprintf(w, "\nPlan: %d to create, %d to update, %d unchanged.\n", counts[monitorActionCreate], counts[monitorActionUpdate], counts[monitorActionUnchanged])
+}
+
+func applyMonitorPlan(ctx context.Context, c *monitorClient, plan []monitorChange) error {
+	for _, change := range plan {
+		switch change.Action {
+		case monitorActionCreate:
+			guid, err := c.createMonitor(ctx, change.Spec)
+			if err != nil {
+				return fmt.Errorf("could not create monitor %s: %s", change.Spec.Name, err)
+			}
+			fmt.Printf("created %s (%s)\n", change.Spec.Name, guid)
+		case monitorActionUpdate:
+			if err := c.updateMonitor(ctx, change.Current.GUID, change.Spec); err != nil {
+				return fmt.Errorf("could not update monitor %s: %s", change.Spec.Name, err)
+			}
+			fmt.Printf("updated %s (%s)\n", change.Spec.Name, change.Current.GUID)
+		}
+	}
+
+	return nil
+}
+
+func init() {
+	cmdMonApply.Flags().StringVarP(&applyMonitorPath, "file", "f", "", "a monitor spec file, or a directory of monitor spec files")
+	cmdMonApply.Flags().BoolVar(&applyDryRun, "dryRun", false, "print the plan of changes without applying it")
+	utils.LogIfError(cmdMonApply.MarkFlagRequired("file"))
+	cmdMon.AddCommand(cmdMonApply)
+}
@@ -0,0 +1,120 @@
+package synthetics
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+
+	"github.com/newrelic/newrelic-cli/internal/client"
+	configAPI "github.com/newrelic/newrelic-cli/internal/config/api"
+	"github.com/newrelic/newrelic-cli/internal/utils"
+)
+
+var (
+	exportMonitorGUIDs []string
+	exportAllMonitors  bool
+	exportOutputDir    string
+)
+
+var cmdMonExport = &cobra.Command{
+	Use:   "export",
+	Short: "Export New Relic Synthetics monitors as YAML specs",
+	Long: `Export New Relic Synthetics monitors as YAML specs
+
+The export command writes a YAML spec for each monitor to the output directory,
+ready to be versioned and applied with the apply command.  The script of
+scripted API and scripted browser monitors is written to a sidecar file next
+to its spec.
+
+Simple, simple browser, scripted API, scripted browser, step, certificate check
+and broken links monitors can be exported.
+`,
+	Example: `newrelic synthetics monitor export --guid "<monitorGUID>" --output monitors/
+newrelic synthetics monitor export --all --accountId 12345 --output monitors/`,
+	PreRun: client.RequireClient,
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(exportMonitorGUIDs) == 0 && !exportAllMonitors {
+			utils.LogIfError(cmd.Help())
+			log.Fatal("one of --guid or --all is required")
+		}
+
+		utils.LogIfFatal(os.MkdirAll(exportOutputDir, 0750))
+
+		paths, err := exportMonitors(utils.SignalCtx, newMonitorClient(client.NRClient), exportMonitorGUIDs, exportAllMonitors, exportOutputDir)
+		utils.LogIfFatal(err)
+
+		for _, p := range paths {
+			fmt.Println(p)
+		}
+	},
+}
+
+// exportMonitors writes the specs of the monitors with the GUIDs, or of all
+// the monitors of the active account, to dir, returning the spec files.
+func exportMonitors(ctx context.Context, c *monitorClient, guids []string, all bool, dir string) ([]string, error) {
+	entities := []monitorEntity{}
+
+	if all {
+		accountID := configAPI.GetActiveProfileAccountID()
+		if accountID == 0 {
+			return nil, fmt.Errorf("an account ID is required to export all monitors, use --accountId or set it in your profile")
+		}
+
+		found, err := c.searchMonitorEntities(ctx, monitorFilter{AccountID: accountID}, 0)
+		if err != nil {
+			return nil, err
+		}
+		entities = append(entities, found...)
+	}
+
+	for _, guid := range guids {
+		e, err := c.getMonitorEntity(ctx, guid)
+		if err != nil {
+			return nil, err
+		}
+		entities = append(entities, *e)
+	}
+
+	paths := []string{}
+	written := map[string]string{}
+	for _, e := range entities {
+		if _, ok := monitorMutationNames[e.MonitorType]; !ok {
+			if !all {
+				return nil, fmt.Errorf("monitor %s has type %s, which cannot be exported", e.Name, e.MonitorType)
+			}
+			log.Warnf("skipping monitor %s, monitors of type %s cannot be exported", e.Name, e.MonitorType)
+			continue
+		}
+
+		if other, ok := written[monitorFileName(e.Name)]; ok {
+			if other == e.GUID {
+				continue
+			}
+			return nil, fmt.Errorf("monitors %s and %s would both be exported to %s.yml, monitor names must be unique", other, e.GUID, monitorFileName(e.Name))
+		}
+		written[monitorFileName(e.Name)] = e.GUID
+
+		s, err := c.getMonitorSpec(ctx, e)
+		if err != nil {
+			return nil, err
+		}
+
+		p, err := writeMonitorSpec(dir, *s)
+		if err != nil {
+			return nil, err
+		}
+		paths = append(paths, p)
+	}
+
+	return paths, nil
+}
+
+func init() {
+	cmdMonExport.Flags().StringSliceVar(&exportMonitorGUIDs, "guid", []string{}, "the GUID of a Synthetics monitor to export, can be repeated")
+	cmdMonExport.Flags().BoolVar(&exportAllMonitors, "all", false, "export all the Synthetics monitors of the account")
+	cmdMonExport.Flags().StringVarP(&exportOutputDir, "output", "o", ".", "the directory to write the monitor specs to")
+	cmdMon.AddCommand(cmdMonExport)
+}
@@ -44,3 +44,17 @@ func TestSyntheticsMonitorRun(t *testing.T) {
 	testcobra.CheckCobraMetadata(t, cmdRun)
 	testcobra.CheckCobraRequiredFlags(t, cmdRun, []string{})
 }
+
+func TestSyntheticsMonitorExport(t *testing.T) {
+	assert.Equal(t, "export", cmdMonExport.Name())
+
+	testcobra.CheckCobraMetadata(t, cmdMonExport)
+	testcobra.CheckCobraRequiredFlags(t, cmdMonExport, []string{})
+}
+
+func TestSyntheticsMonitorApply(t *testing.T) {
+	assert.Equal(t, "apply", cmdMonApply.Name())
+
+	testcobra.CheckCobraMetadata(t, cmdMonApply)
+	testcobra.CheckCobraRequiredFlags(t, cmdMonApply, []string{"file"})
+}
@@ -0,0 +1,148 @@
+package synthetics
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// monitorFilter selects synthetic monitor entities.  Account, name, type,
+// location and tags are filtered by entity search; status and period, which
+// are not searchable, are filtered on the entities returned.
+type monitorFilter struct {
+	AccountID int
+	Name      string
+	Types     []string
+	Statuses  []string
+	Periods   []int
+	Locations []string
+	Tags      []monitorTag
+}
+
+// query returns the entity search query of the filter.  Tag keys are quoted
+// with backticks, which cannot be escaped, so keys with backticks or quotes
+// are rejected.
+func (f monitorFilter) query() (string, error) {
+	conditions := []string{"domain = 'SYNTH'", "type = 'MONITOR'"}
+
+	if f.AccountID != 0 {
+		conditions = append(conditions, fmt.Sprintf("accountId = '%d'", f.AccountID))
+	}
+
+	if f.Name != "" {
+		conditions = append(conditions, fmt.Sprintf("name = %s", entitySearchValue(f.Name)))
+	}
+
+	if len(f.Types) > 0 {
+		conditions = append(conditions, fmt.Sprintf("tags.monitorType IN (%s)", entitySearchValues(f.Types)))
+	}
+
+	if len(f.Locations) > 0 {
+		values := entitySearchValues(f.Locations)
+		conditions = append(conditions, fmt.Sprintf("(tags.publicLocation IN (%s) OR tags.privateLocation IN (%s))", values, values))
+	}
+
+	for _, t := range f.Tags {
+		if strings.ContainsAny(t.Key, "`'\"") {
+			return "", fmt.Errorf("invalid tag key %s, tag keys cannot contain backticks or quotes", t.Key)
+		}
+
+		conditions = append(conditions, fmt.Sprintf("tags.`%s` IN (%s)", t.Key, entitySearchValues(t.Values)))
+	}
+
+	return strings.Join(conditions, " AND "), nil
+}
+
+// matches returns whether the entity matches the filters that entity search
+// cannot apply.
+func (f monitorFilter) matches(e monitorEntity) bool {
+	// Entity search matches names case insensitively.
+	if f.Name != "" && e.Name != f.Name {
+		return false
+	}
+
+	if len(f.Statuses) > 0 && !containsFold(f.Statuses, e.MonitorSummary.Status) {
+		return false
+	}
+
+	if len(f.Periods) > 0 {
+		found := false
+		for _, p := range f.Periods {
+			found = found || p == e.Period
+		}
+		if !found {
+			return false
+		}
+	}
+
+	return true
+}
+
+// parseMonitorPeriod returns the period in minutes of a period given either in
+// minutes or as a monitor input period, such as EVERY_5_MINUTES.
+func parseMonitorPeriod(period string) (int, error) {
+	period = strings.TrimSpace(period)
+
+	if minutes, err := strconv.Atoi(period); err == nil {
+		if _, ok := monitorPeriods[minutes]; ok {
+			return minutes, nil
+		}
+	}
+
+	for minutes, name := range monitorPeriods {
+		if strings.EqualFold(name, period) {
+			return minutes, nil
+		}
+	}
+
+	return 0, fmt.Errorf("unknown monitor period %s", period)
+}
+
+// parseMonitorTags parses tags given as key:value, grouping the values of the
+// same key.
+func parseMonitorTags(tags []string) ([]monitorTag, error) {
+	parsed := []monitorTag{}
+	index := map[string]int{}
+
+	for _, t := range tags {
+		key, value, ok := strings.Cut(t, ":")
+		key = strings.TrimSpace(key)
+		value = strings.TrimSpace(value)
+		if !ok || key == "" || value == "" {
+			return nil, fmt.Errorf("invalid tag %s, tags must be given as key:value", t)
+		}
+
+		if i, ok := index[key]; ok {
+			parsed[i].Values = append(parsed[i].Values, value)
+			continue
+		}
+
+		index[key] = len(parsed)
+		parsed = append(parsed, monitorTag{Key: key, Values: []string{value}})
+	}
+
+	return parsed, nil
+}
+
+func entitySearchValue(v string) string {
+	return "'" + strings.NewReplacer("\\", "\\\\", "'", "\\'").Replace(v) + "'"
+}
+
+func entitySearchValues(values []string) string {
+	quoted := []string{}
+	for _, v := range values {
+		quoted = append(quoted, entitySearchValue(v))
+	}
+
+	return strings.Join(quoted, ", ")
+}
+
+func containsFold(values []string, v string) bool {
+	for _, value := range values {
+		if strings.EqualFold(value, v) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,120 @@
+//go:build unit
+
+package synthetics
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMonitorFilterQueryShouldIncludeSearchableFilters(t *testing.T) {
+	filter := monitorFilter{
+		AccountID: 12345,
+		Name:      "Partner's API",
+		Types:     []string{"SCRIPT_API", "SIMPLE"},
+		Statuses:  []string{"ENABLED"},
+		Periods:   []int{5},
+		Locations: []string{"AWS_US_EAST_1"},
+		Tags:      []monitorTag{{Key: "team", Values: []string{"checkout", "payments"}}},
+	}
+
+	query, err := filter.query()
+	require.NoError(t, err)
+	assert.Equal(t, "domain = 'SYNTH' AND type = 'MONITOR' AND accountId = '12345' AND name = 'Partner\\'s API'"+
+		" AND tags.monitorType IN ('SCRIPT_API', 'SIMPLE')"+
+		" AND (tags.publicLocation IN ('AWS_US_EAST_1') OR tags.privateLocation IN ('AWS_US_EAST_1'))"+
+		" AND tags.`team` IN ('checkout', 'payments')", query)
+}
+
+func TestMonitorFilterQueryShouldEscapeValuesAndRejectQuotedTagKeys(t *testing.T) {
+	filter := monitorFilter{Tags: []monitorTag{{Key: "path", Values: []string{`C:\checkout\`, "it's"}}}}
+
+	query, err := filter.query()
+	require.NoError(t, err)
+	assert.Equal(t, "domain = 'SYNTH' AND type = 'MONITOR' AND tags.`path` IN ('C:\\\\checkout\\\\', 'it\\'s')", query)
+
+	for _, key := range []string{"team` IN ('x') OR tags.`env", "team's", `team"`} {
+		filter := monitorFilter{Tags: []monitorTag{{Key: key, Values: []string{"checkout"}}}}
+
+		_, err := filter.query()
+		assert.EqualError(t, err, "invalid tag key "+key+", tag keys cannot contain backticks or quotes")
+	}
+}
+
+func TestMonitorFilterShouldMatchStatusAndPeriod(t *testing.T) {
+	filter := monitorFilter{Statuses: []string{"DISABLED", "MUTED"}, Periods: []int{5, 10}}
+
+	e := monitorEntity{Name: "Home page", Period: 5}
+	e.MonitorSummary.Status = "MUTED"
+	assert.True(t, filter.matches(e))
+
+	e.Period = 15
+	assert.False(t, filter.matches(e))
+
+	e.Period = 10
+	e.MonitorSummary.Status = "ENABLED"
+	assert.False(t, filter.matches(e))
+}
+
+func TestParseMonitorPeriodShouldAcceptMinutesAndNames(t *testing.T) {
+	minutes, err := parseMonitorPeriod("15")
+	require.NoError(t, err)
+	assert.Equal(t, 15, minutes)
+
+	minutes, err = parseMonitorPeriod("every_hour")
+	require.NoError(t, err)
+	assert.Equal(t, 60, minutes)
+
+	_, err = parseMonitorPeriod("7")
+	assert.EqualError(t, err, "unknown monitor period 7")
+}
+
+func TestParseMonitorTagsShouldGroupValuesByKey(t *testing.T) {
+	tags, err := parseMonitorTags([]string{"team:checkout", "env: production", "team:payments"})
+	require.NoError(t, err)
+	assert.Equal(t, []monitorTag{
+		{Key: "team", Values: []string{"checkout", "payments"}},
+		{Key: "env", Values: []string{"production"}},
+	}, tags)
+
+	_, err = parseMonitorTags([]string{"team"})
+	assert.EqualError(t, err, "invalid tag team, tags must be given as key:value")
+}
+
+func TestListMonitorsShouldIncludeLastResults(t *testing.T) {
+	c := newMonitorClient(newMockNerdGraphClient(t, map[string]string{
+		"entitySearch": `{"actor": {"entitySearch": {"results": {"entities": [
+			{"guid": "GUID-1", "name": "Home page", "accountId": 12345, "monitorId": "MONITOR-1", "monitorType": "SIMPLE", "period": 5,
+			 "monitorSummary": {"status": "ENABLED"},
+			 "tags": [{"key": "publicLocation", "values": ["AWS_US_EAST_1"]}, {"key": "team", "values": ["web"]}]},
+			{"guid": "GUID-2", "name": "Checkout", "accountId": 12345, "monitorType": "SCRIPT_API", "period": 10,
+			 "monitorSummary": {"status": "DISABLED"}},
+			{"guid": "GUID-3", "name": "Search", "accountId": 12345, "monitorType": "BROWSER", "period": 5,
+			 "monitorSummary": {"status": "ENABLED"}}
+		]}}}}`,
+		"nrql(": `{"actor": {"account": {"nrql": {"results": [
+			{"entityGuid": "GUID-1", "result": "SUCCESS"},
+			{"entityGuid": "GUID-3", "result": "FAILED"}
+		]}}}}`,
+	}))
+
+	items, err := listMonitors(context.Background(), c, monitorFilter{Statuses: []string{"ENABLED"}}, 0, true)
+	require.NoError(t, err)
+	require.Len(t, items, 2)
+
+	assert.Equal(t, "GUID-1", items[0].GUID)
+	assert.Equal(t, "MONITOR-1", items[0].ID)
+	assert.Equal(t, uint(5), items[0].Frequency)
+	assert.Equal(t, "SUCCESS", items[0].LastResult)
+	assert.Equal(t, []string{"AWS_US_EAST_1"}, items[0].Locations)
+	assert.Equal(t, map[string][]string{"team": {"web"}}, items[0].Tags)
+	assert.Equal(t, "FAILED", items[1].LastResult)
+
+	items, err = listMonitors(context.Background(), c, monitorFilter{}, 1, false)
+	require.NoError(t, err)
+	require.Len(t, items, 1)
+	assert.Empty(t, items[0].LastResult)
+}
@@ -0,0 +1,784 @@
+package synthetics
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/newrelic/newrelic-client-go/v2/newrelic"
+	"github.com/newrelic/newrelic-client-go/v2/pkg/nrdb"
+	"github.com/newrelic/newrelic-client-go/v2/pkg/synthetics"
+)
+
+// monitorSystemTagKeys are the tags New Relic adds to synthetic monitor
+// entities to describe their settings, rather than tags set by users.
+var monitorSystemTagKeys = map[string]bool{
+	"account":                            true,
+	"accountId":                          true,
+	"trustedAccountId":                   true,
+	"monitorType":                        true,
+	"monitorStatus":                      true,
+	"period":                             true,
+	"publicLocation":                     true,
+	"privateLocation":                    true,
+	"responseValidationText":             true,
+	"useTlsValidation":                   true,
+	"redirectIsFailure":                  true,
+	"bypassHEADRequest":                  true,
+	"runtimeType":                        true,
+	"runtimeTypeVersion":                 true,
+	"scriptLanguage":                     true,
+	"browsers":                           true,
+	"devices":                            true,
+	"deviceOrientation":                  true,
+	"deviceType":                         true,
+	"enableScreenshotOnFailureAndScript": true,
+	"daysUntilExpiration":                true,
+	"numberDaysToFailBeforeCertExpires":  true,
+}
+
+const monitorEntityFields = `guid name accountId monitorId monitorType period monitoredUrl monitorSummary { status } tags { key values }`
+
+const getMonitorQuery = `query($guid: EntityGuid!) {
+  actor {
+    entity(guid: $guid) {
+      ... on SyntheticMonitorEntity { ` + monitorEntityFields + ` }
+    }
+  }
+}`
+
+const searchMonitorsQuery = `query($query: String!, $cursor: String) {
+  actor {
+    entitySearch(query: $query) {
+      results(cursor: $cursor) {
+        nextCursor
+        entities {
+          ... on SyntheticMonitorEntityOutline { ` + monitorEntityFields + ` }
+        }
+      }
+    }
+  }
+}`
+
+type monitorEntity struct {
+	GUID           string `json:"guid"`
+	Name           string `json:"name"`
+	AccountID      int    `json:"accountId"`
+	MonitorID      string `json:"monitorId"`
+	MonitorType    string `json:"monitorType"`
+	Period         int    `json:"period"`
+	MonitoredURL   string `json:"monitoredUrl"`
+	MonitorSummary struct {
+		Status string `json:"status"`
+	} `json:"monitorSummary"`
+	Tags []monitorTag `json:"tags"`
+}
+
+type monitorTag struct {
+	Key    string   `json:"key"`
+	Values []string `json:"values"`
+}
+
+func (e monitorEntity) tag(key string) string {
+	for _, t := range e.Tags {
+		if t.Key == key && len(t.Values) > 0 {
+			return t.Values[0]
+		}
+	}
+
+	return ""
+}
+
+func (e monitorEntity) tagValues(key string) []string {
+	for _, t := range e.Tags {
+		if t.Key == key {
+			return t.Values
+		}
+	}
+
+	return nil
+}
+
+func (e monitorEntity) boolTag(key string) *bool {
+	v, err := strconv.ParseBool(e.tag(key))
+	if err != nil {
+		return nil
+	}
+
+	return &v
+}
+
+// monitorClient reads and writes Synthetics monitors as MonitorSpecs.
+type monitorClient struct {
+	client *newrelic.NewRelic
+}
+
+func newMonitorClient(client *newrelic.NewRelic) *monitorClient {
+	return &monitorClient{client: client}
+}
+
+// getMonitorEntity returns the monitor entity with the GUID.
+func (c *monitorClient) getMonitorEntity(ctx context.Context, guid string) (*monitorEntity, error) {
+	var resp struct {
+		Actor struct {
+			Entity *monitorEntity `json:"entity"`
+		} `json:"actor"`
+	}
+
+	if err := c.client.NerdGraph.QueryWithResponseAndContext(ctx, getMonitorQuery, map[string]interface{}{"guid": guid}, &resp); err != nil {
+		return nil, err
+	}
+
+	if resp.Actor.Entity == nil || resp.Actor.Entity.GUID == "" {
+		return nil, fmt.Errorf("no synthetic monitor found with GUID %s", guid)
+	}
+
+	return resp.Actor.Entity, nil
+}
+
+// searchMonitorEntities returns the monitor entities matching the filter, up to
+// limit entities when limit is greater than 0.
+func (c *monitorClient) searchMonitorEntities(ctx context.Context, filter monitorFilter, limit int) ([]monitorEntity, error) {
+	query, err := filter.query()
+	if err != nil {
+		return nil, err
+	}
+
+	entities := []monitorEntity{}
+	var cursor interface{}
+
+	for {
+		var resp struct {
+			Actor struct {
+				EntitySearch struct {
+					Results struct {
+						NextCursor string          `json:"nextCursor"`
+						Entities   []monitorEntity `json:"entities"`
+					} `json:"results"`
+				} `json:"entitySearch"`
+			} `json:"actor"`
+		}
+
+		vars := map[string]interface{}{"query": query, "cursor": cursor}
+		if err := c.client.NerdGraph.QueryWithResponseAndContext(ctx, searchMonitorsQuery, vars, &resp); err != nil {
+			return nil, err
+		}
+
+		for _, e := range resp.Actor.EntitySearch.Results.Entities {
+			if e.GUID == "" || !filter.matches(e) {
+				continue
+			}
+
+			entities = append(entities, e)
+			if limit > 0 && len(entities) == limit {
+				return entities, nil
+			}
+		}
+
+		if resp.Actor.EntitySearch.Results.NextCursor == "" {
+			return entities, nil
+		}
+		cursor = resp.Actor.EntitySearch.Results.NextCursor
+	}
+}
+
+// findMonitor returns the monitor of the account with the name, or nil.
+func (c *monitorClient) findMonitor(ctx context.Context, accountID int, name string) (*MonitorSpec, error) {
+	entities, err := c.searchMonitorEntities(ctx, monitorFilter{AccountID: accountID, Name: name}, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	switch len(entities) {
+	case 0:
+		return nil, nil
+	case 1:
+		return c.getMonitorSpec(ctx, entities[0])
+	}
+
+	return nil, fmt.Errorf("found %d monitors named %s in account %d, monitor names must be unique to be applied", len(entities), name, accountID)
+}
+
+// getMonitorSpec returns the spec of the monitor entity, with its script or
+// steps.
+func (c *monitorClient) getMonitorSpec(ctx context.Context, e monitorEntity) (*MonitorSpec, error) {
+	if _, ok := monitorMutationNames[e.MonitorType]; !ok {
+		return nil, fmt.Errorf("monitor %s has type %s, which cannot be exported", e.Name, e.MonitorType)
+	}
+
+	s := &MonitorSpec{
+		GUID:      e.GUID,
+		Name:      e.Name,
+		Type:      e.MonitorType,
+		AccountID: e.AccountID,
+		Period:    monitorPeriods[e.Period],
+		Status:    e.MonitorSummary.Status,
+		Locations: MonitorLocations{
+			Public:  e.tagValues("publicLocation"),
+			Private: e.tagValues("privateLocation"),
+		},
+		Browsers: e.tagValues("browsers"),
+		Devices:  e.tagValues("devices"),
+	}
+
+	if s.Status != "DISABLED" {
+		s.Status = "ENABLED"
+	}
+
+	switch e.MonitorType {
+	case monitorTypeCertCheck:
+		s.Domain = e.MonitoredURL
+		s.NumberDaysToFailBeforeCertExpires, _ = strconv.Atoi(e.tag("daysUntilExpiration"))
+	case monitorTypeSimple, monitorTypeSimpleBrowser, monitorTypeBrokenLinks:
+		s.URI = e.MonitoredURL
+	}
+
+	if runtimeType := e.tag("runtimeType"); runtimeType != "" {
+		s.Runtime = &MonitorRuntime{
+			RuntimeType:        runtimeType,
+			RuntimeTypeVersion: e.tag("runtimeTypeVersion"),
+			ScriptLanguage:     e.tag("scriptLanguage"),
+		}
+	}
+
+	options := MonitorAdvancedOptions{}
+	switch e.MonitorType {
+	case monitorTypeSimple:
+		options.ResponseValidationText = e.tag("responseValidationText")
+		options.RedirectIsFailure = e.boolTag("redirectIsFailure")
+		options.ShouldBypassHeadRequest = e.boolTag("bypassHEADRequest")
+		options.UseTLSValidation = e.boolTag("useTlsValidation")
+	case monitorTypeSimpleBrowser:
+		options.ResponseValidationText = e.tag("responseValidationText")
+		options.UseTLSValidation = e.boolTag("useTlsValidation")
+		options.EnableScreenshotOnFailureAndScript = e.boolTag("enableScreenshotOnFailureAndScript")
+	case monitorTypeScriptBrowser, monitorTypeStep:
+		options.EnableScreenshotOnFailureAndScript = e.boolTag("enableScreenshotOnFailureAndScript")
+	}
+	if options != (MonitorAdvancedOptions{}) {
+		s.AdvancedOptions = &options
+	}
+
+	for _, t := range e.Tags {
+		if !monitorSystemTagKeys[t.Key] {
+			if s.Tags == nil {
+				s.Tags = map[string][]string{}
+			}
+			s.Tags[t.Key] = t.Values
+		}
+	}
+
+	if s.isScripted() {
+		script, err := c.client.Synthetics.GetScriptWithContext(ctx, e.AccountID, synthetics.EntityGUID(e.GUID))
+		if err != nil {
+			return nil, err
+		}
+		s.ScriptText = script.Text
+	}
+
+	if s.Type == monitorTypeStep {
+		steps, err := c.client.Synthetics.GetStepsWithContext(ctx, e.AccountID, synthetics.EntityGUID(e.GUID))
+		if err != nil {
+			return nil, err
+		}
+
+		if steps != nil {
+			for _, step := range *steps {
+				s.Steps = append(s.Steps, MonitorStep{
+					Ordinal: step.Ordinal,
+					Type:    string(step.Type),
+					Values:  step.Values,
+				})
+			}
+		}
+		sort.Slice(s.Steps, func(i, j int) bool {
+			return s.Steps[i].Ordinal < s.Steps[j].Ordinal
+		})
+	}
+
+	return s, nil
+}
+
+// createMonitor creates the monitor in the account of the spec, returning its
+// GUID.
+func (c *monitorClient) createMonitor(ctx context.Context, s MonitorSpec) (string, error) {
+	client := &c.client.Synthetics
+
+	switch s.Type {
+	case monitorTypeSimple:
+		r, err := client.SyntheticsCreateSimpleMonitorWithContext(ctx, s.AccountID, synthetics.SyntheticsCreateSimpleMonitorInput{
+			AdvancedOptions: simpleMonitorAdvancedOptions(s),
+			Locations:       monitorLocations(s),
+			Name:            s.Name,
+			Period:          synthetics.SyntheticsMonitorPeriod(s.Period),
+			Status:          synthetics.SyntheticsMonitorStatus(s.Status),
+			Tags:            monitorTags(s),
+			Uri:             s.URI,
+		})
+		if err != nil {
+			return "", err
+		}
+		return createdMonitorGUID(r.Monitor.GUID, r.Errors)
+	case monitorTypeSimpleBrowser:
+		r, err := client.SyntheticsCreateSimpleBrowserMonitorWithContext(ctx, s.AccountID, synthetics.SyntheticsCreateSimpleBrowserMonitorInput{
+			AdvancedOptions: simpleBrowserMonitorAdvancedOptions(s),
+			Browsers:        monitorBrowsers(s),
+			Devices:         monitorDevices(s),
+			Locations:       monitorLocations(s),
+			Name:            s.Name,
+			Period:          synthetics.SyntheticsMonitorPeriod(s.Period),
+			Runtime:         monitorRuntime(s),
+			Status:          synthetics.SyntheticsMonitorStatus(s.Status),
+			Tags:            monitorTags(s),
+			Uri:             s.URI,
+		})
+		if err != nil {
+			return "", err
+		}
+		return createdMonitorGUID(r.Monitor.GUID, r.Errors)
+	case monitorTypeScriptAPI:
+		r, err := client.SyntheticsCreateScriptAPIMonitorWithContext(ctx, s.AccountID, synthetics.SyntheticsCreateScriptAPIMonitorInput{
+			Locations: scriptedMonitorLocations(s),
+			Name:      s.Name,
+			Period:    synthetics.SyntheticsMonitorPeriod(s.Period),
+			Runtime:   monitorRuntime(s),
+			Script:    s.ScriptText,
+			Status:    synthetics.SyntheticsMonitorStatus(s.Status),
+			Tags:      monitorTags(s),
+		})
+		if err != nil {
+			return "", err
+		}
+		return createdMonitorGUID(r.Monitor.GUID, r.Errors)
+	case monitorTypeScriptBrowser:
+		r, err := client.SyntheticsCreateScriptBrowserMonitorWithContext(ctx, s.AccountID, synthetics.SyntheticsCreateScriptBrowserMonitorInput{
+			AdvancedOptions: synthetics.SyntheticsScriptBrowserMonitorAdvancedOptionsInput{
+				EnableScreenshotOnFailureAndScript: enableScreenshotOnFailureAndScript(s),
+			},
+			Browsers:  monitorBrowsers(s),
+			Devices:   monitorDevices(s),
+			Locations: scriptedMonitorLocations(s),
+			Name:      s.Name,
+			Period:    synthetics.SyntheticsMonitorPeriod(s.Period),
+			Runtime:   monitorRuntime(s),
+			Script:    s.ScriptText,
+			Status:    synthetics.SyntheticsMonitorStatus(s.Status),
+			Tags:      monitorTags(s),
+		})
+		if err != nil {
+			return "", err
+		}
+		return createdMonitorGUID(r.Monitor.GUID, r.Errors)
+	case monitorTypeStep:
+		r, err := client.SyntheticsCreateStepMonitorWithContext(ctx, s.AccountID, synthetics.SyntheticsCreateStepMonitorInput{
+			AdvancedOptions: synthetics.SyntheticsStepMonitorAdvancedOptionsInput{
+				EnableScreenshotOnFailureAndScript: enableScreenshotOnFailureAndScript(s),
+			},
+			Browsers:  monitorBrowsers(s),
+			Devices:   monitorDevices(s),
+			Locations: scriptedMonitorLocations(s),
+			Name:      s.Name,
+			Period:    synthetics.SyntheticsMonitorPeriod(s.Period),
+			Runtime:   monitorRuntime(s),
+			Status:    synthetics.SyntheticsMonitorStatus(s.Status),
+			Steps:     monitorSteps(s),
+			Tags:      monitorTags(s),
+		})
+		if err != nil {
+			return "", err
+		}
+		return createdMonitorGUID(r.Monitor.GUID, r.Errors)
+	case monitorTypeCertCheck:
+		r, err := client.SyntheticsCreateCertCheckMonitorWithContext(ctx, s.AccountID, synthetics.SyntheticsCreateCertCheckMonitorInput{
+			Domain:                            s.Domain,
+			Locations:                         monitorLocations(s),
+			Name:                              s.Name,
+			NumberDaysToFailBeforeCertExpires: s.NumberDaysToFailBeforeCertExpires,
+			Period:                            synthetics.SyntheticsMonitorPeriod(s.Period),
+			Runtime:                           extendedMonitorRuntime(s),
+			Status:                            synthetics.SyntheticsMonitorStatus(s.Status),
+			Tags:                              monitorTags(s),
+		})
+		if err != nil {
+			return "", err
+		}
+		return createdMonitorGUID(r.Monitor.GUID, r.Errors)
+	case monitorTypeBrokenLinks:
+		r, err := client.SyntheticsCreateBrokenLinksMonitorWithContext(ctx, s.AccountID, synthetics.SyntheticsCreateBrokenLinksMonitorInput{
+			Locations: monitorLocations(s),
+			Name:      s.Name,
+			Period:    synthetics.SyntheticsMonitorPeriod(s.Period),
+			Runtime:   extendedMonitorRuntime(s),
+			Status:    synthetics.SyntheticsMonitorStatus(s.Status),
+			Tags:      monitorTags(s),
+			Uri:       s.URI,
+		})
+		if err != nil {
+			return "", err
+		}
+		return createdMonitorGUID(r.Monitor.GUID, r.Errors)
+	}
+
+	return "", fmt.Errorf("monitor %s has type %s, which cannot be created", s.Name, s.Type)
+}
+
+// updateMonitor updates the monitor with the GUID to match the spec.
+func (c *monitorClient) updateMonitor(ctx context.Context, guid string, s MonitorSpec) error {
+	client := &c.client.Synthetics
+	monitorGUID := synthetics.EntityGUID(guid)
+
+	switch s.Type {
+	case monitorTypeSimple:
+		r, err := client.SyntheticsUpdateSimpleMonitorWithContext(ctx, monitorGUID, synthetics.SyntheticsUpdateSimpleMonitorInput{
+			AdvancedOptions: simpleMonitorAdvancedOptions(s),
+			Locations:       monitorLocations(s),
+			Name:            s.Name,
+			Period:          synthetics.SyntheticsMonitorPeriod(s.Period),
+			Status:          synthetics.SyntheticsMonitorStatus(s.Status),
+			Tags:            monitorTags(s),
+			Uri:             s.URI,
+		})
+		if err != nil {
+			return err
+		}
+		return updateErrors(r.Errors)
+	case monitorTypeSimpleBrowser:
+		r, err := client.SyntheticsUpdateSimpleBrowserMonitorWithContext(ctx, monitorGUID, synthetics.SyntheticsUpdateSimpleBrowserMonitorInput{
+			AdvancedOptions: simpleBrowserMonitorAdvancedOptions(s),
+			Browsers:        monitorBrowsers(s),
+			Devices:         monitorDevices(s),
+			Locations:       monitorLocations(s),
+			Name:            s.Name,
+			Period:          synthetics.SyntheticsMonitorPeriod(s.Period),
+			Runtime:         monitorRuntime(s),
+			Status:          synthetics.SyntheticsMonitorStatus(s.Status),
+			Tags:            monitorTags(s),
+			Uri:             s.URI,
+		})
+		if err != nil {
+			return err
+		}
+		return updateErrors(r.Errors)
+	case monitorTypeScriptAPI:
+		r, err := client.SyntheticsUpdateScriptAPIMonitorWithContext(ctx, monitorGUID, synthetics.SyntheticsUpdateScriptAPIMonitorInput{
+			Locations: scriptedMonitorLocations(s),
+			Name:      s.Name,
+			Period:    synthetics.SyntheticsMonitorPeriod(s.Period),
+			Runtime:   monitorRuntime(s),
+			Script:    s.ScriptText,
+			Status:    synthetics.SyntheticsMonitorStatus(s.Status),
+			Tags:      monitorTags(s),
+		})
+		if err != nil {
+			return err
+		}
+		return updateErrors(r.Errors)
+	case monitorTypeScriptBrowser:
+		r, err := client.SyntheticsUpdateScriptBrowserMonitorWithContext(ctx, monitorGUID, synthetics.SyntheticsUpdateScriptBrowserMonitorInput{
+			AdvancedOptions: synthetics.SyntheticsScriptBrowserMonitorAdvancedOptionsInput{
+				EnableScreenshotOnFailureAndScript: enableScreenshotOnFailureAndScript(s),
+			},
+			Browsers:  monitorBrowsers(s),
+			Devices:   monitorDevices(s),
+			Locations: scriptedMonitorLocations(s),
+			Name:      s.Name,
+			Period:    synthetics.SyntheticsMonitorPeriod(s.Period),
+			Runtime:   monitorRuntime(s),
+			Script:    s.ScriptText,
+			Status:    synthetics.SyntheticsMonitorStatus(s.Status),
+			Tags:      monitorTags(s),
+		})
+		if err != nil {
+			return err
+		}
+		return updateErrors(r.Errors)
+	case monitorTypeStep:
+		r, err := client.SyntheticsUpdateStepMonitorWithContext(ctx, monitorGUID, synthetics.SyntheticsUpdateStepMonitorInput{
+			AdvancedOptions: synthetics.SyntheticsStepMonitorAdvancedOptionsInput{
+				EnableScreenshotOnFailureAndScript: enableScreenshotOnFailureAndScript(s),
+			},
+			Browsers:  monitorBrowsers(s),
+			Devices:   monitorDevices(s),
+			Locations: scriptedMonitorLocations(s),
+			Name:      s.Name,
+			Period:    synthetics.SyntheticsMonitorPeriod(s.Period),
+			Runtime:   monitorRuntime(s),
+			Status:    synthetics.SyntheticsMonitorStatus(s.Status),
+			Steps:     monitorSteps(s),
+			Tags:      monitorTags(s),
+		})
+		if err != nil {
+			return err
+		}
+		return updateErrors(r.Errors)
+	case monitorTypeCertCheck:
+		r, err := client.SyntheticsUpdateCertCheckMonitorWithContext(ctx, monitorGUID, synthetics.SyntheticsUpdateCertCheckMonitorInput{
+			Domain:                            s.Domain,
+			Locations:                         monitorLocations(s),
+			Name:                              s.Name,
+			NumberDaysToFailBeforeCertExpires: s.NumberDaysToFailBeforeCertExpires,
+			Period:                            synthetics.SyntheticsMonitorPeriod(s.Period),
+			Runtime:                           extendedMonitorRuntime(s),
+			Status:                            synthetics.SyntheticsMonitorStatus(s.Status),
+			Tags:                              monitorTags(s),
+		})
+		if err != nil {
+			return err
+		}
+		return updateErrors(r.Errors)
+	case monitorTypeBrokenLinks:
+		r, err := client.SyntheticsUpdateBrokenLinksMonitorWithContext(ctx, monitorGUID, synthetics.SyntheticsUpdateBrokenLinksMonitorInput{
+			Locations: monitorLocations(s),
+			Name:      s.Name,
+			Period:    synthetics.SyntheticsMonitorPeriod(s.Period),
+			Runtime:   extendedMonitorRuntime(s),
+			Status:    synthetics.SyntheticsMonitorStatus(s.Status),
+			Tags:      monitorTags(s),
+			Uri:       s.URI,
+		})
+		if err != nil {
+			return err
+		}
+		return updateErrors(r.Errors)
+	}
+
+	return fmt.Errorf("monitor %s has type %s, which cannot be updated", s.Name, s.Type)
+}
+
+func createdMonitorGUID(guid synthetics.EntityGUID, errors []synthetics.SyntheticsMonitorCreateError) (string, error) {
+	messages := []string{}
+	for _, e := range errors {
+		messages = append(messages, fmt.Sprintf("%s: %s", e.Type, e.Description))
+	}
+
+	if len(messages) > 0 {
+		return "", fmt.Errorf("%s", strings.Join(messages, "; "))
+	}
+
+	return string(guid), nil
+}
+
+func updateErrors(errors []synthetics.SyntheticsMonitorUpdateError) error {
+	messages := []string{}
+	for _, e := range errors {
+		messages = append(messages, fmt.Sprintf("%s: %s", e.Type, e.Description))
+	}
+
+	if len(messages) > 0 {
+		return fmt.Errorf("%s", strings.Join(messages, "; "))
+	}
+
+	return nil
+}
+
+func monitorTags(s MonitorSpec) []synthetics.SyntheticsTag {
+	keys := []string{}
+	for k := range s.Tags {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	tags := []synthetics.SyntheticsTag{}
+	for _, k := range keys {
+		tags = append(tags, synthetics.SyntheticsTag{Key: k, Values: s.Tags[k]})
+	}
+
+	return tags
+}
+
+func monitorLocations(s MonitorSpec) synthetics.SyntheticsLocationsInput {
+	return synthetics.SyntheticsLocationsInput{
+		Public:  s.Locations.Public,
+		Private: s.Locations.Private,
+	}
+}
+
+// scriptedMonitorLocations returns the locations of monitors running scripts,
+// whose private locations are given as objects with a GUID.
+func scriptedMonitorLocations(s MonitorSpec) synthetics.SyntheticsScriptedMonitorLocationsInput {
+	locations := synthetics.SyntheticsScriptedMonitorLocationsInput{Public: s.Locations.Public}
+	for _, guid := range s.Locations.Private {
+		locations.Private = append(locations.Private, synthetics.SyntheticsPrivateLocationInput{GUID: guid})
+	}
+
+	return locations
+}
+
+func monitorRuntime(s MonitorSpec) *synthetics.SyntheticsRuntimeInput {
+	if s.Runtime == nil {
+		return nil
+	}
+
+	return &synthetics.SyntheticsRuntimeInput{
+		RuntimeType:        s.Runtime.RuntimeType,
+		RuntimeTypeVersion: synthetics.SemVer(s.Runtime.RuntimeTypeVersion),
+		ScriptLanguage:     s.Runtime.ScriptLanguage,
+	}
+}
+
+func extendedMonitorRuntime(s MonitorSpec) *synthetics.SyntheticsExtendedTypeMonitorRuntimeInput {
+	if s.Runtime == nil {
+		return nil
+	}
+
+	return &synthetics.SyntheticsExtendedTypeMonitorRuntimeInput{
+		RuntimeType:        s.Runtime.RuntimeType,
+		RuntimeTypeVersion: synthetics.SemVer(s.Runtime.RuntimeTypeVersion),
+	}
+}
+
+func monitorBrowsers(s MonitorSpec) []synthetics.SyntheticsBrowser {
+	browsers := []synthetics.SyntheticsBrowser{}
+	for _, b := range s.Browsers {
+		browsers = append(browsers, synthetics.SyntheticsBrowser(b))
+	}
+
+	return browsers
+}
+
+func monitorDevices(s MonitorSpec) []synthetics.SyntheticsDevice {
+	devices := []synthetics.SyntheticsDevice{}
+	for _, d := range s.Devices {
+		devices = append(devices, synthetics.SyntheticsDevice(d))
+	}
+
+	return devices
+}
+
+func monitorSteps(s MonitorSpec) []synthetics.SyntheticsStepInput {
+	steps := []synthetics.SyntheticsStepInput{}
+	for _, step := range s.Steps {
+		steps = append(steps, synthetics.SyntheticsStepInput{
+			Ordinal: step.Ordinal,
+			Type:    synthetics.SyntheticsStepType(step.Type),
+			Values:  step.Values,
+		})
+	}
+
+	return steps
+}
+
+func simpleMonitorAdvancedOptions(s MonitorSpec) synthetics.SyntheticsSimpleMonitorAdvancedOptionsInput {
+	if s.AdvancedOptions == nil {
+		return synthetics.SyntheticsSimpleMonitorAdvancedOptionsInput{}
+	}
+
+	return synthetics.SyntheticsSimpleMonitorAdvancedOptionsInput{
+		RedirectIsFailure:       s.AdvancedOptions.RedirectIsFailure,
+		ResponseValidationText:  s.AdvancedOptions.ResponseValidationText,
+		ShouldBypassHeadRequest: s.AdvancedOptions.ShouldBypassHeadRequest,
+		UseTlsValidation:        s.AdvancedOptions.UseTLSValidation,
+	}
+}
+
+func simpleBrowserMonitorAdvancedOptions(s MonitorSpec) synthetics.SyntheticsSimpleBrowserMonitorAdvancedOptionsInput {
+	if s.AdvancedOptions == nil {
+		return synthetics.SyntheticsSimpleBrowserMonitorAdvancedOptionsInput{}
+	}
+
+	return synthetics.SyntheticsSimpleBrowserMonitorAdvancedOptionsInput{
+		EnableScreenshotOnFailureAndScript: s.AdvancedOptions.EnableScreenshotOnFailureAndScript,
+		ResponseValidationText:             s.AdvancedOptions.ResponseValidationText,
+		UseTlsValidation:                   s.AdvancedOptions.UseTLSValidation,
+	}
+}
+
+func enableScreenshotOnFailureAndScript(s MonitorSpec) *bool {
+	if s.AdvancedOptions == nil {
+		return nil
+	}
+
+	return s.AdvancedOptions.EnableScreenshotOnFailureAndScript
+}
+
+// monitorResultsBatchSize is the number of monitors whose last result is
+// queried at once.
+const monitorResultsBatchSize = 100
+
+// getLastResults returns the result of the last check of each monitor of the
+// account, in the last day, keyed by monitor GUID.
+func (c *monitorClient) getLastResults(ctx context.Context, accountID int, guids []string) (map[string]string, error) {
+	results := map[string]string{}
+
+	for start := 0; start < len(guids); start += monitorResultsBatchSize {
+		end := start + monitorResultsBatchSize
+		if end > len(guids) {
+			end = len(guids)
+		}
+
+		query := fmt.Sprintf("SELECT latest(result) AS 'result' FROM SyntheticCheck WHERE entityGuid IN (%s) FACET entityGuid SINCE 1 day ago LIMIT MAX", entitySearchValues(guids[start:end]))
+
+		resp, err := c.client.Nrdb.QueryWithContext(ctx, accountID, nrdb.NRQL(query))
+		if err != nil {
+			return nil, err
+		}
+
+		for _, r := range resp.Results {
+			guid, _ := r["entityGuid"].(string)
+			result, _ := r["result"].(string)
+			if guid != "" {
+				results[guid] = result
+			}
+		}
+	}
+
+	return results, nil
+}
+
+const searchSyntheticsEntitiesQuery = `query($query: String!, $cursor: String) {
+  actor {
+    entitySearch(query: $query) {
+      results(cursor: $cursor) {
+        nextCursor
+        entities { guid name accountId tags { key values } }
+      }
+    }
+  }
+}`
+
+// syntheticsEntity is a Synthetics entity other than a monitor, such as a
+// secure credential or a private location.
+type syntheticsEntity struct {
+	GUID      string       `json:"guid"`
+	Name      string       `json:"name"`
+	AccountID int          `json:"accountId"`
+	Tags      []monitorTag `json:"tags,omitempty"`
+}
+
+// searchSyntheticsEntities returns the Synthetics entities of the type, such
+// as SECURE_CRED or PRIVATE_LOCATION, in the account when it is not 0.
+func (c *monitorClient) searchSyntheticsEntities(ctx context.Context, entityType string, accountID int) ([]syntheticsEntity, error) {
+	query := fmt.Sprintf("domain = 'SYNTH' AND type = %s", entitySearchValue(entityType))
+	if accountID != 0 {
+		query += fmt.Sprintf(" AND accountId = '%d'", accountID)
+	}
+
+	entities := []syntheticsEntity{}
+	var cursor interface{}
+
+	for {
+		var resp struct {
+			Actor struct {
+				EntitySearch struct {
+					Results struct {
+						NextCursor string             `json:"nextCursor"`
+						Entities   []syntheticsEntity `json:"entities"`
+					} `json:"results"`
+				} `json:"entitySearch"`
+			} `json:"actor"`
+		}
+
+		vars := map[string]interface{}{"query": query, "cursor": cursor}
+		if err := c.client.NerdGraph.QueryWithResponseAndContext(ctx, searchSyntheticsEntitiesQuery, vars, &resp); err != nil {
+			return nil, err
+		}
+		entities = append(entities, resp.Actor.EntitySearch.Results.Entities...)
+
+		if resp.Actor.EntitySearch.Results.NextCursor == "" {
+			return entities, nil
+		}
+		cursor = resp.Actor.EntitySearch.Results.NextCursor
+	}
+}
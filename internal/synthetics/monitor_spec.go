@@ -0,0 +1,348 @@
+package synthetics
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Monitor types, as reported by the monitorType of synthetic monitor entities.
+const (
+	monitorTypeSimple        = "SIMPLE"
+	monitorTypeSimpleBrowser = "BROWSER"
+	monitorTypeScriptAPI     = "SCRIPT_API"
+	monitorTypeScriptBrowser = "SCRIPT_BROWSER"
+	monitorTypeStep          = "STEP_MONITOR"
+	monitorTypeCertCheck     = "CERT_CHECK"
+	monitorTypeBrokenLinks   = "BROKEN_LINKS"
+)
+
+// monitorMutationNames maps each monitor type to the name used in its
+// syntheticsCreate<Name>Monitor and syntheticsUpdate<Name>Monitor mutations.
+var monitorMutationNames = map[string]string{
+	monitorTypeSimple:        "Simple",
+	monitorTypeSimpleBrowser: "SimpleBrowser",
+	monitorTypeScriptAPI:     "ScriptApi",
+	monitorTypeScriptBrowser: "ScriptBrowser",
+	monitorTypeStep:          "Step",
+	monitorTypeCertCheck:     "CertCheck",
+	monitorTypeBrokenLinks:   "BrokenLinks",
+}
+
+// monitorPeriods maps the period of a monitor in minutes to its period in
+// monitor inputs.
+var monitorPeriods = map[int]string{
+	1:    "EVERY_MINUTE",
+	5:    "EVERY_5_MINUTES",
+	10:   "EVERY_10_MINUTES",
+	15:   "EVERY_15_MINUTES",
+	30:   "EVERY_30_MINUTES",
+	60:   "EVERY_HOUR",
+	360:  "EVERY_6_HOURS",
+	720:  "EVERY_12_HOURS",
+	1440: "EVERY_DAY",
+}
+
+// MonitorSpec is the definition of a Synthetics monitor as code.  The script of
+// scripted monitors is kept in a sidecar file next to the spec.
+type MonitorSpec struct {
+	Name                              string                  `yaml:"name" json:"name"`
+	Type                              string                  `yaml:"type" json:"type"`
+	AccountID                         int                     `yaml:"accountId,omitempty" json:"accountId,omitempty"`
+	Period                            string                  `yaml:"period" json:"period"`
+	Status                            string                  `yaml:"status" json:"status"`
+	URI                               string                  `yaml:"uri,omitempty" json:"uri,omitempty"`
+	Domain                            string                  `yaml:"domain,omitempty" json:"domain,omitempty"`
+	NumberDaysToFailBeforeCertExpires int                     `yaml:"numberDaysToFailBeforeCertExpires,omitempty" json:"numberDaysToFailBeforeCertExpires,omitempty"`
+	Locations                         MonitorLocations        `yaml:"locations" json:"locations"`
+	Runtime                           *MonitorRuntime         `yaml:"runtime,omitempty" json:"runtime,omitempty"`
+	Browsers                          []string                `yaml:"browsers,omitempty" json:"browsers,omitempty"`
+	Devices                           []string                `yaml:"devices,omitempty" json:"devices,omitempty"`
+	AdvancedOptions                   *MonitorAdvancedOptions `yaml:"advancedOptions,omitempty" json:"advancedOptions,omitempty"`
+	Steps                             []MonitorStep           `yaml:"steps,omitempty" json:"steps,omitempty"`
+	Tags                              map[string][]string     `yaml:"tags,omitempty" json:"tags,omitempty"`
+	// Script is the path of the script file, relative to the spec file.
+	Script string `yaml:"script,omitempty" json:"-"`
+	// ScriptText is the content of the script file.
+	ScriptText string `yaml:"-" json:"script,omitempty"`
+	// GUID is the GUID of the existing monitor, set when the spec is exported
+	// from New Relic.
+	GUID string `yaml:"-" json:"-"`
+}
+
+type MonitorLocations struct {
+	Public  []string `yaml:"public,omitempty" json:"public,omitempty"`
+	Private []string `yaml:"private,omitempty" json:"private,omitempty"`
+}
+
+type MonitorRuntime struct {
+	RuntimeType        string `yaml:"runtimeType" json:"runtimeType"`
+	RuntimeTypeVersion string `yaml:"runtimeTypeVersion" json:"runtimeTypeVersion"`
+	ScriptLanguage     string `yaml:"scriptLanguage,omitempty" json:"scriptLanguage,omitempty"`
+}
+
+type MonitorAdvancedOptions struct {
+	ResponseValidationText             string `yaml:"responseValidationText,omitempty" json:"responseValidationText,omitempty"`
+	RedirectIsFailure                  *bool  `yaml:"redirectIsFailure,omitempty" json:"redirectIsFailure,omitempty"`
+	ShouldBypassHeadRequest            *bool  `yaml:"shouldBypassHeadRequest,omitempty" json:"shouldBypassHeadRequest,omitempty"`
+	UseTLSValidation                   *bool  `yaml:"useTlsValidation,omitempty" json:"useTlsValidation,omitempty"`
+	EnableScreenshotOnFailureAndScript *bool  `yaml:"enableScreenshotOnFailureAndScript,omitempty" json:"enableScreenshotOnFailureAndScript,omitempty"`
+}
+
+type MonitorStep struct {
+	Ordinal int      `yaml:"ordinal" json:"ordinal"`
+	Type    string   `yaml:"type" json:"type"`
+	Values  []string `yaml:"values,omitempty" json:"values,omitempty"`
+}
+
+// isScripted returns whether the monitor runs a script kept in a sidecar file.
+func (s *MonitorSpec) isScripted() bool {
+	return s.Type == monitorTypeScriptAPI || s.Type == monitorTypeScriptBrowser
+}
+
+// validate checks the fields required to create the monitor.
+func (s *MonitorSpec) validate() error {
+	if s.Name == "" {
+		return fmt.Errorf("monitor name is required")
+	}
+
+	if _, ok := monitorMutationNames[s.Type]; !ok {
+		return fmt.Errorf("monitor %s has unknown type %s, must be one of %s", s.Name, s.Type, strings.Join(monitorTypeNames(), ", "))
+	}
+
+	known := false
+	for _, p := range monitorPeriods {
+		known = known || p == s.Period
+	}
+	if !known {
+		return fmt.Errorf("monitor %s has unknown period %s", s.Name, s.Period)
+	}
+
+	if len(s.Locations.Public) == 0 && len(s.Locations.Private) == 0 {
+		return fmt.Errorf("monitor %s has no locations", s.Name)
+	}
+
+	switch s.Type {
+	case monitorTypeSimple, monitorTypeSimpleBrowser, monitorTypeBrokenLinks:
+		if s.URI == "" {
+			return fmt.Errorf("monitor %s of type %s requires a uri", s.Name, s.Type)
+		}
+	case monitorTypeCertCheck:
+		if s.Domain == "" {
+			return fmt.Errorf("monitor %s of type %s requires a domain", s.Name, s.Type)
+		}
+	case monitorTypeScriptAPI, monitorTypeScriptBrowser:
+		if s.ScriptText == "" {
+			return fmt.Errorf("monitor %s of type %s requires a script", s.Name, s.Type)
+		}
+	case monitorTypeStep:
+		if len(s.Steps) == 0 {
+			return fmt.Errorf("monitor %s of type %s requires steps", s.Name, s.Type)
+		}
+	}
+
+	return nil
+}
+
+func monitorTypeNames() []string {
+	names := []string{}
+	for t := range monitorMutationNames {
+		names = append(names, t)
+	}
+	sort.Strings(names)
+	return names
+}
+
+var monitorFileNameRegex = regexp.MustCompile(`[^a-z0-9]+`)
+
+// monitorFileName returns the name of the spec file of a monitor, without
+// extension, derived from its name.
+func monitorFileName(name string) string {
+	f := strings.Trim(monitorFileNameRegex.ReplaceAllString(strings.ToLower(name), "-"), "-")
+	if f == "" {
+		return "monitor"
+	}
+
+	return f
+}
+
+// scriptExtension returns the extension of the script sidecar file.
+func (s *MonitorSpec) scriptExtension() string {
+	if s.Runtime != nil && strings.EqualFold(s.Runtime.ScriptLanguage, "python") {
+		return ".py"
+	}
+
+	return ".js"
+}
+
+// writeMonitorSpec writes the spec to dir, and its script in a sidecar file,
+// returning the path of the spec file.
+func writeMonitorSpec(dir string, s MonitorSpec) (string, error) {
+	base := monitorFileName(s.Name)
+
+	if s.isScripted() {
+		s.Script = base + s.scriptExtension()
+		if err := os.WriteFile(filepath.Join(dir, s.Script), []byte(s.ScriptText), 0600); err != nil {
+			return "", err
+		}
+	}
+
+	content, err := yaml.Marshal(s)
+	if err != nil {
+		return "", err
+	}
+
+	p := filepath.Join(dir, base+".yml")
+	return p, os.WriteFile(p, content, 0600)
+}
+
+// readMonitorSpecs reads the spec files at path, a spec file or a directory
+// of spec files, along with their scripts.  Specs without an account default
+// to accountID, so that specs of the same monitor are found with or without
+// it.
+func readMonitorSpecs(path string, accountID int) ([]MonitorSpec, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+
+	files := []string{path}
+	if info.IsDir() {
+		files = []string{}
+		entries, err := os.ReadDir(path)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, e := range entries {
+			ext := filepath.Ext(e.Name())
+			if !e.IsDir() && (ext == ".yml" || ext == ".yaml") {
+				files = append(files, filepath.Join(path, e.Name()))
+			}
+		}
+	}
+
+	specs := []MonitorSpec{}
+	names := map[string]string{}
+	for _, f := range files {
+		s, err := readMonitorSpec(f)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %s", f, err)
+		}
+
+		if s.AccountID == 0 {
+			s.AccountID = accountID
+		}
+
+		key := fmt.Sprintf("%d/%s", s.AccountID, s.Name)
+		if other, ok := names[key]; ok {
+			return nil, fmt.Errorf("monitor %s is defined in both %s and %s", s.Name, other, f)
+		}
+		names[key] = f
+
+		specs = append(specs, s)
+	}
+
+	return specs, nil
+}
+
+func readMonitorSpec(p string) (MonitorSpec, error) {
+	var s MonitorSpec
+
+	content, err := os.ReadFile(p)
+	if err != nil {
+		return s, err
+	}
+
+	if err = yaml.Unmarshal(content, &s); err != nil {
+		return s, err
+	}
+
+	if s.Status == "" {
+		s.Status = "ENABLED"
+	}
+
+	if s.Script != "" {
+		script, err := os.ReadFile(filepath.Join(filepath.Dir(p), s.Script))
+		if err != nil {
+			return s, err
+		}
+		s.ScriptText = string(script)
+	}
+
+	return s, s.validate()
+}
+
+// monitorSpecDiff returns the fields of the spec that differ from the current
+// monitor, as lines describing the change.
+func monitorSpecDiff(current MonitorSpec, desired MonitorSpec) []string {
+	currentFields := monitorSpecFields(current)
+	desiredFields := monitorSpecFields(desired)
+
+	keys := []string{}
+	for k := range desiredFields {
+		keys = append(keys, k)
+	}
+	for k := range currentFields {
+		if _, ok := desiredFields[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+
+	diff := []string{}
+	for _, k := range keys {
+		if k == "accountId" || reflect.DeepEqual(currentFields[k], desiredFields[k]) {
+			continue
+		}
+
+		if k == "script" {
+			diff = append(diff, fmt.Sprintf("script: %d lines => %d lines", lineCount(current.ScriptText), lineCount(desired.ScriptText)))
+			continue
+		}
+
+		diff = append(diff, fmt.Sprintf("%s: %s => %s", k, monitorFieldString(currentFields[k]), monitorFieldString(desiredFields[k])))
+	}
+
+	return diff
+}
+
+func monitorSpecFields(s MonitorSpec) map[string]interface{} {
+	fields := map[string]interface{}{}
+
+	content, err := json.Marshal(s)
+	if err != nil {
+		return fields
+	}
+
+	_ = json.Unmarshal(content, &fields)
+	return fields
+}
+
+func monitorFieldString(v interface{}) string {
+	if v == nil {
+		return "(none)"
+	}
+
+	content, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+
+	return string(content)
+}
+
+func lineCount(s string) int {
+	if s == "" {
+		return 0
+	}
+
+	return strings.Count(strings.TrimSuffix(s, "\n"), "\n") + 1
+}
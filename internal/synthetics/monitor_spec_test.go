@@ -0,0 +1,211 @@
+//go:build unit
+
+package synthetics
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/newrelic/newrelic-cli/internal/utils"
+	"github.com/newrelic/newrelic-client-go/v2/newrelic"
+)
+
+// newMockNerdGraphClient returns a client of a mock NerdGraph server with the
+// responses, closed at the end of the test.
+func newMockNerdGraphClient(t *testing.T, responses map[string]string) *newrelic.NewRelic {
+	server := utils.NewMockNerdGraphServer(responses)
+	t.Cleanup(server.Close)
+
+	return server.Client()
+}
+
+func TestMonitorSpecShouldRoundTripWithScriptSidecar(t *testing.T) {
+	dir := t.TempDir()
+	spec := MonitorSpec{
+		Name:       "Checkout API",
+		Type:       monitorTypeScriptAPI,
+		AccountID:  12345,
+		Period:     "EVERY_5_MINUTES",
+		Status:     "ENABLED",
+		Locations:  MonitorLocations{Public: []string{"AWS_US_EAST_1"}},
+		Runtime:    &MonitorRuntime{RuntimeType: "NODE_API", RuntimeTypeVersion: "16.10"},
+		Tags:       map[string][]string{"team": {"payments"}},
+		ScriptText: "const assert = require('assert');\n",
+	}
+
+	p, err := writeMonitorSpec(dir, spec)
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(dir, "checkout-api.yml"), p)
+
+	script, err := os.ReadFile(filepath.Join(dir, "checkout-api.js"))
+	require.NoError(t, err)
+	assert.Equal(t, spec.ScriptText, string(script))
+
+	specs, err := readMonitorSpecs(dir, 0)
+	require.NoError(t, err)
+	require.Len(t, specs, 1)
+	assert.Empty(t, monitorSpecDiff(spec, specs[0]))
+}
+
+func TestReadMonitorSpecsShouldDefaultAccountBeforeFindingDuplicates(t *testing.T) {
+	dir := t.TempDir()
+	spec := MonitorSpec{
+		Name:      "Home page",
+		Type:      monitorTypeSimple,
+		Period:    "EVERY_5_MINUTES",
+		URI:       "https://example.com",
+		Locations: MonitorLocations{Public: []string{"AWS_US_EAST_1"}},
+	}
+	_, err := writeMonitorSpec(dir, spec)
+	require.NoError(t, err)
+
+	specs, err := readMonitorSpecs(dir, 12345)
+	require.NoError(t, err)
+	require.Len(t, specs, 1)
+	assert.Equal(t, 12345, specs[0].AccountID)
+
+	content, err := os.ReadFile(filepath.Join(dir, "home-page.yml"))
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "home-page-copy.yml"), append(content, []byte("accountId: 12345\n")...), 0600))
+
+	_, err = readMonitorSpecs(dir, 12345)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "monitor Home page is defined in both")
+
+	specs, err = readMonitorSpecs(dir, 67890)
+	require.NoError(t, err)
+	assert.Len(t, specs, 2)
+}
+
+func TestMonitorSpecShouldRequireFieldsOfType(t *testing.T) {
+	spec := MonitorSpec{
+		Name:      "Certificate",
+		Type:      monitorTypeCertCheck,
+		Period:    "EVERY_DAY",
+		Locations: MonitorLocations{Public: []string{"AWS_US_EAST_1"}},
+	}
+	assert.EqualError(t, spec.validate(), "monitor Certificate of type CERT_CHECK requires a domain")
+
+	spec.Period = "EVERY_2_DAYS"
+	assert.EqualError(t, spec.validate(), "monitor Certificate has unknown period EVERY_2_DAYS")
+
+	spec.Type = "PING"
+	assert.Contains(t, spec.validate().Error(), "monitor Certificate has unknown type PING")
+}
+
+func TestMonitorSpecDiffShouldDescribeChanges(t *testing.T) {
+	current := MonitorSpec{
+		Name:       "Home page",
+		Type:       monitorTypeSimple,
+		AccountID:  12345,
+		Period:     "EVERY_5_MINUTES",
+		Status:     "ENABLED",
+		URI:        "https://example.com",
+		Locations:  MonitorLocations{Public: []string{"AWS_US_EAST_1"}},
+		ScriptText: "a\nb\n",
+	}
+	desired := current
+	desired.AccountID = 0
+	desired.Period = "EVERY_15_MINUTES"
+	desired.Tags = map[string][]string{"team": {"web"}}
+	desired.ScriptText = "a\nb\nc\n"
+
+	assert.Equal(t, []string{
+		`period: "EVERY_5_MINUTES" => "EVERY_15_MINUTES"`,
+		"script: 2 lines => 3 lines",
+		`tags: (none) => {"team":["web"]}`,
+	}, monitorSpecDiff(current, desired))
+}
+
+func TestPlanMonitorsShouldCreateUpdateAndSkipUnchanged(t *testing.T) {
+	c := newMonitorClient(newMockNerdGraphClient(t, map[string]string{
+		"entitySearch": `{"actor": {"entitySearch": {"results": {"entities": [{
+			"guid": "MONITOR-GUID", "name": "Home page", "accountId": 12345, "monitorType": "SIMPLE",
+			"period": 5, "monitoredUrl": "https://example.com", "monitorSummary": {"status": "ENABLED"},
+			"tags": [{"key": "publicLocation", "values": ["AWS_US_EAST_1"]}, {"key": "team", "values": ["web"]}]
+		}]}}}}`,
+	}))
+
+	unchanged := MonitorSpec{
+		Name:      "Home page",
+		Type:      monitorTypeSimple,
+		Period:    "EVERY_5_MINUTES",
+		Status:    "ENABLED",
+		URI:       "https://example.com",
+		Locations: MonitorLocations{Public: []string{"AWS_US_EAST_1"}},
+		Tags:      map[string][]string{"team": {"web"}},
+	}
+	updated := unchanged
+	updated.Status = "DISABLED"
+
+	plan, err := planMonitors(context.Background(), c, []MonitorSpec{unchanged}, 12345)
+	require.NoError(t, err)
+	require.Len(t, plan, 1)
+	assert.Equal(t, monitorActionUnchanged, plan[0].Action)
+
+	plan, err = planMonitors(context.Background(), c, []MonitorSpec{updated}, 12345)
+	require.NoError(t, err)
+	require.Len(t, plan, 1)
+	assert.Equal(t, monitorActionUpdate, plan[0].Action)
+	assert.Equal(t, []string{`status: "ENABLED" => "DISABLED"`}, plan[0].Diff)
+
+	var out bytes.Buffer
+	printMonitorPlan(&out, plan)
+	assert.Contains(t, out.String(), "~ update Home page (MONITOR-GUID)")
+	assert.Contains(t, out.String(), "Plan: 0 to create, 1 to update, 0 unchanged.")
+}
+
+func TestPlanMonitorsShouldRejectTypeChanges(t *testing.T) {
+	c := newMonitorClient(newMockNerdGraphClient(t, map[string]string{
+		"entitySearch": `{"actor": {"entitySearch": {"results": {"entities": [{
+			"guid": "MONITOR-GUID", "name": "Home page", "accountId": 12345, "monitorType": "BROWSER", "period": 5
+		}]}}}}`,
+	}))
+
+	spec := MonitorSpec{Name: "Home page", Type: monitorTypeSimple, AccountID: 12345}
+
+	_, err := planMonitors(context.Background(), c, []MonitorSpec{spec}, 0)
+	assert.EqualError(t, err, "monitor Home page has type BROWSER and cannot be changed to SIMPLE, delete it first")
+}
+
+func TestApplyMonitorPlanShouldCreateMonitors(t *testing.T) {
+	server := utils.NewMockNerdGraphServer(map[string]string{
+		"syntheticsCreateScriptApiMonitor": `{"syntheticsCreateScriptApiMonitor": {"monitor": {"guid": "NEW-GUID"}}}`,
+	})
+	defer server.Close()
+	spec := MonitorSpec{
+		Name:       "Checkout API",
+		Type:       monitorTypeScriptAPI,
+		AccountID:  12345,
+		Period:     "EVERY_5_MINUTES",
+		Status:     "ENABLED",
+		Locations:  MonitorLocations{Private: []string{"LOCATION-GUID"}},
+		ScriptText: "// script",
+	}
+
+	guid, err := newMonitorClient(server.Client()).createMonitor(context.Background(), spec)
+	require.NoError(t, err)
+	assert.Equal(t, "NEW-GUID", guid)
+
+	mutations := server.Mutations()
+	require.Len(t, mutations, 1)
+	input := mutations[0].Variables["monitor"].(map[string]interface{})
+	assert.Equal(t, "// script", input["script"])
+	assert.Equal(t, []interface{}{map[string]interface{}{"guid": "LOCATION-GUID"}}, input["locations"].(map[string]interface{})["private"])
+}
+
+func TestMonitorMutationShouldReturnErrors(t *testing.T) {
+	server := utils.NewMockNerdGraphServer(map[string]string{
+		"syntheticsUpdateSimpleMonitor": `{"syntheticsUpdateSimpleMonitor": {"errors": [{"type": "BAD_REQUEST", "description": "invalid uri"}]}}`,
+	})
+	defer server.Close()
+
+	err := newMonitorClient(server.Client()).updateMonitor(context.Background(), "MONITOR-GUID", MonitorSpec{Type: monitorTypeSimple})
+	assert.EqualError(t, err, "BAD_REQUEST: invalid uri")
+}
@@ -0,0 +1,84 @@
+package terraform
+
+import (
+	"fmt"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+
+	"github.com/newrelic/newrelic-cli/internal/client"
+	configAPI "github.com/newrelic/newrelic-cli/internal/config/api"
+	"github.com/newrelic/newrelic-cli/internal/utils"
+)
+
+var (
+	exportTypeFlags  []string
+	exportOutputDir  string
+	exportShiftWidth int
+	exportOverwrite  bool
+)
+
+// Command represents the terraform command
+var Command = &cobra.Command{
+	Use:   "terraform",
+	Short: "Bring New Relic resources under Terraform",
+	Long: `Bring New Relic resources under Terraform
+
+The terraform commands generate Terraform configuration for the resources of an
+account, to manage resources created in the New Relic UI as code.
+`,
+	Example: "newrelic terraform export --type dashboard,alert_policy --accountId 12345",
+}
+
+var cmdExport = &cobra.Command{
+	Use:   "export",
+	Short: "Export the resources of an account as Terraform HCL and import blocks",
+	Long: `Export the resources of an account as Terraform HCL and import blocks
+
+The export command fetches the resources of the given types from the account and
+writes their HCL to <type>.tf in the output directory, along with an import block
+for each resource in imports.tf.  Running terraform plan in the directory then
+imports the resources into the Terraform state, without changing them.
+
+The types of resources are dashboard, alert_policy, nrql_alert_condition,
+synthetics_monitor, workload and tags.  The tags of the dashboards and workloads
+exported are written as newrelic_entity_tags resources, the tags of monitors are
+written with the monitors.  Conditions reference the policies exported with them.
+
+Import blocks require Terraform 1.5 or later.
+`,
+	Example: `newrelic terraform export --type dashboard,alert_policy,nrql_alert_condition --accountId 12345 --output ./newrelic
+newrelic terraform export --type synthetics_monitor,workload,dashboard,tags --output ./newrelic --overwrite`,
+	PreRun: client.RequireClient,
+	Run: func(cmd *cobra.Command, args []string) {
+		accountID := configAPI.RequireActiveProfileAccountID()
+
+		types, err := parseExportTypes(exportTypeFlags)
+		utils.LogIfFatal(err)
+
+		e := newExporter(client.NRClient, accountID, exportShiftWidth)
+		resources, err := e.export(utils.SignalCtx, types)
+		utils.LogIfFatal(err)
+
+		paths, err := writeExport(exportOutputDir, types, resources, exportShiftWidth, exportOverwrite)
+		utils.LogIfFatal(err)
+
+		if len(paths) == 0 {
+			log.Info("no resources found to export")
+			return
+		}
+
+		for _, p := range paths {
+			fmt.Println(p)
+		}
+	},
+}
+
+func init() {
+	cmdExport.Flags().StringSliceVarP(&exportTypeFlags, "type", "t", []string{}, "the types of resources to export, comma separated")
+	cmdExport.Flags().StringVarP(&exportOutputDir, "output", "o", ".", "the directory to write the Terraform files to")
+	cmdExport.Flags().IntVarP(&exportShiftWidth, "shiftWidth", "w", 2, "the indentation shift with of the output")
+	cmdExport.Flags().BoolVar(&exportOverwrite, "overwrite", false, "replace the Terraform files of the output directory")
+	utils.LogIfError(cmdExport.MarkFlagRequired("type"))
+	Command.AddCommand(cmdExport)
+}
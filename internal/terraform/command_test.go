@@ -0,0 +1,25 @@
+//go:build unit
+
+package terraform
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/newrelic/newrelic-cli/internal/testcobra"
+)
+
+func TestTerraformCommand(t *testing.T) {
+	assert.Equal(t, "terraform", Command.Name())
+
+	testcobra.CheckCobraMetadata(t, Command)
+	testcobra.CheckCobraRequiredFlags(t, Command, []string{})
+}
+
+func TestTerraformExportCommand(t *testing.T) {
+	assert.Equal(t, "export", cmdExport.Name())
+
+	testcobra.CheckCobraMetadata(t, cmdExport)
+	testcobra.CheckCobraRequiredFlags(t, cmdExport, []string{"type"})
+}
@@ -0,0 +1,168 @@
+package terraform
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/newrelic/newrelic-client-go/v2/pkg/alerts"
+)
+
+const (
+	alertPolicyResourceType        = "newrelic_alert_policy"
+	nrqlAlertConditionResourceType = "newrelic_nrql_alert_condition"
+)
+
+// nrqlCondition is the NRQL condition of the typed client, decoded through
+// JSON as the typed condition spreads its fields over embedded structs and
+// optional values.
+type nrqlCondition struct {
+	ID                        string `json:"id"`
+	Name                      string `json:"name"`
+	Type                      string `json:"type"`
+	Enabled                   bool   `json:"enabled"`
+	Description               string `json:"description"`
+	PolicyID                  string `json:"policyId"`
+	RunbookURL                string `json:"runbookUrl"`
+	ViolationTimeLimitSeconds int    `json:"violationTimeLimitSeconds"`
+	BaselineDirection         string `json:"baselineDirection"`
+	Nrql                      struct {
+		Query string `json:"query"`
+	} `json:"nrql"`
+	Signal *struct {
+		AggregationWindow *int     `json:"aggregationWindow"`
+		AggregationMethod string   `json:"aggregationMethod"`
+		AggregationDelay  *int     `json:"aggregationDelay"`
+		AggregationTimer  *int     `json:"aggregationTimer"`
+		FillOption        string   `json:"fillOption"`
+		FillValue         *float64 `json:"fillValue"`
+	} `json:"signal"`
+	Terms []struct {
+		Operator             string  `json:"operator"`
+		Priority             string  `json:"priority"`
+		Threshold            float64 `json:"threshold"`
+		ThresholdDuration    int     `json:"thresholdDuration"`
+		ThresholdOccurrences string  `json:"thresholdOccurrences"`
+	} `json:"terms"`
+	Expiration *struct {
+		CloseViolationsOnExpiration bool `json:"closeViolationsOnExpiration"`
+		ExpirationDuration          *int `json:"expirationDuration"`
+		OpenViolationOnExpiration   bool `json:"openViolationOnExpiration"`
+	} `json:"expiration"`
+}
+
+func (e *exporter) exportAlertPolicies(ctx context.Context) ([]resource, error) {
+	policies, err := e.client.Alerts.QueryPolicySearchWithContext(ctx, e.accountID, alerts.AlertsPoliciesSearchCriteriaInput{})
+	if err != nil {
+		return nil, err
+	}
+
+	resources := []resource{}
+	for _, p := range policies {
+		label := e.label(alertPolicyResourceType, p.Name)
+		e.policyLabels[p.ID] = label
+
+		h := e.newHCLGen()
+		h.WriteBlock("resource", []string{alertPolicyResourceType, label}, func() {
+			h.WriteIntAttribute("account_id", e.accountID)
+			h.WriteStringAttribute("name", p.Name)
+			h.WriteStringAttributeIfNotEmpty("incident_preference", string(p.IncidentPreference))
+		})
+
+		resources = append(resources, resource{Type: alertPolicyResourceType, Label: label, ID: p.ID, HCL: h.String()})
+	}
+
+	return resources, nil
+}
+
+func (e *exporter) exportNrqlAlertConditions(ctx context.Context) ([]resource, error) {
+	results, err := e.client.Alerts.SearchNrqlConditionsQueryWithContext(ctx, e.accountID, alerts.NrqlConditionsSearchCriteria{})
+	if err != nil {
+		return nil, err
+	}
+
+	b, err := json.Marshal(results)
+	if err != nil {
+		return nil, err
+	}
+
+	var conditions []nrqlCondition
+	if err := json.Unmarshal(b, &conditions); err != nil {
+		return nil, err
+	}
+
+	resources := []resource{}
+	for _, c := range conditions {
+		resources = append(resources, e.nrqlConditionResource(c))
+	}
+
+	return resources, nil
+}
+
+func (e *exporter) nrqlConditionResource(c nrqlCondition) resource {
+	label := e.label(nrqlAlertConditionResourceType, c.Name)
+	conditionType := strings.ToLower(c.Type)
+
+	h := e.newHCLGen()
+	h.WriteBlock("resource", []string{nrqlAlertConditionResourceType, label}, func() {
+		h.WriteIntAttribute("account_id", e.accountID)
+		if policyLabel, ok := e.policyLabels[c.PolicyID]; ok {
+			h.WriteReferenceAttribute("policy_id", alertPolicyResourceType+"."+policyLabel+".id")
+		} else {
+			h.WriteReferenceAttribute("policy_id", c.PolicyID)
+		}
+		h.WriteStringAttribute("type", conditionType)
+		h.WriteStringAttribute("name", c.Name)
+		h.WriteStringAttributeIfNotEmpty("description", c.Description)
+		h.WriteStringAttributeIfNotEmpty("runbook_url", c.RunbookURL)
+		h.WriteBooleanAttribute("enabled", c.Enabled)
+		h.WriteIntAttributeIfNotZero("violation_time_limit_seconds", c.ViolationTimeLimitSeconds)
+		h.WriteStringAttributeIfNotEmpty("baseline_direction", strings.ToLower(c.BaselineDirection))
+
+		h.WriteBlock("nrql", []string{}, func() {
+			h.WriteStringAttribute("query", c.Nrql.Query)
+		})
+
+		for _, t := range c.Terms {
+			h.WriteBlock(strings.ToLower(t.Priority), []string{}, func() {
+				h.WriteStringAttribute("operator", strings.ToLower(t.Operator))
+				h.WriteFloatAttribute("threshold", t.Threshold)
+				h.WriteIntAttribute("threshold_duration", t.ThresholdDuration)
+				h.WriteStringAttribute("threshold_occurrences", strings.ToLower(t.ThresholdOccurrences))
+			})
+		}
+
+		if s := c.Signal; s != nil {
+			if s.AggregationWindow != nil {
+				h.WriteIntAttribute("aggregation_window", *s.AggregationWindow)
+			}
+			h.WriteStringAttributeIfNotEmpty("aggregation_method", strings.ToLower(s.AggregationMethod))
+			if s.AggregationDelay != nil {
+				h.WriteIntAttribute("aggregation_delay", *s.AggregationDelay)
+			}
+			if s.AggregationTimer != nil {
+				h.WriteIntAttribute("aggregation_timer", *s.AggregationTimer)
+			}
+			h.WriteStringAttributeIfNotEmpty("fill_option", strings.ToLower(s.FillOption))
+			if s.FillValue != nil {
+				h.WriteFloatAttribute("fill_value", *s.FillValue)
+			}
+		}
+
+		if x := c.Expiration; x != nil {
+			if x.ExpirationDuration != nil {
+				h.WriteIntAttribute("expiration_duration", *x.ExpirationDuration)
+			}
+			h.WriteBooleanAttribute("open_violation_on_expiration", x.OpenViolationOnExpiration)
+			h.WriteBooleanAttribute("close_violations_on_expiration", x.CloseViolationsOnExpiration)
+		}
+	})
+
+	return resource{
+		Type:  nrqlAlertConditionResourceType,
+		Label: label,
+		ID:    fmt.Sprintf("%s:%s:%s", c.PolicyID, c.ID, conditionType),
+		HCL:   h.String(),
+	}
+}
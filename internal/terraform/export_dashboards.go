@@ -0,0 +1,109 @@
+package terraform
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/newrelic/newrelic-cli/internal/utils/terraform"
+	"github.com/newrelic/newrelic-client-go/v2/pkg/common"
+)
+
+const dashboardResourceType = "newrelic_one_dashboard"
+
+// searchDashboardsQuery pages through entity search results with their cursor,
+// which the typed entity search does not take.
+const searchDashboardsQuery = `query($query: String!, $cursor: String) {
+  actor {
+    entitySearch(query: $query) {
+      results(cursor: $cursor) {
+        nextCursor
+        entities {
+          guid
+          name
+          ... on DashboardEntityOutline { dashboardParentGuid }
+        }
+      }
+    }
+  }
+}`
+
+func (e *exporter) exportDashboards(ctx context.Context) ([]resource, error) {
+	query := fmt.Sprintf("type = 'DASHBOARD' AND accountId = '%d'", e.accountID)
+	resources := []resource{}
+
+	var cursor interface{}
+	for {
+		var resp struct {
+			Actor struct {
+				EntitySearch struct {
+					Results struct {
+						NextCursor string `json:"nextCursor"`
+						Entities   []struct {
+							GUID                string `json:"guid"`
+							Name                string `json:"name"`
+							DashboardParentGUID string `json:"dashboardParentGuid"`
+						} `json:"entities"`
+					} `json:"results"`
+				} `json:"entitySearch"`
+			} `json:"actor"`
+		}
+
+		vars := map[string]interface{}{"query": query, "cursor": cursor}
+		if err := e.client.NerdGraph.QueryWithResponseAndContext(ctx, searchDashboardsQuery, vars, &resp); err != nil {
+			return nil, err
+		}
+
+		for _, d := range resp.Actor.EntitySearch.Results.Entities {
+			// Pages of dashboards are dashboard entities too, they are
+			// exported with their dashboard.
+			if d.DashboardParentGUID != "" {
+				continue
+			}
+
+			r, ok, err := e.exportDashboard(ctx, d.GUID, d.Name)
+			if err != nil {
+				return nil, err
+			}
+			if ok {
+				resources = append(resources, r)
+			}
+		}
+
+		if resp.Actor.EntitySearch.Results.NextCursor == "" {
+			return resources, nil
+		}
+		cursor = resp.Actor.EntitySearch.Results.NextCursor
+	}
+}
+
+// exportDashboard returns the resource of the dashboard, and whether it was
+// exported.  Dashboards whose HCL cannot be generated are skipped, so that
+// they do not stop the export of the others.
+func (e *exporter) exportDashboard(ctx context.Context, guid string, name string) (resource, bool, error) {
+	d, err := e.client.Dashboards.GetDashboardEntityWithContext(ctx, common.EntityGUID(guid))
+	if err != nil {
+		return resource{}, false, err
+	}
+
+	// The dashboard entity has the shape of the dashboard JSON export.
+	export, err := json.Marshal(d)
+	if err != nil {
+		return resource{}, false, err
+	}
+
+	label := e.label(dashboardResourceType, name)
+
+	hcl, err := terraform.GenerateDashboardHCL(label, e.shiftWidth, export)
+	if err != nil {
+		log.Warnf("skipping dashboard %s (%s), %s", name, guid, err)
+		return resource{}, false, nil
+	}
+
+	r := resource{Type: dashboardResourceType, Label: label, ID: guid, HCL: hcl}
+	e.taggable = append(e.taggable, taggableResource{resource: r, GUID: guid})
+
+	return r, true, nil
+}
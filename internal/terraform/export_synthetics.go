@@ -0,0 +1,251 @@
+package terraform
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/newrelic/newrelic-client-go/v2/pkg/synthetics"
+)
+
+// syntheticsResourceTypes maps each monitor type to its Terraform resource
+// type.
+var syntheticsResourceTypes = map[string]string{
+	"SIMPLE":         "newrelic_synthetics_monitor",
+	"BROWSER":        "newrelic_synthetics_monitor",
+	"SCRIPT_API":     "newrelic_synthetics_script_monitor",
+	"SCRIPT_BROWSER": "newrelic_synthetics_script_monitor",
+	"STEP_MONITOR":   "newrelic_synthetics_step_monitor",
+	"CERT_CHECK":     "newrelic_synthetics_cert_check_monitor",
+	"BROKEN_LINKS":   "newrelic_synthetics_broken_links_monitor",
+}
+
+// monitorPeriods maps the period of a monitor in minutes to its period in
+// Terraform.
+var monitorPeriods = map[int]string{
+	1:    "EVERY_MINUTE",
+	5:    "EVERY_5_MINUTES",
+	10:   "EVERY_10_MINUTES",
+	15:   "EVERY_15_MINUTES",
+	30:   "EVERY_30_MINUTES",
+	60:   "EVERY_HOUR",
+	360:  "EVERY_6_HOURS",
+	720:  "EVERY_12_HOURS",
+	1440: "EVERY_DAY",
+}
+
+// monitorSettingTagKeys are the tags describing the settings of monitors,
+// which are written as attributes rather than tags.
+var monitorSettingTagKeys = map[string]bool{
+	"monitorType":                        true,
+	"monitorStatus":                      true,
+	"period":                             true,
+	"publicLocation":                     true,
+	"privateLocation":                    true,
+	"responseValidationText":             true,
+	"useTlsValidation":                   true,
+	"redirectIsFailure":                  true,
+	"bypassHEADRequest":                  true,
+	"runtimeType":                        true,
+	"runtimeTypeVersion":                 true,
+	"scriptLanguage":                     true,
+	"browsers":                           true,
+	"devices":                            true,
+	"deviceOrientation":                  true,
+	"deviceType":                         true,
+	"enableScreenshotOnFailureAndScript": true,
+	"daysUntilExpiration":                true,
+}
+
+// searchMonitorsQuery pages through entity search results with their cursor,
+// which the typed entity search does not take.
+const searchMonitorsQuery = `query($query: String!, $cursor: String) {
+  actor {
+    entitySearch(query: $query) {
+      results(cursor: $cursor) {
+        nextCursor
+        entities {
+          ... on SyntheticMonitorEntityOutline {
+            guid name monitorType period monitoredUrl monitorSummary { status } tags { key values }
+          }
+        }
+      }
+    }
+  }
+}`
+
+type monitor struct {
+	GUID           string `json:"guid"`
+	Name           string `json:"name"`
+	MonitorType    string `json:"monitorType"`
+	Period         int    `json:"period"`
+	MonitoredURL   string `json:"monitoredUrl"`
+	MonitorSummary struct {
+		Status string `json:"status"`
+	} `json:"monitorSummary"`
+	Tags []entityTag `json:"tags"`
+}
+
+type monitorStep struct {
+	Ordinal int
+	Type    string
+	Values  []string
+}
+
+func (m monitor) tag(key string) string {
+	if values := m.tagValues(key); len(values) > 0 {
+		return values[0]
+	}
+
+	return ""
+}
+
+func (m monitor) tagValues(key string) []string {
+	for _, t := range m.Tags {
+		if t.Key == key {
+			return t.Values
+		}
+	}
+
+	return nil
+}
+
+func (e *exporter) exportSyntheticsMonitors(ctx context.Context) ([]resource, error) {
+	query := fmt.Sprintf("domain = 'SYNTH' AND type = 'MONITOR' AND accountId = '%d'", e.accountID)
+	resources := []resource{}
+
+	var cursor interface{}
+	for {
+		var resp struct {
+			Actor struct {
+				EntitySearch struct {
+					Results struct {
+						NextCursor string    `json:"nextCursor"`
+						Entities   []monitor `json:"entities"`
+					} `json:"results"`
+				} `json:"entitySearch"`
+			} `json:"actor"`
+		}
+
+		vars := map[string]interface{}{"query": query, "cursor": cursor}
+		if err := e.client.NerdGraph.QueryWithResponseAndContext(ctx, searchMonitorsQuery, vars, &resp); err != nil {
+			return nil, err
+		}
+
+		for _, m := range resp.Actor.EntitySearch.Results.Entities {
+			if _, ok := syntheticsResourceTypes[m.MonitorType]; !ok {
+				log.Warnf("skipping monitor %s, monitors of type %s cannot be exported", m.Name, m.MonitorType)
+				continue
+			}
+
+			r, err := e.exportMonitor(ctx, m)
+			if err != nil {
+				return nil, err
+			}
+			resources = append(resources, r)
+		}
+
+		if resp.Actor.EntitySearch.Results.NextCursor == "" {
+			return resources, nil
+		}
+		cursor = resp.Actor.EntitySearch.Results.NextCursor
+	}
+}
+
+func (e *exporter) exportMonitor(ctx context.Context, m monitor) (resource, error) {
+	var script string
+	var steps []monitorStep
+
+	switch m.MonitorType {
+	case "SCRIPT_API", "SCRIPT_BROWSER":
+		resp, err := e.client.Synthetics.GetScriptWithContext(ctx, e.accountID, synthetics.EntityGUID(m.GUID))
+		if err != nil {
+			return resource{}, err
+		}
+		script = resp.Text
+	case "STEP_MONITOR":
+		resp, err := e.client.Synthetics.GetStepsWithContext(ctx, e.accountID, synthetics.EntityGUID(m.GUID))
+		if err != nil {
+			return resource{}, err
+		}
+
+		if resp != nil {
+			for _, s := range *resp {
+				steps = append(steps, monitorStep{Ordinal: s.Ordinal, Type: string(s.Type), Values: s.Values})
+			}
+		}
+		sort.Slice(steps, func(i, j int) bool { return steps[i].Ordinal < steps[j].Ordinal })
+	}
+
+	resourceType := syntheticsResourceTypes[m.MonitorType]
+	label := e.label(resourceType, m.Name)
+
+	status := m.MonitorSummary.Status
+	if status != "DISABLED" {
+		status = "ENABLED"
+	}
+
+	h := e.newHCLGen()
+	h.WriteBlock("resource", []string{resourceType, label}, func() {
+		h.WriteIntAttribute("account_id", e.accountID)
+		h.WriteStringAttribute("name", m.Name)
+		h.WriteStringAttribute("period", monitorPeriods[m.Period])
+		h.WriteStringAttribute("status", status)
+
+		switch m.MonitorType {
+		case "SIMPLE", "BROWSER":
+			h.WriteStringAttribute("type", m.MonitorType)
+			h.WriteStringAttribute("uri", m.MonitoredURL)
+		case "SCRIPT_API", "SCRIPT_BROWSER":
+			h.WriteStringAttribute("type", m.MonitorType)
+			h.WriteMultilineStringAttribute("script", escapeHeredoc(script))
+		case "CERT_CHECK":
+			h.WriteStringAttribute("domain", m.MonitoredURL)
+			if days, err := strconv.Atoi(m.tag("daysUntilExpiration")); err == nil {
+				h.WriteIntAttribute("certificate_expiration", days)
+			}
+		case "BROKEN_LINKS":
+			h.WriteStringAttribute("uri", m.MonitoredURL)
+		}
+
+		h.WriteStringSliceAttributeIfNotEmpty("locations_public", m.tagValues("publicLocation"))
+
+		private := m.tagValues("privateLocation")
+		switch m.MonitorType {
+		case "SCRIPT_API", "SCRIPT_BROWSER", "STEP_MONITOR":
+			for _, guid := range private {
+				h.WriteBlock("location_private", []string{}, func() {
+					h.WriteStringAttribute("guid", guid)
+				})
+			}
+			h.WriteStringAttributeIfNotEmpty("runtime_type", m.tag("runtimeType"))
+			h.WriteStringAttributeIfNotEmpty("runtime_type_version", m.tag("runtimeTypeVersion"))
+			h.WriteStringAttributeIfNotEmpty("script_language", m.tag("scriptLanguage"))
+		default:
+			h.WriteStringSliceAttributeIfNotEmpty("locations_private", private)
+		}
+
+		for _, s := range steps {
+			h.WriteBlock("steps", []string{}, func() {
+				h.WriteIntAttribute("ordinal", s.Ordinal)
+				h.WriteStringAttribute("type", s.Type)
+				h.WriteStringSliceAttributeIfNotEmpty("values", s.Values)
+			})
+		}
+
+		for _, t := range m.Tags {
+			if systemTagKeys[t.Key] || monitorSettingTagKeys[t.Key] {
+				continue
+			}
+			h.WriteBlock("tag", []string{}, func() {
+				h.WriteStringAttribute("key", t.Key)
+				h.WriteStringSliceAttribute("values", t.Values)
+			})
+		}
+	})
+
+	return resource{Type: resourceType, Label: label, ID: m.GUID, HCL: h.String()}, nil
+}
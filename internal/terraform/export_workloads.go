@@ -0,0 +1,149 @@
+package terraform
+
+import (
+	"context"
+	"fmt"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/newrelic/newrelic-client-go/v2/pkg/common"
+)
+
+const (
+	workloadResourceType   = "newrelic_workload"
+	entityTagsResourceType = "newrelic_entity_tags"
+)
+
+// listWorkloadsQuery lists the workload collections of the account, with the
+// entities and queries defining them, which workload entities do not have.
+const listWorkloadsQuery = `query($accountId: Int!) {
+  actor {
+    account(id: $accountId) {
+      workload {
+        collections {
+          id
+          guid
+          name
+          entities { guid }
+          entitySearchQueries { query }
+          scopeAccounts { accountIds }
+        }
+      }
+    }
+  }
+}`
+
+type workload struct {
+	ID       int    `json:"id"`
+	GUID     string `json:"guid"`
+	Name     string `json:"name"`
+	Entities []struct {
+		GUID string `json:"guid"`
+	} `json:"entities"`
+	EntitySearchQueries []struct {
+		Query string `json:"query"`
+	} `json:"entitySearchQueries"`
+	ScopeAccounts struct {
+		AccountIDs []int `json:"accountIds"`
+	} `json:"scopeAccounts"`
+}
+
+func (e *exporter) exportWorkloads(ctx context.Context) ([]resource, error) {
+	var resp struct {
+		Actor struct {
+			Account struct {
+				Workload struct {
+					Collections []workload `json:"collections"`
+				} `json:"workload"`
+			} `json:"account"`
+		} `json:"actor"`
+	}
+
+	if err := e.client.NerdGraph.QueryWithResponseAndContext(ctx, listWorkloadsQuery, map[string]interface{}{"accountId": e.accountID}, &resp); err != nil {
+		return nil, err
+	}
+
+	resources := []resource{}
+	for _, w := range resp.Actor.Account.Workload.Collections {
+		label := e.label(workloadResourceType, w.Name)
+
+		h := e.newHCLGen()
+		h.WriteBlock("resource", []string{workloadResourceType, label}, func() {
+			h.WriteIntAttribute("account_id", e.accountID)
+			h.WriteStringAttribute("name", w.Name)
+
+			guids := []string{}
+			for _, entity := range w.Entities {
+				guids = append(guids, entity.GUID)
+			}
+			h.WriteStringSliceAttributeIfNotEmpty("entity_guids", guids)
+
+			for _, q := range w.EntitySearchQueries {
+				h.WriteBlock("entity_search_query", []string{}, func() {
+					h.WriteStringAttribute("query", q.Query)
+				})
+			}
+
+			if len(w.ScopeAccounts.AccountIDs) > 0 {
+				h.WriteIntArrayAttribute("scope_account_ids", w.ScopeAccounts.AccountIDs)
+			}
+		})
+
+		r := resource{
+			Type:  workloadResourceType,
+			Label: label,
+			ID:    fmt.Sprintf("%d:%d:%s", e.accountID, w.ID, w.GUID),
+			HCL:   h.String(),
+		}
+		resources = append(resources, r)
+		e.taggable = append(e.taggable, taggableResource{resource: r, GUID: w.GUID})
+	}
+
+	return resources, nil
+}
+
+// exportTags returns the tags of the exported dashboards and workloads, as
+// newrelic_entity_tags resources referencing them.
+func (e *exporter) exportTags(ctx context.Context) ([]resource, error) {
+	resources := []resource{}
+
+	if len(e.taggable) == 0 {
+		log.Warnf("no tags exported, tags are exported for the dashboards and workloads exported with them")
+	}
+
+	for _, r := range e.taggable {
+		entityTags, err := e.client.Entities.GetTagsForEntityWithContext(ctx, common.EntityGUID(r.GUID))
+		if err != nil {
+			return nil, err
+		}
+
+		tags := []entityTag{}
+		for _, t := range entityTags {
+			if !systemTagKeys[t.Key] {
+				tags = append(tags, entityTag{Key: t.Key, Values: t.Values})
+			}
+		}
+
+		if len(tags) == 0 {
+			continue
+		}
+
+		label := e.label(entityTagsResourceType, r.Label)
+
+		h := e.newHCLGen()
+		h.WriteBlock("resource", []string{entityTagsResourceType, label}, func() {
+			h.WriteReferenceAttribute("guid", r.address()+".guid")
+
+			for _, t := range tags {
+				h.WriteBlock("tag", []string{}, func() {
+					h.WriteStringAttribute("key", t.Key)
+					h.WriteStringSliceAttribute("values", t.Values)
+				})
+			}
+		})
+
+		resources = append(resources, resource{Type: entityTagsResourceType, Label: label, ID: r.GUID, HCL: h.String()})
+	}
+
+	return resources, nil
+}
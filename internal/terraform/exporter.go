@@ -0,0 +1,249 @@
+package terraform
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/newrelic/newrelic-cli/internal/utils/terraform"
+	"github.com/newrelic/newrelic-client-go/v2/newrelic"
+)
+
+// Resource types that can be exported, in the order they are exported.
+// Conditions reference the policies exported before them, and tags the
+// dashboards and workloads.
+const (
+	exportTypeDashboard          = "dashboard"
+	exportTypeAlertPolicy        = "alert_policy"
+	exportTypeNrqlAlertCondition = "nrql_alert_condition"
+	exportTypeSyntheticsMonitor  = "synthetics_monitor"
+	exportTypeWorkload           = "workload"
+	exportTypeTags               = "tags"
+)
+
+var exportTypes = []string{
+	exportTypeDashboard,
+	exportTypeAlertPolicy,
+	exportTypeNrqlAlertCondition,
+	exportTypeSyntheticsMonitor,
+	exportTypeWorkload,
+	exportTypeTags,
+}
+
+const importsFileName = "imports.tf"
+
+// resource is a Terraform resource generated from a New Relic resource.
+type resource struct {
+	// Type is the Terraform resource type, such as newrelic_one_dashboard.
+	Type  string
+	Label string
+	// ID is the ID of the resource used to import it.
+	ID  string
+	HCL string
+}
+
+func (r resource) address() string {
+	return r.Type + "." + r.Label
+}
+
+// taggableResource is an exported resource of an entity, whose tags can be
+// exported as a newrelic_entity_tags resource.
+type taggableResource struct {
+	resource
+	GUID string
+}
+
+// exporter generates the HCL of the resources of an account.
+type exporter struct {
+	client     *newrelic.NewRelic
+	accountID  int
+	shiftWidth int
+
+	labels map[string]bool
+	// policyLabels are the labels of the exported alert policies, by ID.
+	policyLabels map[string]string
+	// taggable are the exported resources whose tags can be exported.
+	taggable []taggableResource
+}
+
+func newExporter(client *newrelic.NewRelic, accountID int, shiftWidth int) *exporter {
+	return &exporter{
+		client:       client,
+		accountID:    accountID,
+		shiftWidth:   shiftWidth,
+		labels:       map[string]bool{},
+		policyLabels: map[string]string{},
+	}
+}
+
+// parseExportTypes returns the types to export, in export order.
+func parseExportTypes(types []string) ([]string, error) {
+	requested := map[string]bool{}
+	for _, t := range types {
+		t = strings.TrimSpace(t)
+		if t == "" {
+			continue
+		}
+
+		found := false
+		for _, known := range exportTypes {
+			found = found || known == t
+		}
+		if !found {
+			return nil, fmt.Errorf("unknown resource type %s, must be one of %s", t, strings.Join(exportTypes, ", "))
+		}
+		requested[t] = true
+	}
+
+	if len(requested) == 0 {
+		return nil, fmt.Errorf("at least one resource type is required, one of %s", strings.Join(exportTypes, ", "))
+	}
+
+	ordered := []string{}
+	for _, t := range exportTypes {
+		if requested[t] {
+			ordered = append(ordered, t)
+		}
+	}
+
+	return ordered, nil
+}
+
+// export returns the resources of each type.
+func (e *exporter) export(ctx context.Context, types []string) (map[string][]resource, error) {
+	exporters := map[string]func(context.Context) ([]resource, error){
+		exportTypeDashboard:          e.exportDashboards,
+		exportTypeAlertPolicy:        e.exportAlertPolicies,
+		exportTypeNrqlAlertCondition: e.exportNrqlAlertConditions,
+		exportTypeSyntheticsMonitor:  e.exportSyntheticsMonitors,
+		exportTypeWorkload:           e.exportWorkloads,
+		exportTypeTags:               e.exportTags,
+	}
+
+	resources := map[string][]resource{}
+	for _, t := range types {
+		log.Infof("exporting %s resources of account %d", t, e.accountID)
+
+		r, err := exporters[t](ctx)
+		if err != nil {
+			return nil, fmt.Errorf("could not export %s resources: %w", t, err)
+		}
+		resources[t] = r
+	}
+
+	return resources, nil
+}
+
+var labelRegex = regexp.MustCompile(`[^a-z0-9]+`)
+
+// label returns a unique snake case label for a resource of the type, derived
+// from its name.
+func (e *exporter) label(resourceType string, name string) string {
+	base := strings.Trim(labelRegex.ReplaceAllString(strings.ToLower(name), "_"), "_")
+	if base == "" {
+		base = strings.TrimPrefix(resourceType, "newrelic_")
+	}
+	if base[0] >= '0' && base[0] <= '9' {
+		base = "r_" + base
+	}
+
+	l := base
+	for i := 2; e.labels[resourceType+"."+l]; i++ {
+		l = fmt.Sprintf("%s_%d", base, i)
+	}
+	e.labels[resourceType+"."+l] = true
+
+	return l
+}
+
+func (e *exporter) newHCLGen() *terraform.HCLGen {
+	return terraform.NewHCLGen(e.shiftWidth)
+}
+
+// generateImports returns the import blocks of the resources.
+func generateImports(resources []resource, shiftWidth int) string {
+	h := terraform.NewHCLGen(shiftWidth)
+	for _, r := range resources {
+		h.WriteImportBlock(r.address(), r.ID)
+	}
+
+	return h.String()
+}
+
+// writeExport writes the resources of each type to <type>.tf in dir, and
+// their import blocks to imports.tf, returning the files written.  Existing
+// files are only replaced when overwrite is set.
+func writeExport(dir string, types []string, resources map[string][]resource, shiftWidth int, overwrite bool) ([]string, error) {
+	files := map[string]string{}
+	all := []resource{}
+
+	for _, t := range types {
+		if len(resources[t]) == 0 {
+			continue
+		}
+
+		hcl := []string{}
+		for _, r := range resources[t] {
+			hcl = append(hcl, strings.TrimPrefix(r.HCL, "\n"))
+			all = append(all, r)
+		}
+		files[t+".tf"] = strings.Join(hcl, "\n")
+	}
+
+	if len(all) == 0 {
+		return []string{}, nil
+	}
+	files[importsFileName] = strings.TrimPrefix(generateImports(all, shiftWidth), "\n")
+
+	names := []string{}
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	paths := []string{}
+	for _, name := range names {
+		p := filepath.Join(dir, name)
+		if _, err := os.Stat(p); err == nil && !overwrite {
+			return nil, fmt.Errorf("%s already exists, use --overwrite to replace it", p)
+		}
+		paths = append(paths, p)
+	}
+
+	if err := os.MkdirAll(dir, 0750); err != nil {
+		return nil, err
+	}
+
+	for i, name := range names {
+		if err := os.WriteFile(paths[i], []byte(files[name]), 0600); err != nil {
+			return nil, err
+		}
+	}
+
+	return paths, nil
+}
+
+// escapeHeredoc escapes the template sequences of a value written in a
+// heredoc, so that it is written as is.
+func escapeHeredoc(value string) string {
+	return strings.NewReplacer("${", "$${", "%{", "%%{").Replace(value)
+}
+
+// entityTag is a tag of an entity.
+type entityTag struct {
+	Key    string   `json:"key"`
+	Values []string `json:"values"`
+}
+
+// systemTagKeys are the tags New Relic adds to every entity.
+var systemTagKeys = map[string]bool{
+	"account":          true,
+	"accountId":        true,
+	"trustedAccountId": true,
+}
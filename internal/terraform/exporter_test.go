@@ -0,0 +1,277 @@
+//go:build unit
+
+package terraform
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/newrelic/newrelic-cli/internal/utils"
+)
+
+func TestParseExportTypesShouldOrderTypes(t *testing.T) {
+	types, err := parseExportTypes([]string{"tags", "nrql_alert_condition", " alert_policy", "dashboard"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"dashboard", "alert_policy", "nrql_alert_condition", "tags"}, types)
+
+	_, err = parseExportTypes([]string{"alert_channel"})
+	assert.Contains(t, err.Error(), "unknown resource type alert_channel")
+
+	_, err = parseExportTypes([]string{})
+	assert.Error(t, err)
+}
+
+func TestExporterLabelShouldBeUniqueSnakeCase(t *testing.T) {
+	server := utils.NewMockNerdGraphServer(nil)
+	defer server.Close()
+	e := newExporter(server.Client(), 12345, 2)
+
+	assert.Equal(t, "checkout_api_latency", e.label(alertPolicyResourceType, "Checkout API: latency!"))
+	assert.Equal(t, "checkout_api_latency_2", e.label(alertPolicyResourceType, "checkout-api latency"))
+	assert.Equal(t, "checkout_api_latency", e.label(nrqlAlertConditionResourceType, "Checkout API latency"))
+	assert.Equal(t, "r_5xx_errors", e.label(nrqlAlertConditionResourceType, "5xx errors"))
+	assert.Equal(t, "workload", e.label(workloadResourceType, "???"))
+}
+
+func TestExportShouldReferenceExportedPolicies(t *testing.T) {
+	server := utils.NewMockNerdGraphServer(map[string]string{
+		"policiesSearch": `{"actor": {"account": {"alerts": {"policiesSearch": {"policies": [
+			{"id": "100", "name": "Checkout", "incidentPreference": "PER_CONDITION"}
+		]}}}}}`,
+		"nrqlConditionsSearch": `{"actor": {"account": {"alerts": {"nrqlConditionsSearch": {"nrqlConditions": [
+			{"id": "200", "name": "Error rate", "type": "STATIC", "enabled": true, "policyId": "100",
+			 "violationTimeLimitSeconds": 86400, "nrql": {"query": "SELECT count(*) FROM TransactionError"},
+			 "signal": {"aggregationWindow": 60, "aggregationMethod": "EVENT_FLOW", "aggregationDelay": 120, "fillOption": "NONE"},
+			 "terms": [{"operator": "ABOVE", "priority": "CRITICAL", "threshold": 5, "thresholdDuration": 300, "thresholdOccurrences": "ALL"}]},
+			{"id": "201", "name": "Throughput", "type": "BASELINE", "enabled": false, "policyId": "999",
+			 "baselineDirection": "LOWER_ONLY", "nrql": {"query": "SELECT rate(count(*), 1 minute) FROM Transaction"}}
+		]}}}}}`,
+	})
+	defer server.Close()
+	e := newExporter(server.Client(), 12345, 2)
+
+	resources, err := e.export(context.Background(), []string{exportTypeAlertPolicy, exportTypeNrqlAlertCondition})
+	require.NoError(t, err)
+
+	require.Len(t, resources[exportTypeAlertPolicy], 1)
+	policy := resources[exportTypeAlertPolicy][0]
+	assert.Equal(t, "100", policy.ID)
+	assert.Equal(t, `
+resource "newrelic_alert_policy" "checkout" {
+  account_id = 12345
+  name = "Checkout"
+  incident_preference = "PER_CONDITION"
+}
+`, policy.HCL)
+
+	require.Len(t, resources[exportTypeNrqlAlertCondition], 2)
+	condition := resources[exportTypeNrqlAlertCondition][0]
+	assert.Equal(t, "100:200:static", condition.ID)
+	assert.Equal(t, `
+resource "newrelic_nrql_alert_condition" "error_rate" {
+  account_id = 12345
+  policy_id = newrelic_alert_policy.checkout.id
+  type = "static"
+  name = "Error rate"
+  enabled = true
+  violation_time_limit_seconds = 86400
+
+  nrql {
+    query = "SELECT count(*) FROM TransactionError"
+  }
+
+  critical {
+    operator = "above"
+    threshold = 5
+    threshold_duration = 300
+    threshold_occurrences = "all"
+  }
+  aggregation_window = 60
+  aggregation_method = "event_flow"
+  aggregation_delay = 120
+  fill_option = "none"
+}
+`, condition.HCL)
+
+	baseline := resources[exportTypeNrqlAlertCondition][1]
+	assert.Equal(t, "999:201:baseline", baseline.ID)
+	assert.Contains(t, baseline.HCL, "policy_id = 999\n")
+	assert.Contains(t, baseline.HCL, `baseline_direction = "lower_only"`)
+}
+
+func TestExportShouldSkipDashboardsWithInvalidWidgets(t *testing.T) {
+	server := utils.NewMockNerdGraphServer(map[string]string{
+		"entitySearch": `{"actor": {"entitySearch": {"results": {"entities": [
+			{"__typename": "DashboardEntityOutline", "guid": "DASHBOARD-1", "name": "Checkout"},
+			{"__typename": "DashboardEntityOutline", "guid": "DASHBOARD-2", "name": "Orders"}
+		]}}}}`,
+	})
+	server.AddResponses("entity(guid",
+		`{"actor": {"entity": {"guid": "DASHBOARD-1", "name": "Checkout", "permissions": "PRIVATE",
+			"pages": [{"guid": "PAGE-1", "name": "Overview", "widgets": [{
+				"title": "Latency", "layout": {"column": 1, "row": 1, "width": 4, "height": 3},
+				"visualization": {"id": "viz.line"}, "rawConfiguration": {"thresholds": "high"}
+			}]}]}}}`,
+		`{"actor": {"entity": {"guid": "DASHBOARD-2", "name": "Orders", "permissions": "PRIVATE",
+			"pages": [{"guid": "PAGE-2", "name": "Overview", "widgets": [{
+				"title": "Notes", "layout": {"column": 1, "row": 1, "width": 4, "height": 3},
+				"visualization": {"id": "viz.markdown"}, "rawConfiguration": {"text": "Orders"}
+			}]}]}}}`,
+	)
+	defer server.Close()
+	e := newExporter(server.Client(), 12345, 2)
+
+	resources, err := e.export(context.Background(), []string{exportTypeDashboard})
+	require.NoError(t, err)
+
+	require.Len(t, resources[exportTypeDashboard], 1)
+	assert.Equal(t, "DASHBOARD-2", resources[exportTypeDashboard][0].ID)
+	assert.Contains(t, resources[exportTypeDashboard][0].HCL, `name = "Orders"`)
+}
+
+func TestExportShouldWriteMonitorsByType(t *testing.T) {
+	server := utils.NewMockNerdGraphServer(map[string]string{
+		"entitySearch": `{"actor": {"entitySearch": {"results": {"entities": [
+			{"guid": "MON-1", "name": "Home page", "monitorType": "SIMPLE", "period": 5, "monitoredUrl": "https://example.com",
+			 "monitorSummary": {"status": "ENABLED"},
+			 "tags": [{"key": "publicLocation", "values": ["AWS_US_EAST_1", "AWS_EU_WEST_1"]}, {"key": "team", "values": ["web"]}, {"key": "accountId", "values": ["12345"]}]},
+			{"guid": "MON-2", "name": "Checkout", "monitorType": "SCRIPT_API", "period": 15,
+			 "monitorSummary": {"status": "DISABLED"},
+			 "tags": [{"key": "privateLocation", "values": ["LOC-1"]}, {"key": "runtimeType", "values": ["NODE_API"]}, {"key": "runtimeTypeVersion", "values": ["16.10"]}]},
+			{"guid": "MON-3", "name": "Ping", "monitorType": "PING", "period": 5}
+		]}}}}`,
+		"script(": `{"actor": {"account": {"synthetics": {"script": {"text": "const url = ` + "`${$env.URL}`" + `;"}}}}}`,
+	})
+	defer server.Close()
+	e := newExporter(server.Client(), 12345, 2)
+
+	resources, err := e.export(context.Background(), []string{exportTypeSyntheticsMonitor})
+	require.NoError(t, err)
+	require.Len(t, resources[exportTypeSyntheticsMonitor], 2)
+
+	simple := resources[exportTypeSyntheticsMonitor][0]
+	assert.Equal(t, "newrelic_synthetics_monitor.home_page", simple.address())
+	assert.Equal(t, "MON-1", simple.ID)
+	assert.Equal(t, `
+resource "newrelic_synthetics_monitor" "home_page" {
+  account_id = 12345
+  name = "Home page"
+  period = "EVERY_5_MINUTES"
+  status = "ENABLED"
+  type = "SIMPLE"
+  uri = "https://example.com"
+  locations_public = ["AWS_US_EAST_1","AWS_EU_WEST_1"]
+
+  tag {
+    key = "team"
+    values = ["web"]
+  }
+}
+`, simple.HCL)
+
+	script := resources[exportTypeSyntheticsMonitor][1]
+	assert.Equal(t, "newrelic_synthetics_script_monitor.checkout", script.address())
+	assert.Contains(t, script.HCL, "script = <<EOT\nconst url = `$${$env.URL}`;\nEOT\n")
+	assert.Contains(t, script.HCL, "  location_private {\n    guid = \"LOC-1\"\n  }\n")
+	assert.Contains(t, script.HCL, `status = "DISABLED"`)
+	assert.Contains(t, script.HCL, `runtime_type_version = "16.10"`)
+}
+
+func TestExportShouldWriteWorkloadsAndTheirTags(t *testing.T) {
+	server := utils.NewMockNerdGraphServer(map[string]string{
+		"collections": `{"actor": {"account": {"workload": {"collections": [
+			{"id": 42, "guid": "WL-1", "name": "Checkout", "entities": [{"guid": "APP-1"}],
+			 "entitySearchQueries": [{"query": "name LIKE 'checkout'"}], "scopeAccounts": {"accountIds": [12345, 67890]}}
+		]}}}}`,
+		"entity(guid": `{"actor": {"entity": {"tags": [
+			{"key": "accountId", "values": ["12345"]},
+			{"key": "team", "values": ["checkout", "payments"]},
+			{"key": "owner", "values": ["\"ops\" C:\\teams", "${var.owner}"]}
+		]}}}`,
+	})
+	defer server.Close()
+	e := newExporter(server.Client(), 12345, 2)
+
+	resources, err := e.export(context.Background(), []string{exportTypeWorkload, exportTypeTags})
+	require.NoError(t, err)
+
+	require.Len(t, resources[exportTypeWorkload], 1)
+	wl := resources[exportTypeWorkload][0]
+	assert.Equal(t, "12345:42:WL-1", wl.ID)
+	assert.Equal(t, `
+resource "newrelic_workload" "checkout" {
+  account_id = 12345
+  name = "Checkout"
+  entity_guids = ["APP-1"]
+
+  entity_search_query {
+    query = "name LIKE 'checkout'"
+  }
+  scope_account_ids = [12345, 67890]
+}
+`, wl.HCL)
+
+	require.Len(t, resources[exportTypeTags], 1)
+	tags := resources[exportTypeTags][0]
+	assert.Equal(t, "WL-1", tags.ID)
+	assert.Equal(t, `
+resource "newrelic_entity_tags" "checkout" {
+  guid = newrelic_workload.checkout.guid
+
+  tag {
+    key = "team"
+    values = ["checkout","payments"]
+  }
+
+  tag {
+    key = "owner"
+    values = ["\"ops\" C:\\teams","$${var.owner}"]
+  }
+}
+`, tags.HCL)
+}
+
+func TestWriteExportShouldWriteResourcesAndImports(t *testing.T) {
+	dir := t.TempDir()
+	resources := map[string][]resource{
+		exportTypeAlertPolicy: {
+			{Type: alertPolicyResourceType, Label: "checkout", ID: "100", HCL: "\nresource \"newrelic_alert_policy\" \"checkout\" {\n}\n"},
+		},
+		exportTypeNrqlAlertCondition: {
+			{Type: nrqlAlertConditionResourceType, Label: "error_rate", ID: "100:200:static", HCL: "\nresource \"newrelic_nrql_alert_condition\" \"error_rate\" {\n}\n"},
+		},
+	}
+	types := []string{exportTypeAlertPolicy, exportTypeNrqlAlertCondition}
+
+	paths, err := writeExport(dir, types, resources, 2, false)
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		filepath.Join(dir, "alert_policy.tf"),
+		filepath.Join(dir, "imports.tf"),
+		filepath.Join(dir, "nrql_alert_condition.tf"),
+	}, paths)
+
+	imports, err := os.ReadFile(filepath.Join(dir, importsFileName))
+	require.NoError(t, err)
+	assert.Equal(t, `import {
+  to = newrelic_alert_policy.checkout
+  id = "100"
+}
+
+import {
+  to = newrelic_nrql_alert_condition.error_rate
+  id = "100:200:static"
+}
+`, string(imports))
+
+	_, err = writeExport(dir, types, resources, 2, false)
+	assert.Contains(t, err.Error(), "already exists, use --overwrite to replace it")
+
+	_, err = writeExport(dir, types, resources, 2, true)
+	assert.NoError(t, err)
+}
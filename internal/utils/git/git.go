@@ -0,0 +1,236 @@
+package git
+
+import (
+	"bytes"
+	"fmt"
+	"net/url"
+	"os/exec"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// maxChangelogCommits caps the number of commits listed in a generated changelog.
+const maxChangelogCommits = 50
+
+// Commit is a single commit in a repository's history.
+type Commit struct {
+	SHA     string
+	Subject string
+	Author  string
+}
+
+// Info holds metadata about the HEAD commit of a local git repository and the
+// commits made since a previous reference point, such as the last deployment.
+type Info struct {
+	Commit      string
+	ShortCommit string
+	Tag         string
+	Branch      string
+	Author      string
+	AuthorEmail string
+	Subject     string
+	RemoteURL   string
+	WebURL      string
+	Since       string
+	Commits     []Commit
+}
+
+// Read gathers metadata about the repository in dir.  The commit range starts
+// at since when it is provided; otherwise it starts at the closest tag before
+// HEAD.  When neither is available only the HEAD commit is included.
+func Read(dir string, since string) (*Info, error) {
+	head, err := run(dir, "log", "-1", "--format=%H%x1f%h%x1f%an%x1f%ae%x1f%s")
+	if err != nil {
+		return nil, fmt.Errorf("could not read the HEAD commit: %s", err)
+	}
+
+	fields := strings.Split(head, "\x1f")
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("unexpected git log output: %s", head)
+	}
+
+	info := &Info{
+		Commit:      fields[0],
+		ShortCommit: fields[1],
+		Author:      fields[2],
+		AuthorEmail: fields[3],
+		Subject:     fields[4],
+	}
+
+	info.Tag, _ = run(dir, "describe", "--tags", "--exact-match", "HEAD")
+	info.Branch, _ = run(dir, "rev-parse", "--abbrev-ref", "HEAD")
+	info.RemoteURL, _ = run(dir, "remote", "get-url", "origin")
+	info.WebURL = WebURL(info.RemoteURL)
+
+	if since == "" {
+		since, _ = run(dir, "describe", "--tags", "--abbrev=0", "HEAD^")
+	} else if _, err = run(dir, "rev-parse", "--verify", "--quiet", since+"^{commit}"); err != nil {
+		return nil, fmt.Errorf("could not resolve %s: %s", since, err)
+	}
+	info.Since = since
+
+	logRange := []string{"-1", "HEAD"}
+	if since != "" {
+		logRange = []string{since + "..HEAD"}
+	}
+
+	out, err := run(dir, append([]string{"log", "--format=%H%x1f%s%x1f%an"}, logRange...)...)
+	if err != nil {
+		return nil, fmt.Errorf("could not read the commit history: %s", err)
+	}
+
+	for _, line := range strings.Split(out, "\n") {
+		parts := strings.Split(line, "\x1f")
+		if len(parts) != 3 {
+			continue
+		}
+
+		info.Commits = append(info.Commits, Commit{SHA: parts[0], Subject: parts[1], Author: parts[2]})
+	}
+
+	return info, nil
+}
+
+// HasCommit reports whether ref resolves to a commit in the repository in dir.
+func HasCommit(dir string, ref string) bool {
+	if ref == "" {
+		return false
+	}
+
+	_, err := run(dir, "rev-parse", "--verify", "--quiet", ref+"^{commit}")
+	return err == nil
+}
+
+// MarkerLookup returns the revisions recorded with the markers of previous
+// deployments, such as APM deployment markers or change tracking events, most
+// recent first.
+type MarkerLookup func() ([]string, error)
+
+// PreviousMarkerRevision returns the revision of the most recent marker found by
+// lookup when it refers to a commit in the repository in dir.  Otherwise, or if
+// the markers cannot be looked up, it returns "" so that Read falls back to the
+// previous tag.
+func PreviousMarkerRevision(dir string, lookup MarkerLookup) string {
+	revisions, err := lookup()
+	if err != nil {
+		log.Debugf("could not look up previous markers: %s", err)
+		return ""
+	}
+
+	if len(revisions) == 0 || !HasCommit(dir, revisions[0]) {
+		return ""
+	}
+
+	return revisions[0]
+}
+
+// Version returns the tag pointing at HEAD, falling back to the short commit SHA.
+func (i *Info) Version() string {
+	if i.Tag != "" {
+		return i.Tag
+	}
+
+	return i.ShortCommit
+}
+
+// Changelog returns a plain text changelog listing the commits in the range,
+// one per line.
+func (i *Info) Changelog() string {
+	lines := []string{}
+
+	for n, c := range i.Commits {
+		if n == maxChangelogCommits {
+			lines = append(lines, fmt.Sprintf("... and %d more", len(i.Commits)-n))
+			break
+		}
+
+		sha := c.SHA
+		if len(sha) > 7 {
+			sha = sha[:7]
+		}
+
+		lines = append(lines, fmt.Sprintf("- %s %s (%s)", sha, c.Subject, c.Author))
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// DeepLink returns a URL to view the change on the repository host.  A compare
+// URL is returned when a previous reference point is known, otherwise a link to
+// the HEAD commit.  An empty string is returned when the remote is not a web host.
+func (i *Info) DeepLink() string {
+	if i.WebURL == "" {
+		return ""
+	}
+
+	sep := "/"
+	if strings.Contains(i.WebURL, "gitlab") {
+		sep = "/-/"
+	}
+
+	if i.Since != "" {
+		return fmt.Sprintf("%s%scompare/%s...%s", i.WebURL, sep, i.Since, i.Commit)
+	}
+
+	return fmt.Sprintf("%s%scommit/%s", i.WebURL, sep, i.Commit)
+}
+
+// WebURL converts a git remote URL into the https URL of the repository,
+// removing any credentials and the trailing .git suffix.  Both scp-like
+// (git@host:org/repo.git) and URL remotes are supported.
+func WebURL(remote string) string {
+	remote = strings.TrimSpace(remote)
+	if remote == "" {
+		return ""
+	}
+
+	var host, path string
+
+	if !strings.Contains(remote, "://") {
+		// scp-like syntax: [user@]host:path
+		at := strings.LastIndex(remote, "@")
+		colon := strings.Index(remote, ":")
+		if colon < 0 || colon < at {
+			return ""
+		}
+
+		host = remote[at+1 : colon]
+		path = remote[colon+1:]
+	} else {
+		u, err := url.Parse(remote)
+		if err != nil || u.Hostname() == "" || u.Scheme == "file" {
+			return ""
+		}
+
+		host = u.Hostname()
+		if u.Scheme == "http" || u.Scheme == "https" {
+			host = u.Host
+		}
+		path = u.Path
+	}
+
+	path = strings.TrimSuffix(strings.Trim(path, "/"), ".git")
+	if host == "" || path == "" {
+		return ""
+	}
+
+	return fmt.Sprintf("https://%s/%s", host, path)
+}
+
+func run(dir string, args ...string) (string, error) {
+	var stdout, stderr bytes.Buffer
+
+	cmd := exec.Command("git", append([]string{"-C", dir}, args...)...)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return "", fmt.Errorf("%s", msg)
+		}
+		return "", err
+	}
+
+	return strings.TrimSpace(stdout.String()), nil
+}
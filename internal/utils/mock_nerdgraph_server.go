@@ -0,0 +1,117 @@
+package utils
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/newrelic/newrelic-client-go/v2/newrelic"
+)
+
+// MockNerdGraphServer is a NerdGraph server for testing the typed clients.  It
+// answers each query with the responses of the first key, in sorted order,
+// found in the query.  The responses of a key are returned in turn, the last
+// one being repeated.  Queries without a response get empty data.
+type MockNerdGraphServer struct {
+	*httptest.Server
+
+	// Requests are the queries received, in order.
+	Requests []MockNerdGraphRequest
+
+	mu        sync.Mutex
+	responses map[string][]string
+}
+
+type MockNerdGraphRequest struct {
+	Query     string                 `json:"query"`
+	Variables map[string]interface{} `json:"variables"`
+}
+
+func NewMockNerdGraphServer(responses map[string]string) *MockNerdGraphServer {
+	s := &MockNerdGraphServer{responses: map[string][]string{}}
+	for key, resp := range responses {
+		s.responses[key] = []string{resp}
+	}
+
+	s.Server = httptest.NewServer(http.HandlerFunc(s.handle))
+
+	return s
+}
+
+// AddResponses adds responses returned in turn to the queries containing key.
+func (s *MockNerdGraphServer) AddResponses(key string, responses ...string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.responses[key] = append(s.responses[key], responses...)
+}
+
+// Client returns a New Relic client sending its NerdGraph requests to the server.
+func (s *MockNerdGraphServer) Client() *newrelic.NewRelic {
+	c, err := newrelic.New(
+		newrelic.ConfigPersonalAPIKey("NRAK-MOCK"),
+		newrelic.ConfigNerdGraphBaseURL(s.URL),
+	)
+	if err != nil {
+		panic(err)
+	}
+
+	return c
+}
+
+// Mutations returns the requests whose query is a mutation.
+func (s *MockNerdGraphServer) Mutations() []MockNerdGraphRequest {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	mutations := []MockNerdGraphRequest{}
+	for _, r := range s.Requests {
+		if strings.HasPrefix(strings.TrimSpace(r.Query), "mutation") {
+			mutations = append(mutations, r)
+		}
+	}
+
+	return mutations
+}
+
+func (s *MockNerdGraphServer) handle(w http.ResponseWriter, r *http.Request) {
+	var req MockNerdGraphRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	s.mu.Lock()
+	s.Requests = append(s.Requests, req)
+	data := s.response(req.Query)
+	s.mu.Unlock()
+
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write([]byte(`{"data": ` + data + `}`))
+}
+
+func (s *MockNerdGraphServer) response(query string) string {
+	keys := []string{}
+	for key := range s.responses {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		responses := s.responses[key]
+		if !strings.Contains(query, key) || len(responses) == 0 {
+			continue
+		}
+
+		if len(responses) > 1 {
+			s.responses[key] = responses[1:]
+		}
+
+		return responses[0]
+	}
+
+	return "{}"
+}
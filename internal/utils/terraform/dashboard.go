@@ -67,6 +67,7 @@ type DashboardWidgetRawConfiguration struct {
 	Legend            DashboardWidgetLegend          `json:"legend,omitempty"`
 	Threshold         json.RawMessage                `json:"thresholds,omitempty"`
 	YAxisLeft         DashboardWidgetYAxisLeft       `json:"yAxisLeft,omitempty"`
+	YAxisRight        *DashboardWidgetYAxisRight     `json:"yAxisRight,omitempty"`
 	NullValues        DashboardWidgetNullValues      `json:"nullValues,omitempty"`
 	Units             DashboardWidgetUnits           `json:"units,omitempty"`
 	Colors            DashboardWidgetColors          `json:"colors,omitempty"`
@@ -109,6 +110,13 @@ type DashboardWidgetLineThreshold struct {
 	Threshold      []DashboardWidgetThreshold `json:"thresholds,omitempty"`
 }
 
+type DashboardWidgetTableThreshold struct {
+	ColumnName string  `json:"columnName,omitempty"`
+	From       float64 `json:"from,omitempty"`
+	Severity   string  `json:"severity,omitempty"`
+	To         float64 `json:"to,omitempty"`
+}
+
 type DashboardWidgetBillBoardThreshold struct {
 	AlertSeverity string  `json:"alertSeverity,omitempty"`
 	Value         float64 `json:"value,omitempty"`
@@ -120,6 +128,17 @@ type DashboardWidgetYAxisLeft struct {
 	Zero bool    `json:"zero,omitempty"`
 }
 
+type DashboardWidgetYAxisRight struct {
+	Max    float64                           `json:"max,omitempty"`
+	Min    float64                           `json:"min,omitempty"`
+	Zero   bool                              `json:"zero,omitempty"`
+	Series []DashboardWidgetYAxisRightSeries `json:"series,omitempty"`
+}
+
+type DashboardWidgetYAxisRightSeries struct {
+	Name string `json:"name"`
+}
+
 type DashboardWidgetNullValues struct {
 	NullValue       string                              `json:"nullValue,omitempty"`
 	SeriesOverrides []DashboardWidgetNullValueOverrides `json:"seriesOverrides,omitempty"`
@@ -162,36 +181,110 @@ type DashboardWidgetInitialSorting struct {
 	Name      string `json:"name"`
 }
 
+// dashboardReferences are the fields of a dashboard JSON export that
+// dashboards.DashboardInput does not read.  Exports of the dashboard entity
+// have GUIDs, which identify facet links to the dashboard itself.
+type dashboardReferences struct {
+	GUID  string `json:"guid"`
+	Pages []struct {
+		GUID    string `json:"guid"`
+		Widgets []struct {
+			LinkedEntityGUIDs []string `json:"linkedEntityGuids"`
+			LinkedEntities    []struct {
+				GUID string `json:"guid"`
+			} `json:"linkedEntities"`
+		} `json:"widgets"`
+	} `json:"pages"`
+	Variables []struct {
+		Options *DashboardVariableOptions `json:"options"`
+	} `json:"variables"`
+}
+
+type DashboardVariableOptions struct {
+	IgnoreTimeRange bool `json:"ignoreTimeRange"`
+	Excluded        bool `json:"excluded"`
+	ShowApplyAction bool `json:"showApplyAction"`
+}
+
 func GenerateDashboardHCL(resourceLabel string, shiftWidth int, input []byte) (string, error) {
 	var d dashboards.DashboardInput
 	if err := json.Unmarshal(input, &d); err != nil {
-		log.Fatal(err)
+		return "", fmt.Errorf("failed unmarshaling dashboard: %w", err)
+	}
+
+	var refs dashboardReferences
+	if err := json.Unmarshal(input, &refs); err != nil {
+		return "", fmt.Errorf("failed unmarshaling dashboard: %w", err)
 	}
 
+	// Widgets linked to the dashboard or one of its pages filter the
+	// current dashboard by their facets.
+	ownGUIDs := map[string]bool{}
+	if refs.GUID != "" {
+		ownGUIDs[refs.GUID] = true
+	}
+	for _, p := range refs.Pages {
+		if p.GUID != "" {
+			ownGUIDs[p.GUID] = true
+		}
+	}
+
+	// The first error found writing a widget stops the generation, as the
+	// blocks are written in nested functions.
+	var widgetErr error
+
 	h := NewHCLGen(shiftWidth)
 	h.WriteBlock("resource", []string{dashboardResourceName, resourceLabel}, func() {
 		h.WriteStringAttribute("name", d.Name)
 		h.WriteStringAttributeIfNotEmpty("description", d.Description)
 		h.WriteStringAttributeIfNotEmpty("permissions", strings.ToLower(string(d.Permissions)))
 
-		for _, p := range d.Pages {
+		for i, p := range d.Pages {
 			h.WriteBlock("page", []string{}, func() {
 				h.WriteStringAttribute("name", p.Name)
 				h.WriteStringAttributeIfNotEmpty("description", p.Description)
 
-				for _, w := range p.Widgets {
-					requireValidVisualizationID(w.Visualization.ID)
+				for j, w := range p.Widgets {
+					if widgetErr != nil {
+						return
+					}
+
+					config, err := unmarshalDashboardWidgetRawConfiguration(w.Title, w.Visualization.ID, w.RawConfiguration)
+					if err != nil {
+						widgetErr = err
+						return
+					}
+					widgetType := resolveWidgetType(w.Title, w.Visualization.ID, config)
+
+					linkedEntityGUIDs := config.LinkedEntityGUIDs
+					if len(linkedEntityGUIDs) == 0 && i < len(refs.Pages) && j < len(refs.Pages[i].Widgets) {
+						linkedEntityGUIDs = refs.Pages[i].Widgets[j].LinkedEntityGUIDs
+						for _, e := range refs.Pages[i].Widgets[j].LinkedEntities {
+							linkedEntityGUIDs = append(linkedEntityGUIDs, e.GUID)
+						}
+					}
 
-					h.WriteBlock(widgetTypes[w.Visualization.ID], []string{}, func() {
+					filterCurrentDashboard := false
+					otherGUIDs := []string{}
+					for _, guid := range linkedEntityGUIDs {
+						if ownGUIDs[guid] {
+							filterCurrentDashboard = true
+							continue
+						}
+						otherGUIDs = append(otherGUIDs, guid)
+					}
+
+					h.WriteBlock(widgetType, []string{}, func() {
 						h.WriteStringAttribute("title", w.Title)
 						h.WriteIntAttribute("row", w.Layout.Row)
 						h.WriteIntAttribute("column", w.Layout.Column)
 						h.WriteIntAttribute("height", w.Layout.Height)
 						h.WriteIntAttribute("width", w.Layout.Width)
 
-						config := unmarshalDashboardWidgetRawConfiguration(w.Title, widgetTypes[w.Visualization.ID], w.RawConfiguration)
-
-						h.WriteStringSliceAttributeIfNotEmpty("linked_entity_guids", config.LinkedEntityGUIDs)
+						if filterCurrentDashboard {
+							h.WriteBooleanAttribute("filter_current_dashboard", true)
+						}
+						h.WriteStringSliceAttributeIfNotEmpty("linked_entity_guids", otherGUIDs)
 						h.WriteMultilineStringAttributeIfNotEmpty("text", config.Text)
 
 						for _, q := range config.NRQLQueries {
@@ -203,57 +296,35 @@ func GenerateDashboardHCL(resourceLabel string, shiftWidth int, input []byte) (s
 						h.WriteBooleanAttribute("facet_show_other_series", config.Facet.ShowOtherSeries)
 						h.WriteBooleanAttribute("legend_enabled", config.Legend.Enabled)
 						h.WriteBooleanAttribute("ignore_time_range", config.PlatformOptions.IgnoreTimeRange)
-						h.WriteFloatAttribute("y_axis_left_min", config.YAxisLeft.Min)
-						h.WriteFloatAttribute("y_axis_left_max", config.YAxisLeft.Max)
+						if config.YAxisLeft.Min != 0 || config.YAxisLeft.Max != 0 {
+							h.WriteFloatAttribute("y_axis_left_min", config.YAxisLeft.Min)
+							h.WriteFloatAttribute("y_axis_left_max", config.YAxisLeft.Max)
+						}
 						writeInterfaceValues(h, "refresh_rate", config.RefreshRate.Frequency) // function to handle different types of refresh rates which cant be handled through struct
 
-						if w.Visualization.ID == "viz.line" {
-							writeLineWidgetAttributes(h, config)
-						}
-						if w.Visualization.ID == "viz.billboard" {
-							writeBillboardWidgetAttributes(h, config)
-						}
-						if w.Visualization.ID == "viz.table" {
-							writeTableWidgetAttributes(h, config)
-						}
-						if w.Visualization.ID == "viz.bullet" {
+						var err error
+						switch widgetType {
+						case "widget_line":
+							err = writeLineWidgetAttributes(h, config)
+						case "widget_billboard":
+							err = writeBillboardWidgetAttributes(h, config)
+						case "widget_table":
+							err = writeTableWidgetAttributes(h, config)
+						case "widget_bullet":
 							h.WriteFloatAttribute("limit", config.Limit)
 						}
+						if err != nil {
+							widgetErr = fmt.Errorf("failed writing widget \"%s\" of type \"%s\": %w", w.Title, w.Visualization.ID, err)
+							return
+						}
 
-						h.WriteBlock("null_values", []string{}, func() {
-							h.WriteStringAttribute("null_value", config.NullValues.NullValue)
-							for _, so := range config.NullValues.SeriesOverrides {
-								h.WriteBlock("series_overrides", []string{}, func() {
-									h.WriteStringAttribute("null_value", so.NullValue)
-									h.WriteStringAttribute("series_name", so.SeriesName)
-								})
-							}
-						})
-						h.WriteBlock("units", []string{}, func() {
-							h.WriteStringAttribute("unit", config.Units.Unit)
-							for _, so := range config.Units.SeriesOverrides {
-								h.WriteBlock("series_overrides", []string{}, func() {
-									h.WriteStringAttribute("unit", so.Unit)
-									h.WriteStringAttribute("series_name", so.SeriesName)
-								})
-							}
-						})
-						h.WriteBlock("colors", []string{}, func() {
-							h.WriteStringAttribute("color", config.Colors.Color)
-							for _, so := range config.Colors.SeriesOverrides {
-								h.WriteBlock("series_overrides", []string{}, func() {
-									h.WriteStringAttribute("color", so.Color)
-									h.WriteStringAttribute("series_name", so.SeriesName)
-								})
-							}
-						})
-
+						writeSeriesAttributes(h, config)
 					})
 				}
 			})
 		}
 
-		for _, v := range d.Variables {
+		for i, v := range d.Variables {
 			h.WriteBlock("variable", []string{}, func() {
 				h.WriteStringAttribute("name", v.Name)
 				h.WriteStringAttributeIfNotEmpty("title", v.Title)
@@ -289,10 +360,22 @@ func GenerateDashboardHCL(resourceLabel string, shiftWidth int, input []byte) (s
 
 				h.WriteStringAttributeIfNotEmpty("replacement_strategy", strings.ToLower(string(v.ReplacementStrategy)))
 
+				if i < len(refs.Variables) && refs.Variables[i].Options != nil {
+					options := refs.Variables[i].Options
+					h.WriteBlock("options", []string{}, func() {
+						h.WriteBooleanAttribute("ignore_time_range", options.IgnoreTimeRange)
+						h.WriteBooleanAttribute("excluded", options.Excluded)
+						h.WriteBooleanAttribute("show_apply_action", options.ShowApplyAction)
+					})
+				}
 			})
 		}
 	})
 
+	if widgetErr != nil {
+		return "", widgetErr
+	}
+
 	return h.String(), nil
 }
 
@@ -327,31 +410,61 @@ func writeAccountID(h *HCLGen, q DashboardWidgetNRQLQuery) {
 	h.WriteString(fmt.Sprintf("%saccount_id = jsonencode(%s)\n", h.i, arrayString))
 }
 
-func unmarshalDashboardWidgetRawConfiguration(title string, widgetType string, b []byte) *DashboardWidgetRawConfiguration {
+func unmarshalDashboardWidgetRawConfiguration(title string, visualizationID string, b []byte) (*DashboardWidgetRawConfiguration, error) {
 	var c DashboardWidgetRawConfiguration
-	err := json.Unmarshal(b, &c)
-	if err != nil {
-		log.Fatalf("failed unmarshaling rawConfiguration for widget \"%s\" of type \"%s\"", title, widgetType)
-		panic(err)
+	if err := json.Unmarshal(b, &c); err != nil {
+		return nil, fmt.Errorf("failed unmarshaling rawConfiguration for widget \"%s\" of type \"%s\": %w", title, visualizationID, err)
 	}
 
-	return &c
+	return &c, nil
 }
 
-func requireValidVisualizationID(id string) {
-	if widgetTypes[id] == "" {
-		log.Fatalf("unrecognized widget type \"%s\"", id)
+// resolveWidgetType returns the widget block of a visualization.  Widgets of
+// visualizations without a block, such as custom visualizations, are written
+// as JSON widgets of their queries, or as markdown widgets when they have no
+// queries.
+func resolveWidgetType(title string, visualizationID string, config *DashboardWidgetRawConfiguration) string {
+	if widgetType, ok := widgetTypes[visualizationID]; ok {
+		return widgetType
 	}
+
+	if len(config.NRQLQueries) > 0 {
+		log.Warnf("widget \"%s\" has unsupported visualization \"%s\", writing it as widget_json", title, visualizationID)
+		return "widget_json"
+	}
+
+	log.Warnf("widget \"%s\" has unsupported visualization \"%s\" and no queries, writing it as widget_markdown", title, visualizationID)
+	if config.Text == "" {
+		config.Text = fmt.Sprintf("Visualization %s is not supported.", visualizationID)
+	}
+
+	return "widget_markdown"
 }
 
-func writeLineWidgetAttributes(h *HCLGen, config *DashboardWidgetRawConfiguration) {
+func writeLineWidgetAttributes(h *HCLGen, config *DashboardWidgetRawConfiguration) error {
 	h.WriteBooleanAttribute("y_axis_left_zero", config.YAxisLeft.Zero)
 
+	if r := config.YAxisRight; r != nil {
+		h.WriteBlock("y_axis_right", []string{}, func() {
+			h.WriteBooleanAttribute("y_axis_right_zero", r.Zero)
+			if r.Min != 0 || r.Max != 0 {
+				h.WriteFloatAttribute("y_axis_right_min", r.Min)
+				h.WriteFloatAttribute("y_axis_right_max", r.Max)
+			}
+
+			series := []string{}
+			for _, s := range r.Series {
+				series = append(series, s.Name)
+			}
+			h.WriteStringSliceAttributeIfNotEmpty("y_axis_right_series", series)
+		})
+	}
+
 	// Only process thresholds if they exist in the configuration
 	if len(config.Threshold) > 0 {
 		var widgetLineThreshold DashboardWidgetLineThreshold
 		if err := json.Unmarshal(config.Threshold, &widgetLineThreshold); err != nil {
-			log.Fatal("Error unmarshalling widgetLineThreshold:", err)
+			return fmt.Errorf("failed unmarshaling line thresholds: %w", err)
 		}
 
 		h.WriteBooleanAttribute("is_label_visible", widgetLineThreshold.IsLabelVisible)
@@ -365,28 +478,28 @@ func writeLineWidgetAttributes(h *HCLGen, config *DashboardWidgetRawConfiguratio
 			})
 		}
 	}
+
+	return nil
 }
 
-func writeBillboardWidgetAttributes(h *HCLGen, config *DashboardWidgetRawConfiguration) {
+func writeBillboardWidgetAttributes(h *HCLGen, config *DashboardWidgetRawConfiguration) error {
 	// Only process thresholds if they exist in the configuration
 	if len(config.Threshold) > 0 {
 		var billboardThreshold []DashboardWidgetBillBoardThreshold
 		if err := json.Unmarshal(config.Threshold, &billboardThreshold); err != nil {
-			log.Fatal("Error unmarshalling billboardThreshold:", err)
+			return fmt.Errorf("failed unmarshaling billboard thresholds: %w", err)
 		}
 		for _, q := range billboardThreshold {
-			h.WriteFloatAttribute(ThresholdSeverityValues[q.AlertSeverity], q.Value)
+			severity, ok := ThresholdSeverityValues[q.AlertSeverity]
+			if !ok {
+				log.Warnf("skipping billboard threshold of unsupported severity \"%s\"", q.AlertSeverity)
+				continue
+			}
+			h.WriteFloatAttribute(severity, q.Value)
 		}
 	}
 
-	for _, q := range config.DataFormatters {
-		h.WriteBlock("data_format", []string{}, func() {
-			h.WriteStringAttribute("name", q.Name)
-			h.WriteStringAttribute("type", q.Type)
-			h.WriteStringAttribute("format", q.Format)
-			writeInterfaceValues(h, "precision", q.Precision) // function to handle different types of precision
-		})
-	}
+	writeDataFormatters(h, config)
 
 	if config.BillboardSettings != (DashboardBillboardSettings{}) {
 		h.WriteBlock("billboard_settings", []string{}, func() {
@@ -414,31 +527,94 @@ func writeBillboardWidgetAttributes(h *HCLGen, config *DashboardWidgetRawConfigu
 			}
 		})
 	}
+	return nil
 }
 
-func writeTableWidgetAttributes(h *HCLGen, config *DashboardWidgetRawConfiguration) {
-	h.WriteBlock("initial_sorting", []string{}, func() {
-		h.WriteStringAttribute("name", config.InitialSorting.Name)
-		h.WriteStringAttribute("direction", config.InitialSorting.Direction)
-	})
+func writeTableWidgetAttributes(h *HCLGen, config *DashboardWidgetRawConfiguration) error {
+	if config.InitialSorting.Name != "" {
+		h.WriteBlock("initial_sorting", []string{}, func() {
+			h.WriteStringAttribute("name", config.InitialSorting.Name)
+			h.WriteStringAttribute("direction", config.InitialSorting.Direction)
+		})
+	}
 
+	// Only process thresholds if they exist in the configuration
+	if len(config.Threshold) > 0 {
+		var tableThreshold []DashboardWidgetTableThreshold
+		if err := json.Unmarshal(config.Threshold, &tableThreshold); err != nil {
+			return fmt.Errorf("failed unmarshaling table thresholds: %w", err)
+		}
+		for _, q := range tableThreshold {
+			h.WriteBlock("threshold", []string{}, func() {
+				h.WriteStringAttribute("column_name", q.ColumnName)
+				h.WriteStringAttribute("severity", q.Severity)
+				h.WriteFloatAttribute("from", q.From)
+				h.WriteFloatAttribute("to", q.To)
+			})
+		}
+	}
+
+	writeDataFormatters(h, config)
+
+	return nil
+}
+
+func writeDataFormatters(h *HCLGen, config *DashboardWidgetRawConfiguration) {
 	for _, q := range config.DataFormatters {
 		h.WriteBlock("data_format", []string{}, func() {
 			h.WriteStringAttribute("name", q.Name)
 			h.WriteStringAttribute("type", q.Type)
-			h.WriteStringAttribute("format", q.Format)
+			h.WriteStringAttributeIfNotEmpty("format", q.Format)
 			writeInterfaceValues(h, "precision", q.Precision) // function to handle different types of precision
 		})
 	}
 }
 
+// writeSeriesAttributes writes the null values, units and colors of a widget
+// and of its series, when they are set.
+func writeSeriesAttributes(h *HCLGen, config *DashboardWidgetRawConfiguration) {
+	if config.NullValues.NullValue != "" || len(config.NullValues.SeriesOverrides) > 0 {
+		h.WriteBlock("null_values", []string{}, func() {
+			h.WriteStringAttributeIfNotEmpty("null_value", config.NullValues.NullValue)
+			for _, so := range config.NullValues.SeriesOverrides {
+				h.WriteBlock("series_overrides", []string{}, func() {
+					h.WriteStringAttribute("null_value", so.NullValue)
+					h.WriteStringAttribute("series_name", so.SeriesName)
+				})
+			}
+		})
+	}
+
+	if config.Units.Unit != "" || len(config.Units.SeriesOverrides) > 0 {
+		h.WriteBlock("units", []string{}, func() {
+			h.WriteStringAttributeIfNotEmpty("unit", config.Units.Unit)
+			for _, so := range config.Units.SeriesOverrides {
+				h.WriteBlock("series_overrides", []string{}, func() {
+					h.WriteStringAttribute("unit", so.Unit)
+					h.WriteStringAttribute("series_name", so.SeriesName)
+				})
+			}
+		})
+	}
+
+	if config.Colors.Color != "" || len(config.Colors.SeriesOverrides) > 0 {
+		h.WriteBlock("colors", []string{}, func() {
+			h.WriteStringAttributeIfNotEmpty("color", config.Colors.Color)
+			for _, so := range config.Colors.SeriesOverrides {
+				h.WriteBlock("series_overrides", []string{}, func() {
+					h.WriteStringAttribute("color", so.Color)
+					h.WriteStringAttribute("series_name", so.SeriesName)
+				})
+			}
+		})
+	}
+}
+
 func writeInterfaceValues(h *HCLGen, title string, titleValue interface{}) {
 	switch titleValue := titleValue.(type) {
 	case string:
 		h.WriteStringAttribute(title, titleValue) // string without quotes
 	case float64:
 		h.WriteFloatAttribute(title, titleValue) // integer without quotes
-	default:
-		h.WriteStringAttribute(title, "")
 	}
 }
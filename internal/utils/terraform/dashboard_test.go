@@ -0,0 +1,70 @@
+//go:build unit
+
+package terraform
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestGenerateDashboardHCL generates the HCL of each dashboard JSON export in
+// testdata, comparing it with the HCL in the .tf file of the same name.
+func TestGenerateDashboardHCL(t *testing.T) {
+	t.Parallel()
+
+	files, err := filepath.Glob(filepath.Join("testdata", "*.json"))
+	require.NoError(t, err)
+	require.NotEmpty(t, files)
+
+	for _, file := range files {
+		name := strings.TrimSuffix(filepath.Base(file), ".json")
+
+		t.Run(name, func(t *testing.T) {
+			input, err := os.ReadFile(file)
+			require.NoError(t, err)
+
+			expected, err := os.ReadFile(filepath.Join("testdata", name+".tf"))
+			require.NoError(t, err)
+
+			hcl, err := GenerateDashboardHCL(name, 2, input)
+			require.NoError(t, err)
+			assert.Equal(t, string(expected), hcl)
+		})
+	}
+}
+
+func TestGenerateDashboardHCL_InvalidJSON(t *testing.T) {
+	t.Parallel()
+
+	_, err := GenerateDashboardHCL("dashboard", 2, []byte(`{"name": `))
+	require.Error(t, err)
+}
+
+func TestResolveWidgetType(t *testing.T) {
+	t.Parallel()
+
+	config := &DashboardWidgetRawConfiguration{}
+	assert.Equal(t, "widget_stacked_bar", resolveWidgetType("title", "viz.stacked-bar", config))
+
+	config = &DashboardWidgetRawConfiguration{NRQLQueries: []DashboardWidgetNRQLQuery{{AccountID: 1, Query: "SELECT 1"}}}
+	assert.Equal(t, "widget_json", resolveWidgetType("title", "abc.custom", config))
+
+	config = &DashboardWidgetRawConfiguration{}
+	assert.Equal(t, "widget_markdown", resolveWidgetType("title", "abc.custom", config))
+	assert.Equal(t, "Visualization abc.custom is not supported.", config.Text)
+}
+
+func TestGenerateDashboardHCL_InvalidThresholds(t *testing.T) {
+	t.Parallel()
+
+	_, err := GenerateDashboardHCL("dashboard", 2, []byte(`{"name": "Checkout", "pages": [{"name": "Overview", "widgets": [
+		{"title": "Latency", "visualization": {"id": "viz.line"}, "rawConfiguration": {"thresholds": "high"}}
+	]}]}`))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `widget "Latency" of type "viz.line"`)
+}
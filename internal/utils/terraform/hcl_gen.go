@@ -28,8 +28,16 @@ func (h *HCLGen) WriteMultilineStringAttributeIfNotEmpty(label string, value str
 	}
 }
 
+// hclStringEscaper escapes the characters and template sequences that would
+// otherwise end or interpolate a quoted HCL string.
+var hclStringEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`, "${", "$${", "%{", "%%{")
+
+func quoteString(value string) string {
+	return "\"" + hclStringEscaper.Replace(value) + "\""
+}
+
 func (h *HCLGen) WriteStringAttribute(label string, value string) {
-	h.WriteString(fmt.Sprintf("%s%s = \"%s\"\n", h.i, label, strings.ReplaceAll(value, "\"", "\\\"")))
+	h.WriteString(fmt.Sprintf("%s%s = %s\n", h.i, label, quoteString(value)))
 }
 
 func (h *HCLGen) WriteBooleanAttribute(label string, value bool) {
@@ -46,7 +54,11 @@ func (h *HCLGen) WriteStringAttributeIfNotEmpty(label string, value string) {
 }
 
 func (h *HCLGen) WriteStringSliceAttribute(label string, value []string) {
-	h.WriteString(fmt.Sprintf("%s%s = [\"%s\"]", h.i, label, strings.Join(value, "\",\"")))
+	quoted := make([]string, len(value))
+	for i, v := range value {
+		quoted[i] = quoteString(v)
+	}
+	h.WriteString(fmt.Sprintf("%s%s = [%s]\n", h.i, label, strings.Join(quoted, ",")))
 }
 
 func (h *HCLGen) WriteStringSliceAttributeIfNotEmpty(label string, value []string) {
@@ -76,6 +88,21 @@ func (h *HCLGen) WriteIntArrayAttribute(label string, values []int) {
 	h.WriteString(fmt.Sprintf("%s%s = %s\n", h.i, label, arrayString))
 }
 
+// WriteReferenceAttribute writes an attribute whose value is an expression,
+// such as a reference to another resource, rather than a string.
+func (h *HCLGen) WriteReferenceAttribute(label string, value string) {
+	h.WriteString(fmt.Sprintf("%s%s = %s\n", h.i, label, value))
+}
+
+// WriteImportBlock writes an import block importing the resource with the ID
+// to the resource address.
+func (h *HCLGen) WriteImportBlock(address string, id string) {
+	h.WriteBlock("import", []string{}, func() {
+		h.WriteReferenceAttribute("to", address)
+		h.WriteStringAttribute("id", id)
+	})
+}
+
 func (h *HCLGen) WriteIntAttributeIfNotZero(label string, value int) {
 	if value != 0 {
 		h.WriteIntAttribute(label, value)
@@ -0,0 +1,21 @@
+//go:build unit
+
+package terraform
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHCLGenShouldEscapeStrings(t *testing.T) {
+	t.Parallel()
+
+	h := NewHCLGen(2)
+	h.WriteStringAttribute("name", `"ops" C:\teams ${var.name} %{if}`)
+	h.WriteStringSliceAttribute("values", []string{`"ops"`, `C:\teams`, "${var.owner}", "two\nlines"})
+
+	assert.Equal(t, `name = "\"ops\" C:\\teams $${var.name} %%{if}"
+values = ["\"ops\"","C:\\teams","$${var.owner}","two\nlines"]
+`, h.String())
+}
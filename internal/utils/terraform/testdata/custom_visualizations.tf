@@ -0,0 +1,61 @@
+
+resource "newrelic_one_dashboard" "custom_visualizations" {
+  name = "Custom visualizations"
+  permissions = "private"
+
+  page {
+    name = "Services"
+
+    widget_pie {
+      title = "Services by team"
+      row = 1
+      column = 1
+      height = 3
+      width = 4
+      filter_current_dashboard = true
+      linked_entity_guids = ["MTIzNHxWSVp8REFTSEJPQVJEfDIw"]
+
+      nrql_query {
+        account_id = jsonencode([1234, 5678])
+        query = <<EOT
+SELECT count(*) FROM Transaction FACET team
+EOT
+      }
+      facet_show_other_series = true
+      legend_enabled = true
+      ignore_time_range = false
+    }
+
+    widget_json {
+      title = "Service map"
+      row = 1
+      column = 5
+      height = 3
+      width = 4
+
+      nrql_query {
+        account_id = 1234
+        query = <<EOT
+SELECT count(*) FROM Span FACET service.name, peer.service.name
+EOT
+      }
+      facet_show_other_series = false
+      legend_enabled = false
+      ignore_time_range = false
+    }
+
+    widget_markdown {
+      title = "Team logo"
+      row = 1
+      column = 9
+      height = 3
+      width = 4
+      text = <<EOT
+Visualization a1b2c3d4-e5f6.image is not supported.
+EOT
+      facet_show_other_series = false
+      legend_enabled = false
+      ignore_time_range = false
+    }
+  }
+}
@@ -0,0 +1,167 @@
+
+resource "newrelic_one_dashboard" "widgets" {
+  name = "Service overview"
+  description = "Golden signals of the service"
+  permissions = "public_read_write"
+
+  page {
+    name = "Overview"
+
+    widget_line {
+      title = "Throughput"
+      row = 1
+      column = 1
+      height = 3
+      width = 6
+
+      nrql_query {
+        account_id = 1234
+        query = <<EOT
+SELECT rate(count(*), 1 minute) FROM Transaction TIMESERIES
+EOT
+      }
+      facet_show_other_series = false
+      legend_enabled = true
+      ignore_time_range = false
+      y_axis_left_zero = true
+
+      y_axis_right {
+        y_axis_right_zero = false
+        y_axis_right_series = ["errors"]
+      }
+      is_label_visible = true
+
+      threshold {
+        name = "High"
+        severity = "warning"
+        from = 100
+        to = 200
+      }
+
+      units {
+        unit = "REQUESTS_PER_MINUTE"
+      }
+
+      colors {
+        color = "#0b6acb"
+
+        series_overrides {
+          color = "#df2d24"
+          series_name = "errors"
+        }
+      }
+    }
+
+    widget_billboard {
+      title = "Apdex"
+      row = 1
+      column = 7
+      height = 3
+      width = 3
+
+      nrql_query {
+        account_id = 1234
+        query = <<EOT
+SELECT apdex(duration, t: 0.5) FROM Transaction
+EOT
+      }
+      facet_show_other_series = false
+      legend_enabled = false
+      ignore_time_range = false
+      warning = 0.9
+      critical = 0.7
+
+      data_format {
+        name = "Apdex"
+        type = "decimal"
+        precision = 2
+      }
+    }
+
+    widget_table {
+      title = "Slowest transactions"
+      row = 1
+      column = 10
+      height = 3
+      width = 3
+
+      nrql_query {
+        account_id = 1234
+        query = <<EOT
+SELECT average(duration) FROM Transaction FACET name
+EOT
+      }
+      facet_show_other_series = false
+      legend_enabled = false
+      ignore_time_range = true
+      refresh_rate = 60000
+
+      initial_sorting {
+        name = "Avg duration"
+        direction = "desc"
+      }
+
+      threshold {
+        column_name = "Avg duration"
+        severity = "severe"
+        from = 1
+        to = 5
+      }
+
+      null_values {
+        null_value = "zero"
+      }
+    }
+
+    widget_markdown {
+      title = "About"
+      row = 4
+      column = 1
+      height = 1
+      width = 12
+      text = <<EOT
+# Service overview
+EOT
+      facet_show_other_series = false
+      legend_enabled = false
+      ignore_time_range = false
+    }
+  }
+
+  variable {
+    name = "appName"
+    title = "Application"
+    type = "nrql"
+    default_values = ["checkout"]
+
+    nrql_query {
+      account_ids = [1234]
+      query = "SELECT uniques(appName) FROM Transaction"
+    }
+    is_multi_selection = true
+    replacement_strategy = "string"
+
+    options {
+      ignore_time_range = true
+      excluded = false
+      show_apply_action = true
+    }
+  }
+
+  variable {
+    name = "env"
+    title = "Environment"
+    type = "enum"
+    default_values = ["production"]
+
+    item {
+      value = "production"
+      title = "Production"
+    }
+
+    item {
+      value = "staging"
+    }
+    replacement_strategy = "string"
+  }
+}